@@ -1,37 +1,75 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+
 	"go.uber.org/fx"
 
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/admin"
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/auth"
 	httpAdapter "github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http"
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/relay"
 	grpcAdapter "github.com/albenik/uber-fx-based-service-example/internal/adapters/out/grpc"
 	"github.com/albenik/uber-fx-based-service-example/internal/adapters/out/postgres"
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/out/publisher"
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/out/webhook"
 	"github.com/albenik/uber-fx-based-service-example/internal/config"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/services"
 	"github.com/albenik/uber-fx-based-service-example/internal/telemetry"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/health"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/metrics"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/tracing"
 )
 
 func main() {
-	fx.New(AppModules()...).Run()
+	flags := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	configPath := flags.String("config", "", "path to a YAML or TOML config file; environment variables override it")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s [-config FILE] [config print]\n", flags.Name())
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(os.Args[1:])
+
+	switch args := flags.Args(); {
+	case len(args) == 0:
+		fx.New(AppModules(*configPath)...).Run()
+	case len(args) == 2 && args[0] == "config" && args[1] == "print":
+		os.Exit(printConfig(*configPath, os.Stdout, os.Stderr))
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
 }
 
-func AppModules() []fx.Option {
+func AppModules(configPath string) []fx.Option {
 	return []fx.Option{
 		// Telemetry and monitoring
 		telemetry.Module(),
 
 		// Configuration
-		config.Module(),
+		config.Module(configPath),
 		fx.Invoke(telemetry.ReconfigureLogLevel),
+		metrics.Module(),
+		tracing.Module(),
 
 		// Output adapters (driven/secondary)
 		postgres.Module(),
 		grpcAdapter.Module(),
+		publisher.Module(),
+		webhook.Module(),
 
 		// Core business logic
 		services.Module(),
 
 		// Input adapters (driving/primary)
+		auth.Module(),
 		httpAdapter.Module(),
+		relay.Module(),
+		admin.Module(),
+
+		// Probes come last: their OnStop hook fails readiness before anything stops.
+		health.Module(),
 	}
 }
@@ -1,15 +1,87 @@
 package main_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/fx"
 	"go.uber.org/fx/fxtest"
+	"go.uber.org/mock/gomock"
 
 	main "github.com/albenik/uber-fx-based-service-example/cmd/server"
+	"github.com/albenik/uber-fx-based-service-example/internal/config"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/health"
 )
 
+// idleRelay stands in for the event relay, which needs a database.
+type idleRelay struct{}
+
+func (idleRelay) RelayBatch(context.Context) (int, error) { return 0, nil }
+
+// idleDispatcher stands in for the webhook dispatcher, which needs a database.
+type idleDispatcher struct{}
+
+func (idleDispatcher) Publish(context.Context, *domain.Event) error { return nil }
+
+func (idleDispatcher) DeliverBatch(context.Context) (int, error) { return 0, nil }
+
+// rejectingKeys stands in for the API key service, which needs a database.
+type rejectingKeys struct{}
+
+func (rejectingKeys) Authenticate(context.Context, string) (domain.Principal, error) {
+	return domain.Principal{}, domain.ErrUnauthenticated
+}
+
+// idleIdempotency stands in for the idempotency service, which needs a database.
+type idleIdempotency struct{}
+
+func (idleIdempotency) Begin(context.Context, string, string) (*domain.IdempotentResponse, error) {
+	return nil, nil
+}
+
+func (idleIdempotency) Complete(context.Context, string, *domain.IdempotentResponse) error {
+	return nil
+}
+
+func (idleIdempotency) Release(context.Context, string) error { return nil }
+
+func (idleIdempotency) PurgeExpired(context.Context) (int, error) { return 0, nil }
+
 func TestAppWiring(t *testing.T) {
-	app := fxtest.New(t, main.AppModules()...)
+	// The config must name a database to be valid; migrations are skipped all the same.
+	t.Setenv("DATABASE_MASTER_URL", "postgres://localhost/fleet")
+	// The services behind the HTTP handlers need a database; none of them is called
+	// while the app starts and stops.
+	ctrl := gomock.NewController(t)
+	// So do the collectors of the metrics group, which a registry of its own leaves out,
+	// and the checkers of the health group.
+	reg := prometheus.NewRegistry()
+	app := fxtest.New(t, append(main.AppModules(""),
+		fx.Decorate(func() prometheus.Registerer { return reg }),
+		fx.Decorate(func() prometheus.Gatherer { return reg }),
+		fx.Decorate(func(cfg *config.DatabaseConfig) *config.DatabaseConfig {
+			return &config.DatabaseConfig{ReplicaMaxLag: cfg.ReplicaMaxLag}
+		}),
+		fx.Decorate(func() *health.Registry { return health.NewRegistry(nil, time.Second) }),
+		fx.Decorate(func() ports.LegalEntityService { return mocks.NewMockLegalEntityService(ctrl) }),
+		fx.Decorate(func() ports.FleetService { return mocks.NewMockFleetService(ctrl) }),
+		fx.Decorate(func() ports.VehicleService { return mocks.NewMockVehicleService(ctrl) }),
+		fx.Decorate(func() ports.DriverService { return mocks.NewMockDriverService(ctrl) }),
+		fx.Decorate(func() ports.ContractService { return mocks.NewMockContractService(ctrl) }),
+		fx.Decorate(func() ports.VehicleAssignmentService { return mocks.NewMockVehicleAssignmentService(ctrl) }),
+		fx.Decorate(func() ports.AuditService { return mocks.NewMockAuditService(ctrl) }),
+		fx.Decorate(func() ports.WebhookService { return mocks.NewMockWebhookService(ctrl) }),
+		fx.Decorate(func() ports.EventStreamService { return mocks.NewMockEventStreamService(ctrl) }),
+		fx.Decorate(func() ports.EventRelay { return idleRelay{} }),
+		fx.Decorate(func() ports.WebhookDispatcher { return idleDispatcher{} }),
+		fx.Decorate(func() ports.APIKeyService { return rejectingKeys{} }),
+		fx.Decorate(func() ports.IdempotencyService { return idleIdempotency{} }),
+	)...)
 	app.RequireStart()
 	app.RequireStop()
 }
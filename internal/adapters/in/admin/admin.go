@@ -0,0 +1,139 @@
+// Package admin serves the operational endpoints of the service on a listener of their
+// own: metrics, profiling, build and runtime information, the effective config and the
+// log level. None of them is ever mounted on the public router.
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/config"
+)
+
+// Info describes the running binary and process.
+type Info struct {
+	GoVersion  string `json:"go_version"`
+	Module     string `json:"module"`
+	Version    string `json:"version"`
+	Revision   string `json:"vcs_revision,omitempty"`
+	RevisionAt string `json:"vcs_time,omitempty"`
+	Modified   bool   `json:"vcs_modified"`
+	StartedAt  string `json:"started_at"`
+	Uptime     string `json:"uptime"`
+	Goroutines int    `json:"goroutines"`
+	GOMAXPROCS int    `json:"gomaxprocs"`
+	NumCPU     int    `json:"num_cpu"`
+	HeapAlloc  uint64 `json:"heap_alloc_bytes"`
+	NumGC      uint32 `json:"num_gc"`
+}
+
+// NewHandler serves the metrics of gatherer at /metrics, pprof under /debug/pprof/,
+// build and runtime information at /admin/info, the config with secrets redacted at
+// /admin/config, and reads and changes the log level at /admin/log-level.
+func NewHandler(
+	level zap.AtomicLevel,
+	cfg *config.Config,
+	gatherer prometheus.Gatherer,
+	logger *zap.Logger,
+) http.Handler {
+	startedAt := time.Now()
+	mux := http.NewServeMux()
+
+	mux.Handle("GET /metrics", promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{}))
+
+	mux.HandleFunc("GET /debug/pprof/", pprof.Index)
+	mux.HandleFunc("GET /debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("POST /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
+
+	mux.HandleFunc("GET /admin/info", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, readInfo(startedAt))
+	})
+	mux.HandleFunc("GET /admin/config", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, plain(reflect.ValueOf(cfg)))
+	})
+	mux.Handle("GET /admin/log-level", level)
+	mux.HandleFunc("PUT /admin/log-level", func(w http.ResponseWriter, r *http.Request) {
+		before := level.Level()
+		level.ServeHTTP(w, r)
+		if after := level.Level(); after != before {
+			logger.Warn("Log level changed", zap.Stringer("from", before), zap.Stringer("to", after))
+		}
+	})
+
+	return mux
+}
+
+func readInfo(startedAt time.Time) Info {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	info := Info{
+		GoVersion:  runtime.Version(),
+		StartedAt:  startedAt.UTC().Format(time.RFC3339),
+		Uptime:     time.Since(startedAt).Round(time.Second).String(),
+		Goroutines: runtime.NumGoroutine(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		NumCPU:     runtime.NumCPU(),
+		HeapAlloc:  mem.HeapAlloc,
+		NumGC:      mem.NumGC,
+	}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		info.Module = build.Main.Path
+		info.Version = build.Main.Version
+		for _, s := range build.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				info.Revision = s.Value
+			case "vcs.time":
+				info.RevisionAt = s.Value
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+	}
+	return info
+}
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// plain converts config structs into maps that encode durations as strings such as
+// "1s" rather than nanoseconds.
+func plain(v reflect.Value) any {
+	switch {
+	case v.Kind() == reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return plain(v.Elem())
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String()
+	case v.Kind() == reflect.Struct:
+		out := make(map[string]any, v.NumField())
+		for i := range v.NumField() {
+			if f := v.Type().Field(i); f.IsExported() {
+				out[f.Name] = plain(v.Field(i))
+			}
+		}
+		return out
+	default:
+		return v.Interface()
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
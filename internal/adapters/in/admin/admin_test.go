@@ -0,0 +1,100 @@
+package admin_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/admin"
+	"github.com/albenik/uber-fx-based-service-example/internal/config"
+)
+
+func serve(h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler_LogLevel(t *testing.T) {
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	h := admin.NewHandler(level, &config.Config{}, prometheus.NewRegistry(), zaptest.NewLogger(t))
+
+	rec := serve(h, http.MethodGet, "/admin/log-level", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"level":"info"}`, rec.Body.String())
+
+	rec = serve(h, http.MethodPut, "/admin/log-level", `{"level":"debug"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, zapcore.DebugLevel, level.Level())
+
+	rec = serve(h, http.MethodPut, "/admin/log-level", `{"level":"loud"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, zapcore.DebugLevel, level.Level())
+}
+
+func TestHandler_Config(t *testing.T) {
+	cfg := &config.Config{
+		Database: &config.DatabaseConfig{
+			MasterURL:     "postgres://fleet:s3cret@db:5432/fleet?sslmode=disable",
+			ReplicaMaxLag: 30 * time.Second,
+		},
+	}
+	h := admin.NewHandler(zap.NewAtomicLevel(), cfg.Redacted(), prometheus.NewRegistry(), zaptest.NewLogger(t))
+
+	rec := serve(h, http.MethodGet, "/admin/config", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "s3cret")
+	var got struct {
+		Database struct {
+			MasterURL     string
+			ReplicaMaxLag string
+		}
+		Telemetry any
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, "postgres://fleet:xxxxx@db:5432/fleet?sslmode=disable", got.Database.MasterURL)
+	assert.Equal(t, "30s", got.Database.ReplicaMaxLag)
+	assert.Nil(t, got.Telemetry)
+}
+
+func TestHandler_InfoAndProfiling(t *testing.T) {
+	h := admin.NewHandler(zap.NewAtomicLevel(), &config.Config{}, prometheus.NewRegistry(), zaptest.NewLogger(t))
+
+	rec := serve(h, http.MethodGet, "/admin/info", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var info admin.Info
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &info))
+	assert.NotEmpty(t, info.GoVersion)
+	assert.Positive(t, info.Goroutines)
+
+	rec = serve(h, http.MethodGet, "/debug/pprof/", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = serve(h, http.MethodGet, "/debug/pprof/goroutine?debug=1", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestHandler_Metrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "test_total", Help: "Test counter."})
+	registry.MustRegister(counter)
+	counter.Inc()
+	h := admin.NewHandler(zap.NewAtomicLevel(), &config.Config{}, registry, zaptest.NewLogger(t))
+
+	rec := serve(h, http.MethodGet, "/metrics", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "test_total 1")
+}
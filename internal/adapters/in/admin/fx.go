@@ -0,0 +1,43 @@
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/httpserver"
+	"github.com/albenik/uber-fx-based-service-example/internal/config"
+)
+
+// Module serves the admin endpoints and the Prometheus metrics on ADMIN_HTTP_ADDR, a
+// listener separate from the public API.
+func Module() fx.Option {
+	return fx.Module("admin",
+		fx.Invoke(
+			fx.Annotate(
+				adminServerLifecycle,
+				fx.ParamTags(``, ``, ``, `name:"redacted"`, ``, ``, ``),
+			),
+		),
+	)
+}
+
+func adminServerLifecycle(
+	lc fx.Lifecycle,
+	cfg *config.AdminConfig,
+	level zap.AtomicLevel,
+	effective *config.Config,
+	gatherer prometheus.Gatherer,
+	shutdowner fx.Shutdowner,
+	logger *zap.Logger,
+) {
+	server := &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           NewHandler(level, effective, gatherer, logger),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	lc.Append(httpserver.Hook("Admin", server, shutdowner, logger))
+}
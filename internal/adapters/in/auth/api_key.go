@@ -0,0 +1,28 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+)
+
+// APIKeyHeader carries the static API key of a machine client.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuthenticator authenticates requests by the API key in the APIKeyHeader.
+type APIKeyAuthenticator struct {
+	svc ports.APIKeyService
+}
+
+func NewAPIKeyAuthenticator(svc ports.APIKeyService) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{svc: svc}
+}
+
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (domain.Principal, error) {
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		return domain.Principal{}, ErrNoCredentials
+	}
+	return a.svc.Authenticate(r.Context(), key)
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/config"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+)
+
+// Module provides the authentication Middleware of the HTTP API.
+func Module() fx.Option {
+	return fx.Module("auth",
+		fx.Provide(newMiddleware),
+	)
+}
+
+func newMiddleware(cfg *config.AuthConfig, keys ports.APIKeyService, logger *zap.Logger) (Middleware, error) {
+	if !cfg.Enabled {
+		logger.Warn("Authentication disabled, every request acts as an anonymous administrator")
+		return Disabled(), nil
+	}
+
+	var authenticators []Authenticator
+	if cfg.JWTEnabled() {
+		a, err := NewJWTAuthenticator(cfg)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, a)
+	}
+	if cfg.APIKeysEnabled {
+		authenticators = append(authenticators, NewAPIKeyAuthenticator(keys))
+	}
+	return NewMiddleware(authenticators, logger), nil
+}
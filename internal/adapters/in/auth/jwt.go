@@ -0,0 +1,217 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/config"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+)
+
+// clockSkew is the leeway allowed when checking the time claims of a token.
+const clockSkew = 30 * time.Second
+
+// JWTAuthenticator authenticates requests by the bearer token in the Authorization
+// header. Tokens are signed with HS256 or RS256 and must carry the exp and sub claims;
+// the principal is the subject, with the role and scopes of the claims below.
+type JWTAuthenticator struct {
+	parser    *jwt.Parser
+	hmacKey   []byte
+	rsaKey    *rsa.PublicKey
+	keysByKID map[string]any
+}
+
+// NewJWTAuthenticator loads the verification keys from the files named in cfg.
+func NewJWTAuthenticator(cfg *config.AuthConfig) (*JWTAuthenticator, error) {
+	a := &JWTAuthenticator{keysByKID: make(map[string]any)}
+	if cfg.JWTHS256SecretFile != "" {
+		secret, err := os.ReadFile(cfg.JWTHS256SecretFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read HS256 secret: %w", err)
+		}
+		a.hmacKey = []byte(strings.TrimSpace(string(secret)))
+		if len(a.hmacKey) == 0 {
+			return nil, errors.New("HS256 secret is empty")
+		}
+	}
+	if cfg.JWTRS256PublicKeyFile != "" {
+		pem, err := os.ReadFile(cfg.JWTRS256PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read RS256 public key: %w", err)
+		}
+		if a.rsaKey, err = jwt.ParseRSAPublicKeyFromPEM(pem); err != nil {
+			return nil, fmt.Errorf("failed to parse RS256 public key: %w", err)
+		}
+	}
+	if cfg.JWTJWKSFile != "" {
+		if err := a.loadJWKS(cfg.JWTJWKSFile); err != nil {
+			return nil, err
+		}
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(a.methods()),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(clockSkew),
+	}
+	if cfg.JWTIssuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.JWTIssuer))
+	}
+	if cfg.JWTAudience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.JWTAudience))
+	}
+	a.parser = jwt.NewParser(opts...)
+	return a, nil
+}
+
+// claims are the registered claims plus the authorization of the subject.
+type claims struct {
+	jwt.RegisteredClaims
+	Role           string   `json:"role"`
+	LegalEntityIDs []string `json:"legal_entity_ids"`
+	DriverID       string   `json:"driver_id"`
+}
+
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (domain.Principal, error) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return domain.Principal{}, ErrNoCredentials
+	}
+	var c claims
+	if _, err := a.parser.ParseWithClaims(strings.TrimSpace(token), &c, a.key); err != nil {
+		return domain.Principal{}, fmt.Errorf("%w: %w", domain.ErrUnauthenticated, err)
+	}
+	if c.Subject == "" {
+		return domain.Principal{}, fmt.Errorf("%w: token has no subject", domain.ErrUnauthenticated)
+	}
+	return domain.Principal{
+		ID:             c.Subject,
+		Role:           domain.Role(c.Role),
+		LegalEntityIDs: c.LegalEntityIDs,
+		DriverID:       c.DriverID,
+	}, nil
+}
+
+// key picks the verification key by the kid header, falling back to the key configured
+// for the signing method. A key is never used with a method of another kind, which
+// would let an RSA public key be taken for an HMAC secret.
+func (a *JWTAuthenticator) key(t *jwt.Token) (any, error) {
+	var key any
+	if kid, _ := t.Header["kid"].(string); kid != "" {
+		var ok bool
+		if key, ok = a.keysByKID[kid]; !ok {
+			return nil, fmt.Errorf("unknown key ID %q", kid)
+		}
+	} else {
+		switch t.Method.Alg() {
+		case jwt.SigningMethodHS256.Alg():
+			key = a.hmacKey
+		case jwt.SigningMethodRS256.Alg():
+			key = a.rsaKey
+		}
+	}
+	switch k := key.(type) {
+	case []byte:
+		if t.Method.Alg() == jwt.SigningMethodHS256.Alg() && len(k) > 0 {
+			return k, nil
+		}
+	case *rsa.PublicKey:
+		if t.Method.Alg() == jwt.SigningMethodRS256.Alg() && k != nil {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("no %s key", t.Method.Alg())
+}
+
+func (a *JWTAuthenticator) methods() []string {
+	hs, rs := len(a.hmacKey) > 0, a.rsaKey != nil
+	for _, k := range a.keysByKID {
+		switch k.(type) {
+		case []byte:
+			hs = true
+		case *rsa.PublicKey:
+			rs = true
+		}
+	}
+	var methods []string
+	if hs {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if rs {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	return methods
+}
+
+// jwk is a JSON Web Key (RFC 7517) of type RSA or oct.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+// loadJWKS reads the signing keys of a JSON Web Key Set. Keys of other types, such as
+// EC keys, and encryption keys are skipped.
+func (a *JWTAuthenticator) loadJWKS(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read JWKS: %w", err)
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if k.Kid == "" {
+			return errors.New("JWKS key without kid")
+		}
+		var key any
+		switch k.Kty {
+		case "RSA":
+			key, err = k.rsaPublicKey()
+		case "oct":
+			key, err = base64.RawURLEncoding.DecodeString(k.K)
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to parse JWKS key %q: %w", k.Kid, err)
+		}
+		a.keysByKID[k.Kid] = key
+	}
+	return nil
+}
+
+func (k *jwk) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid exponent")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
@@ -0,0 +1,170 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/auth"
+	"github.com/albenik/uber-fx-based-service-example/internal/config"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+)
+
+const hmacSecret = "0123456789abcdef0123456789abcdef"
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func bearerRequest(token string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/fleets", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	return r
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	require.NoError(t, err)
+	return s
+}
+
+func validClaims() jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		Subject:   "user-1",
+		Issuer:    "https://id.example.com",
+		Audience:  jwt.ClaimStrings{"fleet-api"},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+}
+
+func TestJWTAuthenticator_HS256(t *testing.T) {
+	a, err := auth.NewJWTAuthenticator(&config.AuthConfig{
+		JWTHS256SecretFile: writeFile(t, "secret", []byte(hmacSecret+"\n")),
+		JWTIssuer:          "https://id.example.com",
+		JWTAudience:        "fleet-api",
+	})
+	require.NoError(t, err)
+
+	expired := validClaims()
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+	noExpiry := validClaims()
+	noExpiry.ExpiresAt = nil
+	otherAudience := validClaims()
+	otherAudience.Audience = jwt.ClaimStrings{"billing-api"}
+	noSubject := validClaims()
+	noSubject.Subject = ""
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"valid", sign(t, jwt.SigningMethodHS256, "", []byte(hmacSecret), validClaims()), false},
+		{"expired", sign(t, jwt.SigningMethodHS256, "", []byte(hmacSecret), expired), true},
+		{"no expiry", sign(t, jwt.SigningMethodHS256, "", []byte(hmacSecret), noExpiry), true},
+		{"other audience", sign(t, jwt.SigningMethodHS256, "", []byte(hmacSecret), otherAudience), true},
+		{"no subject", sign(t, jwt.SigningMethodHS256, "", []byte(hmacSecret), noSubject), true},
+		{"bad signature", sign(t, jwt.SigningMethodHS256, "", []byte("another-secret"), validClaims()), true},
+		{"HS512", sign(t, jwt.SigningMethodHS512, "", []byte(hmacSecret), validClaims()), true},
+		{"malformed", "not-a-token", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := a.Authenticate(bearerRequest(tt.token))
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrUnauthenticated)
+				assert.NotErrorIs(t, err, auth.ErrNoCredentials)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, domain.Principal{ID: "user-1"}, p)
+		})
+	}
+}
+
+func TestJWTAuthenticator_RoleClaims(t *testing.T) {
+	a, err := auth.NewJWTAuthenticator(&config.AuthConfig{JWTHS256SecretFile: writeFile(t, "secret", []byte(hmacSecret))})
+	require.NoError(t, err)
+
+	token := sign(t, jwt.SigningMethodHS256, "", []byte(hmacSecret), jwt.MapClaims{
+		"sub":              "user-1",
+		"exp":              time.Now().Add(time.Hour).Unix(),
+		"role":             "fleet_manager",
+		"legal_entity_ids": []string{"le-1", "le-2"},
+	})
+	p, err := a.Authenticate(bearerRequest(token))
+	require.NoError(t, err)
+	assert.Equal(t, domain.Principal{
+		ID:             "user-1",
+		Role:           domain.RoleFleetManager,
+		LegalEntityIDs: []string{"le-1", "le-2"},
+	}, p)
+}
+
+func TestJWTAuthenticator_NoCredentials(t *testing.T) {
+	a, err := auth.NewJWTAuthenticator(&config.AuthConfig{JWTHS256SecretFile: writeFile(t, "secret", []byte(hmacSecret))})
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodGet, "/fleets", nil)
+	_, err = a.Authenticate(r)
+	assert.ErrorIs(t, err, auth.ErrNoCredentials)
+
+	r.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	_, err = a.Authenticate(r)
+	assert.ErrorIs(t, err, auth.ErrNoCredentials)
+}
+
+func TestJWTAuthenticator_RS256WithJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{
+		{
+			"kty": "RSA",
+			"kid": "rsa-1",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		},
+		{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": "", "y": ""},
+	}})
+	require.NoError(t, err)
+
+	a, err := auth.NewJWTAuthenticator(&config.AuthConfig{JWTJWKSFile: writeFile(t, "jwks.json", jwks)})
+	require.NoError(t, err)
+
+	p, err := a.Authenticate(bearerRequest(sign(t, jwt.SigningMethodRS256, "rsa-1", key, validClaims())))
+	require.NoError(t, err)
+	assert.Equal(t, domain.Principal{ID: "user-1"}, p)
+
+	_, err = a.Authenticate(bearerRequest(sign(t, jwt.SigningMethodRS256, "rsa-2", key, validClaims())))
+	assert.ErrorIs(t, err, domain.ErrUnauthenticated)
+
+	// No HS256 key is configured, so the RSA key must not be taken for an HMAC secret.
+	_, err = a.Authenticate(bearerRequest(sign(t, jwt.SigningMethodHS256, "rsa-1", []byte(hmacSecret), validClaims())))
+	assert.ErrorIs(t, err, domain.ErrUnauthenticated)
+}
+
+func TestNewJWTAuthenticator_MissingFile(t *testing.T) {
+	_, err := auth.NewJWTAuthenticator(&config.AuthConfig{JWTRS256PublicKeyFile: filepath.Join(t.TempDir(), "missing.pem")})
+	assert.ErrorContains(t, err, "RS256 public key")
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http/problem"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+)
+
+// ErrNoCredentials is returned by an Authenticator when the request carries no
+// credentials of its kind, so that the next one may try.
+var ErrNoCredentials = fmt.Errorf("%w: no credentials", domain.ErrUnauthenticated)
+
+// Authenticator identifies the caller of a request by one kind of credentials.
+type Authenticator interface {
+	// Authenticate returns the caller, ErrNoCredentials, or an error wrapping
+	// domain.ErrUnauthenticated when the credentials are invalid.
+	Authenticate(r *http.Request) (domain.Principal, error)
+}
+
+// Middleware rejects unauthenticated requests and puts the principal of the others
+// into the request context.
+type Middleware func(http.Handler) http.Handler
+
+// NewMiddleware authenticates requests with the first authenticator that finds
+// credentials in them.
+func NewMiddleware(authenticators []Authenticator, logger *zap.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			err := ErrNoCredentials
+			for _, a := range authenticators {
+				var p domain.Principal
+				p, err = a.Authenticate(r)
+				if err == nil {
+					next.ServeHTTP(w, r.WithContext(domain.ContextWithPrincipal(r.Context(), p)))
+					return
+				}
+				if !errors.Is(err, ErrNoCredentials) {
+					break
+				}
+			}
+			if errors.Is(err, domain.ErrUnauthenticated) {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+			}
+			problem.Error(w, r, logger, "authenticate", err)
+		})
+	}
+}
+
+// Disabled returns a Middleware letting every request through as an anonymous
+// administrator, so that authorization does not get in the way either.
+func Disabled() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p := domain.Principal{Role: domain.RoleAdmin}
+			next.ServeHTTP(w, r.WithContext(domain.ContextWithPrincipal(r.Context(), p)))
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package auth_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/auth"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
+)
+
+// principalEcho responds with the ID of the principal in the request context.
+var principalEcho = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte(domain.PrincipalFromContext(r.Context()).ID))
+})
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiKey     string
+		authErr    error
+		wantStatus int
+		wantBody   string
+	}{
+		{"valid key", "key-1", nil, http.StatusOK, "svc-payroll"},
+		{"no credentials", "", nil, http.StatusUnauthorized, ""},
+		{"invalid key", "key-2", domain.ErrUnauthenticated, http.StatusUnauthorized, ""},
+		{"service failure", "key-3", errors.New("connection refused"), http.StatusInternalServerError, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mocks.NewMockAPIKeyService(gomock.NewController(t))
+			if tt.apiKey != "" {
+				svc.EXPECT().Authenticate(gomock.Any(), tt.apiKey).
+					Return(domain.Principal{ID: "svc-payroll"}, tt.authErr)
+			}
+			authenticate := auth.NewMiddleware(
+				[]auth.Authenticator{auth.NewAPIKeyAuthenticator(svc)}, zaptest.NewLogger(t))
+
+			req := httptest.NewRequest(http.MethodDelete, "/legal-entities/le-1", nil)
+			if tt.apiKey != "" {
+				req.Header.Set(auth.APIKeyHeader, tt.apiKey)
+			}
+			rec := httptest.NewRecorder()
+			authenticate(principalEcho).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, tt.wantBody, rec.Body.String())
+			}
+			if tt.wantStatus == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestDisabled_LetsAnonymousAdministratorThrough(t *testing.T) {
+	var got domain.Principal
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = domain.PrincipalFromContext(r.Context())
+	})
+	rec := httptest.NewRecorder()
+	auth.Disabled()(next).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fleets", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, domain.Principal{Role: domain.RoleAdmin}, got)
+}
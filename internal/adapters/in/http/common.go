@@ -4,16 +4,23 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"mime"
 	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
 
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http/problem"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 )
 
 func requireJSON(w http.ResponseWriter, r *http.Request) bool {
 	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
 	if err != nil || mediaType != "application/json" {
-		http.Error(w, "unsupported media type", http.StatusUnsupportedMediaType)
+		problem.Write(w, r, problem.New(http.StatusUnsupportedMediaType, problem.CodeUnsupportedMediaType,
+			"unsupported media type"))
 		return false
 	}
 	return true
@@ -23,25 +30,57 @@ func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
 	dec := json.NewDecoder(r.Body)
 	dec.DisallowUnknownFields()
 	if err := dec.Decode(v); err != nil {
-		var maxBytesErr *http.MaxBytesError
-		if errors.As(err, &maxBytesErr) {
-			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
-			return false
-		}
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		problem.Write(w, r, decodeProblem(err))
 		return false
 	}
 	if dec.More() {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		problem.Write(w, r, problem.New(http.StatusBadRequest, domain.ErrorCode(domain.ErrInvalidInput),
+			"invalid request body"))
 		return false
 	}
 	return true
 }
 
-func respondJSON(w http.ResponseWriter, status int, data any) {
+// decodeProblem describes why a request body could not be decoded, pointing at the
+// offending field when there is one.
+func decodeProblem(err error) *problem.Details {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return problem.New(http.StatusRequestEntityTooLarge, problem.CodeRequestTooLarge, "request body too large")
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return problem.Invalid(typeErr.Field, domain.RuleFormat, "must be "+jsonKind(typeErr.Type.Kind()))
+	}
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return problem.Invalid(strings.Trim(field, `"`), domain.RuleUnknown, "is not supported")
+	}
+	return problem.New(http.StatusBadRequest, domain.ErrorCode(domain.ErrInvalidInput), "invalid request body")
+}
+
+// jsonKind names the JSON type that a Go value of the kind is decoded from.
+func jsonKind(k reflect.Kind) string {
+	switch k {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
+
+func respondJSON(w http.ResponseWriter, r *http.Request, status int, data any) {
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(data); err != nil {
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		problem.Write(w, r, problem.New(http.StatusInternalServerError, problem.CodeInternal,
+			"internal server error"))
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -49,27 +88,90 @@ func respondJSON(w http.ResponseWriter, status int, data any) {
 	_, _ = buf.WriteTo(w)
 }
 
-func mapDomainErrorToStatus(err error) int {
-	switch {
-	case errors.Is(err, domain.ErrNotFound):
-		return http.StatusNotFound
-	case errors.Is(err, domain.ErrInvalidInput):
-		return http.StatusBadRequest
-	case errors.Is(err, domain.ErrConflict):
-		return http.StatusConflict
-	case errors.Is(err, domain.ErrContractNotActive):
-		return http.StatusUnprocessableEntity
-	case errors.Is(err, domain.ErrDriverAlreadyAssignedInFleet):
-		return http.StatusConflict
-	case errors.Is(err, domain.ErrDriverHasActiveContracts), errors.Is(err, domain.ErrDriverHasActiveAssignments):
-		return http.StatusConflict
-	case errors.Is(err, domain.ErrAlreadyDeleted):
-		return http.StatusConflict
-	case errors.Is(err, domain.ErrValidationServiceUnavailable):
-		return http.StatusServiceUnavailable
-	case errors.Is(err, domain.ErrLicenseValidationFailed):
-		return http.StatusUnprocessableEntity
-	default:
-		return http.StatusInternalServerError
+// parseCascade reads the cascade query parameter of a delete or undelete request.
+func parseCascade(w http.ResponseWriter, r *http.Request) (cascade, ok bool) {
+	v := r.URL.Query().Get("cascade")
+	if v == "" {
+		return false, true
+	}
+	cascade, err := strconv.ParseBool(v)
+	if err != nil {
+		problem.Write(w, r, problem.Invalid("cascade", domain.RuleFormat, "must be a boolean"))
+		return false, false
+	}
+	return cascade, true
+}
+
+// setETag exposes an entity version as a strong ETag.
+func setETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// parseIfMatch returns the entity version required by the If-Match header,
+// or 0 when the header is absent or "*". If-Match compares entity tags strongly, so a
+// weak tag never matches and the precondition fails.
+func parseIfMatch(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return 0, true
+	}
+	if strings.HasPrefix(value, "W/") {
+		problem.Write(w, r, problem.FromError(fmt.Errorf("%w: weak entity tags never match", domain.ErrPreconditionFailed)))
+		return 0, false
+	}
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		problem.Write(w, r, problem.Invalid("If-Match", domain.RuleFormat, "must be a quoted entity version"))
+		return 0, false
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		problem.Write(w, r, problem.Invalid("If-Match", domain.RuleFormat, "must be a quoted entity version"))
+		return 0, false
+	}
+	return version, true
+}
+
+// listResponse is the envelope of every list endpoint.
+type listResponse[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+func newListResponse[E any, T any](page *ports.Page[E], convert func(E) T) listResponse[T] {
+	items := make([]T, 0, len(page.Items))
+	for _, e := range page.Items {
+		items = append(items, convert(e))
+	}
+	return listResponse[T]{Items: items, NextCursor: page.NextCursor}
+}
+
+// parseListQuery reads limit, cursor, sort and order query parameters. Every other
+// query parameter is treated as an equality filter on the field of the same name.
+func parseListQuery(w http.ResponseWriter, r *http.Request) (ports.ListQuery, bool) {
+	var q ports.ListQuery
+	for key, values := range r.URL.Query() {
+		value := values[len(values)-1]
+		switch key {
+		case "limit":
+			limit, err := strconv.Atoi(value)
+			if err != nil {
+				problem.Write(w, r, problem.Invalid("limit", domain.RuleFormat, "must be an integer"))
+				return q, false
+			}
+			q.Limit = limit
+		case "cursor":
+			q.Cursor = value
+		case "sort":
+			q.SortBy = value
+		case "order":
+			q.SortDir = ports.SortDirection(value)
+		default:
+			if q.Filters == nil {
+				q.Filters = make(map[string]string)
+			}
+			q.Filters[key] = value
+		}
 	}
+	return q, true
 }
@@ -1,16 +1,50 @@
 package http
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http/problem"
 )
 
 func TestRespondJSON_EncodingError(t *testing.T) {
 	rec := httptest.NewRecorder()
-	respondJSON(rec, http.StatusOK, make(chan int))
+	respondJSON(rec, httptest.NewRequest(http.MethodGet, "/", nil), http.StatusOK, make(chan int))
 
 	assert.Equal(t, http.StatusInternalServerError, rec.Code)
 }
+
+func TestDecodeJSON_PointsAtInvalidField(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		field string
+	}{
+		{"wrong type", `{"year":"2020"}`, "year"},
+		{"unknown field", `{"colour":"red"}`, "colour"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			var v struct {
+				Year int `json:"year"`
+			}
+			ok := decodeJSON(rec, httptest.NewRequest(http.MethodPost, "/vehicles", strings.NewReader(tt.body)), &v)
+			require.False(t, ok)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+			var d problem.Details
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&d))
+			assert.Equal(t, "invalid_input", d.Code)
+			require.Len(t, d.Errors, 1)
+			assert.Equal(t, tt.field, d.Errors[0].Field)
+		})
+	}
+}
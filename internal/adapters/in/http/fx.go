@@ -1,78 +1,93 @@
 package http
 
 import (
-	"context"
-	"errors"
-	"net"
 	"net/http"
+	"time"
 
 	"go.uber.org/fx"
 	"go.uber.org/zap"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/httpserver"
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/worker"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 )
 
 // Module provides input adapters (driving adapters).
 func Module() fx.Option {
 	return fx.Module("http",
+		routes(),
 		fx.Provide(
-			fx.Annotate(
-				NewLegalEntityHandler,
-				fx.ResultTags(`group:"routes"`),
-			),
-			fx.Annotate(
-				NewFleetHandler,
-				fx.ResultTags(`group:"routes"`),
-			),
-			fx.Annotate(
-				NewVehicleHandler,
-				fx.ResultTags(`group:"routes"`),
-			),
-			fx.Annotate(
-				NewDriverHandler,
-				fx.ResultTags(`group:"routes"`),
-			),
-			fx.Annotate(
-				NewContractHandler,
-				fx.ResultTags(`group:"routes"`),
-			),
-			fx.Annotate(
-				NewAssignmentHandler,
-				fx.ResultTags(`group:"routes"`),
-			),
-		),
-		fx.Provide(
+			NewIdempotencyMiddleware,
 			fx.Annotate(
 				NewServer,
-				fx.ParamTags(``, `group:"routes"`),
+				fx.ParamTags(``, `group:"routes"`, ``, ``, ``, ``, ``),
 			),
 		),
-		fx.Invoke(httpServerLifecycle),
+		fx.Invoke(httpServerLifecycle, idempotencyPurgerLifecycle),
 	)
 }
 
-func httpServerLifecycle(lc fx.Lifecycle, server *http.Server, shutdowner fx.Shutdowner, logger *zap.Logger) {
-	lc.Append(fx.Hook{
-		OnStart: func(ctx context.Context) error {
-			ln, err := net.Listen("tcp", server.Addr)
-			if err != nil {
-				return err
-			}
+// routes provides every handler to the routes group consumed by NewServer.
+func routes() fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			NewLegalEntityHandler,
+			fx.As(new(RouteRegistrar)),
+			fx.ResultTags(`group:"routes"`),
+		),
+		fx.Annotate(
+			NewFleetHandler,
+			fx.As(new(RouteRegistrar)),
+			fx.ResultTags(`group:"routes"`),
+		),
+		fx.Annotate(
+			NewVehicleHandler,
+			fx.As(new(RouteRegistrar)),
+			fx.ResultTags(`group:"routes"`),
+		),
+		fx.Annotate(
+			NewDriverHandler,
+			fx.As(new(RouteRegistrar)),
+			fx.ResultTags(`group:"routes"`),
+		),
+		fx.Annotate(
+			NewContractHandler,
+			fx.As(new(RouteRegistrar)),
+			fx.ResultTags(`group:"routes"`),
+		),
+		fx.Annotate(
+			NewAssignmentHandler,
+			fx.As(new(RouteRegistrar)),
+			fx.ResultTags(`group:"routes"`),
+		),
+		fx.Annotate(
+			NewAuditHandler,
+			fx.As(new(RouteRegistrar)),
+			fx.ResultTags(`group:"routes"`),
+		),
+		fx.Annotate(
+			NewWebhookHandler,
+			fx.As(new(RouteRegistrar)),
+			fx.ResultTags(`group:"routes"`),
+		),
+		fx.Annotate(
+			NewEventStreamHandler,
+			fx.As(new(RouteRegistrar)),
+			fx.ResultTags(`group:"routes"`),
+		),
+	)
+}
 
-			logger.Info("HTTP server listening", zap.String("address", ln.Addr().String()))
-			go func() {
-				if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
-					logger.Error("HTTP server error", zap.Error(err))
-					if shutdownErr := shutdowner.Shutdown(); shutdownErr != nil {
-						logger.Error("failed to trigger shutdown", zap.Error(shutdownErr))
-					}
-				}
-			}()
+func httpServerLifecycle(lc fx.Lifecycle, server *http.Server, shutdowner fx.Shutdowner, logger *zap.Logger) {
+	lc.Append(httpserver.Hook("HTTP", server, shutdowner, logger))
+}
 
-			return nil
-		},
+// purgeInterval is how often expired idempotency keys are looked for. Nothing depends
+// on their prompt removal; they only take up space.
+const purgeInterval = time.Minute
 
-		OnStop: func(ctx context.Context) error {
-			logger.Info("Shutting down HTTP server")
-			return server.Shutdown(ctx)
-		},
-	})
+// idempotencyPurgerLifecycle removes the idempotency keys stored by the middleware once
+// they expire.
+func idempotencyPurgerLifecycle(lc fx.Lifecycle, svc ports.IdempotencyService, logger *zap.Logger) {
+	lc.Append(worker.Hook("idempotency_purger", svc.PurgeExpired, purgeInterval, logger))
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http/problem"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 )
@@ -27,6 +28,7 @@ func (h *AssignmentHandler) RegisterRoutes(r chi.Router) {
 	})
 	r.Route("/assignments", func(r chi.Router) {
 		r.Get("/{id}", h.get)
+		r.Patch("/{id}", h.update)
 		r.Post("/{id}/return", h.returnVehicle)
 		r.Delete("/{id}", h.delete)
 		r.Post("/{id}/undelete", h.undelete)
@@ -37,6 +39,10 @@ type createAssignmentRequest struct {
 	VehicleID string `json:"vehicle_id"`
 }
 
+type updateAssignmentRequest struct {
+	VehicleID *string `json:"vehicle_id"`
+}
+
 type assignmentResponse struct {
 	ID         string  `json:"id"`
 	DriverID   string  `json:"driver_id"`
@@ -44,6 +50,11 @@ type assignmentResponse struct {
 	ContractID string  `json:"contract_id"`
 	StartTime  string  `json:"start_time"`
 	EndTime    *string `json:"end_time,omitempty"`
+	Version    int64   `json:"version"`
+	CreatedAt  string  `json:"created_at"`
+	CreatedBy  string  `json:"created_by"`
+	UpdatedAt  string  `json:"updated_at"`
+	UpdatedBy  string  `json:"updated_by"`
 }
 
 func (h *AssignmentHandler) assign(w http.ResponseWriter, r *http.Request) {
@@ -57,50 +68,75 @@ func (h *AssignmentHandler) assign(w http.ResponseWriter, r *http.Request) {
 	contractID := chi.URLParam(r, "contractId")
 	entity, err := h.svc.Assign(r.Context(), contractID, req.VehicleID)
 	if err != nil {
-		h.handleError(w, "assign vehicle", err)
+		problem.Error(w, r, h.logger, "assign vehicle", err)
 		return
 	}
-	respondJSON(w, http.StatusCreated, assignmentToResponse(entity))
+	setETag(w, entity.Version)
+	respondJSON(w, r, http.StatusCreated, assignmentToResponse(entity))
 }
 
 func (h *AssignmentHandler) get(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	entity, err := h.svc.Get(r.Context(), id)
 	if err != nil {
-		h.handleError(w, "get assignment", err)
+		problem.Error(w, r, h.logger, "get assignment", err)
 		return
 	}
-	respondJSON(w, http.StatusOK, assignmentToResponse(entity))
+	setETag(w, entity.Version)
+	respondJSON(w, r, http.StatusOK, assignmentToResponse(entity))
 }
 
 func (h *AssignmentHandler) listByContract(w http.ResponseWriter, r *http.Request) {
 	contractID := chi.URLParam(r, "contractId")
-	entities, err := h.svc.ListByContract(r.Context(), contractID)
+	q, ok := parseListQuery(w, r)
+	if !ok {
+		return
+	}
+	page, err := h.svc.ListByContract(r.Context(), contractID, q)
 	if err != nil {
-		h.handleError(w, "list assignments", err)
+		problem.Error(w, r, h.logger, "list assignments", err)
+		return
+	}
+	respondJSON(w, r, http.StatusOK, newListResponse(page, assignmentToResponse))
+}
+
+func (h *AssignmentHandler) update(w http.ResponseWriter, r *http.Request) {
+	if !requireJSON(w, r) {
+		return
+	}
+	version, ok := parseIfMatch(w, r)
+	if !ok {
 		return
 	}
-	resp := make([]assignmentResponse, 0, len(entities))
-	for _, e := range entities {
-		resp = append(resp, assignmentToResponse(e))
+	var req updateAssignmentRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	id := chi.URLParam(r, "id")
+	entity, err := h.svc.Update(r.Context(), id, version, domain.VehicleAssignmentPatch{VehicleID: req.VehicleID})
+	if err != nil {
+		problem.Error(w, r, h.logger, "update assignment", err)
+		return
 	}
-	respondJSON(w, http.StatusOK, resp)
+	setETag(w, entity.Version)
+	respondJSON(w, r, http.StatusOK, assignmentToResponse(entity))
 }
 
 func (h *AssignmentHandler) returnVehicle(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	entity, err := h.svc.Return(r.Context(), id)
 	if err != nil {
-		h.handleError(w, "return vehicle", err)
+		problem.Error(w, r, h.logger, "return vehicle", err)
 		return
 	}
-	respondJSON(w, http.StatusOK, assignmentToResponse(entity))
+	setETag(w, entity.Version)
+	respondJSON(w, r, http.StatusOK, assignmentToResponse(entity))
 }
 
 func (h *AssignmentHandler) delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	if err := h.svc.Delete(r.Context(), id); err != nil {
-		h.handleError(w, "delete assignment", err)
+	if err := h.svc.Delete(r.Context(), id, ports.DeleteOptions{Reason: r.URL.Query().Get("reason")}); err != nil {
+		problem.Error(w, r, h.logger, "delete assignment", err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -109,7 +145,7 @@ func (h *AssignmentHandler) delete(w http.ResponseWriter, r *http.Request) {
 func (h *AssignmentHandler) undelete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if err := h.svc.Undelete(r.Context(), id); err != nil {
-		h.handleError(w, "undelete assignment", err)
+		problem.Error(w, r, h.logger, "undelete assignment", err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -128,15 +164,10 @@ func assignmentToResponse(e *domain.VehicleAssignment) assignmentResponse {
 		ContractID: e.ContractID,
 		StartTime:  e.StartTime.Format(time.RFC3339),
 		EndTime:    endTime,
+		Version:    e.Version,
+		CreatedAt:  e.CreatedAt.Format(time.RFC3339),
+		CreatedBy:  e.CreatedBy,
+		UpdatedAt:  e.UpdatedAt.Format(time.RFC3339),
+		UpdatedBy:  e.UpdatedBy,
 	}
 }
-
-func (h *AssignmentHandler) handleError(w http.ResponseWriter, op string, err error) {
-	if domain.IsExposable(err) {
-		http.Error(w, err.Error(), mapDomainErrorToStatus(err))
-		return
-	}
-
-	h.logger.Error("assignment operation failed", zap.String("op", op), zap.Error(err))
-	http.Error(w, "internal server error", http.StatusInternalServerError)
-}
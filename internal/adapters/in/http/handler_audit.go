@@ -0,0 +1,65 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http/problem"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+)
+
+type AuditHandler struct {
+	svc    ports.AuditService
+	logger *zap.Logger
+}
+
+func NewAuditHandler(svc ports.AuditService, logger *zap.Logger) *AuditHandler {
+	return &AuditHandler{svc: svc, logger: logger}
+}
+
+func (h *AuditHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/audit", h.list)
+}
+
+type auditEntryResponse struct {
+	ID         string `json:"id"`
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	Action     string `json:"action"`
+	Actor      string `json:"actor"`
+	At         string `json:"at"`
+	DeletionID string `json:"deletion_id,omitempty"`
+	Before     any    `json:"before"`
+	After      any    `json:"after"`
+}
+
+func (h *AuditHandler) list(w http.ResponseWriter, r *http.Request) {
+	q, ok := parseListQuery(w, r)
+	if !ok {
+		return
+	}
+	page, err := h.svc.List(r.Context(), q)
+	if err != nil {
+		problem.Error(w, r, h.logger, "list audit entries", err)
+		return
+	}
+	respondJSON(w, r, http.StatusOK, newListResponse(page, auditEntryToResponse))
+}
+
+func auditEntryToResponse(e *domain.AuditEntry) auditEntryResponse {
+	return auditEntryResponse{
+		ID:         e.ID,
+		EntityType: string(e.EntityType),
+		EntityID:   e.EntityID,
+		Action:     string(e.Action),
+		Actor:      e.Actor,
+		At:         e.At.Format(time.RFC3339),
+		DeletionID: e.DeletionID,
+		Before:     e.Before,
+		After:      e.After,
+	}
+}
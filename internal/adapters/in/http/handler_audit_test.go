@@ -0,0 +1,86 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+
+	httpAdapter "github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
+)
+
+func setupAuditHandler(t *testing.T) (*mocks.MockAuditService, chi.Router) {
+	ctrl := gomock.NewController(t)
+	mockSvc := mocks.NewMockAuditService(ctrl)
+	handler := httpAdapter.NewAuditHandler(mockSvc, zaptest.NewLogger(t))
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+	return mockSvc, r
+}
+
+func TestAuditHandler_List_ByEntity(t *testing.T) {
+	mockSvc, router := setupAuditHandler(t)
+
+	want := ports.ListQuery{
+		Limit:   10,
+		Filters: map[string]string{"entity_type": "contract", "entity_id": "c1"},
+	}
+	page := &ports.Page[*domain.AuditEntry]{
+		Items: []*domain.AuditEntry{{
+			ID:         "e1",
+			EntityType: domain.EntityContract,
+			EntityID:   "c1",
+			Action:     domain.AuditTerminate,
+			Actor:      "alice",
+			At:         time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+			Before:     json.RawMessage(`{"TerminatedBy":""}`),
+			After:      json.RawMessage(`{"TerminatedBy":"hr"}`),
+		}},
+		NextCursor: "next",
+	}
+	mockSvc.EXPECT().List(gomock.Any(), want).Return(page, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/audit?entity_type=contract&entity_id=c1&limit=10", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp struct {
+		Items      []map[string]any `json:"items"`
+		NextCursor string           `json:"next_cursor"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Items, 1)
+	item := resp.Items[0]
+	assert.Equal(t, "contract", item["entity_type"])
+	assert.Equal(t, "c1", item["entity_id"])
+	assert.Equal(t, "terminate", item["action"])
+	assert.Equal(t, "alice", item["actor"])
+	assert.Equal(t, "2025-06-01T12:00:00Z", item["at"])
+	assert.Equal(t, map[string]any{"TerminatedBy": ""}, item["before"])
+	assert.Equal(t, map[string]any{"TerminatedBy": "hr"}, item["after"])
+	assert.Equal(t, "next", resp.NextCursor)
+}
+
+func TestAuditHandler_List_UnsupportedFilter(t *testing.T) {
+	mockSvc, router := setupAuditHandler(t)
+
+	mockSvc.EXPECT().List(gomock.Any(), gomock.Any()).
+		Return(nil, domain.ErrInvalidInput)
+
+	req := httptest.NewRequest(http.MethodGet, "/audit?color=red", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
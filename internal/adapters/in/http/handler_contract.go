@@ -7,6 +7,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http/problem"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 )
@@ -27,6 +28,7 @@ func (h *ContractHandler) RegisterRoutes(r chi.Router) {
 	})
 	r.Route("/contracts", func(r chi.Router) {
 		r.Get("/{id}", h.get)
+		r.Patch("/{id}", h.update)
 		r.Post("/{id}/terminate", h.terminate)
 		r.Delete("/{id}", h.delete)
 		r.Post("/{id}/undelete", h.undelete)
@@ -40,6 +42,11 @@ type createContractRequest struct {
 	EndDate       string `json:"end_date"`
 }
 
+type updateContractRequest struct {
+	StartDate *string `json:"start_date"`
+	EndDate   *string `json:"end_date"`
+}
+
 type terminateContractRequest struct {
 	TerminatedBy string `json:"terminated_by"`
 }
@@ -53,12 +60,27 @@ type contractResponse struct {
 	EndDate       string  `json:"end_date"`
 	TerminatedAt  *string `json:"terminated_at,omitempty"`
 	TerminatedBy  string  `json:"terminated_by,omitempty"`
+	Version       int64   `json:"version"`
+	CreatedAt     string  `json:"created_at"`
+	CreatedBy     string  `json:"created_by"`
+	UpdatedAt     string  `json:"updated_at"`
+	UpdatedBy     string  `json:"updated_by"`
 }
 
 func parseDate(s string) (time.Time, error) {
 	return time.Parse("2006-01-02", s)
 }
 
+// parseDateField parses a date field of a request, recording a violation when it is
+// malformed.
+func parseDateField(invalid *domain.ValidationError, field, value string) time.Time {
+	t, err := parseDate(value)
+	if err != nil {
+		invalid.Add(field, domain.RuleFormat, "must be a date in YYYY-MM-DD format")
+	}
+	return t
+}
+
 func formatDate(t time.Time) string {
 	return t.Format("2006-01-02")
 }
@@ -72,47 +94,84 @@ func (h *ContractHandler) create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	driverID := chi.URLParam(r, "driverId")
-	startDate, err := parseDate(req.StartDate)
-	if err != nil {
-		http.Error(w, "invalid start_date format (use YYYY-MM-DD)", http.StatusBadRequest)
-		return
-	}
-	endDate, err := parseDate(req.EndDate)
-	if err != nil {
-		http.Error(w, "invalid end_date format (use YYYY-MM-DD)", http.StatusBadRequest)
+	var invalid domain.ValidationError
+	startDate := parseDateField(&invalid, "start_date", req.StartDate)
+	endDate := parseDateField(&invalid, "end_date", req.EndDate)
+	if len(invalid.Violations) > 0 {
+		// The service is not called without the dates, so the parties it would check are
+		// checked here to report every invalid field at once.
+		domain.ValidateContractParties(&invalid, driverID, req.LegalEntityID, req.FleetID)
+		problem.Write(w, r, problem.FromError(invalid.Err()))
 		return
 	}
 	entity, err := h.svc.Create(r.Context(), driverID, req.LegalEntityID, req.FleetID, startDate, endDate)
 	if err != nil {
-		h.handleError(w, "create contract", err)
+		problem.Error(w, r, h.logger, "create contract", err)
 		return
 	}
-	resp := contractToResponse(entity)
-	respondJSON(w, http.StatusCreated, resp)
+	setETag(w, entity.Version)
+	respondJSON(w, r, http.StatusCreated, contractToResponse(entity))
 }
 
 func (h *ContractHandler) get(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	entity, err := h.svc.Get(r.Context(), id)
 	if err != nil {
-		h.handleError(w, "get contract", err)
+		problem.Error(w, r, h.logger, "get contract", err)
 		return
 	}
-	respondJSON(w, http.StatusOK, contractToResponse(entity))
+	setETag(w, entity.Version)
+	respondJSON(w, r, http.StatusOK, contractToResponse(entity))
 }
 
 func (h *ContractHandler) listByDriver(w http.ResponseWriter, r *http.Request) {
 	driverID := chi.URLParam(r, "driverId")
-	entities, err := h.svc.ListByDriver(r.Context(), driverID)
+	q, ok := parseListQuery(w, r)
+	if !ok {
+		return
+	}
+	page, err := h.svc.ListByDriver(r.Context(), driverID, q)
 	if err != nil {
-		h.handleError(w, "list contracts", err)
+		problem.Error(w, r, h.logger, "list contracts", err)
 		return
 	}
-	resp := make([]contractResponse, 0, len(entities))
-	for _, e := range entities {
-		resp = append(resp, contractToResponse(e))
+	respondJSON(w, r, http.StatusOK, newListResponse(page, contractToResponse))
+}
+
+func (h *ContractHandler) update(w http.ResponseWriter, r *http.Request) {
+	if !requireJSON(w, r) {
+		return
 	}
-	respondJSON(w, http.StatusOK, resp)
+	version, ok := parseIfMatch(w, r)
+	if !ok {
+		return
+	}
+	var req updateContractRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	var patch domain.ContractPatch
+	var invalid domain.ValidationError
+	if req.StartDate != nil {
+		startDate := parseDateField(&invalid, "start_date", *req.StartDate)
+		patch.StartDate = &startDate
+	}
+	if req.EndDate != nil {
+		endDate := parseDateField(&invalid, "end_date", *req.EndDate)
+		patch.EndDate = &endDate
+	}
+	if err := invalid.Err(); err != nil {
+		problem.Write(w, r, problem.FromError(err))
+		return
+	}
+	id := chi.URLParam(r, "id")
+	entity, err := h.svc.Update(r.Context(), id, version, patch)
+	if err != nil {
+		problem.Error(w, r, h.logger, "update contract", err)
+		return
+	}
+	setETag(w, entity.Version)
+	respondJSON(w, r, http.StatusOK, contractToResponse(entity))
 }
 
 func (h *ContractHandler) terminate(w http.ResponseWriter, r *http.Request) {
@@ -126,16 +185,17 @@ func (h *ContractHandler) terminate(w http.ResponseWriter, r *http.Request) {
 	}
 	entity, err := h.svc.Terminate(r.Context(), id, req.TerminatedBy)
 	if err != nil {
-		h.handleError(w, "terminate contract", err)
+		problem.Error(w, r, h.logger, "terminate contract", err)
 		return
 	}
-	respondJSON(w, http.StatusOK, contractToResponse(entity))
+	setETag(w, entity.Version)
+	respondJSON(w, r, http.StatusOK, contractToResponse(entity))
 }
 
 func (h *ContractHandler) delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	if err := h.svc.Delete(r.Context(), id); err != nil {
-		h.handleError(w, "delete contract", err)
+	if err := h.svc.Delete(r.Context(), id, ports.DeleteOptions{Reason: r.URL.Query().Get("reason")}); err != nil {
+		problem.Error(w, r, h.logger, "delete contract", err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -144,7 +204,7 @@ func (h *ContractHandler) delete(w http.ResponseWriter, r *http.Request) {
 func (h *ContractHandler) undelete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if err := h.svc.Undelete(r.Context(), id); err != nil {
-		h.handleError(w, "undelete contract", err)
+		problem.Error(w, r, h.logger, "undelete contract", err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -165,14 +225,10 @@ func contractToResponse(e *domain.Contract) contractResponse {
 		EndDate:       formatDate(e.EndDate),
 		TerminatedAt:  terminatedAt,
 		TerminatedBy:  e.TerminatedBy,
+		Version:       e.Version,
+		CreatedAt:     e.CreatedAt.Format(time.RFC3339),
+		CreatedBy:     e.CreatedBy,
+		UpdatedAt:     e.UpdatedAt.Format(time.RFC3339),
+		UpdatedBy:     e.UpdatedBy,
 	}
 }
-
-func (h *ContractHandler) handleError(w http.ResponseWriter, op string, err error) {
-	if domain.IsExposable(err) {
-		http.Error(w, err.Error(), mapDomainErrorToStatus(err))
-		return
-	}
-	h.logger.Error("contract operation failed", zap.String("op", op), zap.Error(err))
-	http.Error(w, "internal server error", http.StatusInternalServerError)
-}
@@ -0,0 +1,53 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+
+	httpAdapter "github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
+)
+
+func setupContractHandler(t *testing.T) (*mocks.MockContractService, chi.Router) {
+	ctrl := gomock.NewController(t)
+	mockSvc := mocks.NewMockContractService(ctrl)
+	handler := httpAdapter.NewContractHandler(mockSvc, zaptest.NewLogger(t))
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+	return mockSvc, r
+}
+
+func TestContractHandler_Create_ListsInvalidDatesWithOtherFields(t *testing.T) {
+	_, router := setupContractHandler(t)
+
+	body, _ := json.Marshal(map[string]string{
+		"legal_entity_id": "le1", "start_date": "01/01/2025", "end_date": "2025-12-31",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/drivers/d1/contracts", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var resp struct {
+		Errors []struct {
+			Field string `json:"field"`
+			Rule  string `json:"rule"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Errors, 2)
+	assert.Equal(t, "start_date", resp.Errors[0].Field)
+	assert.Equal(t, "format", resp.Errors[0].Rule)
+	assert.Equal(t, "fleet_id", resp.Errors[1].Field)
+	assert.Equal(t, "required", resp.Errors[1].Rule)
+}
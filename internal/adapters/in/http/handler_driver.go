@@ -2,10 +2,12 @@ package http
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http/problem"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 )
@@ -24,6 +26,7 @@ func (h *DriverHandler) RegisterRoutes(r chi.Router) {
 		r.Get("/", h.list)
 		r.Post("/", h.create)
 		r.Get("/{id}", h.get)
+		r.Patch("/{id}", h.update)
 		r.Delete("/{id}", h.delete)
 		r.Post("/{id}/undelete", h.undelete)
 		r.Post("/{id}/validate", h.validateLicense)
@@ -36,11 +39,22 @@ type createDriverRequest struct {
 	LicenseNumber string `json:"license_number"`
 }
 
+type updateDriverRequest struct {
+	FirstName     *string `json:"first_name"`
+	LastName      *string `json:"last_name"`
+	LicenseNumber *string `json:"license_number"`
+}
+
 type driverResponse struct {
 	ID            string `json:"id"`
 	FirstName     string `json:"first_name"`
 	LastName      string `json:"last_name"`
 	LicenseNumber string `json:"license_number"`
+	Version       int64  `json:"version"`
+	CreatedAt     string `json:"created_at"`
+	CreatedBy     string `json:"created_by"`
+	UpdatedAt     string `json:"updated_at"`
+	UpdatedBy     string `json:"updated_by"`
 }
 
 func (h *DriverHandler) create(w http.ResponseWriter, r *http.Request) {
@@ -53,39 +67,67 @@ func (h *DriverHandler) create(w http.ResponseWriter, r *http.Request) {
 	}
 	entity, err := h.svc.Create(r.Context(), req.FirstName, req.LastName, req.LicenseNumber)
 	if err != nil {
-		h.handleError(w, "create driver", err)
+		problem.Error(w, r, h.logger, "create driver", err)
 		return
 	}
-	respondJSON(w, http.StatusCreated, driverResponse{ID: entity.ID, FirstName: entity.FirstName, LastName: entity.LastName, LicenseNumber: entity.LicenseNumber})
+	setETag(w, entity.Version)
+	respondJSON(w, r, http.StatusCreated, driverToResponse(entity))
 }
 
 func (h *DriverHandler) get(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	entity, err := h.svc.Get(r.Context(), id)
 	if err != nil {
-		h.handleError(w, "get driver", err)
+		problem.Error(w, r, h.logger, "get driver", err)
 		return
 	}
-	respondJSON(w, http.StatusOK, driverResponse{ID: entity.ID, FirstName: entity.FirstName, LastName: entity.LastName, LicenseNumber: entity.LicenseNumber})
+	setETag(w, entity.Version)
+	respondJSON(w, r, http.StatusOK, driverToResponse(entity))
 }
 
 func (h *DriverHandler) list(w http.ResponseWriter, r *http.Request) {
-	entities, err := h.svc.List(r.Context())
+	q, ok := parseListQuery(w, r)
+	if !ok {
+		return
+	}
+	page, err := h.svc.List(r.Context(), q)
 	if err != nil {
-		h.handleError(w, "list drivers", err)
+		problem.Error(w, r, h.logger, "list drivers", err)
+		return
+	}
+	respondJSON(w, r, http.StatusOK, newListResponse(page, driverToResponse))
+}
+
+func (h *DriverHandler) update(w http.ResponseWriter, r *http.Request) {
+	if !requireJSON(w, r) {
+		return
+	}
+	version, ok := parseIfMatch(w, r)
+	if !ok {
 		return
 	}
-	resp := make([]driverResponse, 0, len(entities))
-	for _, e := range entities {
-		resp = append(resp, driverResponse{ID: e.ID, FirstName: e.FirstName, LastName: e.LastName, LicenseNumber: e.LicenseNumber})
+	var req updateDriverRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	id := chi.URLParam(r, "id")
+	entity, err := h.svc.Update(r.Context(), id, version, domain.DriverPatch{
+		FirstName:     req.FirstName,
+		LastName:      req.LastName,
+		LicenseNumber: req.LicenseNumber,
+	})
+	if err != nil {
+		problem.Error(w, r, h.logger, "update driver", err)
+		return
 	}
-	respondJSON(w, http.StatusOK, resp)
+	setETag(w, entity.Version)
+	respondJSON(w, r, http.StatusOK, driverToResponse(entity))
 }
 
 func (h *DriverHandler) delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	if err := h.svc.Delete(r.Context(), id); err != nil {
-		h.handleError(w, "delete driver", err)
+	if err := h.svc.Delete(r.Context(), id, ports.DeleteOptions{Reason: r.URL.Query().Get("reason")}); err != nil {
+		problem.Error(w, r, h.logger, "delete driver", err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -94,7 +136,7 @@ func (h *DriverHandler) delete(w http.ResponseWriter, r *http.Request) {
 func (h *DriverHandler) undelete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if err := h.svc.Undelete(r.Context(), id); err != nil {
-		h.handleError(w, "undelete driver", err)
+		problem.Error(w, r, h.logger, "undelete driver", err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -109,18 +151,22 @@ func (h *DriverHandler) validateLicense(w http.ResponseWriter, r *http.Request)
 	id := chi.URLParam(r, "id")
 	result, err := h.svc.ValidateLicense(r.Context(), id)
 	if err != nil {
-		h.handleError(w, "validate driver license", err)
+		problem.Error(w, r, h.logger, "validate driver license", err)
 		return
 	}
-	respondJSON(w, http.StatusOK, validateLicenseResponse{DriverID: id, Result: string(result)})
+	respondJSON(w, r, http.StatusOK, validateLicenseResponse{DriverID: id, Result: string(result)})
 }
 
-func (h *DriverHandler) handleError(w http.ResponseWriter, op string, err error) {
-	if domain.IsExposable(err) {
-		http.Error(w, err.Error(), mapDomainErrorToStatus(err))
-		return
+func driverToResponse(e *domain.Driver) driverResponse {
+	return driverResponse{
+		ID:            e.ID,
+		FirstName:     e.FirstName,
+		LastName:      e.LastName,
+		LicenseNumber: e.LicenseNumber,
+		Version:       e.Version,
+		CreatedAt:     e.CreatedAt.Format(time.RFC3339),
+		CreatedBy:     e.CreatedBy,
+		UpdatedAt:     e.UpdatedAt.Format(time.RFC3339),
+		UpdatedBy:     e.UpdatedBy,
 	}
-
-	h.logger.Error("driver operation failed", zap.String("op", op), zap.Error(err))
-	http.Error(w, "internal server error", http.StatusInternalServerError)
 }
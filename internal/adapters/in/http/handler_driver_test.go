@@ -15,6 +15,7 @@ import (
 
 	httpAdapter "github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
 )
 
@@ -30,7 +31,7 @@ func setupDriverHandler(t *testing.T) (*mocks.MockDriverService, chi.Router) {
 func TestDriverHandler_Delete_RejectsActiveContracts(t *testing.T) {
 	mockSvc, router := setupDriverHandler(t)
 
-	mockSvc.EXPECT().Delete(gomock.Any(), "d1").Return(domain.ErrDriverHasActiveContracts)
+	mockSvc.EXPECT().Delete(gomock.Any(), "d1", ports.DeleteOptions{}).Return(domain.ErrDriverHasActiveContracts)
 
 	req := httptest.NewRequest(http.MethodDelete, "/drivers/d1", nil)
 	rec := httptest.NewRecorder()
@@ -43,7 +44,7 @@ func TestDriverHandler_Delete_RejectsActiveContracts(t *testing.T) {
 func TestDriverHandler_Delete_RejectsActiveAssignments(t *testing.T) {
 	mockSvc, router := setupDriverHandler(t)
 
-	mockSvc.EXPECT().Delete(gomock.Any(), "d1").Return(domain.ErrDriverHasActiveAssignments)
+	mockSvc.EXPECT().Delete(gomock.Any(), "d1", ports.DeleteOptions{}).Return(domain.ErrDriverHasActiveAssignments)
 
 	req := httptest.NewRequest(http.MethodDelete, "/drivers/d1", nil)
 	rec := httptest.NewRecorder()
@@ -65,8 +66,54 @@ func TestDriverHandler_Create_Success(t *testing.T) {
 	router.ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusCreated, rec.Code)
-	var resp map[string]string
+	var resp map[string]any
 	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
 	assert.Equal(t, "d1", resp["id"])
 	assert.Equal(t, "John", resp["first_name"])
 }
+
+func TestDriverHandler_Create_ListsInvalidFields(t *testing.T) {
+	mockSvc, router := setupDriverHandler(t)
+
+	invalid := &domain.ValidationError{}
+	invalid.Add("first_name", domain.RuleRequired, "is required")
+	invalid.Add("last_name", domain.RuleRequired, "is required")
+	mockSvc.EXPECT().Create(gomock.Any(), "", "", "DL-123").Return(nil, invalid)
+
+	body, _ := json.Marshal(map[string]string{"license_number": "DL-123"})
+	req := httptest.NewRequest(http.MethodPost, "/drivers", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var resp struct {
+		Code   string `json:"code"`
+		Errors []struct {
+			Field string `json:"field"`
+			Rule  string `json:"rule"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "invalid_input", resp.Code)
+	require.Len(t, resp.Errors, 2)
+	assert.Equal(t, "first_name", resp.Errors[0].Field)
+	assert.Equal(t, "last_name", resp.Errors[1].Field)
+	assert.Equal(t, "required", resp.Errors[1].Rule)
+}
+
+func TestDriverHandler_Create_DuplicateLicenseNumber(t *testing.T) {
+	mockSvc, router := setupDriverHandler(t)
+
+	mockSvc.EXPECT().Create(gomock.Any(), "John", "Doe", "DL-123").
+		Return(nil, &domain.UniqueViolationError{Field: "license_number"})
+
+	body, _ := json.Marshal(map[string]string{"first_name": "John", "last_name": "Doe", "license_number": "DL-123"})
+	req := httptest.NewRequest(http.MethodPost, "/drivers", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "license_number is already in use")
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http/problem"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+)
+
+// heartbeatInterval keeps idle streams from being closed by proxies.
+const heartbeatInterval = 15 * time.Second
+
+type EventStreamHandler struct {
+	svc    ports.EventStreamService
+	logger *zap.Logger
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func NewEventStreamHandler(svc ports.EventStreamService, logger *zap.Logger) *EventStreamHandler {
+	return &EventStreamHandler{svc: svc, logger: logger, closed: make(chan struct{})}
+}
+
+func (h *EventStreamHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/events/stream", h.stream)
+}
+
+// OnShutdown ends all open streams, which would otherwise hold up a graceful shutdown.
+// Clients reconnect to another instance with the Last-Event-ID header.
+func (h *EventStreamHandler) OnShutdown() {
+	h.closeOnce.Do(func() { close(h.closed) })
+}
+
+type eventResponse struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	OccurredAt string `json:"occurred_at"`
+	Payload    any    `json:"payload"`
+}
+
+// stream serves the events matching the fleet_id and legal_entity_id query parameters
+// as Server-Sent Events. A stream resumes after the event in the Last-Event-ID header,
+// or in the last_event_id query parameter for clients that cannot set headers.
+func (h *EventStreamHandler) stream(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := domain.EventFilter{
+		FleetID:       query.Get("fleet_id"),
+		LegalEntityID: query.Get("legal_entity_id"),
+	}
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = query.Get("last_event_id")
+	}
+	events, err := h.svc.Stream(r.Context(), filter, lastEventID)
+	if err != nil {
+		problem.Error(w, r, h.logger, "stream events", err)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// The server write timeout is meant for regular requests, not for streams.
+	_ = rc.SetWriteDeadline(time.Time{})
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		h.logger.Error("event stream not supported", zap.Error(err))
+		return
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.closed:
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, e); err != nil {
+				h.logger.Warn("Failed to write event", zap.String("id", e.ID), zap.Error(err))
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e *domain.Event) error {
+	data, err := json.Marshal(eventResponse{
+		ID:         e.ID,
+		Type:       string(e.Type),
+		OccurredAt: e.OccurredAt.Format(time.RFC3339),
+		Payload:    e.Payload,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
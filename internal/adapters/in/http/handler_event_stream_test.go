@@ -0,0 +1,68 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+
+	httpAdapter "github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
+)
+
+func setupEventStreamHandler(t *testing.T) (*mocks.MockEventStreamService, chi.Router) {
+	ctrl := gomock.NewController(t)
+	mockSvc := mocks.NewMockEventStreamService(ctrl)
+	handler := httpAdapter.NewEventStreamHandler(mockSvc, zaptest.NewLogger(t))
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+	return mockSvc, r
+}
+
+func TestEventStreamHandler_Stream(t *testing.T) {
+	mockSvc, router := setupEventStreamHandler(t)
+
+	events := make(chan *domain.Event, 1)
+	events <- &domain.Event{
+		ID:         "e2",
+		Type:       domain.EventContractTerminated,
+		OccurredAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		Payload:    json.RawMessage(`{"contract_id":"c1"}`),
+	}
+	close(events)
+	mockSvc.EXPECT().
+		Stream(gomock.Any(), domain.EventFilter{FleetID: "f1"}, "e1").
+		Return((<-chan *domain.Event)(events), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/events/stream?fleet_id=f1", nil)
+	req.Header.Set("Last-Event-ID", "e1")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "id: e2\nevent: contract.terminated\n"+
+		`data: {"id":"e2","type":"contract.terminated","occurred_at":"2025-06-01T12:00:00Z","payload":{"contract_id":"c1"}}`+"\n\n",
+		rec.Body.String())
+}
+
+func TestEventStreamHandler_Stream_UnknownLastEventID(t *testing.T) {
+	mockSvc, router := setupEventStreamHandler(t)
+
+	mockSvc.EXPECT().
+		Stream(gomock.Any(), domain.EventFilter{}, "gone").
+		Return(nil, domain.ErrNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/events/stream?last_event_id=gone", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
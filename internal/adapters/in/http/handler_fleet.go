@@ -2,10 +2,12 @@ package http
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http/problem"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 )
@@ -26,6 +28,7 @@ func (h *FleetHandler) RegisterRoutes(r chi.Router) {
 	})
 	r.Route("/fleets", func(r chi.Router) {
 		r.Get("/{id}", h.get)
+		r.Patch("/{id}", h.update)
 		r.Delete("/{id}", h.delete)
 		r.Post("/{id}/undelete", h.undelete)
 	})
@@ -35,10 +38,19 @@ type createFleetRequest struct {
 	Name string `json:"name"`
 }
 
+type updateFleetRequest struct {
+	Name *string `json:"name"`
+}
+
 type fleetResponse struct {
-	ID             string `json:"id"`
-	LegalEntityID  string `json:"legal_entity_id"`
-	Name           string `json:"name"`
+	ID            string `json:"id"`
+	LegalEntityID string `json:"legal_entity_id"`
+	Name          string `json:"name"`
+	Version       int64  `json:"version"`
+	CreatedAt     string `json:"created_at"`
+	CreatedBy     string `json:"created_by"`
+	UpdatedAt     string `json:"updated_at"`
+	UpdatedBy     string `json:"updated_by"`
 }
 
 func (h *FleetHandler) create(w http.ResponseWriter, r *http.Request) {
@@ -52,59 +64,96 @@ func (h *FleetHandler) create(w http.ResponseWriter, r *http.Request) {
 	legalEntityID := chi.URLParam(r, "legalEntityId")
 	entity, err := h.svc.Create(r.Context(), legalEntityID, req.Name)
 	if err != nil {
-		h.handleError(w, "create fleet", err)
+		problem.Error(w, r, h.logger, "create fleet", err)
 		return
 	}
-	respondJSON(w, http.StatusCreated, fleetResponse{ID: entity.ID, LegalEntityID: entity.LegalEntityID, Name: entity.Name})
+	setETag(w, entity.Version)
+	respondJSON(w, r, http.StatusCreated, fleetToResponse(entity))
 }
 
 func (h *FleetHandler) get(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	entity, err := h.svc.Get(r.Context(), id)
 	if err != nil {
-		h.handleError(w, "get fleet", err)
+		problem.Error(w, r, h.logger, "get fleet", err)
 		return
 	}
-	respondJSON(w, http.StatusOK, fleetResponse{ID: entity.ID, LegalEntityID: entity.LegalEntityID, Name: entity.Name})
+	setETag(w, entity.Version)
+	respondJSON(w, r, http.StatusOK, fleetToResponse(entity))
 }
 
 func (h *FleetHandler) listByLegalEntity(w http.ResponseWriter, r *http.Request) {
 	legalEntityID := chi.URLParam(r, "legalEntityId")
-	entities, err := h.svc.ListByLegalEntity(r.Context(), legalEntityID)
+	q, ok := parseListQuery(w, r)
+	if !ok {
+		return
+	}
+	page, err := h.svc.ListByLegalEntity(r.Context(), legalEntityID, q)
 	if err != nil {
-		h.handleError(w, "list fleets", err)
+		problem.Error(w, r, h.logger, "list fleets", err)
+		return
+	}
+	respondJSON(w, r, http.StatusOK, newListResponse(page, fleetToResponse))
+}
+
+func (h *FleetHandler) update(w http.ResponseWriter, r *http.Request) {
+	if !requireJSON(w, r) {
+		return
+	}
+	version, ok := parseIfMatch(w, r)
+	if !ok {
 		return
 	}
-	resp := make([]fleetResponse, 0, len(entities))
-	for _, e := range entities {
-		resp = append(resp, fleetResponse{ID: e.ID, LegalEntityID: e.LegalEntityID, Name: e.Name})
+	var req updateFleetRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	id := chi.URLParam(r, "id")
+	entity, err := h.svc.Update(r.Context(), id, version, domain.FleetPatch{Name: req.Name})
+	if err != nil {
+		problem.Error(w, r, h.logger, "update fleet", err)
+		return
 	}
-	respondJSON(w, http.StatusOK, resp)
+	setETag(w, entity.Version)
+	respondJSON(w, r, http.StatusOK, fleetToResponse(entity))
 }
 
 func (h *FleetHandler) delete(w http.ResponseWriter, r *http.Request) {
+	cascade, ok := parseCascade(w, r)
+	if !ok {
+		return
+	}
 	id := chi.URLParam(r, "id")
-	if err := h.svc.Delete(r.Context(), id); err != nil {
-		h.handleError(w, "delete fleet", err)
+	opts := ports.DeleteOptions{Cascade: cascade, Reason: r.URL.Query().Get("reason")}
+	if err := h.svc.Delete(r.Context(), id, opts); err != nil {
+		problem.Error(w, r, h.logger, "delete fleet", err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
 func (h *FleetHandler) undelete(w http.ResponseWriter, r *http.Request) {
+	cascade, ok := parseCascade(w, r)
+	if !ok {
+		return
+	}
 	id := chi.URLParam(r, "id")
-	if err := h.svc.Undelete(r.Context(), id); err != nil {
-		h.handleError(w, "undelete fleet", err)
+	if err := h.svc.Undelete(r.Context(), id, ports.UndeleteOptions{Cascade: cascade}); err != nil {
+		problem.Error(w, r, h.logger, "undelete fleet", err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *FleetHandler) handleError(w http.ResponseWriter, op string, err error) {
-	if domain.IsExposable(err) {
-		http.Error(w, err.Error(), mapDomainErrorToStatus(err))
-		return
+func fleetToResponse(e *domain.Fleet) fleetResponse {
+	return fleetResponse{
+		ID:            e.ID,
+		LegalEntityID: e.LegalEntityID,
+		Name:          e.Name,
+		Version:       e.Version,
+		CreatedAt:     e.CreatedAt.Format(time.RFC3339),
+		CreatedBy:     e.CreatedBy,
+		UpdatedAt:     e.UpdatedAt.Format(time.RFC3339),
+		UpdatedBy:     e.UpdatedBy,
 	}
-	h.logger.Error("fleet operation failed", zap.String("op", op), zap.Error(err))
-	http.Error(w, "internal server error", http.StatusInternalServerError)
 }
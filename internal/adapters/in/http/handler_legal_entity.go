@@ -2,10 +2,12 @@ package http
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http/problem"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 )
@@ -24,6 +26,7 @@ func (h *LegalEntityHandler) RegisterRoutes(r chi.Router) {
 		r.Get("/", h.list)
 		r.Post("/", h.create)
 		r.Get("/{id}", h.get)
+		r.Patch("/{id}", h.update)
 		r.Delete("/{id}", h.delete)
 		r.Post("/{id}/undelete", h.undelete)
 	})
@@ -34,10 +37,20 @@ type createLegalEntityRequest struct {
 	TaxID string `json:"tax_id"`
 }
 
+type updateLegalEntityRequest struct {
+	Name  *string `json:"name"`
+	TaxID *string `json:"tax_id"`
+}
+
 type legalEntityResponse struct {
-	ID    string  `json:"id"`
-	Name  string  `json:"name"`
-	TaxID string  `json:"tax_id"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	TaxID     string `json:"tax_id"`
+	Version   int64  `json:"version"`
+	CreatedAt string `json:"created_at"`
+	CreatedBy string `json:"created_by"`
+	UpdatedAt string `json:"updated_at"`
+	UpdatedBy string `json:"updated_by"`
 }
 
 func (h *LegalEntityHandler) create(w http.ResponseWriter, r *http.Request) {
@@ -50,59 +63,95 @@ func (h *LegalEntityHandler) create(w http.ResponseWriter, r *http.Request) {
 	}
 	entity, err := h.svc.Create(r.Context(), req.Name, req.TaxID)
 	if err != nil {
-		h.handleError(w, "create legal entity", err)
+		problem.Error(w, r, h.logger, "create legal entity", err)
 		return
 	}
-	respondJSON(w, http.StatusCreated, legalEntityResponse{ID: entity.ID, Name: entity.Name, TaxID: entity.TaxID})
+	setETag(w, entity.Version)
+	respondJSON(w, r, http.StatusCreated, legalEntityToResponse(entity))
 }
 
 func (h *LegalEntityHandler) get(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	entity, err := h.svc.Get(r.Context(), id)
 	if err != nil {
-		h.handleError(w, "get legal entity", err)
+		problem.Error(w, r, h.logger, "get legal entity", err)
 		return
 	}
-	respondJSON(w, http.StatusOK, legalEntityResponse{ID: entity.ID, Name: entity.Name, TaxID: entity.TaxID})
+	setETag(w, entity.Version)
+	respondJSON(w, r, http.StatusOK, legalEntityToResponse(entity))
 }
 
 func (h *LegalEntityHandler) list(w http.ResponseWriter, r *http.Request) {
-	entities, err := h.svc.List(r.Context())
+	q, ok := parseListQuery(w, r)
+	if !ok {
+		return
+	}
+	page, err := h.svc.List(r.Context(), q)
 	if err != nil {
-		h.handleError(w, "list legal entities", err)
+		problem.Error(w, r, h.logger, "list legal entities", err)
+		return
+	}
+	respondJSON(w, r, http.StatusOK, newListResponse(page, legalEntityToResponse))
+}
+
+func (h *LegalEntityHandler) update(w http.ResponseWriter, r *http.Request) {
+	if !requireJSON(w, r) {
+		return
+	}
+	version, ok := parseIfMatch(w, r)
+	if !ok {
 		return
 	}
-	resp := make([]legalEntityResponse, 0, len(entities))
-	for _, e := range entities {
-		resp = append(resp, legalEntityResponse{ID: e.ID, Name: e.Name, TaxID: e.TaxID})
+	var req updateLegalEntityRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	id := chi.URLParam(r, "id")
+	entity, err := h.svc.Update(r.Context(), id, version, domain.LegalEntityPatch{Name: req.Name, TaxID: req.TaxID})
+	if err != nil {
+		problem.Error(w, r, h.logger, "update legal entity", err)
+		return
 	}
-	respondJSON(w, http.StatusOK, resp)
+	setETag(w, entity.Version)
+	respondJSON(w, r, http.StatusOK, legalEntityToResponse(entity))
 }
 
 func (h *LegalEntityHandler) delete(w http.ResponseWriter, r *http.Request) {
+	cascade, ok := parseCascade(w, r)
+	if !ok {
+		return
+	}
 	id := chi.URLParam(r, "id")
-	if err := h.svc.Delete(r.Context(), id); err != nil {
-		h.handleError(w, "delete legal entity", err)
+	opts := ports.DeleteOptions{Cascade: cascade, Reason: r.URL.Query().Get("reason")}
+	if err := h.svc.Delete(r.Context(), id, opts); err != nil {
+		problem.Error(w, r, h.logger, "delete legal entity", err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
 func (h *LegalEntityHandler) undelete(w http.ResponseWriter, r *http.Request) {
+	cascade, ok := parseCascade(w, r)
+	if !ok {
+		return
+	}
 	id := chi.URLParam(r, "id")
-	if err := h.svc.Undelete(r.Context(), id); err != nil {
-		h.handleError(w, "undelete legal entity", err)
+	if err := h.svc.Undelete(r.Context(), id, ports.UndeleteOptions{Cascade: cascade}); err != nil {
+		problem.Error(w, r, h.logger, "undelete legal entity", err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *LegalEntityHandler) handleError(w http.ResponseWriter, op string, err error) {
-	if domain.IsExposable(err) {
-		status := mapDomainErrorToStatus(err)
-		http.Error(w, err.Error(), status)
-		return
+func legalEntityToResponse(e *domain.LegalEntity) legalEntityResponse {
+	return legalEntityResponse{
+		ID:        e.ID,
+		Name:      e.Name,
+		TaxID:     e.TaxID,
+		Version:   e.Version,
+		CreatedAt: e.CreatedAt.Format(time.RFC3339),
+		CreatedBy: e.CreatedBy,
+		UpdatedAt: e.UpdatedAt.Format(time.RFC3339),
+		UpdatedBy: e.UpdatedBy,
 	}
-	h.logger.Error("legal entity operation failed", zap.String("op", op), zap.Error(err))
-	http.Error(w, "internal server error", http.StatusInternalServerError)
 }
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
@@ -15,6 +16,7 @@ import (
 
 	httpAdapter "github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
 )
 
@@ -40,7 +42,7 @@ func TestLegalEntityHandler_Create_Success(t *testing.T) {
 	router.ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusCreated, rec.Code)
-	var resp map[string]string
+	var resp map[string]any
 	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
 	assert.Equal(t, "1", resp["id"])
 	assert.Equal(t, "Acme", resp["name"])
@@ -72,11 +74,38 @@ func TestLegalEntityHandler_Get_Success(t *testing.T) {
 	router.ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusOK, rec.Code)
-	var resp map[string]string
+	var resp map[string]any
 	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
 	assert.Equal(t, "1", resp["id"])
 }
 
+func TestLegalEntityHandler_Get_ExposesAuditFields(t *testing.T) {
+	mockSvc, router := setupLegalEntityHandler(t)
+
+	entity := &domain.LegalEntity{
+		ID:        "1",
+		Name:      "Acme",
+		TaxID:     "123",
+		CreatedAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		CreatedBy: "alice",
+		UpdatedAt: time.Date(2025, 6, 2, 8, 30, 0, 0, time.UTC),
+		UpdatedBy: "bob",
+	}
+	mockSvc.EXPECT().Get(gomock.Any(), "1").Return(entity, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/legal-entities/1", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "2025-06-01T12:00:00Z", resp["created_at"])
+	assert.Equal(t, "alice", resp["created_by"])
+	assert.Equal(t, "2025-06-02T08:30:00Z", resp["updated_at"])
+	assert.Equal(t, "bob", resp["updated_by"])
+}
+
 func TestLegalEntityHandler_Get_NotFound(t *testing.T) {
 	mockSvc, router := setupLegalEntityHandler(t)
 
@@ -92,7 +121,7 @@ func TestLegalEntityHandler_Get_NotFound(t *testing.T) {
 func TestLegalEntityHandler_Delete_Success(t *testing.T) {
 	mockSvc, router := setupLegalEntityHandler(t)
 
-	mockSvc.EXPECT().Delete(gomock.Any(), "1").Return(nil)
+	mockSvc.EXPECT().Delete(gomock.Any(), "1", ports.DeleteOptions{}).Return(nil)
 
 	req := httptest.NewRequest(http.MethodDelete, "/legal-entities/1", nil)
 	rec := httptest.NewRecorder()
@@ -100,3 +129,164 @@ func TestLegalEntityHandler_Delete_Success(t *testing.T) {
 
 	assert.Equal(t, http.StatusNoContent, rec.Code)
 }
+
+func TestLegalEntityHandler_Delete_WithReason(t *testing.T) {
+	mockSvc, router := setupLegalEntityHandler(t)
+
+	mockSvc.EXPECT().Delete(gomock.Any(), "1", ports.DeleteOptions{Reason: "duplicate record"}).Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/legal-entities/1?reason=duplicate+record", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestLegalEntityHandler_List_Paginated(t *testing.T) {
+	mockSvc, router := setupLegalEntityHandler(t)
+
+	want := ports.ListQuery{
+		Limit:   2,
+		Cursor:  "c1",
+		SortBy:  "name",
+		SortDir: ports.SortDesc,
+		Filters: map[string]string{"tax_id": "123"},
+	}
+	page := &ports.Page[*domain.LegalEntity]{
+		Items:      []*domain.LegalEntity{{ID: "1", Name: "Acme", TaxID: "123"}},
+		NextCursor: "c2",
+	}
+	mockSvc.EXPECT().List(gomock.Any(), want).Return(page, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/legal-entities?limit=2&cursor=c1&sort=name&order=desc&tax_id=123", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp struct {
+		Items      []map[string]any `json:"items"`
+		NextCursor string           `json:"next_cursor"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, "1", resp.Items[0]["id"])
+	assert.Equal(t, "c2", resp.NextCursor)
+}
+
+func TestLegalEntityHandler_List_InvalidLimit(t *testing.T) {
+	_, router := setupLegalEntityHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/legal-entities?limit=abc", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestLegalEntityHandler_Update_Success(t *testing.T) {
+	mockSvc, router := setupLegalEntityHandler(t)
+
+	name := "Acme Corp"
+	entity := &domain.LegalEntity{ID: "1", Name: name, TaxID: "123", Version: 4}
+	mockSvc.EXPECT().
+		Update(gomock.Any(), "1", int64(3), domain.LegalEntityPatch{Name: &name}).
+		Return(entity, nil)
+
+	body, _ := json.Marshal(map[string]string{"name": name})
+	req := httptest.NewRequest(http.MethodPatch, "/legal-entities/1", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"3"`)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"4"`, rec.Header().Get("ETag"))
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, name, resp["name"])
+	assert.InDelta(t, 4, resp["version"], 0)
+}
+
+func TestLegalEntityHandler_Update_PreconditionFailed(t *testing.T) {
+	mockSvc, router := setupLegalEntityHandler(t)
+
+	mockSvc.EXPECT().
+		Update(gomock.Any(), "1", int64(3), gomock.Any()).
+		Return(nil, domain.ErrPreconditionFailed)
+
+	body, _ := json.Marshal(map[string]string{"name": "Acme Corp"})
+	req := httptest.NewRequest(http.MethodPatch, "/legal-entities/1", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"3"`)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+}
+
+func TestLegalEntityHandler_Update_WeakIfMatch(t *testing.T) {
+	_, router := setupLegalEntityHandler(t)
+
+	body, _ := json.Marshal(map[string]string{"name": "Acme Corp"})
+	req := httptest.NewRequest(http.MethodPatch, "/legal-entities/1", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `W/"3"`)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	var resp struct {
+		Code string `json:"code"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "precondition_failed", resp.Code)
+}
+
+func TestLegalEntityHandler_Update_InvalidIfMatch(t *testing.T) {
+	_, router := setupLegalEntityHandler(t)
+
+	body, _ := json.Marshal(map[string]string{"name": "Acme Corp"})
+	req := httptest.NewRequest(http.MethodPatch, "/legal-entities/1", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", "abc")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestLegalEntityHandler_Delete_Cascade(t *testing.T) {
+	mockSvc, router := setupLegalEntityHandler(t)
+
+	mockSvc.EXPECT().Delete(gomock.Any(), "1", ports.DeleteOptions{Cascade: true}).Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/legal-entities/1?cascade=true", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestLegalEntityHandler_Delete_RejectsWhenFleetsExist(t *testing.T) {
+	mockSvc, router := setupLegalEntityHandler(t)
+
+	mockSvc.EXPECT().Delete(gomock.Any(), "1", ports.DeleteOptions{}).Return(domain.ErrLegalEntityHasFleets)
+
+	req := httptest.NewRequest(http.MethodDelete, "/legal-entities/1", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestLegalEntityHandler_Undelete_Cascade(t *testing.T) {
+	mockSvc, router := setupLegalEntityHandler(t)
+
+	mockSvc.EXPECT().Undelete(gomock.Any(), "1", ports.UndeleteOptions{Cascade: true}).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/legal-entities/1/undelete?cascade=true", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
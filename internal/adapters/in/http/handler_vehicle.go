@@ -2,10 +2,12 @@ package http
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http/problem"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 )
@@ -26,6 +28,7 @@ func (h *VehicleHandler) RegisterRoutes(r chi.Router) {
 	})
 	r.Route("/vehicles", func(r chi.Router) {
 		r.Get("/{id}", h.get)
+		r.Patch("/{id}", h.update)
 		r.Delete("/{id}", h.delete)
 		r.Post("/{id}/undelete", h.undelete)
 	})
@@ -38,6 +41,13 @@ type createVehicleRequest struct {
 	LicensePlate string `json:"license_plate"`
 }
 
+type updateVehicleRequest struct {
+	Make         *string `json:"make"`
+	Model        *string `json:"model"`
+	Year         *int    `json:"year"`
+	LicensePlate *string `json:"license_plate"`
+}
+
 type vehicleResponse struct {
 	ID           string `json:"id"`
 	FleetID      string `json:"fleet_id"`
@@ -45,6 +55,11 @@ type vehicleResponse struct {
 	Model        string `json:"model"`
 	Year         int    `json:"year"`
 	LicensePlate string `json:"license_plate"`
+	Version      int64  `json:"version"`
+	CreatedAt    string `json:"created_at"`
+	CreatedBy    string `json:"created_by"`
+	UpdatedAt    string `json:"updated_at"`
+	UpdatedBy    string `json:"updated_by"`
 }
 
 func (h *VehicleHandler) create(w http.ResponseWriter, r *http.Request) {
@@ -58,59 +73,104 @@ func (h *VehicleHandler) create(w http.ResponseWriter, r *http.Request) {
 	fleetID := chi.URLParam(r, "fleetId")
 	entity, err := h.svc.Create(r.Context(), fleetID, req.Make, req.Model, req.LicensePlate, req.Year)
 	if err != nil {
-		h.handleError(w, "create vehicle", err)
+		problem.Error(w, r, h.logger, "create vehicle", err)
 		return
 	}
-	respondJSON(w, http.StatusCreated, vehicleResponse{ID: entity.ID, FleetID: entity.FleetID, Make: entity.Make, Model: entity.Model, Year: entity.Year, LicensePlate: entity.LicensePlate})
+	setETag(w, entity.Version)
+	respondJSON(w, r, http.StatusCreated, vehicleToResponse(entity))
 }
 
 func (h *VehicleHandler) get(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	entity, err := h.svc.Get(r.Context(), id)
 	if err != nil {
-		h.handleError(w, "get vehicle", err)
+		problem.Error(w, r, h.logger, "get vehicle", err)
 		return
 	}
-	respondJSON(w, http.StatusOK, vehicleResponse{ID: entity.ID, FleetID: entity.FleetID, Make: entity.Make, Model: entity.Model, Year: entity.Year, LicensePlate: entity.LicensePlate})
+	setETag(w, entity.Version)
+	respondJSON(w, r, http.StatusOK, vehicleToResponse(entity))
 }
 
 func (h *VehicleHandler) listByFleet(w http.ResponseWriter, r *http.Request) {
 	fleetID := chi.URLParam(r, "fleetId")
-	entities, err := h.svc.ListByFleet(r.Context(), fleetID)
+	q, ok := parseListQuery(w, r)
+	if !ok {
+		return
+	}
+	page, err := h.svc.ListByFleet(r.Context(), fleetID, q)
 	if err != nil {
-		h.handleError(w, "list vehicles", err)
+		problem.Error(w, r, h.logger, "list vehicles", err)
+		return
+	}
+	respondJSON(w, r, http.StatusOK, newListResponse(page, vehicleToResponse))
+}
+
+func (h *VehicleHandler) update(w http.ResponseWriter, r *http.Request) {
+	if !requireJSON(w, r) {
 		return
 	}
-	resp := make([]vehicleResponse, 0, len(entities))
-	for _, e := range entities {
-		resp = append(resp, vehicleResponse{ID: e.ID, FleetID: e.FleetID, Make: e.Make, Model: e.Model, Year: e.Year, LicensePlate: e.LicensePlate})
+	version, ok := parseIfMatch(w, r)
+	if !ok {
+		return
 	}
-	respondJSON(w, http.StatusOK, resp)
+	var req updateVehicleRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	id := chi.URLParam(r, "id")
+	entity, err := h.svc.Update(r.Context(), id, version, domain.VehiclePatch{
+		Make:         req.Make,
+		Model:        req.Model,
+		Year:         req.Year,
+		LicensePlate: req.LicensePlate,
+	})
+	if err != nil {
+		problem.Error(w, r, h.logger, "update vehicle", err)
+		return
+	}
+	setETag(w, entity.Version)
+	respondJSON(w, r, http.StatusOK, vehicleToResponse(entity))
 }
 
 func (h *VehicleHandler) delete(w http.ResponseWriter, r *http.Request) {
+	cascade, ok := parseCascade(w, r)
+	if !ok {
+		return
+	}
 	id := chi.URLParam(r, "id")
-	if err := h.svc.Delete(r.Context(), id); err != nil {
-		h.handleError(w, "delete vehicle", err)
+	opts := ports.DeleteOptions{Cascade: cascade, Reason: r.URL.Query().Get("reason")}
+	if err := h.svc.Delete(r.Context(), id, opts); err != nil {
+		problem.Error(w, r, h.logger, "delete vehicle", err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
 func (h *VehicleHandler) undelete(w http.ResponseWriter, r *http.Request) {
+	cascade, ok := parseCascade(w, r)
+	if !ok {
+		return
+	}
 	id := chi.URLParam(r, "id")
-	if err := h.svc.Undelete(r.Context(), id); err != nil {
-		h.handleError(w, "undelete vehicle", err)
+	if err := h.svc.Undelete(r.Context(), id, ports.UndeleteOptions{Cascade: cascade}); err != nil {
+		problem.Error(w, r, h.logger, "undelete vehicle", err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *VehicleHandler) handleError(w http.ResponseWriter, op string, err error) {
-	if domain.IsExposable(err) {
-		http.Error(w, err.Error(), mapDomainErrorToStatus(err))
-		return
+func vehicleToResponse(e *domain.Vehicle) vehicleResponse {
+	return vehicleResponse{
+		ID:           e.ID,
+		FleetID:      e.FleetID,
+		Make:         e.Make,
+		Model:        e.Model,
+		Year:         e.Year,
+		LicensePlate: e.LicensePlate,
+		Version:      e.Version,
+		CreatedAt:    e.CreatedAt.Format(time.RFC3339),
+		CreatedBy:    e.CreatedBy,
+		UpdatedAt:    e.UpdatedAt.Format(time.RFC3339),
+		UpdatedBy:    e.UpdatedBy,
 	}
-	h.logger.Error("vehicle operation failed", zap.String("op", op), zap.Error(err))
-	http.Error(w, "internal server error", http.StatusInternalServerError)
 }
@@ -0,0 +1,232 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http/problem"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+)
+
+type WebhookHandler struct {
+	svc    ports.WebhookService
+	logger *zap.Logger
+}
+
+func NewWebhookHandler(svc ports.WebhookService, logger *zap.Logger) *WebhookHandler {
+	return &WebhookHandler{svc: svc, logger: logger}
+}
+
+func (h *WebhookHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/webhooks", func(r chi.Router) {
+		r.Get("/", h.list)
+		r.Post("/", h.create)
+		r.Get("/{id}", h.get)
+		r.Patch("/{id}", h.update)
+		r.Delete("/{id}", h.delete)
+		r.Get("/{id}/deliveries", h.listDeliveries)
+		r.Post("/{id}/deliveries/{deliveryID}/replay", h.replay)
+	})
+}
+
+type createWebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret"`
+}
+
+type updateWebhookRequest struct {
+	URL        *string  `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Secret     *string  `json:"secret"`
+	Active     *bool    `json:"active"`
+}
+
+// webhookResponse never includes the secret; it is only ever sent by the client.
+type webhookResponse struct {
+	ID                  string   `json:"id"`
+	URL                 string   `json:"url"`
+	EventTypes          []string `json:"event_types"`
+	Active              bool     `json:"active"`
+	ConsecutiveFailures int      `json:"consecutive_failures"`
+	DisabledAt          *string  `json:"disabled_at,omitempty"`
+	Version             int64    `json:"version"`
+	CreatedAt           string   `json:"created_at"`
+	CreatedBy           string   `json:"created_by"`
+	UpdatedAt           string   `json:"updated_at"`
+	UpdatedBy           string   `json:"updated_by"`
+}
+
+type webhookDeliveryResponse struct {
+	ID             string  `json:"id"`
+	SubscriptionID string  `json:"subscription_id"`
+	EventID        string  `json:"event_id"`
+	EventType      string  `json:"event_type"`
+	Status         string  `json:"status"`
+	Attempts       int     `json:"attempts"`
+	LastStatusCode int     `json:"last_status_code,omitempty"`
+	LastError      string  `json:"last_error,omitempty"`
+	NextAttemptAt  string  `json:"next_attempt_at"`
+	CreatedAt      string  `json:"created_at"`
+	DeliveredAt    *string `json:"delivered_at,omitempty"`
+}
+
+func (h *WebhookHandler) create(w http.ResponseWriter, r *http.Request) {
+	if !requireJSON(w, r) {
+		return
+	}
+	var req createWebhookRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	sub, err := h.svc.Create(r.Context(), req.URL, toEventTypes(req.EventTypes), req.Secret)
+	if err != nil {
+		problem.Error(w, r, h.logger, "create webhook", err)
+		return
+	}
+	setETag(w, sub.Version)
+	respondJSON(w, r, http.StatusCreated, webhookToResponse(sub))
+}
+
+func (h *WebhookHandler) get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	sub, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		problem.Error(w, r, h.logger, "get webhook", err)
+		return
+	}
+	setETag(w, sub.Version)
+	respondJSON(w, r, http.StatusOK, webhookToResponse(sub))
+}
+
+func (h *WebhookHandler) list(w http.ResponseWriter, r *http.Request) {
+	q, ok := parseListQuery(w, r)
+	if !ok {
+		return
+	}
+	page, err := h.svc.List(r.Context(), q)
+	if err != nil {
+		problem.Error(w, r, h.logger, "list webhooks", err)
+		return
+	}
+	respondJSON(w, r, http.StatusOK, newListResponse(page, webhookToResponse))
+}
+
+func (h *WebhookHandler) update(w http.ResponseWriter, r *http.Request) {
+	if !requireJSON(w, r) {
+		return
+	}
+	version, ok := parseIfMatch(w, r)
+	if !ok {
+		return
+	}
+	var req updateWebhookRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	id := chi.URLParam(r, "id")
+	sub, err := h.svc.Update(r.Context(), id, version, domain.WebhookSubscriptionPatch{
+		URL:        req.URL,
+		EventTypes: toEventTypes(req.EventTypes),
+		Secret:     req.Secret,
+		Active:     req.Active,
+	})
+	if err != nil {
+		problem.Error(w, r, h.logger, "update webhook", err)
+		return
+	}
+	setETag(w, sub.Version)
+	respondJSON(w, r, http.StatusOK, webhookToResponse(sub))
+}
+
+func (h *WebhookHandler) delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		problem.Error(w, r, h.logger, "delete webhook", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) listDeliveries(w http.ResponseWriter, r *http.Request) {
+	q, ok := parseListQuery(w, r)
+	if !ok {
+		return
+	}
+	page, err := h.svc.ListDeliveries(r.Context(), chi.URLParam(r, "id"), q)
+	if err != nil {
+		problem.Error(w, r, h.logger, "list webhook deliveries", err)
+		return
+	}
+	respondJSON(w, r, http.StatusOK, newListResponse(page, webhookDeliveryToResponse))
+}
+
+func (h *WebhookHandler) replay(w http.ResponseWriter, r *http.Request) {
+	d, err := h.svc.Replay(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "deliveryID"))
+	if err != nil {
+		problem.Error(w, r, h.logger, "replay webhook delivery", err)
+		return
+	}
+	respondJSON(w, r, http.StatusAccepted, webhookDeliveryToResponse(d))
+}
+
+// toEventTypes keeps a missing list nil, so an update leaves the event types unchanged.
+func toEventTypes(types []string) []domain.EventType {
+	if types == nil {
+		return nil
+	}
+	out := make([]domain.EventType, len(types))
+	for i, t := range types {
+		out[i] = domain.EventType(t)
+	}
+	return out
+}
+
+func webhookToResponse(s *domain.WebhookSubscription) webhookResponse {
+	eventTypes := make([]string, len(s.EventTypes))
+	for i, t := range s.EventTypes {
+		eventTypes[i] = string(t)
+	}
+	return webhookResponse{
+		ID:                  s.ID,
+		URL:                 s.URL,
+		EventTypes:          eventTypes,
+		Active:              s.Active,
+		ConsecutiveFailures: s.ConsecutiveFailures,
+		DisabledAt:          formatTime(s.DisabledAt),
+		Version:             s.Version,
+		CreatedAt:           s.CreatedAt.Format(time.RFC3339),
+		CreatedBy:           s.CreatedBy,
+		UpdatedAt:           s.UpdatedAt.Format(time.RFC3339),
+		UpdatedBy:           s.UpdatedBy,
+	}
+}
+
+func webhookDeliveryToResponse(d *domain.WebhookDelivery) webhookDeliveryResponse {
+	return webhookDeliveryResponse{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.Event.ID,
+		EventType:      string(d.Event.Type),
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		NextAttemptAt:  d.NextAttemptAt.Format(time.RFC3339),
+		CreatedAt:      d.CreatedAt.Format(time.RFC3339),
+		DeliveredAt:    formatTime(d.DeliveredAt),
+	}
+}
+
+// formatTime renders an optional timestamp as RFC 3339, keeping nil for omitempty.
+func formatTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format(time.RFC3339)
+	return &s
+}
@@ -0,0 +1,96 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+
+	httpAdapter "github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
+)
+
+func setupWebhookHandler(t *testing.T) (*mocks.MockWebhookService, chi.Router) {
+	ctrl := gomock.NewController(t)
+	mockSvc := mocks.NewMockWebhookService(ctrl)
+	handler := httpAdapter.NewWebhookHandler(mockSvc, zaptest.NewLogger(t))
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+	return mockSvc, r
+}
+
+func TestWebhookHandler_Create_OmitsSecret(t *testing.T) {
+	mockSvc, router := setupWebhookHandler(t)
+
+	mockSvc.EXPECT().
+		Create(gomock.Any(), "https://partner.example/hook", []domain.EventType{domain.EventContractTerminated}, "0123456789abcdef").
+		Return(&domain.WebhookSubscription{
+			ID:         "w1",
+			URL:        "https://partner.example/hook",
+			EventTypes: []domain.EventType{domain.EventContractTerminated},
+			Secret:     "0123456789abcdef",
+			Active:     true,
+			Version:    1,
+		}, nil)
+
+	body := `{"url":"https://partner.example/hook","event_types":["contract.terminated"],"secret":"0123456789abcdef"}`
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, `"1"`, rec.Header().Get("ETag"))
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "w1", resp["id"])
+	assert.Equal(t, []any{"contract.terminated"}, resp["event_types"])
+	assert.Equal(t, true, resp["active"])
+	assert.NotContains(t, resp, "secret")
+	assert.NotContains(t, rec.Body.String(), "0123456789abcdef")
+}
+
+func TestWebhookHandler_Replay(t *testing.T) {
+	mockSvc, router := setupWebhookHandler(t)
+
+	mockSvc.EXPECT().Replay(gomock.Any(), "w1", "d1").Return(&domain.WebhookDelivery{
+		ID:             "d1",
+		SubscriptionID: "w1",
+		Event:          domain.Event{ID: "e1", Type: domain.EventAssignmentReturned},
+		Status:         domain.WebhookDeliveryPending,
+		NextAttemptAt:  time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/w1/deliveries/d1/replay", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "pending", resp["status"])
+	assert.Equal(t, "e1", resp["event_id"])
+	assert.Equal(t, "assignment.returned", resp["event_type"])
+	assert.Equal(t, "2025-06-01T12:00:00Z", resp["next_attempt_at"])
+}
+
+func TestWebhookHandler_Replay_Conflict(t *testing.T) {
+	mockSvc, router := setupWebhookHandler(t)
+
+	mockSvc.EXPECT().Replay(gomock.Any(), "w1", "d1").Return(nil, domain.ErrConflict)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/w1/deliveries/d1/replay", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
@@ -0,0 +1,150 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http/problem"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client-chosen key that makes a POST request safe
+	// to retry.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a response replayed from an earlier request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// replayedHeaders are the response headers stored for replay; the others describe the
+// connection or the moment of the response rather than its content.
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// IdempotencyMiddleware makes POST requests with an Idempotency-Key header safe to retry.
+// The first response to a key is stored and sent again to retries of the same request.
+// Reusing a key for a request with another method, path or body fails with 422, and
+// retrying while the first request is still processed fails with 409. Server errors are
+// not stored, so that a retry may succeed.
+type IdempotencyMiddleware struct {
+	svc    ports.IdempotencyService
+	logger *zap.Logger
+}
+
+func NewIdempotencyMiddleware(svc ports.IdempotencyService, logger *zap.Logger) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{svc: svc, logger: logger}
+}
+
+func (m *IdempotencyMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if r.Method != http.MethodPost || key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			problem.Write(w, r, decodeProblem(err))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		stored, err := m.svc.Begin(r.Context(), key, fingerprint(r, body))
+		if err != nil {
+			problem.Error(w, r, m.logger, "begin", err)
+			return
+		}
+		if stored != nil {
+			replay(w, stored)
+			return
+		}
+
+		// The outcome is recorded even when the client is gone: it is the retry that
+		// needs it.
+		ctx := context.WithoutCancel(r.Context())
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			if err := m.svc.Release(ctx, key); err != nil {
+				m.logger.Error("Failed to release idempotency key", zap.Error(err))
+			}
+		}()
+		next.ServeHTTP(rec, r)
+		if rec.status >= http.StatusInternalServerError {
+			return
+		}
+		err = m.svc.Complete(ctx, key, &domain.IdempotentResponse{
+			StatusCode: rec.status,
+			Header:     storedHeader(w.Header()),
+			Body:       rec.body.Bytes(),
+		})
+		if err != nil {
+			m.logger.Error("Failed to store idempotent response", zap.Error(err))
+			return
+		}
+		completed = true
+	})
+}
+
+// fingerprint identifies a request by its method, path and body.
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	_, _ = io.WriteString(h, r.Method+" "+r.URL.Path+"\n")
+	_, _ = h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func storedHeader(h http.Header) map[string][]string {
+	stored := make(map[string][]string)
+	for _, name := range replayedHeaders {
+		if v := h.Values(name); len(v) > 0 {
+			stored[name] = v
+		}
+	}
+	return stored
+}
+
+func replay(w http.ResponseWriter, resp *domain.IdempotentResponse) {
+	for name, values := range resp.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(IdempotentReplayedHeader, strconv.FormatBool(true))
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(resp.Body)
+}
+
+// responseRecorder passes a response through while keeping a copy of it.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = status, true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,129 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+
+	httpAdapter "github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
+)
+
+// createdHandler answers like a create endpoint and counts its calls.
+func createdHandler(calls *int, status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"1"`)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"id":"d1"}`))
+	})
+}
+
+func postDriver(key, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/drivers", strings.NewReader(body))
+	req.Header.Set(httpAdapter.IdempotencyKeyHeader, key)
+	return req
+}
+
+func TestIdempotencyMiddleware_StoresFirstResponse(t *testing.T) {
+	svc := mocks.NewMockIdempotencyService(gomock.NewController(t))
+	svc.EXPECT().Begin(gomock.Any(), "k1", gomock.Any()).Return(nil, nil)
+	svc.EXPECT().Complete(gomock.Any(), "k1", &domain.IdempotentResponse{
+		StatusCode: http.StatusCreated,
+		Header:     map[string][]string{"Content-Type": {"application/json"}, "ETag": {`"1"`}},
+		Body:       []byte(`{"id":"d1"}`),
+	}).Return(nil)
+
+	var calls int
+	rec := httptest.NewRecorder()
+	httpAdapter.NewIdempotencyMiddleware(svc, zaptest.NewLogger(t)).
+		Handler(createdHandler(&calls, http.StatusCreated)).ServeHTTP(rec, postDriver("k1", `{}`))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotencyMiddleware_ReplaysStoredResponse(t *testing.T) {
+	svc := mocks.NewMockIdempotencyService(gomock.NewController(t))
+	svc.EXPECT().Begin(gomock.Any(), "k1", gomock.Any()).Return(&domain.IdempotentResponse{
+		StatusCode: http.StatusCreated,
+		Header:     map[string][]string{"Content-Type": {"application/json"}},
+		Body:       []byte(`{"id":"d1"}`),
+	}, nil)
+
+	var calls int
+	rec := httptest.NewRecorder()
+	httpAdapter.NewIdempotencyMiddleware(svc, zaptest.NewLogger(t)).
+		Handler(createdHandler(&calls, http.StatusCreated)).ServeHTTP(rec, postDriver("k1", `{}`))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, `{"id":"d1"}`, rec.Body.String())
+	assert.Equal(t, "true", rec.Header().Get(httpAdapter.IdempotentReplayedHeader))
+	assert.Zero(t, calls)
+}
+
+func TestIdempotencyMiddleware_FingerprintsRequest(t *testing.T) {
+	svc := mocks.NewMockIdempotencyService(gomock.NewController(t))
+	var fingerprints []string
+	svc.EXPECT().Begin(gomock.Any(), "k1", gomock.Any()).
+		DoAndReturn(func(_ any, _, fp string) (*domain.IdempotentResponse, error) {
+			fingerprints = append(fingerprints, fp)
+			return nil, domain.ErrIdempotencyKeyReused
+		}).Times(3)
+
+	m := httpAdapter.NewIdempotencyMiddleware(svc, zaptest.NewLogger(t))
+	var calls int
+	for _, req := range []*http.Request{postDriver("k1", `{"a":1}`), postDriver("k1", `{"a":1}`), postDriver("k1", `{"a":2}`)} {
+		rec := httptest.NewRecorder()
+		m.Handler(createdHandler(&calls, http.StatusCreated)).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	}
+	assert.Equal(t, fingerprints[0], fingerprints[1])
+	assert.NotEqual(t, fingerprints[0], fingerprints[2])
+	assert.Zero(t, calls)
+}
+
+func TestIdempotencyMiddleware_RejectsRetryInProgress(t *testing.T) {
+	svc := mocks.NewMockIdempotencyService(gomock.NewController(t))
+	svc.EXPECT().Begin(gomock.Any(), "k1", gomock.Any()).Return(nil, domain.ErrConflict)
+
+	var calls int
+	rec := httptest.NewRecorder()
+	httpAdapter.NewIdempotencyMiddleware(svc, zaptest.NewLogger(t)).
+		Handler(createdHandler(&calls, http.StatusCreated)).ServeHTTP(rec, postDriver("k1", `{}`))
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Zero(t, calls)
+}
+
+func TestIdempotencyMiddleware_ReleasesKeyOnServerError(t *testing.T) {
+	svc := mocks.NewMockIdempotencyService(gomock.NewController(t))
+	svc.EXPECT().Begin(gomock.Any(), "k1", gomock.Any()).Return(nil, nil)
+	svc.EXPECT().Release(gomock.Any(), "k1").Return(nil)
+
+	var calls int
+	rec := httptest.NewRecorder()
+	httpAdapter.NewIdempotencyMiddleware(svc, zaptest.NewLogger(t)).
+		Handler(createdHandler(&calls, http.StatusServiceUnavailable)).ServeHTTP(rec, postDriver("k1", `{}`))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestIdempotencyMiddleware_IgnoresRequestsWithoutKey(t *testing.T) {
+	m := httpAdapter.NewIdempotencyMiddleware(mocks.NewMockIdempotencyService(gomock.NewController(t)), zaptest.NewLogger(t))
+
+	patch := httptest.NewRequest(http.MethodPatch, "/drivers/d1", strings.NewReader(`{}`))
+	patch.Header.Set(httpAdapter.IdempotencyKeyHeader, "k1")
+	var calls int
+	for _, req := range []*http.Request{postDriver("", `{}`), patch} {
+		m.Handler(createdHandler(&calls, http.StatusCreated)).ServeHTTP(httptest.NewRecorder(), req)
+	}
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,133 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http/problem"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/logctx"
+)
+
+const (
+	requestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 128
+)
+
+// probePaths are polled by orchestrators and load balancers; their requests are
+// logged at debug level only.
+var probePaths = map[string]bool{"/health": true, "/livez": true, "/readyz": true}
+
+// requestIDMiddleware takes the request ID from the X-Request-ID header, or generates
+// one when it is missing or unusable, and echoes it in the response. The ID is stored
+// where chi's middleware.GetReqID finds it.
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		w.Header().Set(requestIDHeader, id)
+		ctx := context.WithValue(r.Context(), middleware.RequestIDKey, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// validRequestID accepts IDs of printable ASCII characters, so that a caller cannot
+// forge log lines or response headers through them.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := range len(id) {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// accessLogMiddleware attaches a logger carrying the request ID, and the trace ID when
+// the request is traced, to the request context, and logs one line per request once
+// it has been served. Failed requests are logged at error level.
+func accessLogMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			fields := []zap.Field{zap.String("request_id", middleware.GetReqID(r.Context()))}
+			if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+				fields = append(fields, zap.String("trace_id", sc.TraceID().String()))
+			}
+			reqLogger := logger.With(fields...)
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(logctx.With(r.Context(), reqLogger)))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			route := ""
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				route = rctx.RoutePattern()
+			}
+			level := zapcore.InfoLevel
+			switch {
+			case status >= http.StatusInternalServerError:
+				level = zapcore.ErrorLevel
+			case probePaths[r.URL.Path]:
+				level = zapcore.DebugLevel
+			}
+			reqLogger.Log(level, "HTTP request",
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.String("route", route),
+				zap.Int("status", status),
+				zap.Int("bytes", ww.BytesWritten()),
+				zap.Duration("latency", time.Since(start)),
+				zap.String("remote_addr", r.RemoteAddr),
+				zap.String("user_agent", r.UserAgent()),
+			)
+		})
+	}
+}
+
+// recoverMiddleware turns a panic in a handler into a logged 500 problem response
+// instead of a dropped connection. A handler that already started its response is
+// left to end it as it is, as a problem response can no longer be sent.
+// http.ErrAbortHandler is re-raised, as it is meant to abort the response.
+func recoverMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if err, ok := rec.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(rec)
+				}
+				logctx.From(r.Context(), logger).Error("handler panicked",
+					zap.Any("panic", rec),
+					zap.Bool("response_started", ww.Status() != 0),
+					zap.ByteString("stack", debug.Stack()),
+				)
+				if ww.Status() == 0 {
+					problem.Write(ww, r, problem.New(http.StatusInternalServerError, problem.CodeInternal,
+						"internal server error"))
+				}
+			}()
+			next.ServeHTTP(ww, r)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package http_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/auth"
+	httpAdapter "github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http"
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http/problem"
+	"github.com/albenik/uber-fx-based-service-example/internal/config"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
+)
+
+// panickingRoutes registers routes whose handlers panic, before and after starting the
+// response.
+type panickingRoutes struct{}
+
+func (panickingRoutes) RegisterRoutes(r chi.Router) {
+	r.Get("/boom/{id}", func(http.ResponseWriter, *http.Request) { panic("boom") })
+	r.Get("/boom-late", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("partial"))
+		panic("boom")
+	})
+}
+
+func observedServer(t *testing.T, handlers ...httpAdapter.RouteRegistrar) (http.Handler, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	srv := httpAdapter.NewServer(&config.HTTPServerConfig{Addr: ":8080"}, handlers, auth.Disabled(),
+		noIdempotency(t), noMetrics(t), noProbes(), zap.New(core))
+	return srv.Handler, logs
+}
+
+func TestNewServer_RequestID(t *testing.T) {
+	h, _ := observedServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/livez", nil)
+	req.Header.Set("X-Request-ID", "req-42")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, "req-42", rec.Header().Get("X-Request-ID"))
+
+	for _, id := range []string{"", "line\nbreak", strings.Repeat("x", 129)} {
+		req = httptest.NewRequest(http.MethodGet, "/livez", nil)
+		req.Header.Set("X-Request-ID", id)
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		generated := rec.Header().Get("X-Request-ID")
+		assert.NotEmpty(t, generated)
+		assert.NotEqual(t, id, generated)
+	}
+}
+
+func TestNewServer_RecoversPanics(t *testing.T) {
+	h, logs := observedServer(t, panickingRoutes{})
+
+	req := httptest.NewRequest(http.MethodGet, "/boom/1", nil)
+	req.Header.Set("X-Request-ID", "req-42")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	var body struct {
+		Code      string `json:"code"`
+		RequestID string `json:"request_id"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, problem.CodeInternal, body.Code)
+	assert.Equal(t, "req-42", body.RequestID)
+
+	panics := logs.FilterMessage("handler panicked").All()
+	require.Len(t, panics, 1)
+	assert.Equal(t, "req-42", panics[0].ContextMap()["request_id"])
+	assert.Equal(t, "boom", panics[0].ContextMap()["panic"])
+
+	access := logs.FilterMessage("HTTP request").All()
+	require.Len(t, access, 1)
+	assert.Equal(t, zapcore.ErrorLevel, access[0].Level)
+	fields := access[0].ContextMap()
+	assert.Equal(t, "req-42", fields["request_id"])
+	assert.Equal(t, "/boom/{id}", fields["route"])
+	assert.Equal(t, "/boom/1", fields["path"])
+	assert.EqualValues(t, http.StatusInternalServerError, fields["status"])
+	assert.Contains(t, fields, "latency")
+}
+
+func TestNewServer_RecoversPanicsAfterResponseStarted(t *testing.T) {
+	h, logs := observedServer(t, panickingRoutes{})
+
+	req := httptest.NewRequest(http.MethodGet, "/boom-late", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/plain", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "partial", rec.Body.String())
+
+	panics := logs.FilterMessage("handler panicked").All()
+	require.Len(t, panics, 1)
+	assert.Equal(t, true, panics[0].ContextMap()["response_started"])
+}
+
+func TestNewServer_LogsErrorsWithRequestLogger(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc := mocks.NewMockLegalEntityService(ctrl)
+	svc.EXPECT().Get(gomock.Any(), "le-1").Return(nil, errors.New("connection reset"))
+	// The handler logger is only a fallback; the error is logged by the request logger.
+	h, logs := observedServer(t, httpAdapter.NewLegalEntityHandler(svc, zap.NewNop()))
+
+	req := httptest.NewRequest(http.MethodGet, "/legal-entities/le-1", nil)
+	req.Header.Set("X-Request-ID", "req-42")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	failures := logs.FilterMessage("operation failed").All()
+	require.Len(t, failures, 1)
+	assert.Equal(t, "req-42", failures[0].ContextMap()["request_id"])
+
+	access := logs.FilterMessage("HTTP request").All()
+	require.Len(t, access, 1)
+	assert.Equal(t, "/legal-entities/{id}", access[0].ContextMap()["route"])
+}
+
+func TestNewServer_LogsProbesAtDebugLevel(t *testing.T) {
+	h, logs := observedServer(t)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	access := logs.FilterMessage("HTTP request").All()
+	require.Len(t, access, 1)
+	assert.Equal(t, zapcore.DebugLevel, access[0].Level)
+}
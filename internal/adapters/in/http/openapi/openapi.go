@@ -0,0 +1,35 @@
+// Package openapi serves the OpenAPI description of the HTTP API and a Swagger UI
+// page to browse it. The page loads Swagger UI from the unpkg.com CDN, so browsing it
+// needs access to the internet; the document itself is served by the binary alone.
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Spec is the OpenAPI 3.1 document of every route registered by the HTTP handlers.
+//
+//go:embed openapi.json
+var Spec []byte
+
+//go:embed index.html
+var index []byte
+
+// RegisterRoutes serves the document at /openapi.json and the Swagger UI at /docs.
+// Updating Swagger UI means changing its pinned version in index.html and running
+// make swagger-ui-sri, which pins the integrity hashes the browser checks the assets
+// against.
+func RegisterRoutes(r chi.Router) {
+	r.Get("/openapi.json", serve("application/json", Spec))
+	r.Get("/docs", serve("text/html; charset=utf-8", index))
+}
+
+func serve(contentType string, body []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write(body)
+	}
+}
@@ -0,0 +1,399 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http/openapi"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
+)
+
+// routeShape describes a call of a route: the Go type its request body is decoded
+// into, filled in by fill, and the status it answers with. A nil request means the
+// route takes no body.
+type routeShape struct {
+	request any
+	status  int
+	// schema overrides the location of the response schema, for non-JSON responses.
+	schema string
+}
+
+// routeShapes lists every route of the API. Values of fields the handlers parse are
+// spelled out; every other field is filled in by fill.
+var routeShapes = map[string]routeShape{
+	"GET /legal-entities":                {nil, http.StatusOK, ""},
+	"POST /legal-entities":               {createLegalEntityRequest{}, http.StatusCreated, ""},
+	"GET /legal-entities/{id}":           {nil, http.StatusOK, ""},
+	"PATCH /legal-entities/{id}":         {updateLegalEntityRequest{}, http.StatusOK, ""},
+	"DELETE /legal-entities/{id}":        {nil, http.StatusNoContent, ""},
+	"POST /legal-entities/{id}/undelete": {nil, http.StatusNoContent, ""},
+
+	"GET /legal-entities/{legalEntityId}/fleets":  {nil, http.StatusOK, ""},
+	"POST /legal-entities/{legalEntityId}/fleets": {createFleetRequest{}, http.StatusCreated, ""},
+	"GET /fleets/{id}":                            {nil, http.StatusOK, ""},
+	"PATCH /fleets/{id}":                          {updateFleetRequest{}, http.StatusOK, ""},
+	"DELETE /fleets/{id}":                         {nil, http.StatusNoContent, ""},
+	"POST /fleets/{id}/undelete":                  {nil, http.StatusNoContent, ""},
+
+	"GET /fleets/{fleetId}/vehicles":  {nil, http.StatusOK, ""},
+	"POST /fleets/{fleetId}/vehicles": {createVehicleRequest{}, http.StatusCreated, ""},
+	"GET /vehicles/{id}":              {nil, http.StatusOK, ""},
+	"PATCH /vehicles/{id}":            {updateVehicleRequest{}, http.StatusOK, ""},
+	"DELETE /vehicles/{id}":           {nil, http.StatusNoContent, ""},
+	"POST /vehicles/{id}/undelete":    {nil, http.StatusNoContent, ""},
+
+	"GET /drivers":                {nil, http.StatusOK, ""},
+	"POST /drivers":               {createDriverRequest{}, http.StatusCreated, ""},
+	"GET /drivers/{id}":           {nil, http.StatusOK, ""},
+	"PATCH /drivers/{id}":         {updateDriverRequest{}, http.StatusOK, ""},
+	"DELETE /drivers/{id}":        {nil, http.StatusNoContent, ""},
+	"POST /drivers/{id}/undelete": {nil, http.StatusNoContent, ""},
+	"POST /drivers/{id}/validate": {nil, http.StatusOK, ""},
+
+	"GET /drivers/{driverId}/contracts": {nil, http.StatusOK, ""},
+	"POST /drivers/{driverId}/contracts": {
+		createContractRequest{StartDate: "2025-01-01", EndDate: "2025-12-31"}, http.StatusCreated, "",
+	},
+	"GET /contracts/{id}": {nil, http.StatusOK, ""},
+	"PATCH /contracts/{id}": {
+		updateContractRequest{StartDate: new("2025-01-01"), EndDate: new("2025-12-31")}, http.StatusOK, "",
+	},
+	"POST /contracts/{id}/terminate": {terminateContractRequest{}, http.StatusOK, ""},
+	"DELETE /contracts/{id}":         {nil, http.StatusNoContent, ""},
+	"POST /contracts/{id}/undelete":  {nil, http.StatusNoContent, ""},
+
+	"GET /contracts/{contractId}/assignments":  {nil, http.StatusOK, ""},
+	"POST /contracts/{contractId}/assignments": {createAssignmentRequest{}, http.StatusCreated, ""},
+	"GET /assignments/{id}":                    {nil, http.StatusOK, ""},
+	"PATCH /assignments/{id}":                  {updateAssignmentRequest{}, http.StatusOK, ""},
+	"POST /assignments/{id}/return":            {nil, http.StatusOK, ""},
+	"DELETE /assignments/{id}":                 {nil, http.StatusNoContent, ""},
+	"POST /assignments/{id}/undelete":          {nil, http.StatusNoContent, ""},
+
+	"GET /audit":         {nil, http.StatusOK, ""},
+	"GET /events/stream": {nil, http.StatusOK, "#/components/schemas/Event"},
+
+	"GET /webhooks": {nil, http.StatusOK, ""},
+	"POST /webhooks": {
+		createWebhookRequest{EventTypes: []string{"contract.created"}, Secret: "0123456789abcdef"}, http.StatusCreated, "",
+	},
+	"GET /webhooks/{id}": {nil, http.StatusOK, ""},
+	"PATCH /webhooks/{id}": {
+		updateWebhookRequest{EventTypes: []string{"contract.terminated"}, Secret: new("0123456789abcdef")},
+		http.StatusOK, "",
+	},
+	"DELETE /webhooks/{id}":                              {nil, http.StatusNoContent, ""},
+	"GET /webhooks/{id}/deliveries":                      {nil, http.StatusOK, ""},
+	"POST /webhooks/{id}/deliveries/{deliveryID}/replay": {nil, http.StatusAccepted, ""},
+}
+
+// enumValues gives the value fill sets for domain types with an enumerated set of values.
+var enumValues = map[reflect.Type]any{
+	reflect.TypeFor[domain.EntityType]():              domain.EntityVehicleAssignment,
+	reflect.TypeFor[domain.AuditAction]():             domain.AuditReassign,
+	reflect.TypeFor[domain.EventType]():               domain.EventAssignmentReturned,
+	reflect.TypeFor[domain.WebhookDeliveryStatus]():   domain.WebhookDeliveryFailed,
+	reflect.TypeFor[domain.LicenseValidationResult](): domain.LicenseDataMismatch,
+}
+
+// sampleTime is the value fill sets for times.
+var sampleTime = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// newRouter registers the routes of every handler provided to the routes group, backed
+// by service mocks that succeed with filled results whatever they are called with.
+func newRouter(t *testing.T) chi.Router {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	services := []any{
+		mocks.NewMockLegalEntityService(ctrl),
+		mocks.NewMockFleetService(ctrl),
+		mocks.NewMockVehicleService(ctrl),
+		mocks.NewMockDriverService(ctrl),
+		mocks.NewMockContractService(ctrl),
+		mocks.NewMockVehicleAssignmentService(ctrl),
+		mocks.NewMockAuditService(ctrl),
+		mocks.NewMockWebhookService(ctrl),
+		mocks.NewMockEventStreamService(ctrl),
+	}
+	for _, svc := range services {
+		stubService(ctrl, svc)
+	}
+	var handlers []RouteRegistrar
+	fxtest.New(t,
+		routes(),
+		fx.Supply(zaptest.NewLogger(t)),
+		fx.Supply(
+			fx.Annotate(services[0], fx.As(new(ports.LegalEntityService))),
+			fx.Annotate(services[1], fx.As(new(ports.FleetService))),
+			fx.Annotate(services[2], fx.As(new(ports.VehicleService))),
+			fx.Annotate(services[3], fx.As(new(ports.DriverService))),
+			fx.Annotate(services[4], fx.As(new(ports.ContractService))),
+			fx.Annotate(services[5], fx.As(new(ports.VehicleAssignmentService))),
+			fx.Annotate(services[6], fx.As(new(ports.AuditService))),
+			fx.Annotate(services[7], fx.As(new(ports.WebhookService))),
+			fx.Annotate(services[8], fx.As(new(ports.EventStreamService))),
+		),
+		fx.Invoke(fx.Annotate(func(hs []RouteRegistrar) { handlers = hs }, fx.ParamTags(`group:"routes"`))),
+	)
+
+	r := chi.NewRouter()
+	for _, h := range handlers {
+		h.RegisterRoutes(r)
+	}
+	return r
+}
+
+// stubService lets every method of the service mock svc be called any number of times,
+// returning filled results and no error.
+func stubService(ctrl *gomock.Controller, svc any) {
+	typ := reflect.TypeOf(svc)
+	for i := range typ.NumMethod() {
+		method := typ.Method(i)
+		if method.Name == "EXPECT" {
+			continue
+		}
+		args := make([]any, method.Type.NumIn()-1)
+		for j := range args {
+			args[j] = gomock.Any()
+		}
+		results := make([]any, method.Type.NumOut())
+		for j := range results {
+			results[j] = sample(method.Type.Out(j)).Interface()
+		}
+		ctrl.RecordCall(svc, method.Name, args...).Return(results...).AnyTimes()
+	}
+}
+
+// sample returns a filled value of type t. A channel receives one value and is closed.
+func sample(t reflect.Type) reflect.Value {
+	if t.Kind() == reflect.Chan {
+		ch := reflect.MakeChan(reflect.ChanOf(reflect.BothDir, t.Elem()), 1)
+		ch.Send(sample(t.Elem()))
+		ch.Close()
+		return ch.Convert(t)
+	}
+	v := reflect.New(t).Elem()
+	fill(v)
+	return v
+}
+
+// registeredRoutes returns the routes of every handler provided to the routes group,
+// as "METHOD /pattern".
+func registeredRoutes(t *testing.T) []string {
+	t.Helper()
+	var out []string
+	err := chi.Walk(newRouter(t), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		// Sub-routers register their root as "/prefix/".
+		if len(route) > 1 {
+			route = strings.TrimSuffix(route, "/")
+		}
+		out = append(out, method+" "+route)
+		return nil
+	})
+	require.NoError(t, err)
+	return out
+}
+
+// specOperations returns the operations of the OpenAPI document as "METHOD /path".
+func specOperations(t *testing.T) []string {
+	t.Helper()
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(openapi.Spec, &doc))
+	var out []string
+	for path, item := range doc.Paths {
+		for method := range item {
+			switch method {
+			case "get", "put", "post", "patch", "delete":
+				out = append(out, strings.ToUpper(method)+" "+path)
+			}
+		}
+	}
+	return out
+}
+
+func TestOpenAPI_DocumentsEveryRoute(t *testing.T) {
+	routes := registeredRoutes(t)
+	require.NotEmpty(t, routes)
+	assert.ElementsMatch(t, routes, specOperations(t))
+
+	shapes := make([]string, 0, len(routeShapes))
+	for route := range routeShapes {
+		shapes = append(shapes, route)
+	}
+	assert.ElementsMatch(t, routes, shapes, "routeShapes is out of date")
+}
+
+func TestOpenAPI_MatchesRouteShapes(t *testing.T) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(openapi.Spec))
+	require.NoError(t, err)
+	c := jsonschema.NewCompiler()
+	require.NoError(t, c.AddResource("openapi.json", doc))
+	router := newRouter(t)
+
+	for route, shape := range routeShapes {
+		t.Run(route, func(t *testing.T) {
+			method, path, _ := strings.Cut(route, " ")
+			op := "#/paths/" + pointerEscape(path) + "/" + strings.ToLower(method)
+
+			var body []byte
+			requestBody := lookup(doc, op+"/requestBody")
+			if shape.request == nil {
+				assert.Nil(t, requestBody, "the route takes no request body")
+			} else {
+				body = validate(t, c, op+"/requestBody/content/application~1json/schema", fillCopy(shape.request))
+			}
+
+			rec := serve(router, method, pathParams.ReplaceAllString(path, "x"), body)
+			require.Equal(t, shape.status, rec.Code, "%s", rec.Body)
+			response := op + "/responses/" + strconv.Itoa(shape.status)
+			require.NotNil(t, lookup(doc, response), "status %d is not documented", shape.status)
+			switch {
+			case lookup(doc, response+"/content") == nil:
+				assert.Empty(t, rec.Body.String(), "the route is documented to answer without a body")
+			case shape.schema != "":
+				_, data, ok := strings.Cut(rec.Body.String(), "\ndata: ")
+				require.True(t, ok, "no event was sent: %s", rec.Body)
+				data, _, _ = strings.Cut(data, "\n")
+				validate(t, c, shape.schema, json.RawMessage(data))
+			default:
+				assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+				validate(t, c, response+"/content/application~1json/schema", json.RawMessage(rec.Body.Bytes()))
+			}
+		})
+	}
+
+	t.Run("problem", func(t *testing.T) {
+		body := []byte(`{"start_date": "01/01/2025", "end_date": "2025-12-31"}`)
+		rec := serve(router, http.MethodPost, "/drivers/x/contracts", body)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		validate(t, c, "#/components/schemas/Problem", json.RawMessage(rec.Body.Bytes()))
+	})
+}
+
+// pathParams matches the parameters of a route pattern.
+var pathParams = regexp.MustCompile(`\{[^}]+\}`)
+
+// serve sends a request to router and records the response. A non-nil body is sent
+// as JSON.
+func serve(router http.Handler, method, path string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// fillCopy returns a copy of v with every zero field filled in by fill.
+func fillCopy(v any) any {
+	filled := reflect.New(reflect.TypeOf(v)).Elem()
+	filled.Set(reflect.ValueOf(v))
+	fill(filled)
+	return filled.Interface()
+}
+
+// validate encodes v as JSON, checks it against the schema at the JSON pointer ptr of
+// the OpenAPI document and returns the encoding.
+func validate(t *testing.T, c *jsonschema.Compiler, ptr string, v any) []byte {
+	t.Helper()
+	sch, err := c.Compile("openapi.json" + ptr)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	inst, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.NoError(t, sch.Validate(inst), "%s", data)
+	return data
+}
+
+// fill sets every zero field of v, recursively, so that optional fields are encoded
+// and checked too. Slices get one element.
+func fill(v reflect.Value) {
+	if !v.CanSet() {
+		return
+	}
+	if value, ok := enumValues[v.Type()]; ok {
+		if v.IsZero() {
+			v.Set(reflect.ValueOf(value))
+		}
+		return
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		fill(v.Elem())
+	case reflect.Struct:
+		if v.Type() == reflect.TypeFor[time.Time]() {
+			if v.IsZero() {
+				v.Set(reflect.ValueOf(sampleTime))
+			}
+			return
+		}
+		for i := range v.NumField() {
+			fill(v.Field(i))
+		}
+	case reflect.Slice:
+		if v.Type() == reflect.TypeFor[json.RawMessage]() {
+			return
+		}
+		if v.Len() == 0 {
+			v.Set(reflect.Append(v, reflect.New(v.Type().Elem()).Elem()))
+		}
+		for i := range v.Len() {
+			fill(v.Index(i))
+		}
+	case reflect.String:
+		if v.String() == "" {
+			v.SetString("x")
+		}
+	case reflect.Int, reflect.Int64:
+		if v.Int() == 0 {
+			v.SetInt(1)
+		}
+	case reflect.Bool:
+		v.SetBool(true)
+	default:
+		// Free-form values, such as audit snapshots, are left null.
+	}
+}
+
+// lookup returns the value at the JSON pointer ptr of doc, or nil.
+func lookup(doc any, ptr string) any {
+	for _, token := range strings.Split(strings.TrimPrefix(ptr, "#/"), "/") {
+		obj, ok := doc.(map[string]any)
+		if !ok {
+			return nil
+		}
+		doc = obj[strings.NewReplacer("~1", "/", "~0", "~").Replace(token)]
+	}
+	return doc
+}
+
+func pointerEscape(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
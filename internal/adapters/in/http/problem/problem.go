@@ -0,0 +1,147 @@
+// Package problem renders API errors as RFC 7807 problem details.
+package problem
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/logctx"
+)
+
+// ContentType is the media type of problem details.
+const ContentType = "application/problem+json"
+
+// Codes of problems detected by the HTTP adapter rather than reported by a domain
+// error. Domain errors bring their own code, see domain.Exposable.
+const (
+	CodeInternal             = "internal_error"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeRequestTooLarge      = "request_body_too_large"
+)
+
+// Details is the body of an error response. Clients tell problems apart by Code,
+// which is stable, rather than by Detail, which is meant for humans.
+type Details struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError points at an invalid field of the request and the rule it violates, see
+// the Rule constants of the domain package.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// New returns the problem details of a response with the given status.
+func New(status int, code, detail string, fields ...FieldError) *Details {
+	return &Details{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+		Errors: fields,
+	}
+}
+
+// Invalid returns the details of a bad request caused by one field.
+func Invalid(field, rule, message string) *Details {
+	return New(http.StatusBadRequest, domain.ErrorCode(domain.ErrInvalidInput), field+" "+message,
+		FieldError{Field: field, Rule: rule, Message: message})
+}
+
+// FromError returns the details of an exposable error, or nil for any other error.
+func FromError(err error) *Details {
+	if !domain.IsExposable(err) {
+		return nil
+	}
+	d := New(Status(err), domain.ErrorCode(err), err.Error())
+	var invalid *domain.ValidationError
+	if errors.As(err, &invalid) {
+		for _, v := range invalid.Violations {
+			d.Errors = append(d.Errors, FieldError{Field: v.Field, Rule: v.Rule, Message: v.Message})
+		}
+	}
+	var unique *domain.UniqueViolationError
+	if errors.As(err, &unique) {
+		d.Errors = []FieldError{{Field: unique.Field, Rule: domain.RuleUnique, Message: "is already in use"}}
+	}
+	return d
+}
+
+// Write sends the problem details as the response to r.
+func Write(w http.ResponseWriter, r *http.Request, d *Details) {
+	d.RequestID = middleware.GetReqID(r.Context())
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(d); err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(d.Status)
+	_, _ = buf.WriteTo(w)
+}
+
+// Error responds to a failed operation. The messages of errors that are not exposable
+// are logged, with the request-scoped logger when r carries one, and replaced by a
+// generic internal error.
+func Error(w http.ResponseWriter, r *http.Request, logger *zap.Logger, op string, err error) {
+	if d := FromError(err); d != nil {
+		Write(w, r, d)
+		return
+	}
+
+	logctx.From(r.Context(), logger).Error("operation failed", zap.String("op", op), zap.Error(err))
+	Write(w, r, New(http.StatusInternalServerError, CodeInternal, "internal server error"))
+}
+
+// Status returns the HTTP status of a domain error.
+func Status(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrConflict), errors.Is(err, domain.ErrDuplicateValue):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, domain.ErrContractNotActive):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrDriverAlreadyAssignedInFleet):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrDriverHasActiveContracts), errors.Is(err, domain.ErrDriverHasActiveAssignments):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrLegalEntityHasFleets), errors.Is(err, domain.ErrLegalEntityHasActiveContracts),
+		errors.Is(err, domain.ErrLegalEntityHasActiveAssignments),
+		errors.Is(err, domain.ErrFleetHasVehicles), errors.Is(err, domain.ErrFleetHasActiveContracts),
+		errors.Is(err, domain.ErrFleetHasActiveAssignments), errors.Is(err, domain.ErrVehicleHasActiveAssignments):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrAlreadyDeleted), errors.Is(err, domain.ErrParentDeleted):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrValidationServiceUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, domain.ErrLicenseValidationFailed), errors.Is(err, domain.ErrIdempotencyKeyReused):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
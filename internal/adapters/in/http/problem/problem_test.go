@@ -0,0 +1,92 @@
+package problem_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http/problem"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+)
+
+func respond(t *testing.T, err error) (*httptest.ResponseRecorder, problem.Details) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/contracts/c1/assignments", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.RequestIDKey, "req-1"))
+	rec := httptest.NewRecorder()
+	problem.Error(rec, req, zaptest.NewLogger(t), "assign vehicle", err)
+
+	var d problem.Details
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&d))
+	return rec, d
+}
+
+func TestError_ExposableError(t *testing.T) {
+	rec, d := respond(t, domain.ErrDriverAlreadyAssignedInFleet)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+	assert.Equal(t, problem.Details{
+		Type:      "about:blank",
+		Title:     "Conflict",
+		Status:    http.StatusConflict,
+		Detail:    "driver already has an active vehicle assignment for this fleet",
+		Code:      "driver_already_assigned_in_fleet",
+		RequestID: "req-1",
+	}, d)
+}
+
+func TestError_WrappedErrorKeepsCode(t *testing.T) {
+	rec, d := respond(t, fmt.Errorf("%w: vehicle must belong to the contract's fleet", domain.ErrInvalidInput))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "invalid_input", d.Code)
+	assert.Equal(t, "invalid input: vehicle must belong to the contract's fleet", d.Detail)
+}
+
+func TestError_UniqueViolationPointsAtField(t *testing.T) {
+	rec, d := respond(t, &domain.UniqueViolationError{Field: "license_number"})
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "duplicate_value", d.Code)
+	assert.Equal(t, []problem.FieldError{{Field: "license_number", Rule: "unique", Message: "is already in use"}}, d.Errors)
+}
+
+func TestError_ValidationErrorListsEveryField(t *testing.T) {
+	var invalid domain.ValidationError
+	invalid.Add("first_name", domain.RuleRequired, "is required")
+	invalid.Add("year", domain.RuleRange, "must be between 1900 and 2100")
+	rec, d := respond(t, invalid.Err())
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "invalid_input", d.Code)
+	assert.Equal(t, "invalid input: first_name is required; year must be between 1900 and 2100", d.Detail)
+	assert.Equal(t, []problem.FieldError{
+		{Field: "first_name", Rule: "required", Message: "is required"},
+		{Field: "year", Rule: "range", Message: "must be between 1900 and 2100"},
+	}, d.Errors)
+}
+
+func TestError_ParentDeleted(t *testing.T) {
+	rec, d := respond(t, &domain.ParentDeletedError{Parent: "fleet", ID: "f1"})
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "parent_deleted", d.Code)
+}
+
+func TestError_HidesInternalErrors(t *testing.T) {
+	rec, d := respond(t, errors.New("pq: connection refused"))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, problem.CodeInternal, d.Code)
+	assert.Equal(t, "internal server error", d.Detail)
+}
@@ -5,8 +5,14 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
 
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/auth"
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http/openapi"
 	"github.com/albenik/uber-fx-based-service-example/internal/config"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/health"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/metrics"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/tracing"
 )
 
 const maxRequestBodySize = 1 << 20 // 1 MB
@@ -16,9 +22,34 @@ type RouteRegistrar interface {
 	RegisterRoutes(chi.Router)
 }
 
-func NewServer(cfg *config.HTTPServerConfig, handlers []RouteRegistrar) *http.Server {
+// shutdownNotifier is implemented by handlers that keep requests open, such as event
+// streams. OnShutdown is called when the server starts shutting down, so they can end
+// those requests instead of holding up the graceful shutdown.
+type shutdownNotifier interface {
+	OnShutdown()
+}
+
+// NewServer serves the routes of handlers to authenticated callers only; the health
+// check, the liveness and readiness probes and the API description stay open.
+// Idempotency keys are scoped to the caller, so they are handled after authentication.
+// Every request is measured and logged, rejected ones included, and a panic in a
+// handler is answered with a 500 problem response.
+func NewServer(
+	cfg *config.HTTPServerConfig,
+	handlers []RouteRegistrar,
+	authenticate auth.Middleware,
+	idempotency *IdempotencyMiddleware,
+	httpMetrics *metrics.HTTP,
+	probes *health.Registry,
+	logger *zap.Logger,
+) *http.Server {
 	mux := chi.NewRouter()
 
+	mux.Use(tracing.Middleware)
+	mux.Use(httpMetrics.Middleware)
+	mux.Use(requestIDMiddleware)
+	mux.Use(accessLogMiddleware(logger))
+	mux.Use(recoverMiddleware(logger))
 	mux.Use(maxBytesMiddleware(maxRequestBodySize))
 
 	// Health check
@@ -27,12 +58,19 @@ func NewServer(cfg *config.HTTPServerConfig, handlers []RouteRegistrar) *http.Se
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	})
+	mux.Get("/livez", probes.Livez)
+	mux.Get("/readyz", probes.Readyz)
+	openapi.RegisterRoutes(mux)
 
-	for _, h := range handlers {
-		h.RegisterRoutes(mux)
-	}
+	mux.Group(func(r chi.Router) {
+		r.Use(authenticate)
+		r.Use(idempotency.Handler)
+		for _, h := range handlers {
+			h.RegisterRoutes(r)
+		}
+	})
 
-	return &http.Server{
+	server := &http.Server{
 		Addr:              cfg.Addr,
 		Handler:           mux,
 		ReadHeaderTimeout: 10 * time.Second,
@@ -40,6 +78,12 @@ func NewServer(cfg *config.HTTPServerConfig, handlers []RouteRegistrar) *http.Se
 		WriteTimeout:      30 * time.Second,
 		IdleTimeout:       120 * time.Second,
 	}
+	for _, h := range handlers {
+		if n, ok := h.(shutdownNotifier); ok {
+			server.RegisterOnShutdown(n.OnShutdown)
+		}
+	}
+	return server
 }
 
 func maxBytesMiddleware(maxBytes int64) func(http.Handler) http.Handler {
@@ -5,22 +5,46 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap/zaptest"
 
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/auth"
 	httpAdapter "github.com/albenik/uber-fx-based-service-example/internal/adapters/in/http"
 	"github.com/albenik/uber-fx-based-service-example/internal/config"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/health"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/metrics"
 )
 
+// noIdempotency returns a middleware that fails the test when a request carries an
+// idempotency key.
+func noIdempotency(t *testing.T) *httpAdapter.IdempotencyMiddleware {
+	return httpAdapter.NewIdempotencyMiddleware(mocks.NewMockIdempotencyService(gomock.NewController(t)), zaptest.NewLogger(t))
+}
+
+// noMetrics returns HTTP instruments registered with a registry of their own.
+func noMetrics(t *testing.T) *metrics.HTTP {
+	m, err := metrics.NewHTTP(prometheus.NewRegistry())
+	require.NoError(t, err)
+	return m
+}
+
+// noProbes returns a probe registry without checkers.
+func noProbes() *health.Registry {
+	return health.NewRegistry(nil, time.Second)
+}
+
 func TestNewServer_UsesConfigAddr(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockSvc := mocks.NewMockLegalEntityService(ctrl)
 	handler := httpAdapter.NewLegalEntityHandler(mockSvc, zaptest.NewLogger(t))
 
-	srv := httpAdapter.NewServer(&config.HTTPServerConfig{Addr: ":9090"}, []httpAdapter.RouteRegistrar{handler})
+	srv := httpAdapter.NewServer(&config.HTTPServerConfig{Addr: ":9090"}, []httpAdapter.RouteRegistrar{handler}, auth.Disabled(), noIdempotency(t), noMetrics(t), noProbes(), zaptest.NewLogger(t))
 	assert.Equal(t, ":9090", srv.Addr)
 }
 
@@ -29,7 +53,7 @@ func TestNewServer_HealthCheck(t *testing.T) {
 	mockSvc := mocks.NewMockLegalEntityService(ctrl)
 	handler := httpAdapter.NewLegalEntityHandler(mockSvc, zaptest.NewLogger(t))
 
-	srv := httpAdapter.NewServer(&config.HTTPServerConfig{Addr: ":8080"}, []httpAdapter.RouteRegistrar{handler})
+	srv := httpAdapter.NewServer(&config.HTTPServerConfig{Addr: ":8080"}, []httpAdapter.RouteRegistrar{handler}, auth.Disabled(), noIdempotency(t), noMetrics(t), noProbes(), zaptest.NewLogger(t))
 
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	rec := httptest.NewRecorder()
@@ -45,7 +69,7 @@ func TestNewServer_BodyTooLarge(t *testing.T) {
 	mockSvc := mocks.NewMockLegalEntityService(ctrl)
 	handler := httpAdapter.NewLegalEntityHandler(mockSvc, zaptest.NewLogger(t))
 
-	srv := httpAdapter.NewServer(&config.HTTPServerConfig{Addr: ":8080"}, []httpAdapter.RouteRegistrar{handler})
+	srv := httpAdapter.NewServer(&config.HTTPServerConfig{Addr: ":8080"}, []httpAdapter.RouteRegistrar{handler}, auth.Disabled(), noIdempotency(t), noMetrics(t), noProbes(), zaptest.NewLogger(t))
 
 	largeBody := `{"name":"foo","tax_id":"` + strings.Repeat("x", 2<<20) + `"}`
 	req := httptest.NewRequest(http.MethodPost, "/legal-entities", strings.NewReader(largeBody))
@@ -55,3 +79,48 @@ func TestNewServer_BodyTooLarge(t *testing.T) {
 
 	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
 }
+
+func TestNewServer_RequiresAuthentication(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockSvc := mocks.NewMockLegalEntityService(ctrl)
+	handler := httpAdapter.NewLegalEntityHandler(mockSvc, zaptest.NewLogger(t))
+
+	srv := httpAdapter.NewServer(&config.HTTPServerConfig{Addr: ":8080"}, []httpAdapter.RouteRegistrar{handler},
+		auth.NewMiddleware(nil, zaptest.NewLogger(t)), noIdempotency(t), noMetrics(t), noProbes(), zaptest.NewLogger(t))
+
+	rec := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/legal-entities/le-1", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	for _, path := range []string{"/health", "/livez", "/readyz"} {
+		rec = httptest.NewRecorder()
+		srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+	}
+}
+
+func TestNewServer_ServesAPIDescription(t *testing.T) {
+	srv := httpAdapter.NewServer(&config.HTTPServerConfig{Addr: ":8080"}, nil,
+		auth.NewMiddleware(nil, zaptest.NewLogger(t)), noIdempotency(t), noMetrics(t), noProbes(), zaptest.NewLogger(t))
+
+	rec := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `"openapi": "3.1.0"`)
+
+	rec = httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `url: "/openapi.json"`)
+}
+
+func TestNewServer_DoesNotServeAdminEndpoints(t *testing.T) {
+	srv := httpAdapter.NewServer(&config.HTTPServerConfig{Addr: ":8080"}, nil, auth.Disabled(), noIdempotency(t), noMetrics(t), noProbes(), zaptest.NewLogger(t))
+
+	for _, path := range []string{"/debug/pprof/", "/admin/log-level", "/admin/config", "/admin/info"} {
+		rec := httptest.NewRecorder()
+		srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code, path)
+	}
+}
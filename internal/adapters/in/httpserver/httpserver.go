@@ -0,0 +1,43 @@
+// Package httpserver runs the HTTP servers of the service within the fx lifecycle.
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// Hook listens on the address of server when the app starts and shuts server down
+// gracefully when it stops. name tells the servers apart in the logs. A server that
+// fails after it started stops the whole app.
+func Hook(name string, server *http.Server, shutdowner fx.Shutdowner, logger *zap.Logger) fx.Hook {
+	return fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			ln, err := net.Listen("tcp", server.Addr)
+			if err != nil {
+				return err
+			}
+
+			logger.Info(name+" server listening", zap.String("address", ln.Addr().String()))
+			go func() {
+				if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					logger.Error(name+" server error", zap.Error(err))
+					if shutdownErr := shutdowner.Shutdown(); shutdownErr != nil {
+						logger.Error("failed to trigger shutdown", zap.Error(shutdownErr))
+					}
+				}
+			}()
+
+			return nil
+		},
+
+		OnStop: func(ctx context.Context) error {
+			logger.Info("Shutting down " + name + " server")
+			return server.Shutdown(ctx)
+		},
+	}
+}
@@ -0,0 +1,28 @@
+package relay
+
+import (
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/in/worker"
+	"github.com/albenik/uber-fx-based-service-example/internal/config"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+)
+
+// Module runs the event relay and the webhook dispatcher while the application is up.
+func Module() fx.Option {
+	return fx.Module("relay",
+		fx.Invoke(relayLifecycle),
+	)
+}
+
+func relayLifecycle(
+	lc fx.Lifecycle,
+	relay ports.EventRelay,
+	dispatcher ports.WebhookDispatcher,
+	cfg *config.EventsConfig,
+	logger *zap.Logger,
+) {
+	lc.Append(worker.Hook("event_relay", relay.RelayBatch, cfg.RelayInterval, logger))
+	lc.Append(worker.Hook("webhook_dispatcher", dispatcher.DeliverBatch, cfg.RelayInterval, logger))
+}
@@ -0,0 +1,60 @@
+// Package worker runs the background workers of the service within the fx lifecycle.
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// BatchFunc processes one batch of work and returns the number of items processed.
+type BatchFunc func(ctx context.Context) (int, error)
+
+// Hook runs batch in the background while the app is up, see Run. name tells the
+// workers apart in the logs. Stopping waits for the batch in progress to return.
+func Hook(name string, batch BatchFunc, interval time.Duration, logger *zap.Logger) fx.Hook {
+	logger = logger.With(zap.String("worker", name))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	return fx.Hook{
+		OnStart: func(context.Context) error {
+			logger.Info("Starting background worker", zap.Duration("interval", interval))
+			go func() {
+				defer close(done)
+				Run(ctx, batch, interval, logger)
+			}()
+			return nil
+		},
+
+		OnStop: func(stopCtx context.Context) error {
+			logger.Info("Stopping background worker")
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	}
+}
+
+// Run processes batches until ctx is done. It moves on to the next batch right away
+// while there is work and waits interval when there is none or the batch fails.
+func Run(ctx context.Context, batch BatchFunc, interval time.Duration, logger *zap.Logger) {
+	for ctx.Err() == nil {
+		n, err := batch(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.Error("Batch failed", zap.Error(err))
+		}
+		if err == nil && n > 0 {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(interval):
+		}
+	}
+}
@@ -11,34 +11,47 @@ import (
 
 	"github.com/albenik/uber-fx-based-service-example/internal/config"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/health"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/metrics"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/tracing"
 )
 
 // Module provides the driver license validation gRPC client.
 // When DRIVER_LICENSE_GRPC_ADDR is empty, a no-op validator is used that returns
-// an error on ValidateLicense calls.
+// an error on ValidateLicense calls. Otherwise the connectivity of the client is
+// checked by the readiness probe.
 func Module() fx.Option {
 	return fx.Module("driverlicense",
-		fx.Provide(newDriverLicenseValidator),
+		fx.Provide(
+			fx.Annotate(
+				newDriverLicenseValidator,
+				fx.ResultTags(``, `group:"health,flatten"`),
+			),
+		),
 	)
 }
 
 func newDriverLicenseValidator(
 	lc fx.Lifecycle,
 	cfg *config.DriverLicenseGRPCConfig,
+	grpcMetrics *metrics.GRPCClient,
 	logger *zap.Logger,
-) (ports.DriverLicenseValidator, error) {
+) (ports.DriverLicenseValidator, []health.Checker, error) {
 	if cfg == nil || cfg.Addr == "" {
 		logger.Info("DRIVER_LICENSE_GRPC_ADDR not set, using no-op license validator")
-		return noopValidator{}, nil
+		return noopValidator{}, nil, nil
 	}
 
 	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
 	if cfg.TLSEnabled {
 		creds = grpc.WithTransportCredentials(credentials.NewTLS(nil))
 	}
-	conn, err := grpc.NewClient(cfg.Addr, creds)
+	conn, err := grpc.NewClient(cfg.Addr, creds, grpc.WithChainUnaryInterceptor(
+		tracing.UnaryClientInterceptor(),
+		grpcMetrics.UnaryClientInterceptor(),
+	))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	lc.Append(fx.Hook{
@@ -48,5 +61,5 @@ func newDriverLicenseValidator(
 		},
 	})
 
-	return NewClient(conn, logger), nil
+	return NewClient(conn, logger), []health.Checker{newConnectivityChecker(conn)}, nil
 }
@@ -0,0 +1,28 @@
+package driverlicense
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/health"
+)
+
+// newConnectivityChecker fails while the connection to the validation service is in
+// TRANSIENT_FAILURE or shut down. An idle connection is asked to connect, so that the
+// next check sees whether the service is reachable.
+func newConnectivityChecker(conn *grpc.ClientConn) health.Checker {
+	return health.NewChecker("grpc_driver_license", func(context.Context) error {
+		switch state := conn.GetState(); state {
+		case connectivity.Idle:
+			conn.Connect()
+			return nil
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			return fmt.Errorf("connection is %s", state)
+		default:
+			return nil
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+)
+
+// APIKeyRepository implements ports.APIKeyRepository.
+type APIKeyRepository struct {
+	db *DB
+}
+
+// NewAPIKeyRepository creates a new APIKeyRepository.
+func NewAPIKeyRepository(db *DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// FindByHash looks the key up on the master, so that a revocation takes effect at once.
+func (r *APIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	var row apiKeyRow
+	const query = `
+		SELECT id::text, name, key_hash, principal_id, role, legal_entity_ids, created_at, expires_at, revoked_at
+		FROM api_keys
+		WHERE key_hash = $1
+	`
+	if err := r.db.writer(ctx).GetContext(ctx, &row, query, keyHash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return row.toDomain(), nil
+}
@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 )
 
 // VehicleAssignmentRepository implements ports.VehicleAssignmentRepository.
@@ -23,30 +25,49 @@ func NewVehicleAssignmentRepository(db *DB) *VehicleAssignmentRepository {
 func (r *VehicleAssignmentRepository) Save(ctx context.Context, entity *domain.VehicleAssignment) error {
 	row := vehicleAssignmentToRow(entity)
 	const query = `
-		INSERT INTO vehicle_assignments (id, driver_id, vehicle_id, contract_id, start_time, end_time, deleted_at)
-		VALUES (:id, :driver_id, :vehicle_id, :contract_id, :start_time, :end_time, :deleted_at)
+		INSERT INTO vehicle_assignments (
+			id, driver_id, vehicle_id, contract_id, fleet_id, start_time, end_time,
+			created_at, created_by, updated_at, updated_by, deleted_at
+		)
+		VALUES (
+			:id, :driver_id, :vehicle_id, :contract_id,
+			(SELECT fleet_id FROM contracts WHERE id = :contract_id),
+			:start_time, :end_time,
+			:created_at, :created_by, :updated_at, :updated_by, :deleted_at
+		)
 		ON CONFLICT (id) DO UPDATE SET
 			driver_id = EXCLUDED.driver_id,
 			vehicle_id = EXCLUDED.vehicle_id,
 			contract_id = EXCLUDED.contract_id,
+			fleet_id = EXCLUDED.fleet_id,
 			start_time = EXCLUDED.start_time,
 			end_time = EXCLUDED.end_time,
-			deleted_at = EXCLUDED.deleted_at
+			updated_at = EXCLUDED.updated_at,
+			updated_by = EXCLUDED.updated_by,
+			deleted_at = EXCLUDED.deleted_at,
+			version = vehicle_assignments.version + 1
+		WHERE vehicle_assignments.version = :version
+		RETURNING version
 	`
-	_, err := r.db.Master().NamedExecContext(ctx, query, row)
-	return err
+	version, err := saveVersioned(ctx, r.db.writer(ctx), query, row)
+	if err != nil {
+		return err
+	}
+	entity.Version = version
+	return nil
 }
 
 // FindByID returns a vehicle assignment by ID, excluding soft-deleted.
 func (r *VehicleAssignmentRepository) FindByID(ctx context.Context, id string) (*domain.VehicleAssignment, error) {
 	var row vehicleAssignmentRow
 	const query = `
-		SELECT id::text, driver_id::text, vehicle_id::text, contract_id::text, start_time, end_time, deleted_at
+		SELECT id::text, driver_id::text, vehicle_id::text, contract_id::text, start_time, end_time, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
 		FROM vehicle_assignments
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	if err := r.db.Replica().GetContext(ctx, &row, query, id); err != nil {
+	if err := r.db.reader(ctx).GetContext(ctx, &row, query, id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrNotFound
 		}
@@ -55,27 +76,66 @@ func (r *VehicleAssignmentRepository) FindByID(ctx context.Context, id string) (
 	return row.toDomain(), nil
 }
 
-// FindByContractID returns all non-deleted assignments for a contract, sorted by StartTime.
+// FindDeletedByID returns a soft-deleted vehicle assignment by ID.
+func (r *VehicleAssignmentRepository) FindDeletedByID(ctx context.Context, id string) (*domain.VehicleAssignment, error) {
+	var row vehicleAssignmentRow
+	const query = `
+		SELECT id::text, driver_id::text, vehicle_id::text, contract_id::text, start_time, end_time, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason, deletion_id::text
+		FROM vehicle_assignments
+		WHERE id = $1
+	`
+
+	if err := r.db.reader(ctx).GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	if row.DeletedAt == nil {
+		return nil, fmt.Errorf("%w: entity is not deleted", domain.ErrConflict)
+	}
+
+	return row.toDomain(), nil
+}
+
+var vehicleAssignmentListSpec = &listSpec[vehicleAssignmentRow]{
+	sorts: map[string]sortColumn[vehicleAssignmentRow]{
+		"id": {listColumn{"id", "uuid"}, func(r *vehicleAssignmentRow) string { return r.ID }},
+		"start_time": {
+			listColumn{"start_time", "timestamptz"},
+			func(r *vehicleAssignmentRow) string { return timestampValue(r.StartTime) },
+		},
+	},
+	filters: map[string]listColumn{
+		"vehicle_id": {"vehicle_id", "uuid"},
+	},
+	defaultSort: "start_time",
+	id:          func(r *vehicleAssignmentRow) string { return r.ID },
+}
+
+// FindByContractID returns a page of non-deleted assignments for a contract, sorted by StartTime unless q says otherwise.
 func (r *VehicleAssignmentRepository) FindByContractID(
 	ctx context.Context,
 	contractID string,
-) ([]*domain.VehicleAssignment, error) {
+	q ports.ListQuery,
+) (*ports.Page[*domain.VehicleAssignment], error) {
+	st, err := vehicleAssignmentListSpec.build(q, []any{contractID})
+	if err != nil {
+		return nil, err
+	}
 	var rows []vehicleAssignmentRow
-	const query = `
-		SELECT id::text, driver_id::text, vehicle_id::text, contract_id::text, start_time, end_time, deleted_at
+	query := `
+		SELECT id::text, driver_id::text, vehicle_id::text, contract_id::text, start_time, end_time, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
 		FROM vehicle_assignments
-		WHERE contract_id = $1 AND deleted_at IS NULL
-		ORDER BY start_time
-	`
+		WHERE contract_id = $1 AND deleted_at IS NULL` + st.clause
 
-	if err := r.db.Replica().SelectContext(ctx, &rows, query, contractID); err != nil {
+	if err := r.db.reader(ctx).SelectContext(ctx, &rows, query, st.args...); err != nil {
 		return nil, err
 	}
-	result := make([]*domain.VehicleAssignment, len(rows))
-	for i := range rows {
-		result[i] = rows[i].toDomain()
-	}
-	return result, nil
+	return toPage(vehicleAssignmentListSpec, st, rows, (*vehicleAssignmentRow).toDomain), nil
 }
 
 // FindActiveByDriverID returns all active (end_time IS NULL) assignments for a driver.
@@ -85,12 +145,13 @@ func (r *VehicleAssignmentRepository) FindActiveByDriverID(
 ) ([]*domain.VehicleAssignment, error) {
 	var rows []vehicleAssignmentRow
 	const query = `
-		SELECT id::text, driver_id::text, vehicle_id::text, contract_id::text, start_time, end_time, deleted_at
+		SELECT id::text, driver_id::text, vehicle_id::text, contract_id::text, start_time, end_time, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
 		FROM vehicle_assignments
 		WHERE driver_id = $1 AND end_time IS NULL AND deleted_at IS NULL
 	`
 
-	if err := r.db.Replica().SelectContext(ctx, &rows, query, driverID); err != nil {
+	if err := r.db.reader(ctx).SelectContext(ctx, &rows, query, driverID); err != nil {
 		return nil, err
 	}
 
@@ -117,7 +178,14 @@ func (r *VehicleAssignmentRepository) FindActiveByDriverIDAndFleetID(
 			va.contract_id::text,
 			va.start_time,
 			va.end_time,
-			va.deleted_at
+			va.version,
+			va.created_at,
+			va.created_by,
+			va.updated_at,
+			va.updated_by,
+			va.deleted_at,
+			va.deleted_by,
+			va.delete_reason
 		FROM vehicle_assignments va
 		JOIN contracts c ON c.id = va.contract_id
 		WHERE va.driver_id = $1 AND c.fleet_id = $2
@@ -125,7 +193,7 @@ func (r *VehicleAssignmentRepository) FindActiveByDriverIDAndFleetID(
 		LIMIT 1
 	`
 
-	if err := r.db.Replica().GetContext(ctx, &row, query, driverID, fleetID); err != nil {
+	if err := r.db.reader(ctx).GetContext(ctx, &row, query, driverID, fleetID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
@@ -135,15 +203,54 @@ func (r *VehicleAssignmentRepository) FindActiveByDriverIDAndFleetID(
 	return row.toDomain(), nil
 }
 
-// SoftDelete marks a vehicle assignment as deleted.
-func (r *VehicleAssignmentRepository) SoftDelete(ctx context.Context, id string) error {
+// ExistsActiveByVehicleID reports whether a vehicle has a non-deleted assignment that was not returned yet.
+func (r *VehicleAssignmentRepository) ExistsActiveByVehicleID(ctx context.Context, vehicleID string) (bool, error) {
+	var exists bool
+	const query = `SELECT EXISTS (SELECT 1 FROM vehicle_assignments WHERE vehicle_id = $1 AND end_time IS NULL AND deleted_at IS NULL)`
+	if err := r.db.reader(ctx).GetContext(ctx, &exists, query, vehicleID); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// ExistsActiveByFleetID reports whether a fleet has a non-deleted assignment that was not returned yet.
+func (r *VehicleAssignmentRepository) ExistsActiveByFleetID(ctx context.Context, fleetID string) (bool, error) {
+	var exists bool
+	const query = `SELECT EXISTS (SELECT 1 FROM vehicle_assignments WHERE fleet_id = $1 AND end_time IS NULL AND deleted_at IS NULL)`
+	if err := r.db.reader(ctx).GetContext(ctx, &exists, query, fleetID); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// ExistsActiveByLegalEntityID reports whether a non-deleted assignment in the fleets or under the
+// contracts of a legal entity was not returned yet.
+func (r *VehicleAssignmentRepository) ExistsActiveByLegalEntityID(ctx context.Context, legalEntityID string) (bool, error) {
+	var exists bool
+	const query = `
+		SELECT EXISTS (
+			SELECT 1 FROM vehicle_assignments
+			WHERE end_time IS NULL AND deleted_at IS NULL AND (
+				fleet_id IN (SELECT id FROM fleets WHERE legal_entity_id = $1)
+				OR contract_id IN (SELECT id FROM contracts WHERE legal_entity_id = $1)
+			)
+		)
+	`
+	if err := r.db.reader(ctx).GetContext(ctx, &exists, query, legalEntityID); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// SoftDelete marks a vehicle assignment as deleted by d.
+func (r *VehicleAssignmentRepository) SoftDelete(ctx context.Context, id string, d ports.Deletion) error {
 	const query = `
 		UPDATE vehicle_assignments
-		SET deleted_at = NOW()
+		SET deleted_at = $2, deleted_by = $3, delete_reason = $4, deletion_id = $5, version = version + 1
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	res, err := r.db.Master().ExecContext(ctx, query, id)
+	res, err := r.db.writer(ctx).ExecContext(ctx, query, id, d.At, d.By, d.Reason, d.ID)
 	if err != nil {
 		return err
 	}
@@ -155,7 +262,7 @@ func (r *VehicleAssignmentRepository) SoftDelete(ctx context.Context, id string)
 	if n == 0 {
 		var n2 int
 		const checkQuery = `SELECT 1 FROM vehicle_assignments WHERE id = $1 AND deleted_at IS NOT NULL`
-		switch err := r.db.Master().GetContext(ctx, &n2, checkQuery, id); {
+		switch err := r.db.writer(ctx).GetContext(ctx, &n2, checkQuery, id); {
 		case err == nil:
 			return domain.ErrAlreadyDeleted
 		case errors.Is(err, sql.ErrNoRows):
@@ -168,12 +275,101 @@ func (r *VehicleAssignmentRepository) SoftDelete(ctx context.Context, id string)
 	return nil
 }
 
+// SoftDeleteByVehicleID marks all non-deleted assignments of a vehicle as deleted by d.
+func (r *VehicleAssignmentRepository) SoftDeleteByVehicleID(
+	ctx context.Context,
+	vehicleID string,
+	d ports.Deletion,
+) ([]*domain.VehicleAssignment, error) {
+	var rows []vehicleAssignmentRow
+	const query = `
+		WITH deleted AS (
+			SELECT id::text, driver_id::text, vehicle_id::text, contract_id::text, start_time, end_time, version,
+				created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
+			FROM vehicle_assignments
+			WHERE vehicle_id = $1 AND deleted_at IS NULL
+			FOR UPDATE
+		)
+		UPDATE vehicle_assignments
+		SET deleted_at = $2, deleted_by = $3, delete_reason = $4, deletion_id = $5, version = vehicle_assignments.version + 1
+		FROM deleted
+		WHERE vehicle_assignments.id = deleted.id::uuid
+		RETURNING deleted.*
+	`
+	if err := r.db.writer(ctx).SelectContext(ctx, &rows, query, vehicleID, d.At, d.By, d.Reason, d.ID); err != nil {
+		return nil, err
+	}
+	return toDomainAll(rows, (*vehicleAssignmentRow).toDomain), nil
+}
+
+// SoftDeleteByFleetID marks all non-deleted assignments in a fleet as deleted by d.
+func (r *VehicleAssignmentRepository) SoftDeleteByFleetID(
+	ctx context.Context,
+	fleetID string,
+	d ports.Deletion,
+) ([]*domain.VehicleAssignment, error) {
+	var rows []vehicleAssignmentRow
+	const query = `
+		WITH deleted AS (
+			SELECT id::text, driver_id::text, vehicle_id::text, contract_id::text, start_time, end_time, version,
+				created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
+			FROM vehicle_assignments
+			WHERE fleet_id = $1 AND deleted_at IS NULL
+			FOR UPDATE
+		)
+		UPDATE vehicle_assignments
+		SET deleted_at = $2, deleted_by = $3, delete_reason = $4, deletion_id = $5, version = vehicle_assignments.version + 1
+		FROM deleted
+		WHERE vehicle_assignments.id = deleted.id::uuid
+		RETURNING deleted.*
+	`
+	if err := r.db.writer(ctx).SelectContext(ctx, &rows, query, fleetID, d.At, d.By, d.Reason, d.ID); err != nil {
+		return nil, err
+	}
+	return toDomainAll(rows, (*vehicleAssignmentRow).toDomain), nil
+}
+
+// SoftDeleteByLegalEntityID marks all non-deleted assignments in the fleets or under the contracts of a legal entity as deleted by d.
+func (r *VehicleAssignmentRepository) SoftDeleteByLegalEntityID(
+	ctx context.Context,
+	legalEntityID string,
+	d ports.Deletion,
+) ([]*domain.VehicleAssignment, error) {
+	var rows []vehicleAssignmentRow
+	const query = `
+		WITH deleted AS (
+			SELECT id::text, driver_id::text, vehicle_id::text, contract_id::text, start_time, end_time, version,
+				created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
+			FROM vehicle_assignments
+			WHERE deleted_at IS NULL AND (
+				fleet_id IN (SELECT id FROM fleets WHERE legal_entity_id = $1)
+				OR contract_id IN (SELECT id FROM contracts WHERE legal_entity_id = $1)
+			)
+			FOR UPDATE
+		)
+		UPDATE vehicle_assignments
+		SET deleted_at = $2, deleted_by = $3, delete_reason = $4, deletion_id = $5, version = vehicle_assignments.version + 1
+		FROM deleted
+		WHERE vehicle_assignments.id = deleted.id::uuid
+		RETURNING deleted.*
+	`
+	if err := r.db.writer(ctx).SelectContext(ctx, &rows, query, legalEntityID, d.At, d.By, d.Reason, d.ID); err != nil {
+		return nil, err
+	}
+	return toDomainAll(rows, (*vehicleAssignmentRow).toDomain), nil
+}
+
 // Undelete restores a soft-deleted vehicle assignment.
-func (r *VehicleAssignmentRepository) Undelete(ctx context.Context, id string) error {
-	const query = `UPDATE vehicle_assignments SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
-	res, err := r.db.Master().ExecContext(ctx, query, id)
+func (r *VehicleAssignmentRepository) Undelete(ctx context.Context, id string, at time.Time, by string) error {
+	const query = `
+		UPDATE vehicle_assignments
+		SET deleted_at = NULL, deleted_by = '', delete_reason = '', deletion_id = NULL,
+			updated_at = $2, updated_by = $3, version = version + 1
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+	res, err := r.db.writer(ctx).ExecContext(ctx, query, id, at, by)
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 
 	n, err := res.RowsAffected()
@@ -183,7 +379,7 @@ func (r *VehicleAssignmentRepository) Undelete(ctx context.Context, id string) e
 	if n == 0 {
 		var n2 int
 		const checkQuery = `SELECT 1 FROM vehicle_assignments WHERE id = $1 AND deleted_at IS NULL`
-		switch err := r.db.Master().GetContext(ctx, &n2, checkQuery, id); {
+		switch err := r.db.writer(ctx).GetContext(ctx, &n2, checkQuery, id); {
 		case err == nil:
 			return fmt.Errorf("%w: entity is not deleted", domain.ErrConflict)
 		case errors.Is(err, sql.ErrNoRows):
@@ -195,3 +391,80 @@ func (r *VehicleAssignmentRepository) Undelete(ctx context.Context, id string) e
 
 	return nil
 }
+
+// vehicleAssignmentParentsLive restricts a vehicle_assignments query to assignments whose
+// contract and vehicle are not deleted.
+const vehicleAssignmentParentsLive = `
+	AND EXISTS (SELECT 1 FROM contracts c WHERE c.id = vehicle_assignments.contract_id AND c.deleted_at IS NULL)
+	AND EXISTS (SELECT 1 FROM vehicles v WHERE v.id = vehicle_assignments.vehicle_id AND v.deleted_at IS NULL)
+`
+
+// UndeleteByVehicleID restores the assignments of a vehicle that were deleted by deletionID.
+func (r *VehicleAssignmentRepository) UndeleteByVehicleID(
+	ctx context.Context,
+	vehicleID, deletionID string,
+	at time.Time,
+	by string,
+) ([]*domain.VehicleAssignment, error) {
+	var rows []vehicleAssignmentRow
+	const query = `
+		UPDATE vehicle_assignments
+		SET deleted_at = NULL, deleted_by = '', delete_reason = '', deletion_id = NULL,
+			updated_at = $3, updated_by = $4, version = version + 1
+		WHERE vehicle_id = $1 AND deletion_id = $2 AND deleted_at IS NOT NULL` + vehicleAssignmentParentsLive + `
+		RETURNING id::text, driver_id::text, vehicle_id::text, contract_id::text, start_time, end_time, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
+	`
+	if err := r.db.writer(ctx).SelectContext(ctx, &rows, query, vehicleID, deletionID, at, by); err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainAll(rows, (*vehicleAssignmentRow).toDomain), nil
+}
+
+// UndeleteByFleetID restores the assignments in a fleet that were deleted by deletionID.
+func (r *VehicleAssignmentRepository) UndeleteByFleetID(
+	ctx context.Context,
+	fleetID, deletionID string,
+	at time.Time,
+	by string,
+) ([]*domain.VehicleAssignment, error) {
+	var rows []vehicleAssignmentRow
+	const query = `
+		UPDATE vehicle_assignments
+		SET deleted_at = NULL, deleted_by = '', delete_reason = '', deletion_id = NULL,
+			updated_at = $3, updated_by = $4, version = version + 1
+		WHERE fleet_id = $1 AND deletion_id = $2 AND deleted_at IS NOT NULL` + vehicleAssignmentParentsLive + `
+		RETURNING id::text, driver_id::text, vehicle_id::text, contract_id::text, start_time, end_time, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
+	`
+	if err := r.db.writer(ctx).SelectContext(ctx, &rows, query, fleetID, deletionID, at, by); err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainAll(rows, (*vehicleAssignmentRow).toDomain), nil
+}
+
+// UndeleteByLegalEntityID restores the assignments in the fleets or under the contracts of a legal entity
+// that were deleted by deletionID.
+func (r *VehicleAssignmentRepository) UndeleteByLegalEntityID(
+	ctx context.Context,
+	legalEntityID, deletionID string,
+	at time.Time,
+	by string,
+) ([]*domain.VehicleAssignment, error) {
+	var rows []vehicleAssignmentRow
+	const query = `
+		UPDATE vehicle_assignments
+		SET deleted_at = NULL, deleted_by = '', delete_reason = '', deletion_id = NULL,
+			updated_at = $3, updated_by = $4, version = version + 1
+		WHERE deletion_id = $2 AND deleted_at IS NOT NULL AND (
+			fleet_id IN (SELECT id FROM fleets WHERE legal_entity_id = $1)
+			OR contract_id IN (SELECT id FROM contracts WHERE legal_entity_id = $1)
+		)` + vehicleAssignmentParentsLive + `
+		RETURNING id::text, driver_id::text, vehicle_id::text, contract_id::text, start_time, end_time, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
+	`
+	if err := r.db.writer(ctx).SelectContext(ctx, &rows, query, legalEntityID, deletionID, at, by); err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainAll(rows, (*vehicleAssignmentRow).toDomain), nil
+}
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+)
+
+// AuditLogRepository implements ports.AuditLog.
+type AuditLogRepository struct {
+	db *DB
+}
+
+// NewAuditLogRepository creates a new AuditLogRepository.
+func NewAuditLogRepository(db *DB) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+// Append inserts an audit entry.
+func (r *AuditLogRepository) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	row, err := auditEntryToRow(entry)
+	if err != nil {
+		return err
+	}
+	const query = `
+		INSERT INTO audit_log (id, entity_type, entity_id, action, actor, at, deletion_id, before, after)
+		VALUES (:id, :entity_type, :entity_id, :action, :actor, :at, :deletion_id, :before, :after)
+	`
+	_, err = sqlx.NamedExecContext(ctx, r.db.writer(ctx), query, row)
+	return err
+}
+
+var auditEntryListSpec = &listSpec[auditEntryRow]{
+	sorts: map[string]sortColumn[auditEntryRow]{
+		"at": {listColumn{"at", "timestamptz"}, func(r *auditEntryRow) string { return timestampValue(r.At) }},
+	},
+	filters: map[string]listColumn{
+		"entity_type": {"entity_type", "text"},
+		"entity_id":   {"entity_id", "uuid"},
+		"action":      {"action", "text"},
+		"actor":       {"actor", "text"},
+		"deletion_id": {"deletion_id", "uuid"},
+	},
+	defaultSort: "at",
+	id:          func(r *auditEntryRow) string { return r.ID },
+}
+
+// FindAll returns a page of audit entries, sorted by time unless q says otherwise.
+func (r *AuditLogRepository) FindAll(ctx context.Context, q ports.ListQuery) (*ports.Page[*domain.AuditEntry], error) {
+	st, err := auditEntryListSpec.build(q, nil)
+	if err != nil {
+		return nil, err
+	}
+	var rows []auditEntryRow
+	query := `
+		SELECT id::text, entity_type, entity_id::text, action, actor, at, deletion_id::text, before, after
+		FROM audit_log
+		WHERE TRUE` + st.clause
+	if err := r.db.reader(ctx).SelectContext(ctx, &rows, query, st.args...); err != nil {
+		return nil, err
+	}
+	return toPage(auditEntryListSpec, st, rows, (*auditEntryRow).toDomain), nil
+}
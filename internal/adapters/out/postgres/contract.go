@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 )
 
 // ContractRepository implements ports.ContractRepository.
@@ -33,6 +34,10 @@ func (r *ContractRepository) Save(ctx context.Context, entity *domain.Contract)
 			end_date,
 			terminated_at,
 			terminated_by,
+			created_at,
+			created_by,
+			updated_at,
+			updated_by,
 			deleted_at
 		)
 		VALUES (
@@ -44,6 +49,10 @@ func (r *ContractRepository) Save(ctx context.Context, entity *domain.Contract)
 			:end_date,
 			:terminated_at,
 			:terminated_by,
+			:created_at,
+			:created_by,
+			:updated_at,
+			:updated_by,
 			:deleted_at
 		)
 		ON CONFLICT (id) DO UPDATE SET
@@ -54,10 +63,19 @@ func (r *ContractRepository) Save(ctx context.Context, entity *domain.Contract)
 			end_date = EXCLUDED.end_date,
 			terminated_at = EXCLUDED.terminated_at,
 			terminated_by = EXCLUDED.terminated_by,
-			deleted_at = EXCLUDED.deleted_at
+			updated_at = EXCLUDED.updated_at,
+			updated_by = EXCLUDED.updated_by,
+			deleted_at = EXCLUDED.deleted_at,
+			version = contracts.version + 1
+		WHERE contracts.version = :version
+		RETURNING version
 	`
-	_, err := r.db.Master().NamedExecContext(ctx, query, row)
-	return err
+	version, err := saveVersioned(ctx, r.db.writer(ctx), query, row)
+	if err != nil {
+		return err
+	}
+	entity.Version = version
+	return nil
 }
 
 // FindByID returns a contract by ID, excluding soft-deleted.
@@ -65,11 +83,12 @@ func (r *ContractRepository) FindByID(ctx context.Context, id string) (*domain.C
 	var row contractRow
 	const query = `
 		SELECT id::text, driver_id::text, legal_entity_id::text, fleet_id::text,
-			start_date, end_date, terminated_at, terminated_by, deleted_at
+			start_date, end_date, terminated_at, terminated_by, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
 		FROM contracts
 		WHERE id = $1 AND deleted_at IS NULL
 	`
-	if err := r.db.Replica().GetContext(ctx, &row, query, id); err != nil {
+	if err := r.db.reader(ctx).GetContext(ctx, &row, query, id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrNotFound
 		}
@@ -78,24 +97,62 @@ func (r *ContractRepository) FindByID(ctx context.Context, id string) (*domain.C
 	return row.toDomain(), nil
 }
 
-// FindByDriverID returns all non-deleted contracts for a driver, sorted by StartDate.
-func (r *ContractRepository) FindByDriverID(ctx context.Context, driverID string) ([]*domain.Contract, error) {
-	var rows []contractRow
+// FindDeletedByID returns a soft-deleted contract by ID.
+func (r *ContractRepository) FindDeletedByID(ctx context.Context, id string) (*domain.Contract, error) {
+	var row contractRow
 	const query = `
-		SELECT id::text, driver_id::text, legal_entity_id::text, fleet_id::text,
-			start_date, end_date, terminated_at, terminated_by, deleted_at
+		SELECT id::text, driver_id::text, legal_entity_id::text, fleet_id::text, start_date, end_date, terminated_at, terminated_by, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason, deletion_id::text
 		FROM contracts
-		WHERE driver_id = $1 AND deleted_at IS NULL
-		ORDER BY start_date
+		WHERE id = $1
 	`
-	if err := r.db.Replica().SelectContext(ctx, &rows, query, driverID); err != nil {
+	if err := r.db.reader(ctx).GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
 		return nil, err
 	}
-	result := make([]*domain.Contract, len(rows))
-	for i := range rows {
-		result[i] = rows[i].toDomain()
+	if row.DeletedAt == nil {
+		return nil, fmt.Errorf("%w: entity is not deleted", domain.ErrConflict)
 	}
-	return result, nil
+	return row.toDomain(), nil
+}
+
+var contractListSpec = &listSpec[contractRow]{
+	sorts: map[string]sortColumn[contractRow]{
+		"id":         {listColumn{"id", "uuid"}, func(r *contractRow) string { return r.ID }},
+		"start_date": {listColumn{"start_date", "date"}, func(r *contractRow) string { return dateValue(r.StartDate) }},
+		"end_date":   {listColumn{"end_date", "date"}, func(r *contractRow) string { return dateValue(r.EndDate) }},
+	},
+	filters: map[string]listColumn{
+		"legal_entity_id": {"legal_entity_id", "uuid"},
+		"fleet_id":        {"fleet_id", "uuid"},
+	},
+	defaultSort: "start_date",
+	id:          func(r *contractRow) string { return r.ID },
+}
+
+// FindByDriverID returns a page of non-deleted contracts for a driver, sorted by StartDate unless q says otherwise.
+func (r *ContractRepository) FindByDriverID(
+	ctx context.Context,
+	driverID string,
+	q ports.ListQuery,
+) (*ports.Page[*domain.Contract], error) {
+	st, err := contractListSpec.build(q, []any{driverID})
+	if err != nil {
+		return nil, err
+	}
+	var rows []contractRow
+	query := `
+		SELECT id::text, driver_id::text, legal_entity_id::text, fleet_id::text,
+			start_date, end_date, terminated_at, terminated_by, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
+		FROM contracts
+		WHERE driver_id = $1 AND deleted_at IS NULL` + st.clause
+	if err := r.db.reader(ctx).SelectContext(ctx, &rows, query, st.args...); err != nil {
+		return nil, err
+	}
+	return toPage(contractListSpec, st, rows, (*contractRow).toDomain), nil
 }
 
 // FindOverlapping returns contracts that overlap with the given date range for the same driver/legal/fleet.
@@ -106,18 +163,23 @@ func (r *ContractRepository) FindOverlapping(
 	excludeID string,
 ) ([]*domain.Contract, error) {
 	var rows []contractRow
-	// terminated_at::date truncates to date-level precision intentionally —
+	// The effective range must match the excl_contracts_overlap constraint.
+	// terminated_at is truncated to a UTC date intentionally —
 	// contracts use date-only semantics (no time component).
 	const query = `
 		SELECT id::text, driver_id::text, legal_entity_id::text, fleet_id::text,
-			start_date, end_date, terminated_at, terminated_by, deleted_at
+			start_date, end_date, terminated_at, terminated_by, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
 		FROM contracts
 		WHERE driver_id = $1 AND legal_entity_id = $2 AND fleet_id = $3
 			AND ($4 = '' OR id::text != $4) AND deleted_at IS NULL
-			AND $5::date < COALESCE(terminated_at::date, end_date)
-			AND $6::date > start_date
+			AND daterange(
+				start_date,
+				GREATEST(start_date, COALESCE((terminated_at AT TIME ZONE 'UTC')::date, end_date)),
+				'[)'
+			) && daterange($5::date, $6::date, '[)')
 	`
-	if err := r.db.Replica().SelectContext(ctx, &rows, query,
+	if err := r.db.reader(ctx).SelectContext(ctx, &rows, query,
 		driverID,
 		legalEntityID,
 		fleetID,
@@ -134,14 +196,46 @@ func (r *ContractRepository) FindOverlapping(
 	return result, nil
 }
 
-// SoftDelete marks a contract as deleted.
-func (r *ContractRepository) SoftDelete(ctx context.Context, id string) error {
+// ExistsActiveByLegalEntityID reports whether a legal entity has a non-deleted, non-terminated contract ending on or after at.
+func (r *ContractRepository) ExistsActiveByLegalEntityID(ctx context.Context, legalEntityID string, at time.Time) (bool, error) {
+	var exists bool
+	const query = `
+		SELECT EXISTS (
+			SELECT 1 FROM contracts
+			WHERE legal_entity_id = $1 AND deleted_at IS NULL
+				AND terminated_at IS NULL AND end_date >= $2::date
+		)
+	`
+	if err := r.db.reader(ctx).GetContext(ctx, &exists, query, legalEntityID, at); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// ExistsActiveByFleetID reports whether a fleet has a non-deleted, non-terminated contract ending on or after at.
+func (r *ContractRepository) ExistsActiveByFleetID(ctx context.Context, fleetID string, at time.Time) (bool, error) {
+	var exists bool
+	const query = `
+		SELECT EXISTS (
+			SELECT 1 FROM contracts
+			WHERE fleet_id = $1 AND deleted_at IS NULL
+				AND terminated_at IS NULL AND end_date >= $2::date
+		)
+	`
+	if err := r.db.reader(ctx).GetContext(ctx, &exists, query, fleetID, at); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// SoftDelete marks a contract as deleted by d.
+func (r *ContractRepository) SoftDelete(ctx context.Context, id string, d ports.Deletion) error {
 	const query = `
 		UPDATE contracts
-		SET deleted_at = NOW()
+		SET deleted_at = $2, deleted_by = $3, delete_reason = $4, deletion_id = $5, version = version + 1
 		WHERE id = $1 AND deleted_at IS NULL
 	`
-	res, err := r.db.Master().ExecContext(ctx, query, id)
+	res, err := r.db.writer(ctx).ExecContext(ctx, query, id, d.At, d.By, d.Reason, d.ID)
 	if err != nil {
 		return err
 	}
@@ -152,7 +246,7 @@ func (r *ContractRepository) SoftDelete(ctx context.Context, id string) error {
 	if n == 0 {
 		var n2 int
 		const checkQuery = `SELECT 1 FROM contracts WHERE id = $1 AND deleted_at IS NOT NULL`
-		switch err := r.db.Master().GetContext(ctx, &n2, checkQuery, id); {
+		switch err := r.db.writer(ctx).GetContext(ctx, &n2, checkQuery, id); {
 		case err == nil:
 			return domain.ErrAlreadyDeleted
 		case errors.Is(err, sql.ErrNoRows):
@@ -164,12 +258,73 @@ func (r *ContractRepository) SoftDelete(ctx context.Context, id string) error {
 	return nil
 }
 
+// SoftDeleteByFleetID marks all non-deleted contracts of a fleet as deleted by d.
+func (r *ContractRepository) SoftDeleteByFleetID(
+	ctx context.Context,
+	fleetID string,
+	d ports.Deletion,
+) ([]*domain.Contract, error) {
+	var rows []contractRow
+	const query = `
+		WITH deleted AS (
+			SELECT id::text, driver_id::text, legal_entity_id::text, fleet_id::text, start_date, end_date, terminated_at, terminated_by, version,
+				created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
+			FROM contracts
+			WHERE fleet_id = $1 AND deleted_at IS NULL
+			FOR UPDATE
+		)
+		UPDATE contracts
+		SET deleted_at = $2, deleted_by = $3, delete_reason = $4, deletion_id = $5, version = contracts.version + 1
+		FROM deleted
+		WHERE contracts.id = deleted.id::uuid
+		RETURNING deleted.*
+	`
+	if err := r.db.writer(ctx).SelectContext(ctx, &rows, query, fleetID, d.At, d.By, d.Reason, d.ID); err != nil {
+		return nil, err
+	}
+	return toDomainAll(rows, (*contractRow).toDomain), nil
+}
+
+// SoftDeleteByLegalEntityID marks all non-deleted contracts of a legal entity or of its fleets as deleted by d.
+func (r *ContractRepository) SoftDeleteByLegalEntityID(
+	ctx context.Context,
+	legalEntityID string,
+	d ports.Deletion,
+) ([]*domain.Contract, error) {
+	var rows []contractRow
+	const query = `
+		WITH deleted AS (
+			SELECT id::text, driver_id::text, legal_entity_id::text, fleet_id::text, start_date, end_date, terminated_at, terminated_by, version,
+				created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
+			FROM contracts
+			WHERE deleted_at IS NULL AND (
+				legal_entity_id = $1 OR fleet_id IN (SELECT id FROM fleets WHERE legal_entity_id = $1)
+			)
+			FOR UPDATE
+		)
+		UPDATE contracts
+		SET deleted_at = $2, deleted_by = $3, delete_reason = $4, deletion_id = $5, version = contracts.version + 1
+		FROM deleted
+		WHERE contracts.id = deleted.id::uuid
+		RETURNING deleted.*
+	`
+	if err := r.db.writer(ctx).SelectContext(ctx, &rows, query, legalEntityID, d.At, d.By, d.Reason, d.ID); err != nil {
+		return nil, err
+	}
+	return toDomainAll(rows, (*contractRow).toDomain), nil
+}
+
 // Undelete restores a soft-deleted contract.
-func (r *ContractRepository) Undelete(ctx context.Context, id string) error {
-	const query = `UPDATE contracts SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
-	res, err := r.db.Master().ExecContext(ctx, query, id)
+func (r *ContractRepository) Undelete(ctx context.Context, id string, at time.Time, by string) error {
+	const query = `
+		UPDATE contracts
+		SET deleted_at = NULL, deleted_by = '', delete_reason = '', deletion_id = NULL,
+			updated_at = $2, updated_by = $3, version = version + 1
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+	res, err := r.db.writer(ctx).ExecContext(ctx, query, id, at, by)
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 	n, err := res.RowsAffected()
 	if err != nil {
@@ -178,7 +333,7 @@ func (r *ContractRepository) Undelete(ctx context.Context, id string) error {
 	if n == 0 {
 		var n2 int
 		const checkQuery = `SELECT 1 FROM contracts WHERE id = $1 AND deleted_at IS NULL`
-		switch err := r.db.Master().GetContext(ctx, &n2, checkQuery, id); {
+		switch err := r.db.writer(ctx).GetContext(ctx, &n2, checkQuery, id); {
 		case err == nil:
 			return fmt.Errorf("%w: entity is not deleted", domain.ErrConflict)
 		case errors.Is(err, sql.ErrNoRows):
@@ -189,3 +344,57 @@ func (r *ContractRepository) Undelete(ctx context.Context, id string) error {
 	}
 	return nil
 }
+
+// contractParentsLive restricts a contracts query to contracts whose driver, legal entity
+// and fleet are not deleted.
+const contractParentsLive = `
+	AND EXISTS (SELECT 1 FROM drivers d WHERE d.id = contracts.driver_id AND d.deleted_at IS NULL)
+	AND EXISTS (SELECT 1 FROM legal_entities le WHERE le.id = contracts.legal_entity_id AND le.deleted_at IS NULL)
+	AND EXISTS (SELECT 1 FROM fleets f WHERE f.id = contracts.fleet_id AND f.deleted_at IS NULL)
+`
+
+// UndeleteByFleetID restores the contracts of a fleet that were deleted by deletionID.
+func (r *ContractRepository) UndeleteByFleetID(
+	ctx context.Context,
+	fleetID, deletionID string,
+	at time.Time,
+	by string,
+) ([]*domain.Contract, error) {
+	var rows []contractRow
+	const query = `
+		UPDATE contracts
+		SET deleted_at = NULL, deleted_by = '', delete_reason = '', deletion_id = NULL,
+			updated_at = $3, updated_by = $4, version = version + 1
+		WHERE fleet_id = $1 AND deletion_id = $2 AND deleted_at IS NOT NULL` + contractParentsLive + `
+		RETURNING id::text, driver_id::text, legal_entity_id::text, fleet_id::text, start_date, end_date, terminated_at, terminated_by, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
+	`
+	if err := r.db.writer(ctx).SelectContext(ctx, &rows, query, fleetID, deletionID, at, by); err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainAll(rows, (*contractRow).toDomain), nil
+}
+
+// UndeleteByLegalEntityID restores the contracts of a legal entity or of its fleets that were deleted by deletionID.
+func (r *ContractRepository) UndeleteByLegalEntityID(
+	ctx context.Context,
+	legalEntityID, deletionID string,
+	at time.Time,
+	by string,
+) ([]*domain.Contract, error) {
+	var rows []contractRow
+	const query = `
+		UPDATE contracts
+		SET deleted_at = NULL, deleted_by = '', delete_reason = '', deletion_id = NULL,
+			updated_at = $3, updated_by = $4, version = version + 1
+		WHERE deletion_id = $2 AND deleted_at IS NOT NULL AND (
+			legal_entity_id = $1 OR fleet_id IN (SELECT id FROM fleets WHERE legal_entity_id = $1)
+		)` + contractParentsLive + `
+		RETURNING id::text, driver_id::text, legal_entity_id::text, fleet_id::text, start_date, end_date, terminated_at, terminated_by, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
+	`
+	if err := r.db.writer(ctx).SelectContext(ctx, &rows, query, legalEntityID, deletionID, at, by); err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainAll(rows, (*contractRow).toDomain), nil
+}
@@ -3,11 +3,16 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/jackc/pgx/v5/stdlib"
 
 	"github.com/albenik/uber-fx-based-service-example/internal/config"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
 )
 
 var errMissingMasterURL = errors.New("database config with MasterURL is required")
@@ -16,39 +21,66 @@ var errMissingMasterURL = errors.New("database config with MasterURL is required
 type DB struct {
 	master  *sqlx.DB
 	replica *sqlx.DB
+	// masterTimeout and replicaTimeout bound each repository statement run on the pool.
+	masterTimeout  time.Duration
+	replicaTimeout time.Duration
 }
 
-// NewDB creates master and optionally replica pools. If ReplicaURL is empty, master is used for both.
+// NewDB creates master and optionally replica pools, each tuned by its own pool
+// settings. If ReplicaURL is empty, master is used for both.
 func NewDB(ctx context.Context, cfg *config.DatabaseConfig) (*DB, error) {
 	if cfg == nil || cfg.MasterURL == "" {
 		return nil, errMissingMasterURL
 	}
 
-	master, err := sqlx.ConnectContext(ctx, "pgx", cfg.MasterURL)
+	master, err := openPool(ctx, cfg.MasterURL, &cfg.Master)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := master.PingContext(ctx); err != nil {
-		_ = master.Close()
-		return nil, err
+	db := &DB{
+		master:         master,
+		replica:        master,
+		masterTimeout:  cfg.Master.QueryTimeout,
+		replicaTimeout: cfg.Master.QueryTimeout,
 	}
-
-	replica := master
 	if cfg.ReplicaURL != "" {
-		replica, err = sqlx.ConnectContext(ctx, "pgx", cfg.ReplicaURL)
+		replica, err := openPool(ctx, cfg.ReplicaURL, &cfg.Replica)
 		if err != nil {
 			_ = master.Close()
 			return nil, err
 		}
-		if err := replica.PingContext(ctx); err != nil {
-			_ = master.Close()
-			_ = replica.Close()
-			return nil, err
-		}
+		db.replica, db.replicaTimeout = replica, cfg.Replica.QueryTimeout
+	}
+
+	return db, nil
+}
+
+// openPool connects to url with the session settings of pool, sizes the pool and
+// checks that the database is reachable.
+func openPool(ctx context.Context, url string, pool *config.DatabasePoolConfig) (*sqlx.DB, error) {
+	connConfig, err := pgx.ParseConfig(url)
+	if err != nil {
+		return nil, err
+	}
+	if pool.ApplicationName != "" {
+		connConfig.RuntimeParams["application_name"] = pool.ApplicationName
 	}
+	if pool.StatementTimeout > 0 {
+		connConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(pool.StatementTimeout.Milliseconds(), 10)
+	}
+
+	db := sqlx.NewDb(stdlib.OpenDB(*connConfig), "pgx")
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
 
-	return &DB{master: master, replica: replica}, nil
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return db, nil
 }
 
 // Master returns the pool for write operations.
@@ -70,3 +102,51 @@ func (db *DB) Close() error {
 	}
 	return errors.Join(masterErr, replicaErr)
 }
+
+// executor is the query API shared by *sqlx.DB and *sqlx.Tx.
+type executor interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+}
+
+// writer returns the transaction started by TxManager for ctx, or the master pool.
+func (db *DB) writer(ctx context.Context) executor {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return timedExecutor{tracedExecutor{tx}, db.masterTimeout}
+	}
+	return timedExecutor{tracedExecutor{db.master}, db.masterTimeout}
+}
+
+// reader returns the transaction started by TxManager for ctx, or the replica pool.
+// Reads inside a transaction must see its own writes, so they never go to the replica.
+func (db *DB) reader(ctx context.Context) executor {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return timedExecutor{tracedExecutor{tx}, db.masterTimeout}
+	}
+	return timedExecutor{tracedExecutor{db.replica}, db.replicaTimeout}
+}
+
+// saveVersioned runs a named upsert guarded by an optimistic-lock version check that
+// returns the stored version. No returned row means the version did not match.
+func saveVersioned(ctx context.Context, db executor, query string, arg any) (int64, error) {
+	ctx, cancel := statementContext(ctx, db)
+	defer cancel()
+	rows, err := sqlx.NamedQueryContext(ctx, db, query, arg)
+	if err != nil {
+		return 0, translateError(err)
+	}
+	defer rows.Close() //nolint:errcheck // rows.Err is checked below
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, translateError(err)
+		}
+		return 0, fmt.Errorf("%w: entity was modified concurrently", domain.ErrConflict)
+	}
+	var version int64
+	if err := rows.Scan(&version); err != nil {
+		return 0, err
+	}
+	return version, rows.Err()
+}
@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 )
 
 // DriverRepository implements ports.DriverRepository.
@@ -23,27 +25,43 @@ func NewDriverRepository(db *DB) *DriverRepository {
 func (r *DriverRepository) Save(ctx context.Context, entity *domain.Driver) error {
 	row := driverToRow(entity)
 	const query = `
-		INSERT INTO drivers (id, first_name, last_name, license_number, deleted_at)
-		VALUES (:id, :first_name, :last_name, :license_number, :deleted_at)
+		INSERT INTO drivers (
+			id, first_name, last_name, license_number,
+			created_at, created_by, updated_at, updated_by, deleted_at
+		)
+		VALUES (
+			:id, :first_name, :last_name, :license_number,
+			:created_at, :created_by, :updated_at, :updated_by, :deleted_at
+		)
 		ON CONFLICT (id) DO UPDATE SET
 			first_name = EXCLUDED.first_name,
 			last_name = EXCLUDED.last_name,
 			license_number = EXCLUDED.license_number,
-			deleted_at = EXCLUDED.deleted_at
+			updated_at = EXCLUDED.updated_at,
+			updated_by = EXCLUDED.updated_by,
+			deleted_at = EXCLUDED.deleted_at,
+			version = drivers.version + 1
+		WHERE drivers.version = :version
+		RETURNING version
 	`
-	_, err := r.db.Master().NamedExecContext(ctx, query, row)
-	return err
+	version, err := saveVersioned(ctx, r.db.writer(ctx), query, row)
+	if err != nil {
+		return err
+	}
+	entity.Version = version
+	return nil
 }
 
 // FindByID returns a driver by ID, excluding soft-deleted.
 func (r *DriverRepository) FindByID(ctx context.Context, id string) (*domain.Driver, error) {
 	var row driverRow
 	const query = `
-		SELECT id::text, first_name, last_name, license_number, deleted_at
+		SELECT id::text, first_name, last_name, license_number, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
 		FROM drivers
 		WHERE id = $1 AND deleted_at IS NULL
 	`
-	if err := r.db.Replica().GetContext(ctx, &row, query, id); err != nil {
+	if err := r.db.reader(ctx).GetContext(ctx, &row, query, id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrNotFound
 		}
@@ -52,33 +70,84 @@ func (r *DriverRepository) FindByID(ctx context.Context, id string) (*domain.Dri
 	return row.toDomain(), nil
 }
 
-// FindAll returns all non-deleted drivers, sorted by ID.
-func (r *DriverRepository) FindAll(ctx context.Context) ([]*domain.Driver, error) {
-	var rows []driverRow
+// FindDeletedByID returns a soft-deleted driver by ID.
+func (r *DriverRepository) FindDeletedByID(ctx context.Context, id string) (*domain.Driver, error) {
+	var row driverRow
 	const query = `
-		SELECT id::text, first_name, last_name, license_number, deleted_at
+		SELECT id::text, first_name, last_name, license_number, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
 		FROM drivers
-		WHERE deleted_at IS NULL
-		ORDER BY id
+		WHERE id = $1
 	`
-	if err := r.db.Replica().SelectContext(ctx, &rows, query); err != nil {
+	if err := r.db.reader(ctx).GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	if row.DeletedAt == nil {
+		return nil, fmt.Errorf("%w: entity is not deleted", domain.ErrConflict)
+	}
+	return row.toDomain(), nil
+}
+
+var driverListSpec = &listSpec[driverRow]{
+	sorts: map[string]sortColumn[driverRow]{
+		"id":             {listColumn{"id", "uuid"}, func(r *driverRow) string { return r.ID }},
+		"first_name":     {listColumn{"first_name", "text"}, func(r *driverRow) string { return r.FirstName }},
+		"last_name":      {listColumn{"last_name", "text"}, func(r *driverRow) string { return r.LastName }},
+		"license_number": {listColumn{"license_number", "text"}, func(r *driverRow) string { return r.LicenseNumber }},
+	},
+	filters: map[string]listColumn{
+		"first_name":     {"first_name", "text"},
+		"last_name":      {"last_name", "text"},
+		"license_number": {"license_number", "text"},
+	},
+	defaultSort: "id",
+	id:          func(r *driverRow) string { return r.ID },
+}
+
+// FindAll returns a page of non-deleted drivers, sorted by ID unless q says otherwise.
+func (r *DriverRepository) FindAll(ctx context.Context, q ports.ListQuery) (*ports.Page[*domain.Driver], error) {
+	st, err := driverListSpec.build(q, nil)
+	if err != nil {
 		return nil, err
 	}
-	result := make([]*domain.Driver, len(rows))
-	for i := range rows {
-		result[i] = rows[i].toDomain()
+	var rows []driverRow
+	query := `
+		SELECT id::text, first_name, last_name, license_number, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
+		FROM drivers
+		WHERE deleted_at IS NULL` + st.clause
+	if err := r.db.reader(ctx).SelectContext(ctx, &rows, query, st.args...); err != nil {
+		return nil, err
 	}
-	return result, nil
+	return toPage(driverListSpec, st, rows, (*driverRow).toDomain), nil
 }
 
-// SoftDelete marks a driver as deleted.
-func (r *DriverRepository) SoftDelete(ctx context.Context, id string) error {
+// ExistsByLicenseNumber reports whether a non-deleted driver other than excludeID has the given license number.
+func (r *DriverRepository) ExistsByLicenseNumber(ctx context.Context, licenseNumber, excludeID string) (bool, error) {
+	var exists bool
+	const query = `
+		SELECT EXISTS (
+			SELECT 1 FROM drivers
+			WHERE license_number = $1 AND ($2 = '' OR id::text != $2) AND deleted_at IS NULL
+		)
+	`
+	if err := r.db.reader(ctx).GetContext(ctx, &exists, query, licenseNumber, excludeID); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// SoftDelete marks a driver as deleted by d.
+func (r *DriverRepository) SoftDelete(ctx context.Context, id string, d ports.Deletion) error {
 	const query = `
 		UPDATE drivers
-		SET deleted_at = NOW()
+		SET deleted_at = $2, deleted_by = $3, delete_reason = $4, deletion_id = $5, version = version + 1
 		WHERE id = $1 AND deleted_at IS NULL
 	`
-	res, err := r.db.Master().ExecContext(ctx, query, id)
+	res, err := r.db.writer(ctx).ExecContext(ctx, query, id, d.At, d.By, d.Reason, d.ID)
 	if err != nil {
 		return err
 	}
@@ -89,7 +158,7 @@ func (r *DriverRepository) SoftDelete(ctx context.Context, id string) error {
 	if n == 0 {
 		var n2 int
 		const checkQuery = `SELECT 1 FROM drivers WHERE id = $1 AND deleted_at IS NOT NULL`
-		switch err := r.db.Master().GetContext(ctx, &n2, checkQuery, id); {
+		switch err := r.db.writer(ctx).GetContext(ctx, &n2, checkQuery, id); {
 		case err == nil:
 			return domain.ErrAlreadyDeleted
 		case errors.Is(err, sql.ErrNoRows):
@@ -102,11 +171,16 @@ func (r *DriverRepository) SoftDelete(ctx context.Context, id string) error {
 }
 
 // Undelete restores a soft-deleted driver.
-func (r *DriverRepository) Undelete(ctx context.Context, id string) error {
-	const query = `UPDATE drivers SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
-	res, err := r.db.Master().ExecContext(ctx, query, id)
+func (r *DriverRepository) Undelete(ctx context.Context, id string, at time.Time, by string) error {
+	const query = `
+		UPDATE drivers
+		SET deleted_at = NULL, deleted_by = '', delete_reason = '', deletion_id = NULL,
+			updated_at = $2, updated_by = $3, version = version + 1
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+	res, err := r.db.writer(ctx).ExecContext(ctx, query, id, at, by)
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 	n, err := res.RowsAffected()
 	if err != nil {
@@ -115,7 +189,7 @@ func (r *DriverRepository) Undelete(ctx context.Context, id string) error {
 	if n == 0 {
 		var n2 int
 		const checkQuery = `SELECT 1 FROM drivers WHERE id = $1 AND deleted_at IS NULL`
-		switch err := r.db.Master().GetContext(ctx, &n2, checkQuery, id); {
+		switch err := r.db.writer(ctx).GetContext(ctx, &n2, checkQuery, id); {
 		case err == nil:
 			return fmt.Errorf("%w: entity is not deleted", domain.ErrConflict)
 		case errors.Is(err, sql.ErrNoRows):
@@ -1,39 +1,109 @@
 package postgres
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 )
 
 type legalEntityRow struct {
-	ID        string     `db:"id"`
-	Name      string     `db:"name"`
-	TaxID     string     `db:"tax_id"`
-	DeletedAt *time.Time `db:"deleted_at"`
+	ID           string     `db:"id"`
+	Name         string     `db:"name"`
+	TaxID        string     `db:"tax_id"`
+	Version      int64      `db:"version"`
+	CreatedAt    time.Time  `db:"created_at"`
+	CreatedBy    string     `db:"created_by"`
+	UpdatedAt    time.Time  `db:"updated_at"`
+	UpdatedBy    string     `db:"updated_by"`
+	DeletedAt    *time.Time `db:"deleted_at"`
+	DeletedBy    string     `db:"deleted_by"`
+	DeleteReason string     `db:"delete_reason"`
+	DeletionID   *string    `db:"deletion_id"`
 }
 
 func (r *legalEntityRow) toDomain() *domain.LegalEntity {
-	return &domain.LegalEntity{ID: r.ID, Name: r.Name, TaxID: r.TaxID, DeletedAt: r.DeletedAt}
+	return &domain.LegalEntity{
+		ID:           r.ID,
+		Name:         r.Name,
+		TaxID:        r.TaxID,
+		Version:      r.Version,
+		CreatedAt:    r.CreatedAt,
+		CreatedBy:    r.CreatedBy,
+		UpdatedAt:    r.UpdatedAt,
+		UpdatedBy:    r.UpdatedBy,
+		DeletedAt:    r.DeletedAt,
+		DeletedBy:    r.DeletedBy,
+		DeleteReason: r.DeleteReason,
+		DeletionID:   stringValue(r.DeletionID),
+	}
 }
 
 func legalEntityToRow(e *domain.LegalEntity) *legalEntityRow {
-	return &legalEntityRow{ID: e.ID, Name: e.Name, TaxID: e.TaxID, DeletedAt: e.DeletedAt}
+	return &legalEntityRow{
+		ID:           e.ID,
+		Name:         e.Name,
+		TaxID:        e.TaxID,
+		Version:      e.Version,
+		CreatedAt:    e.CreatedAt,
+		CreatedBy:    e.CreatedBy,
+		UpdatedAt:    e.UpdatedAt,
+		UpdatedBy:    e.UpdatedBy,
+		DeletedAt:    e.DeletedAt,
+		DeletedBy:    e.DeletedBy,
+		DeleteReason: e.DeleteReason,
+	}
 }
 
 type fleetRow struct {
 	ID            string     `db:"id"`
 	LegalEntityID string     `db:"legal_entity_id"`
 	Name          string     `db:"name"`
+	Version       int64      `db:"version"`
+	CreatedAt     time.Time  `db:"created_at"`
+	CreatedBy     string     `db:"created_by"`
+	UpdatedAt     time.Time  `db:"updated_at"`
+	UpdatedBy     string     `db:"updated_by"`
 	DeletedAt     *time.Time `db:"deleted_at"`
+	DeletedBy     string     `db:"deleted_by"`
+	DeleteReason  string     `db:"delete_reason"`
+	DeletionID    *string    `db:"deletion_id"`
 }
 
 func (r *fleetRow) toDomain() *domain.Fleet {
-	return &domain.Fleet{ID: r.ID, LegalEntityID: r.LegalEntityID, Name: r.Name, DeletedAt: r.DeletedAt}
+	return &domain.Fleet{
+		ID:            r.ID,
+		LegalEntityID: r.LegalEntityID,
+		Name:          r.Name,
+		Version:       r.Version,
+		CreatedAt:     r.CreatedAt,
+		CreatedBy:     r.CreatedBy,
+		UpdatedAt:     r.UpdatedAt,
+		UpdatedBy:     r.UpdatedBy,
+		DeletedAt:     r.DeletedAt,
+		DeletedBy:     r.DeletedBy,
+		DeleteReason:  r.DeleteReason,
+		DeletionID:    stringValue(r.DeletionID),
+	}
 }
 
 func fleetToRow(e *domain.Fleet) *fleetRow {
-	return &fleetRow{ID: e.ID, LegalEntityID: e.LegalEntityID, Name: e.Name, DeletedAt: e.DeletedAt}
+	return &fleetRow{
+		ID:            e.ID,
+		LegalEntityID: e.LegalEntityID,
+		Name:          e.Name,
+		Version:       e.Version,
+		CreatedAt:     e.CreatedAt,
+		CreatedBy:     e.CreatedBy,
+		UpdatedAt:     e.UpdatedAt,
+		UpdatedBy:     e.UpdatedBy,
+		DeletedAt:     e.DeletedAt,
+		DeletedBy:     e.DeletedBy,
+		DeleteReason:  e.DeleteReason,
+	}
 }
 
 type vehicleRow struct {
@@ -43,7 +113,15 @@ type vehicleRow struct {
 	Model        string     `db:"model"`
 	Year         int        `db:"year"`
 	LicensePlate string     `db:"license_plate"`
+	Version      int64      `db:"version"`
+	CreatedAt    time.Time  `db:"created_at"`
+	CreatedBy    string     `db:"created_by"`
+	UpdatedAt    time.Time  `db:"updated_at"`
+	UpdatedBy    string     `db:"updated_by"`
 	DeletedAt    *time.Time `db:"deleted_at"`
+	DeletedBy    string     `db:"deleted_by"`
+	DeleteReason string     `db:"delete_reason"`
+	DeletionID   *string    `db:"deletion_id"`
 }
 
 func (r *vehicleRow) toDomain() *domain.Vehicle {
@@ -54,14 +132,34 @@ func (r *vehicleRow) toDomain() *domain.Vehicle {
 		Model:        r.Model,
 		Year:         r.Year,
 		LicensePlate: r.LicensePlate,
+		Version:      r.Version,
+		CreatedAt:    r.CreatedAt,
+		CreatedBy:    r.CreatedBy,
+		UpdatedAt:    r.UpdatedAt,
+		UpdatedBy:    r.UpdatedBy,
 		DeletedAt:    r.DeletedAt,
+		DeletedBy:    r.DeletedBy,
+		DeleteReason: r.DeleteReason,
+		DeletionID:   stringValue(r.DeletionID),
 	}
 }
 
 func vehicleToRow(e *domain.Vehicle) *vehicleRow {
 	return &vehicleRow{
-		ID: e.ID, FleetID: e.FleetID, Make: e.Make, Model: e.Model,
-		Year: e.Year, LicensePlate: e.LicensePlate, DeletedAt: e.DeletedAt,
+		ID:           e.ID,
+		FleetID:      e.FleetID,
+		Make:         e.Make,
+		Model:        e.Model,
+		Year:         e.Year,
+		LicensePlate: e.LicensePlate,
+		Version:      e.Version,
+		CreatedAt:    e.CreatedAt,
+		CreatedBy:    e.CreatedBy,
+		UpdatedAt:    e.UpdatedAt,
+		UpdatedBy:    e.UpdatedBy,
+		DeletedAt:    e.DeletedAt,
+		DeletedBy:    e.DeletedBy,
+		DeleteReason: e.DeleteReason,
 	}
 }
 
@@ -70,20 +168,49 @@ type driverRow struct {
 	FirstName     string     `db:"first_name"`
 	LastName      string     `db:"last_name"`
 	LicenseNumber string     `db:"license_number"`
+	Version       int64      `db:"version"`
+	CreatedAt     time.Time  `db:"created_at"`
+	CreatedBy     string     `db:"created_by"`
+	UpdatedAt     time.Time  `db:"updated_at"`
+	UpdatedBy     string     `db:"updated_by"`
 	DeletedAt     *time.Time `db:"deleted_at"`
+	DeletedBy     string     `db:"deleted_by"`
+	DeleteReason  string     `db:"delete_reason"`
+	DeletionID    *string    `db:"deletion_id"`
 }
 
 func (r *driverRow) toDomain() *domain.Driver {
 	return &domain.Driver{
-		ID: r.ID, FirstName: r.FirstName, LastName: r.LastName,
-		LicenseNumber: r.LicenseNumber, DeletedAt: r.DeletedAt,
+		ID:            r.ID,
+		FirstName:     r.FirstName,
+		LastName:      r.LastName,
+		LicenseNumber: r.LicenseNumber,
+		Version:       r.Version,
+		CreatedAt:     r.CreatedAt,
+		CreatedBy:     r.CreatedBy,
+		UpdatedAt:     r.UpdatedAt,
+		UpdatedBy:     r.UpdatedBy,
+		DeletedAt:     r.DeletedAt,
+		DeletedBy:     r.DeletedBy,
+		DeleteReason:  r.DeleteReason,
+		DeletionID:    stringValue(r.DeletionID),
 	}
 }
 
 func driverToRow(e *domain.Driver) *driverRow {
 	return &driverRow{
-		ID: e.ID, FirstName: e.FirstName, LastName: e.LastName,
-		LicenseNumber: e.LicenseNumber, DeletedAt: e.DeletedAt,
+		ID:            e.ID,
+		FirstName:     e.FirstName,
+		LastName:      e.LastName,
+		LicenseNumber: e.LicenseNumber,
+		Version:       e.Version,
+		CreatedAt:     e.CreatedAt,
+		CreatedBy:     e.CreatedBy,
+		UpdatedAt:     e.UpdatedAt,
+		UpdatedBy:     e.UpdatedBy,
+		DeletedAt:     e.DeletedAt,
+		DeletedBy:     e.DeletedBy,
+		DeleteReason:  e.DeleteReason,
 	}
 }
 
@@ -96,45 +223,461 @@ type contractRow struct {
 	EndDate       time.Time  `db:"end_date"`
 	TerminatedAt  *time.Time `db:"terminated_at"`
 	TerminatedBy  string     `db:"terminated_by"`
+	Version       int64      `db:"version"`
+	CreatedAt     time.Time  `db:"created_at"`
+	CreatedBy     string     `db:"created_by"`
+	UpdatedAt     time.Time  `db:"updated_at"`
+	UpdatedBy     string     `db:"updated_by"`
 	DeletedAt     *time.Time `db:"deleted_at"`
+	DeletedBy     string     `db:"deleted_by"`
+	DeleteReason  string     `db:"delete_reason"`
+	DeletionID    *string    `db:"deletion_id"`
 }
 
 func (r *contractRow) toDomain() *domain.Contract {
 	return &domain.Contract{
-		ID: r.ID, DriverID: r.DriverID, LegalEntityID: r.LegalEntityID, FleetID: r.FleetID,
-		StartDate: r.StartDate, EndDate: r.EndDate,
-		TerminatedAt: r.TerminatedAt, TerminatedBy: r.TerminatedBy, DeletedAt: r.DeletedAt,
+		ID:            r.ID,
+		DriverID:      r.DriverID,
+		LegalEntityID: r.LegalEntityID,
+		FleetID:       r.FleetID,
+		StartDate:     r.StartDate,
+		EndDate:       r.EndDate,
+		TerminatedAt:  r.TerminatedAt,
+		TerminatedBy:  r.TerminatedBy,
+		Version:       r.Version,
+		CreatedAt:     r.CreatedAt,
+		CreatedBy:     r.CreatedBy,
+		UpdatedAt:     r.UpdatedAt,
+		UpdatedBy:     r.UpdatedBy,
+		DeletedAt:     r.DeletedAt,
+		DeletedBy:     r.DeletedBy,
+		DeleteReason:  r.DeleteReason,
+		DeletionID:    stringValue(r.DeletionID),
 	}
 }
 
 func contractToRow(e *domain.Contract) *contractRow {
 	return &contractRow{
-		ID: e.ID, DriverID: e.DriverID, LegalEntityID: e.LegalEntityID, FleetID: e.FleetID,
-		StartDate: e.StartDate, EndDate: e.EndDate,
-		TerminatedAt: e.TerminatedAt, TerminatedBy: e.TerminatedBy, DeletedAt: e.DeletedAt,
+		ID:            e.ID,
+		DriverID:      e.DriverID,
+		LegalEntityID: e.LegalEntityID,
+		FleetID:       e.FleetID,
+		StartDate:     e.StartDate,
+		EndDate:       e.EndDate,
+		TerminatedAt:  e.TerminatedAt,
+		TerminatedBy:  e.TerminatedBy,
+		Version:       e.Version,
+		CreatedAt:     e.CreatedAt,
+		CreatedBy:     e.CreatedBy,
+		UpdatedAt:     e.UpdatedAt,
+		UpdatedBy:     e.UpdatedBy,
+		DeletedAt:     e.DeletedAt,
+		DeletedBy:     e.DeletedBy,
+		DeleteReason:  e.DeleteReason,
 	}
 }
 
 type vehicleAssignmentRow struct {
-	ID         string     `db:"id"`
-	DriverID   string     `db:"driver_id"`
-	VehicleID  string     `db:"vehicle_id"`
-	ContractID string     `db:"contract_id"`
-	StartTime  time.Time  `db:"start_time"`
-	EndTime    *time.Time `db:"end_time"`
-	DeletedAt  *time.Time `db:"deleted_at"`
+	ID           string     `db:"id"`
+	DriverID     string     `db:"driver_id"`
+	VehicleID    string     `db:"vehicle_id"`
+	ContractID   string     `db:"contract_id"`
+	StartTime    time.Time  `db:"start_time"`
+	EndTime      *time.Time `db:"end_time"`
+	Version      int64      `db:"version"`
+	CreatedAt    time.Time  `db:"created_at"`
+	CreatedBy    string     `db:"created_by"`
+	UpdatedAt    time.Time  `db:"updated_at"`
+	UpdatedBy    string     `db:"updated_by"`
+	DeletedAt    *time.Time `db:"deleted_at"`
+	DeletedBy    string     `db:"deleted_by"`
+	DeleteReason string     `db:"delete_reason"`
+	DeletionID   *string    `db:"deletion_id"`
 }
 
 func (r *vehicleAssignmentRow) toDomain() *domain.VehicleAssignment {
 	return &domain.VehicleAssignment{
-		ID: r.ID, DriverID: r.DriverID, VehicleID: r.VehicleID, ContractID: r.ContractID,
-		StartTime: r.StartTime, EndTime: r.EndTime, DeletedAt: r.DeletedAt,
+		ID:           r.ID,
+		DriverID:     r.DriverID,
+		VehicleID:    r.VehicleID,
+		ContractID:   r.ContractID,
+		StartTime:    r.StartTime,
+		EndTime:      r.EndTime,
+		Version:      r.Version,
+		CreatedAt:    r.CreatedAt,
+		CreatedBy:    r.CreatedBy,
+		UpdatedAt:    r.UpdatedAt,
+		UpdatedBy:    r.UpdatedBy,
+		DeletedAt:    r.DeletedAt,
+		DeletedBy:    r.DeletedBy,
+		DeleteReason: r.DeleteReason,
+		DeletionID:   stringValue(r.DeletionID),
 	}
 }
 
 func vehicleAssignmentToRow(e *domain.VehicleAssignment) *vehicleAssignmentRow {
 	return &vehicleAssignmentRow{
-		ID: e.ID, DriverID: e.DriverID, VehicleID: e.VehicleID, ContractID: e.ContractID,
-		StartTime: e.StartTime, EndTime: e.EndTime, DeletedAt: e.DeletedAt,
+		ID:           e.ID,
+		DriverID:     e.DriverID,
+		VehicleID:    e.VehicleID,
+		ContractID:   e.ContractID,
+		StartTime:    e.StartTime,
+		EndTime:      e.EndTime,
+		Version:      e.Version,
+		CreatedAt:    e.CreatedAt,
+		CreatedBy:    e.CreatedBy,
+		UpdatedAt:    e.UpdatedAt,
+		UpdatedBy:    e.UpdatedBy,
+		DeletedAt:    e.DeletedAt,
+		DeletedBy:    e.DeletedBy,
+		DeleteReason: e.DeleteReason,
+	}
+}
+
+type auditEntryRow struct {
+	ID         string    `db:"id"`
+	EntityType string    `db:"entity_type"`
+	EntityID   string    `db:"entity_id"`
+	Action     string    `db:"action"`
+	Actor      string    `db:"actor"`
+	At         time.Time `db:"at"`
+	DeletionID *string   `db:"deletion_id"`
+	Before     *string   `db:"before"`
+	After      *string   `db:"after"`
+}
+
+func (r *auditEntryRow) toDomain() *domain.AuditEntry {
+	return &domain.AuditEntry{
+		ID:         r.ID,
+		EntityType: domain.EntityType(r.EntityType),
+		EntityID:   r.EntityID,
+		Action:     domain.AuditAction(r.Action),
+		Actor:      r.Actor,
+		At:         r.At,
+		DeletionID: stringValue(r.DeletionID),
+		Before:     rawJSON(r.Before),
+		After:      rawJSON(r.After),
+	}
+}
+
+func auditEntryToRow(e *domain.AuditEntry) (*auditEntryRow, error) {
+	before, err := jsonValue(e.Before)
+	if err != nil {
+		return nil, err
+	}
+	after, err := jsonValue(e.After)
+	if err != nil {
+		return nil, err
+	}
+	return &auditEntryRow{
+		ID:         e.ID,
+		EntityType: string(e.EntityType),
+		EntityID:   e.EntityID,
+		Action:     string(e.Action),
+		Actor:      e.Actor,
+		At:         e.At,
+		DeletionID: nullString(e.DeletionID),
+		Before:     before,
+		After:      after,
+	}, nil
+}
+
+type outboxRow struct {
+	ID         string    `db:"id"`
+	EventType  string    `db:"event_type"`
+	Payload    string    `db:"payload"`
+	OccurredAt time.Time `db:"occurred_at"`
+	Attempts   int       `db:"attempts"`
+}
+
+func (r *outboxRow) toMessage() *ports.OutboxMessage {
+	return &ports.OutboxMessage{
+		Event: &domain.Event{
+			ID:         r.ID,
+			Type:       domain.EventType(r.EventType),
+			OccurredAt: r.OccurredAt,
+			Payload:    json.RawMessage(r.Payload),
+		},
+		Attempts: r.Attempts,
+	}
+}
+
+func outboxToRow(e *domain.Event) (*outboxRow, error) {
+	payload, err := json.Marshal(e.Payload)
+	if err != nil {
+		return nil, err
+	}
+	return &outboxRow{
+		ID:         e.ID,
+		EventType:  string(e.Type),
+		Payload:    string(payload),
+		OccurredAt: e.OccurredAt,
+	}, nil
+}
+
+// toDomainAll converts rows to domain entities with toDomain.
+func toDomainAll[R any, T any](rows []R, toDomain func(*R) T) []T {
+	result := make([]T, len(rows))
+	for i := range rows {
+		result[i] = toDomain(&rows[i])
+	}
+	return result
+}
+
+// jsonValue encodes v for a nullable JSONB column.
+func jsonValue(v any) (*string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	s := string(b)
+	return &s, nil
+}
+
+// rawJSON returns the content of a nullable JSONB column, keeping nil as an untyped nil.
+func rawJSON(s *string) any {
+	if s == nil {
+		return nil
+	}
+	return json.RawMessage(*s)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// nullString maps the empty string to NULL.
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+type webhookSubscriptionRow struct {
+	ID                  string     `db:"id"`
+	URL                 string     `db:"url"`
+	EventTypes          eventTypes `db:"event_types"`
+	Secret              string     `db:"secret"`
+	Active              bool       `db:"active"`
+	ConsecutiveFailures int        `db:"consecutive_failures"`
+	DisabledAt          *time.Time `db:"disabled_at"`
+	Version             int64      `db:"version"`
+	CreatedAt           time.Time  `db:"created_at"`
+	CreatedBy           string     `db:"created_by"`
+	UpdatedAt           time.Time  `db:"updated_at"`
+	UpdatedBy           string     `db:"updated_by"`
+}
+
+func (r *webhookSubscriptionRow) toDomain() *domain.WebhookSubscription {
+	return &domain.WebhookSubscription{
+		ID:                  r.ID,
+		URL:                 r.URL,
+		EventTypes:          r.EventTypes,
+		Secret:              r.Secret,
+		Active:              r.Active,
+		ConsecutiveFailures: r.ConsecutiveFailures,
+		DisabledAt:          r.DisabledAt,
+		Version:             r.Version,
+		CreatedAt:           r.CreatedAt,
+		CreatedBy:           r.CreatedBy,
+		UpdatedAt:           r.UpdatedAt,
+		UpdatedBy:           r.UpdatedBy,
+	}
+}
+
+func webhookSubscriptionToRow(s *domain.WebhookSubscription) *webhookSubscriptionRow {
+	return &webhookSubscriptionRow{
+		ID:                  s.ID,
+		URL:                 s.URL,
+		EventTypes:          s.EventTypes,
+		Secret:              s.Secret,
+		Active:              s.Active,
+		ConsecutiveFailures: s.ConsecutiveFailures,
+		DisabledAt:          s.DisabledAt,
+		Version:             s.Version,
+		CreatedAt:           s.CreatedAt,
+		CreatedBy:           s.CreatedBy,
+		UpdatedAt:           s.UpdatedAt,
+		UpdatedBy:           s.UpdatedBy,
+	}
+}
+
+// eventTypes maps a list of event types to a JSONB array.
+type eventTypes []domain.EventType
+
+func (t eventTypes) Value() (driver.Value, error) {
+	b, err := json.Marshal([]domain.EventType(t))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (t *eventTypes) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), (*[]domain.EventType)(t))
+	case []byte:
+		return json.Unmarshal(v, (*[]domain.EventType)(t))
+	default:
+		return fmt.Errorf("cannot scan %T into event types", src)
+	}
+}
+
+type webhookDeliveryRow struct {
+	ID             string     `db:"id"`
+	SubscriptionID string     `db:"subscription_id"`
+	EventID        string     `db:"event_id"`
+	EventType      string     `db:"event_type"`
+	Payload        string     `db:"payload"`
+	OccurredAt     time.Time  `db:"occurred_at"`
+	Status         string     `db:"status"`
+	Attempts       int        `db:"attempts"`
+	LastStatusCode int        `db:"last_status_code"`
+	LastError      string     `db:"last_error"`
+	NextAttemptAt  time.Time  `db:"next_attempt_at"`
+	CreatedAt      time.Time  `db:"created_at"`
+	DeliveredAt    *time.Time `db:"delivered_at"`
+}
+
+func (r *webhookDeliveryRow) toDomain() *domain.WebhookDelivery {
+	return &domain.WebhookDelivery{
+		ID:             r.ID,
+		SubscriptionID: r.SubscriptionID,
+		Event: domain.Event{
+			ID:         r.EventID,
+			Type:       domain.EventType(r.EventType),
+			OccurredAt: r.OccurredAt,
+			Payload:    json.RawMessage(r.Payload),
+		},
+		Status:         domain.WebhookDeliveryStatus(r.Status),
+		Attempts:       r.Attempts,
+		LastStatusCode: r.LastStatusCode,
+		LastError:      r.LastError,
+		NextAttemptAt:  r.NextAttemptAt,
+		CreatedAt:      r.CreatedAt,
+		DeliveredAt:    r.DeliveredAt,
+	}
+}
+
+func webhookDeliveryToRow(d *domain.WebhookDelivery) (*webhookDeliveryRow, error) {
+	payload, err := json.Marshal(d.Event.Payload)
+	if err != nil {
+		return nil, err
+	}
+	return &webhookDeliveryRow{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.Event.ID,
+		EventType:      string(d.Event.Type),
+		Payload:        string(payload),
+		OccurredAt:     d.Event.OccurredAt,
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		NextAttemptAt:  d.NextAttemptAt,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}, nil
+}
+
+type apiKeyRow struct {
+	ID             string     `db:"id"`
+	Name           string     `db:"name"`
+	KeyHash        string     `db:"key_hash"`
+	PrincipalID    string     `db:"principal_id"`
+	Role           string     `db:"role"`
+	LegalEntityIDs stringList `db:"legal_entity_ids"`
+	CreatedAt      time.Time  `db:"created_at"`
+	ExpiresAt      *time.Time `db:"expires_at"`
+	RevokedAt      *time.Time `db:"revoked_at"`
+}
+
+func (r *apiKeyRow) toDomain() *domain.APIKey {
+	return &domain.APIKey{
+		ID:             r.ID,
+		Name:           r.Name,
+		KeyHash:        r.KeyHash,
+		PrincipalID:    r.PrincipalID,
+		Role:           domain.Role(r.Role),
+		LegalEntityIDs: r.LegalEntityIDs,
+		CreatedAt:      r.CreatedAt,
+		ExpiresAt:      r.ExpiresAt,
+		RevokedAt:      r.RevokedAt,
+	}
+}
+
+// stringList maps a list of strings to a JSONB array.
+type stringList []string
+
+func (l *stringList) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), (*[]string)(l))
+	case []byte:
+		return json.Unmarshal(v, (*[]string)(l))
+	default:
+		return fmt.Errorf("cannot scan %T into string list", src)
+	}
+}
+
+type idempotencyRow struct {
+	PrincipalID string     `db:"principal_id"`
+	Key         string     `db:"key"`
+	Fingerprint string     `db:"fingerprint"`
+	StatusCode  *int       `db:"status_code"`
+	Header      httpHeader `db:"header"`
+	Body        []byte     `db:"body"`
+	CreatedAt   time.Time  `db:"created_at"`
+	ExpiresAt   time.Time  `db:"expires_at"`
+}
+
+func (r *idempotencyRow) toDomain() *domain.IdempotencyRecord {
+	rec := &domain.IdempotencyRecord{
+		PrincipalID: r.PrincipalID,
+		Key:         r.Key,
+		Fingerprint: r.Fingerprint,
+		CreatedAt:   r.CreatedAt,
+		ExpiresAt:   r.ExpiresAt,
+	}
+	if r.StatusCode != nil {
+		rec.Response = &domain.IdempotentResponse{StatusCode: *r.StatusCode, Header: r.Header, Body: r.Body}
+	}
+	return rec
+}
+
+// httpHeader maps response headers to a nullable JSONB object.
+type httpHeader map[string][]string
+
+func (h httpHeader) Value() (driver.Value, error) {
+	if h == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(map[string][]string(h))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (h *httpHeader) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*h = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), (*map[string][]string)(h))
+	case []byte:
+		return json.Unmarshal(v, (*map[string][]string)(h))
+	default:
+		return fmt.Errorf("cannot scan %T into header", src)
 	}
 }
@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+)
+
+const (
+	pgUniqueViolation    = "23505"
+	pgExclusionViolation = "23P01"
+)
+
+// constraintErrors maps database constraints enforcing business rules to the domain
+// errors reported when a write violates them.
+var constraintErrors = map[string]error{
+	"excl_contracts_overlap":        fmt.Errorf("%w: contract dates overlap with existing contract", domain.ErrConflict),
+	"uq_vehicle_assignments_active": domain.ErrDriverAlreadyAssignedInFleet,
+	"uq_legal_entities_tax_id":      &domain.UniqueViolationError{Field: "tax_id"},
+	"uq_drivers_license_number":     &domain.UniqueViolationError{Field: "license_number"},
+	"uq_vehicles_license_plate":     &domain.UniqueViolationError{Field: "license_plate"},
+}
+
+// translateError converts unique and exclusion constraint violations into domain errors.
+// Other errors are returned unchanged.
+func translateError(err error) error {
+	pgErr, ok := errors.AsType[*pgconn.PgError](err)
+	if !ok {
+		return err
+	}
+	switch pgErr.Code {
+	case pgUniqueViolation, pgExclusionViolation:
+		if mapped, ok := constraintErrors[pgErr.ConstraintName]; ok {
+			return mapped
+		}
+		return domain.ErrConflict
+	default:
+		return err
+	}
+}
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+)
+
+func TestTranslateError(t *testing.T) {
+	plain := errors.New("connection reset")
+	foreignKey := &pgconn.PgError{Code: "23503", ConstraintName: "contracts_driver_id_fkey"}
+	// Only unique and exclusion violations are mapped, whatever the constraint is named.
+	foreignKeyNamedLikeUnique := &pgconn.PgError{Code: "23503", ConstraintName: "uq_legal_entities_tax_id"}
+
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		field  string
+	}{
+		{"unique tax id", &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "uq_legal_entities_tax_id"}, domain.ErrDuplicateValue, "tax_id"},
+		{"unique license number", &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "uq_drivers_license_number"}, domain.ErrDuplicateValue, "license_number"},
+		{"unique license plate", &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "uq_vehicles_license_plate"}, domain.ErrDuplicateValue, "license_plate"},
+		{"active assignment", &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "uq_vehicle_assignments_active"}, domain.ErrDriverAlreadyAssignedInFleet, ""},
+		{"contract overlap", &pgconn.PgError{Code: pgExclusionViolation, ConstraintName: "excl_contracts_overlap"}, domain.ErrConflict, ""},
+		{"unknown unique constraint", &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "outbox_pkey"}, domain.ErrConflict, ""},
+		{"unknown exclusion constraint", &pgconn.PgError{Code: pgExclusionViolation, ConstraintName: "excl_other"}, domain.ErrConflict, ""},
+		{"wrapped violation", fmt.Errorf("save: %w", &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "uq_drivers_license_number"}), domain.ErrDuplicateValue, "license_number"},
+		{"foreign key violation", foreignKey, foreignKey, ""},
+		{"known name with other code", foreignKeyNamedLikeUnique, foreignKeyNamedLikeUnique, ""},
+		{"not a postgres error", plain, plain, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := translateError(tt.err)
+			assert.ErrorIs(t, got, tt.target)
+			if uv, ok := errors.AsType[*domain.UniqueViolationError](got); tt.field != "" {
+				if assert.True(t, ok) {
+					assert.Equal(t, tt.field, uv.Field)
+				}
+			} else {
+				assert.False(t, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+)
+
+const (
+	// eventChannel is the NOTIFY channel the outbox trigger publishes event IDs on.
+	eventChannel = "outbox_events"
+	// feedBufferSize is the number of events a subscriber may lag behind before it is dropped.
+	feedBufferSize = 64
+	// feedPageSize is the number of events read from the outbox at a time.
+	feedPageSize = 100
+	// feedPollInterval is how often the outbox is read without a notification, to pick up
+	// events held back by a transaction that enqueued none.
+	feedPollInterval = time.Second
+	// reconnectDelay is the pause before listening again after the connection was lost.
+	reconnectDelay = time.Second
+)
+
+// EventFeed implements ports.EventFeed with LISTEN/NOTIFY: every instance of the service
+// listens on its own connection to the master and learns about events committed by any
+// of them. Notifications carry nothing; the events are read from the outbox in outbox
+// order, following the last one broadcast.
+type EventFeed struct {
+	db     *DB
+	url    string
+	logger *zap.Logger
+
+	mu   sync.Mutex
+	subs map[chan *domain.Event]struct{}
+}
+
+// NewEventFeed creates an EventFeed listening on a dedicated connection to url.
+// It does not listen before Run is called.
+func NewEventFeed(db *DB, url string, logger *zap.Logger) *EventFeed {
+	return &EventFeed{
+		db:     db,
+		url:    url,
+		logger: logger,
+		subs:   make(map[chan *domain.Event]struct{}),
+	}
+}
+
+// Subscribe registers a subscriber until ctx is done.
+func (f *EventFeed) Subscribe(ctx context.Context) (<-chan *domain.Event, error) {
+	ch := make(chan *domain.Event, feedBufferSize)
+	f.mu.Lock()
+	f.subs[ch] = struct{}{}
+	f.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		f.drop(ch)
+	}()
+	return ch, nil
+}
+
+// Run listens for events until ctx is done, reconnecting after failures. Subscribers are
+// dropped whenever the connection is lost, as events may be missed until it is back.
+func (f *EventFeed) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := f.listen(ctx)
+		f.dropAll()
+		if ctx.Err() != nil {
+			return
+		}
+		f.logger.Error("Event feed connection lost", zap.Error(err))
+		select {
+		case <-ctx.Done():
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (f *EventFeed) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, f.url)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background()) //nolint:errcheck // the connection is discarded anyway
+
+	if _, err := conn.Exec(ctx, "LISTEN "+eventChannel); err != nil {
+		return err
+	}
+	pos, err := headPosition(ctx, f.db.writer(ctx))
+	if err != nil {
+		return err
+	}
+	for {
+		waitCtx, cancel := context.WithTimeout(ctx, feedPollInterval)
+		_, err := conn.WaitForNotification(waitCtx)
+		cancel()
+		if err != nil && (ctx.Err() != nil || !pgconn.Timeout(err)) {
+			return err
+		}
+		if pos, err = f.drain(ctx, pos); err != nil {
+			return err
+		}
+	}
+}
+
+// drain broadcasts the events following pos and returns the position of the last one.
+func (f *EventFeed) drain(ctx context.Context, pos outboxPosition) (outboxPosition, error) {
+	for {
+		rows, err := eventsAfter(ctx, f.db.writer(ctx), pos, feedPageSize)
+		if err != nil {
+			return pos, err
+		}
+		for i := range rows {
+			f.broadcast(rows[i].toMessage().Event)
+			pos = rows[i].outboxPosition
+		}
+		if len(rows) < feedPageSize {
+			return pos, nil
+		}
+	}
+}
+
+// broadcast hands event to every subscriber. A subscriber whose buffer is full is
+// dropped rather than allowed to hold up the others.
+func (f *EventFeed) broadcast(event *domain.Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for ch := range f.subs {
+		select {
+		case ch <- event:
+		default:
+			delete(f.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+func (f *EventFeed) drop(ch chan *domain.Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.subs[ch]; ok {
+		delete(f.subs, ch)
+		close(ch)
+	}
+}
+
+func (f *EventFeed) dropAll() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for ch := range f.subs {
+		delete(f.subs, ch)
+		close(ch)
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 )
 
 // FleetRepository implements ports.FleetRepository.
@@ -23,26 +25,42 @@ func NewFleetRepository(db *DB) *FleetRepository {
 func (r *FleetRepository) Save(ctx context.Context, entity *domain.Fleet) error {
 	row := fleetToRow(entity)
 	const query = `
-		INSERT INTO fleets (id, legal_entity_id, name, deleted_at)
-		VALUES (:id, :legal_entity_id, :name, :deleted_at)
+		INSERT INTO fleets (
+			id, legal_entity_id, name,
+			created_at, created_by, updated_at, updated_by, deleted_at
+		)
+		VALUES (
+			:id, :legal_entity_id, :name,
+			:created_at, :created_by, :updated_at, :updated_by, :deleted_at
+		)
 		ON CONFLICT (id) DO UPDATE SET
 			legal_entity_id = EXCLUDED.legal_entity_id,
 			name = EXCLUDED.name,
-			deleted_at = EXCLUDED.deleted_at
+			updated_at = EXCLUDED.updated_at,
+			updated_by = EXCLUDED.updated_by,
+			deleted_at = EXCLUDED.deleted_at,
+			version = fleets.version + 1
+		WHERE fleets.version = :version
+		RETURNING version
 	`
-	_, err := r.db.Master().NamedExecContext(ctx, query, row)
-	return err
+	version, err := saveVersioned(ctx, r.db.writer(ctx), query, row)
+	if err != nil {
+		return err
+	}
+	entity.Version = version
+	return nil
 }
 
 // FindByID returns a fleet by ID, excluding soft-deleted.
 func (r *FleetRepository) FindByID(ctx context.Context, id string) (*domain.Fleet, error) {
 	var row fleetRow
 	const query = `
-		SELECT id::text, legal_entity_id::text, name, deleted_at
+		SELECT id::text, legal_entity_id::text, name, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
 		FROM fleets
 		WHERE id = $1 AND deleted_at IS NULL
 	`
-	if err := r.db.Replica().GetContext(ctx, &row, query, id); err != nil {
+	if err := r.db.reader(ctx).GetContext(ctx, &row, query, id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrNotFound
 		}
@@ -51,33 +69,79 @@ func (r *FleetRepository) FindByID(ctx context.Context, id string) (*domain.Flee
 	return row.toDomain(), nil
 }
 
-// FindByLegalEntityID returns all non-deleted fleets for a legal entity, sorted by ID.
-func (r *FleetRepository) FindByLegalEntityID(ctx context.Context, legalEntityID string) ([]*domain.Fleet, error) {
-	var rows []fleetRow
+// FindDeletedByID returns a soft-deleted fleet by ID.
+func (r *FleetRepository) FindDeletedByID(ctx context.Context, id string) (*domain.Fleet, error) {
+	var row fleetRow
 	const query = `
-		SELECT id::text, legal_entity_id::text, name, deleted_at
+		SELECT id::text, legal_entity_id::text, name, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason, deletion_id::text
 		FROM fleets
-		WHERE legal_entity_id = $1 AND deleted_at IS NULL
-		ORDER BY id
+		WHERE id = $1
 	`
-	if err := r.db.Replica().SelectContext(ctx, &rows, query, legalEntityID); err != nil {
+	if err := r.db.reader(ctx).GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	if row.DeletedAt == nil {
+		return nil, fmt.Errorf("%w: entity is not deleted", domain.ErrConflict)
+	}
+	return row.toDomain(), nil
+}
+
+var fleetListSpec = &listSpec[fleetRow]{
+	sorts: map[string]sortColumn[fleetRow]{
+		"id":   {listColumn{"id", "uuid"}, func(r *fleetRow) string { return r.ID }},
+		"name": {listColumn{"name", "text"}, func(r *fleetRow) string { return r.Name }},
+	},
+	filters: map[string]listColumn{
+		"name": {"name", "text"},
+	},
+	defaultSort: "id",
+	id:          func(r *fleetRow) string { return r.ID },
+}
+
+// FindByLegalEntityID returns a page of non-deleted fleets for a legal entity, sorted by ID unless q says otherwise.
+func (r *FleetRepository) FindByLegalEntityID(
+	ctx context.Context,
+	legalEntityID string,
+	q ports.ListQuery,
+) (*ports.Page[*domain.Fleet], error) {
+	st, err := fleetListSpec.build(q, []any{legalEntityID})
+	if err != nil {
 		return nil, err
 	}
-	result := make([]*domain.Fleet, len(rows))
-	for i := range rows {
-		result[i] = rows[i].toDomain()
+	var rows []fleetRow
+	query := `
+		SELECT id::text, legal_entity_id::text, name, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
+		FROM fleets
+		WHERE legal_entity_id = $1 AND deleted_at IS NULL` + st.clause
+	if err := r.db.reader(ctx).SelectContext(ctx, &rows, query, st.args...); err != nil {
+		return nil, err
 	}
-	return result, nil
+	return toPage(fleetListSpec, st, rows, (*fleetRow).toDomain), nil
 }
 
-// SoftDelete marks a fleet as deleted.
-func (r *FleetRepository) SoftDelete(ctx context.Context, id string) error {
+// ExistsByLegalEntityID reports whether a legal entity has non-deleted fleets.
+func (r *FleetRepository) ExistsByLegalEntityID(ctx context.Context, legalEntityID string) (bool, error) {
+	var exists bool
+	const query = `SELECT EXISTS (SELECT 1 FROM fleets WHERE legal_entity_id = $1 AND deleted_at IS NULL)`
+	if err := r.db.reader(ctx).GetContext(ctx, &exists, query, legalEntityID); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// SoftDelete marks a fleet as deleted by d.
+func (r *FleetRepository) SoftDelete(ctx context.Context, id string, d ports.Deletion) error {
 	const query = `
 		UPDATE fleets
-		SET deleted_at = NOW()
+		SET deleted_at = $2, deleted_by = $3, delete_reason = $4, deletion_id = $5, version = version + 1
 		WHERE id = $1 AND deleted_at IS NULL
 	`
-	res, err := r.db.Master().ExecContext(ctx, query, id)
+	res, err := r.db.writer(ctx).ExecContext(ctx, query, id, d.At, d.By, d.Reason, d.ID)
 	if err != nil {
 		return err
 	}
@@ -88,7 +152,7 @@ func (r *FleetRepository) SoftDelete(ctx context.Context, id string) error {
 	if n == 0 {
 		var n2 int
 		const checkQuery = `SELECT 1 FROM fleets WHERE id = $1 AND deleted_at IS NOT NULL`
-		switch err := r.db.Master().GetContext(ctx, &n2, checkQuery, id); {
+		switch err := r.db.writer(ctx).GetContext(ctx, &n2, checkQuery, id); {
 		case err == nil:
 			return domain.ErrAlreadyDeleted
 		case errors.Is(err, sql.ErrNoRows):
@@ -100,12 +164,44 @@ func (r *FleetRepository) SoftDelete(ctx context.Context, id string) error {
 	return nil
 }
 
+// SoftDeleteByLegalEntityID marks all non-deleted fleets of a legal entity as deleted by d.
+func (r *FleetRepository) SoftDeleteByLegalEntityID(
+	ctx context.Context,
+	legalEntityID string,
+	d ports.Deletion,
+) ([]*domain.Fleet, error) {
+	var rows []fleetRow
+	const query = `
+		WITH deleted AS (
+			SELECT id::text, legal_entity_id::text, name, version,
+				created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
+			FROM fleets
+			WHERE legal_entity_id = $1 AND deleted_at IS NULL
+			FOR UPDATE
+		)
+		UPDATE fleets
+		SET deleted_at = $2, deleted_by = $3, delete_reason = $4, deletion_id = $5, version = fleets.version + 1
+		FROM deleted
+		WHERE fleets.id = deleted.id::uuid
+		RETURNING deleted.*
+	`
+	if err := r.db.writer(ctx).SelectContext(ctx, &rows, query, legalEntityID, d.At, d.By, d.Reason, d.ID); err != nil {
+		return nil, err
+	}
+	return toDomainAll(rows, (*fleetRow).toDomain), nil
+}
+
 // Undelete restores a soft-deleted fleet.
-func (r *FleetRepository) Undelete(ctx context.Context, id string) error {
-	const query = `UPDATE fleets SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
-	res, err := r.db.Master().ExecContext(ctx, query, id)
+func (r *FleetRepository) Undelete(ctx context.Context, id string, at time.Time, by string) error {
+	const query = `
+		UPDATE fleets
+		SET deleted_at = NULL, deleted_by = '', delete_reason = '', deletion_id = NULL,
+			updated_at = $2, updated_by = $3, version = version + 1
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+	res, err := r.db.writer(ctx).ExecContext(ctx, query, id, at, by)
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 	n, err := res.RowsAffected()
 	if err != nil {
@@ -114,7 +210,7 @@ func (r *FleetRepository) Undelete(ctx context.Context, id string) error {
 	if n == 0 {
 		var n2 int
 		const checkQuery = `SELECT 1 FROM fleets WHERE id = $1 AND deleted_at IS NULL`
-		switch err := r.db.Master().GetContext(ctx, &n2, checkQuery, id); {
+		switch err := r.db.writer(ctx).GetContext(ctx, &n2, checkQuery, id); {
 		case err == nil:
 			return fmt.Errorf("%w: entity is not deleted", domain.ErrConflict)
 		case errors.Is(err, sql.ErrNoRows):
@@ -125,3 +221,26 @@ func (r *FleetRepository) Undelete(ctx context.Context, id string) error {
 	}
 	return nil
 }
+
+// UndeleteByLegalEntityID restores the fleets of a legal entity that were deleted by deletionID.
+func (r *FleetRepository) UndeleteByLegalEntityID(
+	ctx context.Context,
+	legalEntityID, deletionID string,
+	at time.Time,
+	by string,
+) ([]*domain.Fleet, error) {
+	var rows []fleetRow
+	const query = `
+		UPDATE fleets
+		SET deleted_at = NULL, deleted_by = '', delete_reason = '', deletion_id = NULL,
+			updated_at = $3, updated_by = $4, version = version + 1
+		WHERE legal_entity_id = $1 AND deletion_id = $2 AND deleted_at IS NOT NULL
+			AND EXISTS (SELECT 1 FROM legal_entities le WHERE le.id = fleets.legal_entity_id AND le.deleted_at IS NULL)
+		RETURNING id::text, legal_entity_id::text, name, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
+	`
+	if err := r.db.writer(ctx).SelectContext(ctx, &rows, query, legalEntityID, deletionID, at, by); err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainAll(rows, (*fleetRow).toDomain), nil
+}
@@ -2,8 +2,8 @@ package postgres
 
 import (
 	"context"
-	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 
@@ -16,6 +16,10 @@ func Module() fx.Option {
 	return fx.Module("postgres",
 		fx.Provide(
 			newDB,
+			fx.Annotate(
+				NewTxManager,
+				fx.As(new(ports.TxManager)),
+			),
 			fx.Annotate(
 				NewLegalEntityRepository,
 				fx.As(new(ports.LegalEntityRepository)),
@@ -40,13 +44,54 @@ func Module() fx.Option {
 				NewVehicleAssignmentRepository,
 				fx.As(new(ports.VehicleAssignmentRepository)),
 			),
+			fx.Annotate(
+				NewAuditLogRepository,
+				fx.As(new(ports.AuditLog)),
+			),
+			fx.Annotate(
+				NewOutboxRepository,
+				fx.As(new(ports.Outbox)),
+			),
+			fx.Annotate(
+				NewWebhookSubscriptionRepository,
+				fx.As(new(ports.WebhookSubscriptionRepository)),
+			),
+			fx.Annotate(
+				NewWebhookDeliveryRepository,
+				fx.As(new(ports.WebhookDeliveryRepository)),
+			),
+			fx.Annotate(
+				NewAPIKeyRepository,
+				fx.As(new(ports.APIKeyRepository)),
+			),
+			fx.Annotate(
+				NewIdempotencyRepository,
+				fx.As(new(ports.IdempotencyRepository)),
+			),
+			fx.Annotate(
+				newEventFeed,
+				fx.As(new(ports.EventFeed)),
+			),
+			fx.Annotate(
+				NewPoolCollectors,
+				fx.ResultTags(`group:"metrics,flatten"`),
+			),
+			fx.Annotate(
+				NewBusinessCollector,
+				fx.As(new(prometheus.Collector)),
+				fx.ResultTags(`group:"metrics"`),
+			),
+			fx.Annotate(
+				NewHealthCheckers,
+				fx.ResultTags(`group:"health,flatten"`),
+			),
 		),
 		fx.Invoke(runMigrationsLifecycle),
 	)
 }
 
 func newDB(lc fx.Lifecycle, cfg *config.DatabaseConfig, logger *zap.Logger) (*DB, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
 	defer cancel()
 	db, err := NewDB(ctx, cfg)
 	if err != nil {
@@ -63,6 +108,35 @@ func newDB(lc fx.Lifecycle, cfg *config.DatabaseConfig, logger *zap.Logger) (*DB
 	return db, nil
 }
 
+func newEventFeed(lc fx.Lifecycle, db *DB, cfg *config.DatabaseConfig, logger *zap.Logger) *EventFeed {
+	feed := NewEventFeed(db, cfg.MasterURL, logger)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			logger.Info("Starting event feed")
+			go func() {
+				defer close(done)
+				feed.Run(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			logger.Info("Stopping event feed")
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+
+	return feed
+}
+
 func runMigrationsLifecycle(lc fx.Lifecycle, cfg *config.DatabaseConfig, logger *zap.Logger) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
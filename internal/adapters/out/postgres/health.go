@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pressly/goose/v3"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/config"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/health"
+	"github.com/albenik/uber-fx-based-service-example/migrations"
+)
+
+// replicationLagQuery measures how far the replica is behind the master. A replica
+// that has replayed everything it received is not lagging, however long ago the last
+// transaction was committed on an idle master.
+const replicationLagQuery = `
+	SELECT CASE
+		WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END`
+
+// NewHealthCheckers creates the readiness checks of the database: the master pool, the
+// replica pool and its replication lag when a replica is configured, and whether every
+// embedded migration has been applied.
+func NewHealthCheckers(db *DB, cfg *config.DatabaseConfig) ([]health.Checker, error) {
+	migrator, err := goose.NewProvider(goose.DialectPostgres, db.master.DB, migrations.SQL)
+	if err != nil {
+		return nil, err
+	}
+
+	checkers := []health.Checker{
+		health.NewChecker("postgres_master", db.master.PingContext),
+	}
+	if db.replica != db.master {
+		checkers = append(checkers, health.NewChecker("postgres_replica", func(ctx context.Context) error {
+			var lag float64
+			if err := db.replica.GetContext(ctx, &lag, replicationLagQuery); err != nil {
+				return err
+			}
+			if d := time.Duration(lag * float64(time.Second)); d > cfg.ReplicaMaxLag {
+				return fmt.Errorf("replication lag %s exceeds %s", d.Round(time.Millisecond), cfg.ReplicaMaxLag)
+			}
+			return nil
+		}))
+	}
+	checkers = append(checkers, health.NewChecker("postgres_migrations", func(ctx context.Context) error {
+		current, target, err := migrator.GetVersions(ctx)
+		if err != nil {
+			return err
+		}
+		if current < target {
+			return fmt.Errorf("database is at migration %d, want %d", current, target)
+		}
+		return nil
+	}))
+	return checkers, nil
+}
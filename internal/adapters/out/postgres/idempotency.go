@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+)
+
+// maxClaimAttempts bounds the retries of Claim when the record it conflicted with is
+// gone before it could be read.
+const maxClaimAttempts = 3
+
+// IdempotencyRepository implements ports.IdempotencyRepository.
+type IdempotencyRepository struct {
+	db *DB
+}
+
+// NewIdempotencyRepository creates a new IdempotencyRepository.
+func NewIdempotencyRepository(db *DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Claim inserts the record, replacing an expired one. The primary key makes concurrent
+// claims of the same key wait for each other, so only one of them succeeds.
+func (r *IdempotencyRepository) Claim(
+	ctx context.Context,
+	rec *domain.IdempotencyRecord,
+	now time.Time,
+) (*domain.IdempotencyRecord, error) {
+	const claim = `
+		INSERT INTO idempotency_keys (principal_id, key, fingerprint, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (principal_id, key) DO UPDATE SET
+			fingerprint = EXCLUDED.fingerprint,
+			status_code = NULL,
+			header = NULL,
+			body = NULL,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= $6
+	`
+	const find = `
+		SELECT principal_id, key, fingerprint, status_code, header, body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE principal_id = $1 AND key = $2
+	`
+	for range maxClaimAttempts {
+		res, err := r.db.writer(ctx).ExecContext(ctx, claim,
+			rec.PrincipalID, rec.Key, rec.Fingerprint, rec.CreatedAt, rec.ExpiresAt, now)
+		if err != nil {
+			return nil, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if n == 1 {
+			return nil, nil
+		}
+		var row idempotencyRow
+		err = r.db.writer(ctx).GetContext(ctx, &row, find, rec.PrincipalID, rec.Key)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Released meanwhile; claim it again.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return row.toDomain(), nil
+	}
+	return nil, fmt.Errorf("failed to claim idempotency key after %d attempts", maxClaimAttempts)
+}
+
+// SaveResponse completes a claimed record.
+func (r *IdempotencyRepository) SaveResponse(
+	ctx context.Context,
+	principalID, key string,
+	resp *domain.IdempotentResponse,
+	expiresAt time.Time,
+) error {
+	const query = `
+		UPDATE idempotency_keys
+		SET status_code = $3, header = $4, body = $5, expires_at = $6
+		WHERE principal_id = $1 AND key = $2
+	`
+	res, err := r.db.writer(ctx).ExecContext(ctx, query,
+		principalID, key, resp.StatusCode, httpHeader(resp.Header), resp.Body, expiresAt)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes a record.
+func (r *IdempotencyRepository) Delete(ctx context.Context, principalID, key string) error {
+	const query = `DELETE FROM idempotency_keys WHERE principal_id = $1 AND key = $2`
+	_, err := r.db.writer(ctx).ExecContext(ctx, query, principalID, key)
+	return err
+}
+
+// DeleteExpired removes a batch of expired records.
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, before time.Time, limit int) (int, error) {
+	const query = `
+		DELETE FROM idempotency_keys
+		WHERE (principal_id, key) IN (
+			SELECT principal_id, key FROM idempotency_keys
+			WHERE expires_at < $1
+			LIMIT $2
+		)
+	`
+	res, err := r.db.writer(ctx).ExecContext(ctx, query, before, limit)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 )
 
 // LegalEntityRepository implements ports.LegalEntityRepository.
@@ -23,28 +25,44 @@ func NewLegalEntityRepository(db *DB) *LegalEntityRepository {
 func (r *LegalEntityRepository) Save(ctx context.Context, entity *domain.LegalEntity) error {
 	row := legalEntityToRow(entity)
 	const query = `
-		INSERT INTO legal_entities (id, name, tax_id, deleted_at)
-		VALUES (:id, :name, :tax_id, :deleted_at)
+		INSERT INTO legal_entities (
+			id, name, tax_id,
+			created_at, created_by, updated_at, updated_by, deleted_at
+		)
+		VALUES (
+			:id, :name, :tax_id,
+			:created_at, :created_by, :updated_at, :updated_by, :deleted_at
+		)
 		ON CONFLICT (id) DO UPDATE SET
 			name = EXCLUDED.name,
 			tax_id = EXCLUDED.tax_id,
-			deleted_at = EXCLUDED.deleted_at
+			updated_at = EXCLUDED.updated_at,
+			updated_by = EXCLUDED.updated_by,
+			deleted_at = EXCLUDED.deleted_at,
+			version = legal_entities.version + 1
+		WHERE legal_entities.version = :version
+		RETURNING version
 	`
 
-	_, err := r.db.Master().NamedExecContext(ctx, query, row)
-	return err
+	version, err := saveVersioned(ctx, r.db.writer(ctx), query, row)
+	if err != nil {
+		return err
+	}
+	entity.Version = version
+	return nil
 }
 
 // FindByID returns a legal entity by ID, excluding soft-deleted.
 func (r *LegalEntityRepository) FindByID(ctx context.Context, id string) (*domain.LegalEntity, error) {
 	var row legalEntityRow
 	const query = `
-		SELECT id::text, name, tax_id, deleted_at
+		SELECT id::text, name, tax_id, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
 		FROM legal_entities
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	if err := r.db.Replica().GetContext(ctx, &row, query, id); err != nil {
+	if err := r.db.reader(ctx).GetContext(ctx, &row, query, id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrNotFound
 		}
@@ -54,37 +72,92 @@ func (r *LegalEntityRepository) FindByID(ctx context.Context, id string) (*domai
 	return row.toDomain(), nil
 }
 
-// FindAll returns all non-deleted legal entities, sorted by ID.
-func (r *LegalEntityRepository) FindAll(ctx context.Context) ([]*domain.LegalEntity, error) {
-	var rows []legalEntityRow
+// FindDeletedByID returns a soft-deleted legal entity by ID.
+func (r *LegalEntityRepository) FindDeletedByID(ctx context.Context, id string) (*domain.LegalEntity, error) {
+	var row legalEntityRow
 	const query = `
-		SELECT id::text, name, tax_id, deleted_at
+		SELECT id::text, name, tax_id, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason, deletion_id::text
 		FROM legal_entities
-		WHERE deleted_at IS NULL
-		ORDER BY id
+		WHERE id = $1
 	`
 
-	if err := r.db.Replica().SelectContext(ctx, &rows, query); err != nil {
+	if err := r.db.reader(ctx).GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
 		return nil, err
 	}
 
-	result := make([]*domain.LegalEntity, len(rows))
-	for i := range rows {
-		result[i] = rows[i].toDomain()
+	if row.DeletedAt == nil {
+		return nil, fmt.Errorf("%w: entity is not deleted", domain.ErrConflict)
 	}
 
-	return result, nil
+	return row.toDomain(), nil
+}
+
+var legalEntityListSpec = &listSpec[legalEntityRow]{
+	sorts: map[string]sortColumn[legalEntityRow]{
+		"id":     {listColumn{"id", "uuid"}, func(r *legalEntityRow) string { return r.ID }},
+		"name":   {listColumn{"name", "text"}, func(r *legalEntityRow) string { return r.Name }},
+		"tax_id": {listColumn{"tax_id", "text"}, func(r *legalEntityRow) string { return r.TaxID }},
+	},
+	filters: map[string]listColumn{
+		"name":   {"name", "text"},
+		"tax_id": {"tax_id", "text"},
+	},
+	defaultSort: "id",
+	id:          func(r *legalEntityRow) string { return r.ID },
 }
 
-// SoftDelete marks a legal entity as deleted.
-func (r *LegalEntityRepository) SoftDelete(ctx context.Context, id string) error {
+// FindAll returns a page of non-deleted legal entities, sorted by ID unless q says otherwise.
+func (r *LegalEntityRepository) FindAll(
+	ctx context.Context,
+	q ports.ListQuery,
+) (*ports.Page[*domain.LegalEntity], error) {
+	st, err := legalEntityListSpec.build(q, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []legalEntityRow
+	query := `
+		SELECT id::text, name, tax_id, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
+		FROM legal_entities
+		WHERE deleted_at IS NULL` + st.clause
+
+	if err := r.db.reader(ctx).SelectContext(ctx, &rows, query, st.args...); err != nil {
+		return nil, err
+	}
+
+	return toPage(legalEntityListSpec, st, rows, (*legalEntityRow).toDomain), nil
+}
+
+// ExistsByTaxID reports whether a non-deleted legal entity other than excludeID has the given tax ID.
+func (r *LegalEntityRepository) ExistsByTaxID(ctx context.Context, taxID, excludeID string) (bool, error) {
+	var exists bool
+	const query = `
+		SELECT EXISTS (
+			SELECT 1 FROM legal_entities
+			WHERE tax_id = $1 AND ($2 = '' OR id::text != $2) AND deleted_at IS NULL
+		)
+	`
+	if err := r.db.reader(ctx).GetContext(ctx, &exists, query, taxID, excludeID); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// SoftDelete marks a legal entity as deleted by d.
+func (r *LegalEntityRepository) SoftDelete(ctx context.Context, id string, d ports.Deletion) error {
 	const query = `
 		UPDATE legal_entities
-		SET deleted_at = NOW()
+		SET deleted_at = $2, deleted_by = $3, delete_reason = $4, deletion_id = $5, version = version + 1
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	res, err := r.db.Master().ExecContext(ctx, query, id)
+	res, err := r.db.writer(ctx).ExecContext(ctx, query, id, d.At, d.By, d.Reason, d.ID)
 	if err != nil {
 		return err
 	}
@@ -96,7 +169,7 @@ func (r *LegalEntityRepository) SoftDelete(ctx context.Context, id string) error
 	if n == 0 {
 		var n2 int
 		const checkQuery = `SELECT 1 FROM legal_entities WHERE id = $1 AND deleted_at IS NOT NULL`
-		switch err := r.db.Master().GetContext(ctx, &n2, checkQuery, id); {
+		switch err := r.db.writer(ctx).GetContext(ctx, &n2, checkQuery, id); {
 		case err == nil:
 			return domain.ErrAlreadyDeleted
 		case errors.Is(err, sql.ErrNoRows):
@@ -109,12 +182,17 @@ func (r *LegalEntityRepository) SoftDelete(ctx context.Context, id string) error
 }
 
 // Undelete restores a soft-deleted legal entity.
-func (r *LegalEntityRepository) Undelete(ctx context.Context, id string) error {
-	const query = `UPDATE legal_entities SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+func (r *LegalEntityRepository) Undelete(ctx context.Context, id string, at time.Time, by string) error {
+	const query = `
+		UPDATE legal_entities
+		SET deleted_at = NULL, deleted_by = '', delete_reason = '', deletion_id = NULL,
+			updated_at = $2, updated_by = $3, version = version + 1
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
 
-	res, err := r.db.Master().ExecContext(ctx, query, id)
+	res, err := r.db.writer(ctx).ExecContext(ctx, query, id, at, by)
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 	n, err := res.RowsAffected()
 	if err != nil {
@@ -123,7 +201,7 @@ func (r *LegalEntityRepository) Undelete(ctx context.Context, id string) error {
 	if n == 0 {
 		var n2 int
 		const checkQuery = `SELECT 1 FROM legal_entities WHERE id = $1 AND deleted_at IS NULL`
-		switch err := r.db.Master().GetContext(ctx, &n2, checkQuery, id); {
+		switch err := r.db.writer(ctx).GetContext(ctx, &n2, checkQuery, id); {
 		case err == nil:
 			return fmt.Errorf("%w: entity is not deleted", domain.ErrConflict)
 		case errors.Is(err, sql.ErrNoRows):
@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+)
+
+// listColumn maps an API field name to a SQL column of type cast.
+// Values coming from cursors and filters are checked against cast by normalize,
+// then bound as text and cast in SQL, so the column index can still be used.
+type listColumn struct {
+	name string
+	cast string
+}
+
+// sortColumn is a listColumn usable for keyset pagination. value renders the
+// column value of a row in a form accepted by cast.
+type sortColumn[R any] struct {
+	listColumn
+	value func(*R) string
+}
+
+// listSpec describes which fields of a row type R can be sorted and filtered on.
+// Every sort is made unique by using id as the tie-breaker.
+type listSpec[R any] struct {
+	sorts       map[string]sortColumn[R]
+	filters     map[string]listColumn
+	defaultSort string
+	id          func(*R) string
+}
+
+// listCursor is the decoded form of the opaque ports.ListQuery cursor.
+type listCursor struct {
+	SortBy  string              `json:"s"`
+	SortDir ports.SortDirection `json:"d"`
+	Value   string              `json:"v"`
+	ID      string              `json:"id"`
+}
+
+func encodeCursor(c listCursor) string {
+	b, _ := json.Marshal(c) //nolint:errchkjson // plain struct of strings
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (listCursor, error) {
+	var c listCursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidInput)
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidInput)
+	}
+	return c, nil
+}
+
+// normalize checks that v is a valid value of the column type and returns it in the
+// form PostgreSQL parses, so that a bad filter or a tampered cursor is rejected as
+// invalid input instead of failing the statement.
+func (c listColumn) normalize(v string) (string, error) {
+	switch c.cast {
+	case "uuid":
+		u, err := uuid.Parse(v)
+		if err != nil {
+			return "", err
+		}
+		return u.String(), nil
+	case "integer":
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(n, 10), nil
+	case "boolean":
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatBool(b), nil
+	case "date":
+		t, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			return "", err
+		}
+		return dateValue(t), nil
+	case "timestamptz":
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return "", err
+		}
+		return timestampValue(t), nil
+	default:
+		return v, nil
+	}
+}
+
+// listStatement is a query tail produced by listSpec.build.
+type listStatement struct {
+	sortBy  string
+	sortDir ports.SortDirection
+	limit   int
+	clause  string
+	args    []any
+}
+
+// build appends filter, keyset, ORDER BY and LIMIT clauses for q to a query
+// whose WHERE clause already binds the given args. The returned clause starts
+// with " AND" and must be appended right after the existing WHERE conditions.
+func (s *listSpec[R]) build(q ports.ListQuery, args []any) (*listStatement, error) {
+	st := &listStatement{sortBy: q.SortBy, sortDir: q.SortDir, limit: q.Limit, args: args}
+	if st.sortBy == "" {
+		st.sortBy = s.defaultSort
+	}
+	if st.sortDir == "" {
+		st.sortDir = ports.SortAsc
+	}
+	if st.limit <= 0 {
+		st.limit = ports.DefaultListLimit
+	}
+	sc, ok := s.sorts[st.sortBy]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported sort field %q", domain.ErrInvalidInput, st.sortBy)
+	}
+
+	var b strings.Builder
+	bind := func(v any) string {
+		st.args = append(st.args, v)
+		return "$" + strconv.Itoa(len(st.args))
+	}
+
+	// Sort filter keys so the generated SQL is stable.
+	keys := make([]string, 0, len(q.Filters))
+	for k := range q.Filters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		col, ok := s.filters[k]
+		if !ok {
+			return nil, fmt.Errorf("%w: unsupported filter %q", domain.ErrInvalidInput, k)
+		}
+		v, err := col.normalize(q.Filters[k])
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid value %q for filter %q", domain.ErrInvalidInput, q.Filters[k], k)
+		}
+		fmt.Fprintf(&b, " AND %s = %s::text::%s", col.name, bind(v), col.cast)
+	}
+
+	op, dir := ">", "ASC"
+	if st.sortDir == ports.SortDesc {
+		op, dir = "<", "DESC"
+	}
+	if q.Cursor != "" {
+		c, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if c.SortBy != st.sortBy || c.SortDir != st.sortDir {
+			return nil, fmt.Errorf("%w: cursor does not match sort order", domain.ErrInvalidInput)
+		}
+		id, err := listColumn{"id", "uuid"}.normalize(c.ID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidInput)
+		}
+		if sc.name == "id" {
+			fmt.Fprintf(&b, " AND id %s %s::uuid", op, bind(id))
+		} else {
+			value, err := sc.normalize(c.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidInput)
+			}
+			fmt.Fprintf(&b, " AND (%s, id) %s (%s::text::%s, %s::uuid)",
+				sc.name, op, bind(value), sc.cast, bind(id))
+		}
+	}
+
+	if sc.name == "id" {
+		fmt.Fprintf(&b, " ORDER BY id %s", dir)
+	} else {
+		fmt.Fprintf(&b, " ORDER BY %s %s, id %s", sc.name, dir, dir)
+	}
+	// Fetch one extra row to learn whether there is a next page.
+	fmt.Fprintf(&b, " LIMIT %d", st.limit+1)
+
+	st.clause = b.String()
+	return st, nil
+}
+
+// toPage converts fetched rows into a ports.Page, trimming the look-ahead row.
+func toPage[R any, T any](s *listSpec[R], st *listStatement, rows []R, toDomain func(*R) T) *ports.Page[T] {
+	result := &ports.Page[T]{}
+	if len(rows) > st.limit {
+		rows = rows[:st.limit]
+		last := &rows[len(rows)-1]
+		result.NextCursor = encodeCursor(listCursor{
+			SortBy:  st.sortBy,
+			SortDir: st.sortDir,
+			Value:   s.sorts[st.sortBy].value(last),
+			ID:      s.id(last),
+		})
+	}
+	result.Items = make([]T, len(rows))
+	for i := range rows {
+		result.Items[i] = toDomain(&rows[i])
+	}
+	return result
+}
+
+func dateValue(t time.Time) string { return t.Format(time.DateOnly) }
+
+func timestampValue(t time.Time) string { return t.Format(time.RFC3339Nano) }
@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"encoding/base64"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+)
+
+const (
+	testID      = "0b6f3c52-9a0e-4c4e-8f43-3f0d6b0c8d11"
+	testOtherID = "5d1e8f7a-2b3c-4d5e-9f60-718293a4b5c6"
+)
+
+type testRow struct {
+	ID    string
+	Name  string
+	Year  int
+	Start time.Time
+}
+
+var testListSpec = &listSpec[testRow]{
+	sorts: map[string]sortColumn[testRow]{
+		"id":    {listColumn{"id", "uuid"}, func(r *testRow) string { return r.ID }},
+		"name":  {listColumn{"name", "text"}, func(r *testRow) string { return r.Name }},
+		"year":  {listColumn{"year", "integer"}, func(r *testRow) string { return strconv.Itoa(r.Year) }},
+		"start": {listColumn{"start_date", "date"}, func(r *testRow) string { return dateValue(r.Start) }},
+	},
+	filters: map[string]listColumn{
+		"name":     {"name", "text"},
+		"year":     {"year", "integer"},
+		"fleet_id": {"fleet_id", "uuid"},
+		"active":   {"active", "boolean"},
+	},
+	defaultSort: "id",
+	id:          func(r *testRow) string { return r.ID },
+}
+
+func cursorFor(sortBy string, dir ports.SortDirection, value, id string) string {
+	return encodeCursor(listCursor{SortBy: sortBy, SortDir: dir, Value: value, ID: id})
+}
+
+func TestListSpec_Build(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      ports.ListQuery
+		wantClause string
+		wantArgs   []any
+	}{
+		{
+			name:       "defaults",
+			query:      ports.ListQuery{},
+			wantClause: " ORDER BY id ASC LIMIT 51",
+			wantArgs:   []any{"owner"},
+		},
+		{
+			name: "filters in key order, normalized",
+			query: ports.ListQuery{Limit: 10, Filters: map[string]string{
+				"year":     "2020",
+				"fleet_id": "{0B6F3C52-9A0E-4C4E-8F43-3F0D6B0C8D11}",
+				"active":   "TRUE",
+				"name":     "Fleet",
+			}},
+			wantClause: " AND active = $2::text::boolean AND fleet_id = $3::text::uuid" +
+				" AND name = $4::text::text AND year = $5::text::integer ORDER BY id ASC LIMIT 11",
+			wantArgs: []any{"owner", "true", testID, "Fleet", "2020"},
+		},
+		{
+			name: "cursor on id",
+			query: ports.ListQuery{SortDir: ports.SortDesc, Limit: 5,
+				Cursor: cursorFor("id", ports.SortDesc, testID, testID)},
+			wantClause: " AND id < $2::uuid ORDER BY id DESC LIMIT 6",
+			wantArgs:   []any{"owner", testID},
+		},
+		{
+			name: "cursor on another column",
+			query: ports.ListQuery{SortBy: "start", Limit: 5,
+				Cursor: cursorFor("start", ports.SortAsc, "2024-03-01", testOtherID)},
+			wantClause: " AND (start_date, id) > ($2::text::date, $3::uuid)" +
+				" ORDER BY start_date ASC, id ASC LIMIT 6",
+			wantArgs: []any{"owner", "2024-03-01", testOtherID},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, err := testListSpec.build(tt.query, []any{"owner"})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantClause, st.clause)
+			assert.Equal(t, tt.wantArgs, st.args)
+		})
+	}
+}
+
+func TestListSpec_Build_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   ports.ListQuery
+		wantErr string
+	}{
+		{"unknown sort", ports.ListQuery{SortBy: "color"}, `unsupported sort field "color"`},
+		{"unknown filter", ports.ListQuery{Filters: map[string]string{"color": "red"}}, `unsupported filter "color"`},
+		{"integer filter", ports.ListQuery{Filters: map[string]string{"year": "abc"}}, `invalid value "abc" for filter "year"`},
+		{"uuid filter", ports.ListQuery{Filters: map[string]string{"fleet_id": "42"}}, `invalid value "42" for filter "fleet_id"`},
+		{"boolean filter", ports.ListQuery{Filters: map[string]string{"active": "maybe"}}, `for filter "active"`},
+		{"cursor not base64", ports.ListQuery{Cursor: "%%%"}, "malformed cursor"},
+		{"cursor not json", ports.ListQuery{Cursor: base64.RawURLEncoding.EncodeToString([]byte("nope"))}, "malformed cursor"},
+		{
+			"cursor of another sort",
+			ports.ListQuery{SortBy: "name", Cursor: cursorFor("id", ports.SortAsc, testID, testID)},
+			"cursor does not match sort order",
+		},
+		{
+			"tampered cursor id",
+			ports.ListQuery{Cursor: cursorFor("id", ports.SortAsc, testID, "1 OR 1=1")},
+			"malformed cursor",
+		},
+		{
+			"tampered cursor value",
+			ports.ListQuery{SortBy: "year", Cursor: cursorFor("year", ports.SortAsc, "abc", testID)},
+			"malformed cursor",
+		},
+		{
+			"tampered cursor date",
+			ports.ListQuery{SortBy: "start", Cursor: cursorFor("start", ports.SortAsc, "2024-13-01", testID)},
+			"malformed cursor",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := testListSpec.build(tt.query, nil)
+			require.ErrorIs(t, err, domain.ErrInvalidInput)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	c := listCursor{SortBy: "year", SortDir: ports.SortDesc, Value: "2020", ID: testID}
+
+	got, err := decodeCursor(encodeCursor(c))
+	require.NoError(t, err)
+	assert.Equal(t, c, got)
+}
+
+func TestToPage(t *testing.T) {
+	rows := []testRow{
+		{ID: testID, Year: 2019},
+		{ID: testOtherID, Year: 2020},
+		{ID: testID, Year: 2021},
+	}
+	st := &listStatement{sortBy: "year", sortDir: ports.SortAsc, limit: 2}
+
+	page := toPage(testListSpec, st, rows, func(r *testRow) int { return r.Year })
+	assert.Equal(t, []int{2019, 2020}, page.Items)
+
+	next, err := testListSpec.build(ports.ListQuery{SortBy: "year", Cursor: page.NextCursor}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"2020", testOtherID}, next.args)
+
+	last := toPage(testListSpec, st, rows[:2], func(r *testRow) int { return r.Year })
+	assert.Empty(t, last.NextCursor)
+}
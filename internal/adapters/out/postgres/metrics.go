@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"go.uber.org/zap"
+)
+
+// metricsQueryTimeout bounds the query run on every scrape by BusinessCollector.
+const metricsQueryTimeout = 5 * time.Second
+
+// NewPoolCollectors exports the connection pool stats of master and, when it is a
+// pool of its own, replica, labelled by db_name.
+func NewPoolCollectors(db *DB) []prometheus.Collector {
+	cs := []prometheus.Collector{collectors.NewDBStatsCollector(db.Master().DB, "master")}
+	if db.Replica() != db.Master() {
+		cs = append(cs, collectors.NewDBStatsCollector(db.Replica().DB, "replica"))
+	}
+	return cs
+}
+
+// BusinessCollector exports business metrics counted in the database on every
+// scrape, so that all instances report the same values and restarts lose nothing.
+type BusinessCollector struct {
+	db     *DB
+	logger *zap.Logger
+
+	contractsCreated  *prometheus.Desc
+	assignmentsActive *prometheus.Desc
+}
+
+// NewBusinessCollector creates a new BusinessCollector.
+func NewBusinessCollector(db *DB, logger *zap.Logger) *BusinessCollector {
+	return &BusinessCollector{
+		db:     db,
+		logger: logger,
+		contractsCreated: prometheus.NewDesc("fleet_contracts_created_total",
+			"Contracts created, including terminated and deleted ones.", nil, nil),
+		assignmentsActive: prometheus.NewDesc("fleet_vehicle_assignments_active",
+			"Vehicle assignments whose vehicle has not been returned yet.", nil, nil),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *BusinessCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.contractsCreated
+	ch <- c.assignmentsActive
+}
+
+// Collect implements prometheus.Collector. The counts are read from the replica.
+func (c *BusinessCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), metricsQueryTimeout)
+	defer cancel()
+
+	var counts struct {
+		Contracts         int64 `db:"contracts"`
+		ActiveAssignments int64 `db:"active_assignments"`
+	}
+	err := c.db.Replica().GetContext(ctx, &counts, `
+		SELECT
+			(SELECT count(*) FROM contracts) AS contracts,
+			(SELECT count(*) FROM vehicle_assignments WHERE end_time IS NULL AND deleted_at IS NULL) AS active_assignments`)
+	if err != nil {
+		c.logger.Warn("Failed to count business metrics", zap.Error(err))
+		ch <- prometheus.NewInvalidMetric(c.contractsCreated, err)
+		ch <- prometheus.NewInvalidMetric(c.assignmentsActive, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.contractsCreated, prometheus.CounterValue, float64(counts.Contracts))
+	ch <- prometheus.MustNewConstMetric(c.assignmentsActive, prometheus.GaugeValue, float64(counts.ActiveAssignments))
+}
+
+// Ensure BusinessCollector implements prometheus.Collector.
+var _ prometheus.Collector = (*BusinessCollector)(nil)
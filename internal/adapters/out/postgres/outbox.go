@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+)
+
+// OutboxRepository implements ports.Outbox.
+type OutboxRepository struct {
+	db *DB
+}
+
+// NewOutboxRepository creates a new OutboxRepository.
+func NewOutboxRepository(db *DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// Enqueue inserts an event due for delivery right away.
+func (r *OutboxRepository) Enqueue(ctx context.Context, event *domain.Event) error {
+	row, err := outboxToRow(event)
+	if err != nil {
+		return err
+	}
+	const query = `
+		INSERT INTO outbox (id, event_type, payload, occurred_at, next_attempt_at)
+		VALUES (:id, :event_type, :payload, :occurred_at, :occurred_at)
+	`
+	_, err = sqlx.NamedExecContext(ctx, r.db.writer(ctx), query, row)
+	return err
+}
+
+// Claim leases due undelivered events. SKIP LOCKED lets several relays claim
+// disjoint batches concurrently.
+func (r *OutboxRepository) Claim(ctx context.Context, limit int, now, leaseUntil time.Time) ([]*ports.OutboxMessage, error) {
+	var rows []outboxRow
+	const query = `
+		UPDATE outbox
+		SET locked_until = $3
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE delivered_at IS NULL AND next_attempt_at <= $1
+				AND (locked_until IS NULL OR locked_until <= $1)
+			ORDER BY occurred_at, id
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id::text, event_type, payload, occurred_at, attempts
+	`
+	if err := r.db.writer(ctx).SelectContext(ctx, &rows, query, now, limit, leaseUntil); err != nil {
+		return nil, err
+	}
+	// RETURNING does not keep the order of the subquery.
+	slices.SortFunc(rows, func(a, b outboxRow) int {
+		if c := a.OccurredAt.Compare(b.OccurredAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	messages := make([]*ports.OutboxMessage, len(rows))
+	for i := range rows {
+		messages[i] = rows[i].toMessage()
+	}
+	return messages, nil
+}
+
+// MarkDelivered records a successful delivery and releases the lease.
+func (r *OutboxRepository) MarkDelivered(ctx context.Context, id string, at time.Time) error {
+	const query = `
+		UPDATE outbox
+		SET delivered_at = $2, attempts = attempts + 1, last_error = '', locked_until = NULL
+		WHERE id = $1
+	`
+	_, err := r.db.writer(ctx).ExecContext(ctx, query, id, at)
+	return err
+}
+
+// MarkFailed records a failed delivery, schedules the retry and releases the lease.
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id, cause string, retryAt time.Time) error {
+	const query = `
+		UPDATE outbox
+		SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3, locked_until = NULL
+		WHERE id = $1
+	`
+	_, err := r.db.writer(ctx).ExecContext(ctx, query, id, cause, retryAt)
+	return err
+}
+
+// FindAfter returns events following afterID in outbox order. A malformed ID is reported
+// as not found, like an unknown one. It reads from the master: the ID usually comes
+// from the live feed and may not have reached the replica yet.
+func (r *OutboxRepository) FindAfter(ctx context.Context, afterID string, limit int) ([]*domain.Event, error) {
+	if uuid.Validate(afterID) != nil {
+		return nil, domain.ErrNotFound
+	}
+	var pos outboxPosition
+	const posQuery = `SELECT txid, seq FROM outbox WHERE id = $1`
+	if err := r.db.writer(ctx).GetContext(ctx, &pos, posQuery, afterID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	rows, err := eventsAfter(ctx, r.db.writer(ctx), pos, limit)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*domain.Event, len(rows))
+	for i := range rows {
+		events[i] = rows[i].toMessage().Event
+	}
+	return events, nil
+}
+
+// outboxPosition is the place of an event in the outbox order: by enqueuing transaction,
+// then by seq within it. Events are only read once every older transaction is over, so
+// one committed later always takes a position after those already read.
+type outboxPosition struct {
+	TxID uint64 `db:"txid"`
+	Seq  int64  `db:"seq"`
+}
+
+type outboxEventRow struct {
+	outboxRow
+	outboxPosition
+}
+
+// eventsAfter returns up to limit events following pos in outbox order. Events of a
+// transaction older than a running one are held back until that one is over.
+func eventsAfter(ctx context.Context, q executor, pos outboxPosition, limit int) ([]outboxEventRow, error) {
+	var rows []outboxEventRow
+	const query = `
+		SELECT id::text, event_type, payload, occurred_at, attempts, txid, seq
+		FROM outbox
+		WHERE (txid, seq) > ($1, $2) AND txid < pg_snapshot_xmin(pg_current_snapshot())
+		ORDER BY txid, seq
+		LIMIT $3
+	`
+	if err := q.SelectContext(ctx, &rows, query, pos.TxID, pos.Seq, limit); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// headPosition returns the position of the last event eventsAfter may return now.
+func headPosition(ctx context.Context, q executor) (outboxPosition, error) {
+	var pos outboxPosition
+	const query = `
+		SELECT txid, seq
+		FROM outbox
+		WHERE txid < pg_snapshot_xmin(pg_current_snapshot())
+		ORDER BY txid DESC, seq DESC
+		LIMIT 1
+	`
+	if err := q.GetContext(ctx, &pos, query); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return outboxPosition{}, err
+	}
+	return pos, nil
+}
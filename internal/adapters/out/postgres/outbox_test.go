@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/config"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+)
+
+// testDB connects to the migrated database at TEST_DATABASE_URL, skipping the test when
+// it is not set.
+func testDB(t *testing.T) *DB {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	cfg := &config.DatabaseConfig{MasterURL: url}
+	require.NoError(t, RunMigrations(t.Context(), cfg))
+	db, err := NewDB(t.Context(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func testEvent() *domain.Event {
+	return &domain.Event{
+		ID:         uuid.NewString(),
+		Type:       domain.EventContractCreated,
+		OccurredAt: time.Now().UTC(),
+		Payload:    map[string]string{},
+	}
+}
+
+func eventIDs(events []*domain.Event) []string {
+	ids := make([]string, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+func TestOutboxRepository_FindAfter_CommitOrder(t *testing.T) {
+	db := testDB(t)
+	repo := NewOutboxRepository(db)
+	ctx := t.Context()
+
+	first := testEvent()
+	require.NoError(t, repo.Enqueue(ctx, first))
+
+	// a is enqueued before b but committed after it.
+	a, b := testEvent(), testEvent()
+	txA := db.master.MustBeginTx(ctx, nil)
+	t.Cleanup(func() { _ = txA.Rollback() })
+	require.NoError(t, repo.Enqueue(context.WithValue(ctx, txKey{}, txA), a))
+	txB := db.master.MustBeginTx(ctx, nil)
+	require.NoError(t, repo.Enqueue(context.WithValue(ctx, txKey{}, txB), b))
+	require.NoError(t, txB.Commit())
+
+	// b is held back while a may still commit ahead of it.
+	events, err := repo.FindAfter(ctx, first.ID, 10)
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	require.NoError(t, txA.Commit())
+
+	events, err = repo.FindAfter(ctx, first.ID, 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{a.ID, b.ID}, eventIDs(events))
+
+	events, err = repo.FindAfter(ctx, a.ID, 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{b.ID}, eventIDs(events))
+}
+
+func TestOutboxRepository_FindAfter_UnknownID(t *testing.T) {
+	repo := NewOutboxRepository(testDB(t))
+
+	_, err := repo.FindAfter(t.Context(), uuid.NewString(), 10)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	_, err = repo.FindAfter(t.Context(), "not-a-uuid", 10)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
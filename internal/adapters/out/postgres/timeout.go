@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// timedExecutor bounds every statement run through the wrapped executor by a deadline,
+// so that a slow query fails the repository call instead of holding a connection for
+// as long as the caller waits. Queries whose rows are read after the call returns are
+// left to the caller, which bounds them with statementContext.
+type timedExecutor struct {
+	executor
+	timeout time.Duration
+}
+
+func (e timedExecutor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, cancel := e.withTimeout(ctx)
+	defer cancel()
+	return e.executor.ExecContext(ctx, query, args...)
+}
+
+func (e timedExecutor) GetContext(ctx context.Context, dest any, query string, args ...any) error {
+	ctx, cancel := e.withTimeout(ctx)
+	defer cancel()
+	return e.executor.GetContext(ctx, dest, query, args...)
+}
+
+func (e timedExecutor) SelectContext(ctx context.Context, dest any, query string, args ...any) error {
+	ctx, cancel := e.withTimeout(ctx)
+	defer cancel()
+	return e.executor.SelectContext(ctx, dest, query, args...)
+}
+
+func (e timedExecutor) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if e.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, e.timeout)
+}
+
+// statementContext bounds ctx by the query timeout of db, for a query whose rows are
+// read before cancel is called.
+func statementContext(ctx context.Context, db executor) (context.Context, context.CancelFunc) {
+	if e, ok := db.(timedExecutor); ok {
+		return e.withTimeout(ctx)
+	}
+	return ctx, func() {}
+}
@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/albenik/uber-fx-based-service-example/internal/adapters/out/postgres")
+
+// tracedExecutor records a span for every statement run through the wrapped executor.
+// Spans are named after the statement ("SELECT contracts"); bound values are never
+// recorded. Statements run outside of a trace, such as those of background workers,
+// are not traced.
+type tracedExecutor struct {
+	executor
+}
+
+func (e tracedExecutor) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := startStatement(ctx, query)
+	rows, err := e.executor.QueryContext(ctx, query, args...)
+	endStatement(span, err)
+	return rows, err
+}
+
+func (e tracedExecutor) QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error) {
+	ctx, span := startStatement(ctx, query)
+	rows, err := e.executor.QueryxContext(ctx, query, args...)
+	endStatement(span, err)
+	return rows, err
+}
+
+func (e tracedExecutor) QueryRowxContext(ctx context.Context, query string, args ...any) *sqlx.Row {
+	ctx, span := startStatement(ctx, query)
+	row := e.executor.QueryRowxContext(ctx, query, args...)
+	endStatement(span, row.Err())
+	return row
+}
+
+func (e tracedExecutor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := startStatement(ctx, query)
+	res, err := e.executor.ExecContext(ctx, query, args...)
+	endStatement(span, err)
+	return res, err
+}
+
+func (e tracedExecutor) GetContext(ctx context.Context, dest any, query string, args ...any) error {
+	ctx, span := startStatement(ctx, query)
+	err := e.executor.GetContext(ctx, dest, query, args...)
+	endStatement(span, err)
+	return err
+}
+
+func (e tracedExecutor) SelectContext(ctx context.Context, dest any, query string, args ...any) error {
+	ctx, span := startStatement(ctx, query)
+	err := e.executor.SelectContext(ctx, dest, query, args...)
+	endStatement(span, err)
+	return err
+}
+
+func startStatement(ctx context.Context, query string) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, nil
+	}
+	operation, table := statementName(query)
+	name := operation
+	if table != "" {
+		name += " " + table
+	}
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system.name", "postgresql"),
+		attribute.String("db.operation.name", operation),
+	}
+	if table != "" {
+		attrs = append(attrs, attribute.String("db.collection.name", table))
+	}
+	return tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+func endStatement(span trace.Span, err error) {
+	if span == nil {
+		return
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// statementName returns the operation of a SQL statement and the table it primarily
+// targets: the one after INTO for INSERT, after UPDATE for UPDATE and after the first
+// FROM otherwise. Common table expressions are skipped to reach the main statement.
+func statementName(query string) (operation, table string) {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "", ""
+	}
+	start := 0
+	if strings.EqualFold(fields[0], "WITH") {
+		start = mainStatement(fields)
+	}
+	if start >= len(fields) {
+		return "WITH", ""
+	}
+	operation = strings.ToUpper(fields[start])
+	var keyword string
+	switch operation {
+	case "INSERT":
+		keyword = "INTO"
+	case "UPDATE":
+		return operation, tableName(fields, start+1)
+	default:
+		keyword = "FROM"
+	}
+	for i := start + 1; i < len(fields); i++ {
+		if strings.EqualFold(fields[i], keyword) {
+			return operation, tableName(fields, i+1)
+		}
+	}
+	return operation, ""
+}
+
+// mainStatement returns the index of the first top-level keyword after the common
+// table expressions of a WITH statement.
+func mainStatement(fields []string) int {
+	depth := 0
+	for i, f := range fields {
+		if depth == 0 && i > 0 {
+			switch strings.ToUpper(f) {
+			case "SELECT", "INSERT", "UPDATE", "DELETE":
+				return i
+			}
+		}
+		depth += strings.Count(f, "(") - strings.Count(f, ")")
+	}
+	return len(fields)
+}
+
+func tableName(fields []string, i int) string {
+	if i >= len(fields) {
+		return ""
+	}
+	name := strings.TrimRight(fields[i], ",;)")
+	if j := strings.IndexByte(name, '('); j >= 0 {
+		name = name[:j]
+	}
+	return strings.ToLower(name)
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/logctx"
+)
+
+// maxTxAttempts bounds how many times a transaction is run when postgres reports
+// a serialization failure or a deadlock.
+const maxTxAttempts = 3
+
+type txKey struct{}
+
+// TxManager implements ports.TxManager on the master pool.
+type TxManager struct {
+	db     *DB
+	logger *zap.Logger
+}
+
+// NewTxManager creates a new TxManager.
+func NewTxManager(db *DB, logger *zap.Logger) *TxManager {
+	return &TxManager{db: db, logger: logger}
+}
+
+// WithinTx runs fn in a serializable transaction and retries it on serialization failures.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+	var err error
+	for attempt := 1; attempt <= maxTxAttempts; attempt++ {
+		err = m.run(ctx, fn)
+		if !isRetryable(err) {
+			return err
+		}
+		m.logger.Debug("Retrying serialization failure", zap.Int("attempt", attempt), zap.Error(err))
+	}
+	// The postgres error is logged only: conflict details are shown to the client.
+	logctx.From(ctx, m.logger).Warn("Transaction could not be serialized",
+		zap.Int("attempts", maxTxAttempts), zap.Error(err))
+	return fmt.Errorf("%w: transaction could not be serialized", domain.ErrConflict)
+}
+
+func (m *TxManager) run(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := m.db.master.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return err
+	}
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			m.logger.Warn("Failed to roll back transaction", zap.Error(rbErr))
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+func isRetryable(err error) bool {
+	pgErr, ok := errors.AsType[*pgconn.PgError](err)
+	if !ok {
+		return false
+	}
+	// serialization_failure, deadlock_detected
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
@@ -5,8 +5,11 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 )
 
 // VehicleRepository implements ports.VehicleRepository.
@@ -23,31 +26,47 @@ func NewVehicleRepository(db *DB) *VehicleRepository {
 func (r *VehicleRepository) Save(ctx context.Context, entity *domain.Vehicle) error {
 	row := vehicleToRow(entity)
 	const query = `
-		INSERT INTO vehicles (id, fleet_id, make, model, year, license_plate, deleted_at)
-		VALUES (:id, :fleet_id, :make, :model, :year, :license_plate, :deleted_at)
+		INSERT INTO vehicles (
+			id, fleet_id, make, model, year, license_plate,
+			created_at, created_by, updated_at, updated_by, deleted_at
+		)
+		VALUES (
+			:id, :fleet_id, :make, :model, :year, :license_plate,
+			:created_at, :created_by, :updated_at, :updated_by, :deleted_at
+		)
 		ON CONFLICT (id) DO UPDATE SET
 			fleet_id = EXCLUDED.fleet_id,
 			make = EXCLUDED.make,
 			model = EXCLUDED.model,
 			year = EXCLUDED.year,
 			license_plate = EXCLUDED.license_plate,
-			deleted_at = EXCLUDED.deleted_at
+			updated_at = EXCLUDED.updated_at,
+			updated_by = EXCLUDED.updated_by,
+			deleted_at = EXCLUDED.deleted_at,
+			version = vehicles.version + 1
+		WHERE vehicles.version = :version
+		RETURNING version
 	`
 
-	_, err := r.db.Master().NamedExecContext(ctx, query, row)
-	return err
+	version, err := saveVersioned(ctx, r.db.writer(ctx), query, row)
+	if err != nil {
+		return err
+	}
+	entity.Version = version
+	return nil
 }
 
 // FindByID returns a vehicle by ID, excluding soft-deleted.
 func (r *VehicleRepository) FindByID(ctx context.Context, id string) (*domain.Vehicle, error) {
 	var row vehicleRow
 	const query = `
-		SELECT id::text, fleet_id::text, make, model, year, license_plate, deleted_at
+		SELECT id::text, fleet_id::text, make, model, year, license_plate, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
 		FROM vehicles
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	if err := r.db.Replica().GetContext(ctx, &row, query, id); err != nil {
+	if err := r.db.reader(ctx).GetContext(ctx, &row, query, id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrNotFound
 		}
@@ -57,37 +76,107 @@ func (r *VehicleRepository) FindByID(ctx context.Context, id string) (*domain.Ve
 	return row.toDomain(), nil
 }
 
-// FindByFleetID returns all non-deleted vehicles for a fleet, sorted by ID.
-func (r *VehicleRepository) FindByFleetID(ctx context.Context, fleetID string) ([]*domain.Vehicle, error) {
-	var rows []vehicleRow
+// FindDeletedByID returns a soft-deleted vehicle by ID.
+func (r *VehicleRepository) FindDeletedByID(ctx context.Context, id string) (*domain.Vehicle, error) {
+	var row vehicleRow
 	const query = `
-		SELECT id::text, fleet_id::text, make, model, year, license_plate, deleted_at
+		SELECT id::text, fleet_id::text, make, model, year, license_plate, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason, deletion_id::text
 		FROM vehicles
-		WHERE fleet_id = $1 AND deleted_at IS NULL
-		ORDER BY id
+		WHERE id = $1
 	`
 
-	if err := r.db.Replica().SelectContext(ctx, &rows, query, fleetID); err != nil {
+	if err := r.db.reader(ctx).GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	if row.DeletedAt == nil {
+		return nil, fmt.Errorf("%w: entity is not deleted", domain.ErrConflict)
+	}
+
+	return row.toDomain(), nil
+}
+
+var vehicleListSpec = &listSpec[vehicleRow]{
+	sorts: map[string]sortColumn[vehicleRow]{
+		"id":            {listColumn{"id", "uuid"}, func(r *vehicleRow) string { return r.ID }},
+		"make":          {listColumn{"make", "text"}, func(r *vehicleRow) string { return r.Make }},
+		"model":         {listColumn{"model", "text"}, func(r *vehicleRow) string { return r.Model }},
+		"year":          {listColumn{"year", "integer"}, func(r *vehicleRow) string { return strconv.Itoa(r.Year) }},
+		"license_plate": {listColumn{"license_plate", "text"}, func(r *vehicleRow) string { return r.LicensePlate }},
+	},
+	filters: map[string]listColumn{
+		"make":          {"make", "text"},
+		"model":         {"model", "text"},
+		"year":          {"year", "integer"},
+		"license_plate": {"license_plate", "text"},
+	},
+	defaultSort: "id",
+	id:          func(r *vehicleRow) string { return r.ID },
+}
+
+// FindByFleetID returns a page of non-deleted vehicles for a fleet, sorted by ID unless q says otherwise.
+func (r *VehicleRepository) FindByFleetID(
+	ctx context.Context,
+	fleetID string,
+	q ports.ListQuery,
+) (*ports.Page[*domain.Vehicle], error) {
+	st, err := vehicleListSpec.build(q, []any{fleetID})
+	if err != nil {
 		return nil, err
 	}
 
-	result := make([]*domain.Vehicle, len(rows))
-	for i := range rows {
-		result[i] = rows[i].toDomain()
+	var rows []vehicleRow
+	query := `
+		SELECT id::text, fleet_id::text, make, model, year, license_plate, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
+		FROM vehicles
+		WHERE fleet_id = $1 AND deleted_at IS NULL` + st.clause
+
+	if err := r.db.reader(ctx).SelectContext(ctx, &rows, query, st.args...); err != nil {
+		return nil, err
 	}
 
-	return result, nil
+	return toPage(vehicleListSpec, st, rows, (*vehicleRow).toDomain), nil
 }
 
-// SoftDelete marks a vehicle as deleted.
-func (r *VehicleRepository) SoftDelete(ctx context.Context, id string) error {
+// ExistsByLicensePlate reports whether a non-deleted vehicle other than excludeID has the given license plate.
+func (r *VehicleRepository) ExistsByLicensePlate(ctx context.Context, licensePlate, excludeID string) (bool, error) {
+	var exists bool
+	const query = `
+		SELECT EXISTS (
+			SELECT 1 FROM vehicles
+			WHERE license_plate = $1 AND ($2 = '' OR id::text != $2) AND deleted_at IS NULL
+		)
+	`
+	if err := r.db.reader(ctx).GetContext(ctx, &exists, query, licensePlate, excludeID); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// ExistsByFleetID reports whether a fleet has non-deleted vehicles.
+func (r *VehicleRepository) ExistsByFleetID(ctx context.Context, fleetID string) (bool, error) {
+	var exists bool
+	const query = `SELECT EXISTS (SELECT 1 FROM vehicles WHERE fleet_id = $1 AND deleted_at IS NULL)`
+	if err := r.db.reader(ctx).GetContext(ctx, &exists, query, fleetID); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// SoftDelete marks a vehicle as deleted by d.
+func (r *VehicleRepository) SoftDelete(ctx context.Context, id string, d ports.Deletion) error {
 	const query = `
 		UPDATE vehicles
-		SET deleted_at = NOW()
+		SET deleted_at = $2, deleted_by = $3, delete_reason = $4, deletion_id = $5, version = version + 1
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	res, err := r.db.Master().ExecContext(ctx, query, id)
+	res, err := r.db.writer(ctx).ExecContext(ctx, query, id, d.At, d.By, d.Reason, d.ID)
 	if err != nil {
 		return err
 	}
@@ -99,7 +188,7 @@ func (r *VehicleRepository) SoftDelete(ctx context.Context, id string) error {
 	if n == 0 {
 		var n2 int
 		const checkQuery = `SELECT 1 FROM vehicles WHERE id = $1 AND deleted_at IS NOT NULL`
-		switch err := r.db.Master().GetContext(ctx, &n2, checkQuery, id); {
+		switch err := r.db.writer(ctx).GetContext(ctx, &n2, checkQuery, id); {
 		case err == nil:
 			return domain.ErrAlreadyDeleted
 		case errors.Is(err, sql.ErrNoRows):
@@ -112,13 +201,72 @@ func (r *VehicleRepository) SoftDelete(ctx context.Context, id string) error {
 	return nil
 }
 
+// SoftDeleteByFleetID marks all non-deleted vehicles of a fleet as deleted by d.
+func (r *VehicleRepository) SoftDeleteByFleetID(
+	ctx context.Context,
+	fleetID string,
+	d ports.Deletion,
+) ([]*domain.Vehicle, error) {
+	var rows []vehicleRow
+	const query = `
+		WITH deleted AS (
+			SELECT id::text, fleet_id::text, make, model, year, license_plate, version,
+				created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
+			FROM vehicles
+			WHERE fleet_id = $1 AND deleted_at IS NULL
+			FOR UPDATE
+		)
+		UPDATE vehicles
+		SET deleted_at = $2, deleted_by = $3, delete_reason = $4, deletion_id = $5, version = vehicles.version + 1
+		FROM deleted
+		WHERE vehicles.id = deleted.id::uuid
+		RETURNING deleted.*
+	`
+	if err := r.db.writer(ctx).SelectContext(ctx, &rows, query, fleetID, d.At, d.By, d.Reason, d.ID); err != nil {
+		return nil, err
+	}
+	return toDomainAll(rows, (*vehicleRow).toDomain), nil
+}
+
+// SoftDeleteByLegalEntityID marks all non-deleted vehicles in the fleets of a legal entity as deleted by d.
+func (r *VehicleRepository) SoftDeleteByLegalEntityID(
+	ctx context.Context,
+	legalEntityID string,
+	d ports.Deletion,
+) ([]*domain.Vehicle, error) {
+	var rows []vehicleRow
+	const query = `
+		WITH deleted AS (
+			SELECT id::text, fleet_id::text, make, model, year, license_plate, version,
+				created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
+			FROM vehicles
+			WHERE fleet_id IN (SELECT id FROM fleets WHERE legal_entity_id = $1) AND deleted_at IS NULL
+			FOR UPDATE
+		)
+		UPDATE vehicles
+		SET deleted_at = $2, deleted_by = $3, delete_reason = $4, deletion_id = $5, version = vehicles.version + 1
+		FROM deleted
+		WHERE vehicles.id = deleted.id::uuid
+		RETURNING deleted.*
+	`
+	if err := r.db.writer(ctx).SelectContext(ctx, &rows, query, legalEntityID, d.At, d.By, d.Reason, d.ID); err != nil {
+		return nil, err
+	}
+	return toDomainAll(rows, (*vehicleRow).toDomain), nil
+}
+
 // Undelete restores a soft-deleted vehicle.
-func (r *VehicleRepository) Undelete(ctx context.Context, id string) error {
-	const query = `UPDATE vehicles SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+func (r *VehicleRepository) Undelete(ctx context.Context, id string, at time.Time, by string) error {
+	const query = `
+		UPDATE vehicles
+		SET deleted_at = NULL, deleted_by = '', delete_reason = '', deletion_id = NULL,
+			updated_at = $2, updated_by = $3, version = version + 1
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
 
-	res, err := r.db.Master().ExecContext(ctx, query, id)
+	res, err := r.db.writer(ctx).ExecContext(ctx, query, id, at, by)
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 
 	n, err := res.RowsAffected()
@@ -128,7 +276,7 @@ func (r *VehicleRepository) Undelete(ctx context.Context, id string) error {
 	if n == 0 {
 		var n2 int
 		const checkQuery = `SELECT 1 FROM vehicles WHERE id = $1 AND deleted_at IS NULL`
-		switch err := r.db.Master().GetContext(ctx, &n2, checkQuery, id); {
+		switch err := r.db.writer(ctx).GetContext(ctx, &n2, checkQuery, id); {
 		case err == nil:
 			return fmt.Errorf("%w: entity is not deleted", domain.ErrConflict)
 		case errors.Is(err, sql.ErrNoRows):
@@ -140,3 +288,53 @@ func (r *VehicleRepository) Undelete(ctx context.Context, id string) error {
 
 	return nil
 }
+
+// vehicleParentsLive restricts a vehicles query to vehicles whose fleet is not deleted.
+const vehicleParentsLive = `
+	AND EXISTS (SELECT 1 FROM fleets f WHERE f.id = vehicles.fleet_id AND f.deleted_at IS NULL)
+`
+
+// UndeleteByFleetID restores the vehicles of a fleet that were deleted by deletionID.
+func (r *VehicleRepository) UndeleteByFleetID(
+	ctx context.Context,
+	fleetID, deletionID string,
+	at time.Time,
+	by string,
+) ([]*domain.Vehicle, error) {
+	var rows []vehicleRow
+	const query = `
+		UPDATE vehicles
+		SET deleted_at = NULL, deleted_by = '', delete_reason = '', deletion_id = NULL,
+			updated_at = $3, updated_by = $4, version = version + 1
+		WHERE fleet_id = $1 AND deletion_id = $2 AND deleted_at IS NOT NULL` + vehicleParentsLive + `
+		RETURNING id::text, fleet_id::text, make, model, year, license_plate, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
+	`
+	if err := r.db.writer(ctx).SelectContext(ctx, &rows, query, fleetID, deletionID, at, by); err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainAll(rows, (*vehicleRow).toDomain), nil
+}
+
+// UndeleteByLegalEntityID restores the vehicles in the fleets of a legal entity that were deleted by deletionID.
+func (r *VehicleRepository) UndeleteByLegalEntityID(
+	ctx context.Context,
+	legalEntityID, deletionID string,
+	at time.Time,
+	by string,
+) ([]*domain.Vehicle, error) {
+	var rows []vehicleRow
+	const query = `
+		UPDATE vehicles
+		SET deleted_at = NULL, deleted_by = '', delete_reason = '', deletion_id = NULL,
+			updated_at = $3, updated_by = $4, version = version + 1
+		WHERE fleet_id IN (SELECT id FROM fleets WHERE legal_entity_id = $1)
+			AND deletion_id = $2 AND deleted_at IS NOT NULL` + vehicleParentsLive + `
+		RETURNING id::text, fleet_id::text, make, model, year, license_plate, version,
+			created_at, created_by, updated_at, updated_by, deleted_at, deleted_by, delete_reason
+	`
+	if err := r.db.writer(ctx).SelectContext(ctx, &rows, query, legalEntityID, deletionID, at, by); err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainAll(rows, (*vehicleRow).toDomain), nil
+}
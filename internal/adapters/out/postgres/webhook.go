@@ -0,0 +1,344 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+)
+
+// WebhookSubscriptionRepository implements ports.WebhookSubscriptionRepository.
+type WebhookSubscriptionRepository struct {
+	db *DB
+}
+
+// NewWebhookSubscriptionRepository creates a new WebhookSubscriptionRepository.
+func NewWebhookSubscriptionRepository(db *DB) *WebhookSubscriptionRepository {
+	return &WebhookSubscriptionRepository{db: db}
+}
+
+// Save inserts or updates a subscription.
+func (r *WebhookSubscriptionRepository) Save(ctx context.Context, sub *domain.WebhookSubscription) error {
+	row := webhookSubscriptionToRow(sub)
+	const query = `
+		INSERT INTO webhook_subscriptions (
+			id, url, event_types, secret, active, consecutive_failures, disabled_at,
+			created_at, created_by, updated_at, updated_by
+		)
+		VALUES (
+			:id, :url, :event_types, :secret, :active, :consecutive_failures, :disabled_at,
+			:created_at, :created_by, :updated_at, :updated_by
+		)
+		ON CONFLICT (id) DO UPDATE SET
+			url = EXCLUDED.url,
+			event_types = EXCLUDED.event_types,
+			secret = EXCLUDED.secret,
+			active = EXCLUDED.active,
+			consecutive_failures = EXCLUDED.consecutive_failures,
+			disabled_at = EXCLUDED.disabled_at,
+			updated_at = EXCLUDED.updated_at,
+			updated_by = EXCLUDED.updated_by,
+			version = webhook_subscriptions.version + 1
+		WHERE webhook_subscriptions.version = :version
+		RETURNING version
+	`
+	version, err := saveVersioned(ctx, r.db.writer(ctx), query, row)
+	if err != nil {
+		return err
+	}
+	sub.Version = version
+	return nil
+}
+
+// FindByID returns a subscription by ID.
+func (r *WebhookSubscriptionRepository) FindByID(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	var row webhookSubscriptionRow
+	const query = `
+		SELECT id::text, url, event_types, secret, active, consecutive_failures, disabled_at, version,
+			created_at, created_by, updated_at, updated_by
+		FROM webhook_subscriptions
+		WHERE id = $1
+	`
+	if err := r.db.reader(ctx).GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return row.toDomain(), nil
+}
+
+var webhookSubscriptionListSpec = &listSpec[webhookSubscriptionRow]{
+	sorts: map[string]sortColumn[webhookSubscriptionRow]{
+		"id":         {listColumn{"id", "uuid"}, func(r *webhookSubscriptionRow) string { return r.ID }},
+		"url":        {listColumn{"url", "text"}, func(r *webhookSubscriptionRow) string { return r.URL }},
+		"created_at": {listColumn{"created_at", "timestamptz"}, func(r *webhookSubscriptionRow) string { return timestampValue(r.CreatedAt) }},
+	},
+	filters: map[string]listColumn{
+		"url":    {"url", "text"},
+		"active": {"active", "boolean"},
+	},
+	defaultSort: "id",
+	id:          func(r *webhookSubscriptionRow) string { return r.ID },
+}
+
+// FindAll returns a page of subscriptions, sorted by ID unless q says otherwise.
+func (r *WebhookSubscriptionRepository) FindAll(ctx context.Context, q ports.ListQuery) (*ports.Page[*domain.WebhookSubscription], error) {
+	st, err := webhookSubscriptionListSpec.build(q, nil)
+	if err != nil {
+		return nil, err
+	}
+	var rows []webhookSubscriptionRow
+	query := `
+		SELECT id::text, url, event_types, secret, active, consecutive_failures, disabled_at, version,
+			created_at, created_by, updated_at, updated_by
+		FROM webhook_subscriptions
+		WHERE TRUE` + st.clause
+	if err := r.db.reader(ctx).SelectContext(ctx, &rows, query, st.args...); err != nil {
+		return nil, err
+	}
+	return toPage(webhookSubscriptionListSpec, st, rows, (*webhookSubscriptionRow).toDomain), nil
+}
+
+// FindActiveByEventType returns the active subscriptions whose event types contain t.
+func (r *WebhookSubscriptionRepository) FindActiveByEventType(ctx context.Context, t domain.EventType) ([]*domain.WebhookSubscription, error) {
+	var rows []webhookSubscriptionRow
+	const query = `
+		SELECT id::text, url, event_types, secret, active, consecutive_failures, disabled_at, version,
+			created_at, created_by, updated_at, updated_by
+		FROM webhook_subscriptions
+		WHERE active AND event_types @> jsonb_build_array($1::text)
+		ORDER BY id
+	`
+	if err := r.db.reader(ctx).SelectContext(ctx, &rows, query, string(t)); err != nil {
+		return nil, err
+	}
+	subs := make([]*domain.WebhookSubscription, len(rows))
+	for i := range rows {
+		subs[i] = rows[i].toDomain()
+	}
+	return subs, nil
+}
+
+// Delete removes a subscription; its deliveries are removed by the foreign key cascade.
+func (r *WebhookSubscriptionRepository) Delete(ctx context.Context, id string) error {
+	res, err := r.db.writer(ctx).ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// RecordFailure increments the failure count in a single statement, so concurrent
+// deliveries to the same subscription count every failure.
+func (r *WebhookSubscriptionRepository) RecordFailure(ctx context.Context, id string, limit int, at time.Time) (bool, error) {
+	var disabled bool
+	const query = `
+		UPDATE webhook_subscriptions s
+		SET consecutive_failures = s.consecutive_failures + 1,
+			active = s.active AND s.consecutive_failures + 1 < $2,
+			disabled_at = CASE WHEN s.active AND s.consecutive_failures + 1 >= $2 THEN $3 ELSE s.disabled_at END,
+			version = s.version + 1
+		FROM (SELECT id, active FROM webhook_subscriptions WHERE id = $1 FOR UPDATE) old
+		WHERE s.id = old.id
+		RETURNING old.active AND NOT s.active
+	`
+	if err := r.db.writer(ctx).GetContext(ctx, &disabled, query, id, limit, at); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, domain.ErrNotFound
+		}
+		return false, err
+	}
+	return disabled, nil
+}
+
+// ResetFailures clears the failure count; it leaves the row untouched when it is already zero.
+func (r *WebhookSubscriptionRepository) ResetFailures(ctx context.Context, id string) error {
+	const query = `
+		UPDATE webhook_subscriptions
+		SET consecutive_failures = 0, version = version + 1
+		WHERE id = $1 AND consecutive_failures > 0
+	`
+	_, err := r.db.writer(ctx).ExecContext(ctx, query, id)
+	return err
+}
+
+// WebhookDeliveryRepository implements ports.WebhookDeliveryRepository.
+type WebhookDeliveryRepository struct {
+	db *DB
+}
+
+// NewWebhookDeliveryRepository creates a new WebhookDeliveryRepository.
+func NewWebhookDeliveryRepository(db *DB) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{db: db}
+}
+
+// Create inserts a delivery unless the subscription already has one for the event.
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, d *domain.WebhookDelivery) error {
+	row, err := webhookDeliveryToRow(d)
+	if err != nil {
+		return err
+	}
+	const query = `
+		INSERT INTO webhook_deliveries (
+			id, subscription_id, event_id, event_type, payload, occurred_at,
+			status, next_attempt_at, created_at
+		)
+		VALUES (
+			:id, :subscription_id, :event_id, :event_type, :payload, :occurred_at,
+			:status, :next_attempt_at, :created_at
+		)
+		ON CONFLICT ON CONSTRAINT uq_webhook_deliveries_event DO NOTHING
+	`
+	_, err = sqlx.NamedExecContext(ctx, r.db.writer(ctx), query, row)
+	return err
+}
+
+// FindByID returns a delivery by ID.
+func (r *WebhookDeliveryRepository) FindByID(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	var row webhookDeliveryRow
+	const query = `
+		SELECT id::text, subscription_id::text, event_id::text, event_type, payload, occurred_at,
+			status, attempts, last_status_code, last_error, next_attempt_at, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE id = $1
+	`
+	if err := r.db.reader(ctx).GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return row.toDomain(), nil
+}
+
+var webhookDeliveryListSpec = &listSpec[webhookDeliveryRow]{
+	sorts: map[string]sortColumn[webhookDeliveryRow]{
+		"created_at": {listColumn{"created_at", "timestamptz"}, func(r *webhookDeliveryRow) string { return timestampValue(r.CreatedAt) }},
+		"attempts":   {listColumn{"attempts", "integer"}, func(r *webhookDeliveryRow) string { return strconv.Itoa(r.Attempts) }},
+	},
+	filters: map[string]listColumn{
+		"status":     {"status", "text"},
+		"event_type": {"event_type", "text"},
+	},
+	defaultSort: "created_at",
+	id:          func(r *webhookDeliveryRow) string { return r.ID },
+}
+
+// FindBySubscriptionID returns a page of the deliveries to a subscription, sorted by
+// creation time unless q says otherwise.
+func (r *WebhookDeliveryRepository) FindBySubscriptionID(ctx context.Context, subscriptionID string, q ports.ListQuery) (*ports.Page[*domain.WebhookDelivery], error) {
+	st, err := webhookDeliveryListSpec.build(q, []any{subscriptionID})
+	if err != nil {
+		return nil, err
+	}
+	var rows []webhookDeliveryRow
+	query := `
+		SELECT id::text, subscription_id::text, event_id::text, event_type, payload, occurred_at,
+			status, attempts, last_status_code, last_error, next_attempt_at, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE subscription_id = $1` + st.clause
+	if err := r.db.reader(ctx).SelectContext(ctx, &rows, query, st.args...); err != nil {
+		return nil, err
+	}
+	return toPage(webhookDeliveryListSpec, st, rows, (*webhookDeliveryRow).toDomain), nil
+}
+
+// Claim leases due pending deliveries. SKIP LOCKED lets several dispatchers claim
+// disjoint batches concurrently.
+func (r *WebhookDeliveryRepository) Claim(ctx context.Context, limit int, now, leaseUntil time.Time) ([]*domain.WebhookDelivery, error) {
+	var rows []webhookDeliveryRow
+	const query = `
+		UPDATE webhook_deliveries
+		SET locked_until = $3
+		WHERE id IN (
+			SELECT d.id FROM webhook_deliveries d
+			JOIN webhook_subscriptions s ON s.id = d.subscription_id
+			WHERE d.status = 'pending' AND s.active AND d.next_attempt_at <= $1
+				AND (d.locked_until IS NULL OR d.locked_until <= $1)
+			ORDER BY d.next_attempt_at, d.id
+			LIMIT $2
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		RETURNING id::text, subscription_id::text, event_id::text, event_type, payload, occurred_at,
+			status, attempts, last_status_code, last_error, next_attempt_at, created_at, delivered_at
+	`
+	if err := r.db.writer(ctx).SelectContext(ctx, &rows, query, now, limit, leaseUntil); err != nil {
+		return nil, err
+	}
+	// RETURNING does not keep the order of the subquery.
+	slices.SortFunc(rows, func(a, b webhookDeliveryRow) int {
+		if c := a.NextAttemptAt.Compare(b.NextAttemptAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	deliveries := make([]*domain.WebhookDelivery, len(rows))
+	for i := range rows {
+		deliveries[i] = rows[i].toDomain()
+	}
+	return deliveries, nil
+}
+
+// MarkSucceeded records a successful attempt and releases the lease.
+func (r *WebhookDeliveryRepository) MarkSucceeded(ctx context.Context, id string, statusCode int, at time.Time) error {
+	const query = `
+		UPDATE webhook_deliveries
+		SET status = 'succeeded', attempts = attempts + 1, last_status_code = $2, last_error = '',
+			delivered_at = $3, locked_until = NULL
+		WHERE id = $1
+	`
+	_, err := r.db.writer(ctx).ExecContext(ctx, query, id, statusCode, at)
+	return err
+}
+
+// MarkFailed records a failed attempt, schedules the retry or gives up, and releases the lease.
+func (r *WebhookDeliveryRepository) MarkFailed(ctx context.Context, id string, statusCode int, cause string, retryAt *time.Time) error {
+	const query = `
+		UPDATE webhook_deliveries
+		SET status = CASE WHEN $4::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+			attempts = attempts + 1, last_status_code = $2, last_error = $3,
+			next_attempt_at = COALESCE($4, next_attempt_at), locked_until = NULL
+		WHERE id = $1
+	`
+	_, err := r.db.writer(ctx).ExecContext(ctx, query, id, statusCode, cause, retryAt)
+	return err
+}
+
+// Reschedule makes a finished delivery pending again. It fails with domain.ErrConflict
+// when the delivery is still pending.
+func (r *WebhookDeliveryRepository) Reschedule(ctx context.Context, id string, at time.Time) error {
+	const query = `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = $2, delivered_at = NULL, locked_until = NULL
+		WHERE id = $1 AND status != 'pending'
+	`
+	res, err := r.db.writer(ctx).ExecContext(ctx, query, id, at)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: delivery is pending", domain.ErrConflict)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package publisher
+
+import (
+	"net/http"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/config"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// Module provides the domain event publishers. Events are always logged; they are
+// also delivered by webhook when EVENTS_WEBHOOK_URL is set.
+func Module() fx.Option {
+	return fx.Module("publisher",
+		fx.Provide(
+			fx.Annotate(
+				newPublishers,
+				fx.ResultTags(`group:"event_publishers,flatten"`),
+			),
+		),
+	)
+}
+
+func newPublishers(cfg *config.EventsConfig, logger *zap.Logger) []ports.EventPublisher {
+	publishers := []ports.EventPublisher{NewLogPublisher(logger)}
+	if cfg == nil || cfg.WebhookURL == "" {
+		logger.Info("EVENTS_WEBHOOK_URL not set, events are not delivered by webhook")
+		return publishers
+	}
+	return append(publishers, NewWebhookPublisher(cfg.WebhookURL, &http.Client{Timeout: webhookTimeout}))
+}
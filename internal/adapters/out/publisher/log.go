@@ -0,0 +1,39 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+
+	"go.uber.org/zap"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+)
+
+// LogPublisher implements ports.EventPublisher by writing every event to the log.
+type LogPublisher struct {
+	logger *zap.Logger
+}
+
+// NewLogPublisher creates a new LogPublisher.
+func NewLogPublisher(logger *zap.Logger) *LogPublisher {
+	return &LogPublisher{logger: logger}
+}
+
+// Publish logs the event.
+func (p *LogPublisher) Publish(_ context.Context, event *domain.Event) error {
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return err
+	}
+	p.logger.Info("Published event",
+		zap.String("id", event.ID),
+		zap.String("type", string(event.Type)),
+		zap.Time("occurred_at", event.OccurredAt),
+		zap.ByteString("payload", payload),
+	)
+	return nil
+}
+
+// Ensure LogPublisher implements ports.EventPublisher.
+var _ ports.EventPublisher = (*LogPublisher)(nil)
@@ -0,0 +1,72 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+)
+
+// envelope is the JSON body of a webhook delivery.
+type envelope struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Payload    any       `json:"payload"`
+}
+
+// WebhookPublisher implements ports.EventPublisher by POSTing every event to a URL.
+// Any response other than 2xx counts as a failed delivery.
+type WebhookPublisher struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookPublisher creates a new WebhookPublisher.
+func NewWebhookPublisher(url string, client *http.Client) *WebhookPublisher {
+	return &WebhookPublisher{url: url, client: client}
+}
+
+// EncodeEvent returns the JSON body under which event is delivered by webhook.
+func EncodeEvent(event *domain.Event) ([]byte, error) {
+	return json.Marshal(envelope{
+		ID:         event.ID,
+		Type:       string(event.Type),
+		OccurredAt: event.OccurredAt,
+		Payload:    event.Payload,
+	})
+}
+
+// Publish delivers the event. The X-Event-ID header lets the receiver deduplicate redeliveries.
+func (p *WebhookPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	body, err := EncodeEvent(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", event.ID)
+	req.Header.Set("X-Event-Type", string(event.Type))
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck // the response is fully handled below
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Ensure WebhookPublisher implements ports.EventPublisher.
+var _ ports.EventPublisher = (*WebhookPublisher)(nil)
@@ -0,0 +1,57 @@
+package publisher_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/out/publisher"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+)
+
+func TestWebhookPublisher_Publish(t *testing.T) {
+	var got *http.Request
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		b, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(b, &body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	event := &domain.Event{
+		ID:         "e1",
+		Type:       domain.EventContractTerminated,
+		OccurredAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		Payload:    json.RawMessage(`{"contract_id":"c1"}`),
+	}
+	p := publisher.NewWebhookPublisher(srv.URL, srv.Client())
+	require.NoError(t, p.Publish(t.Context(), event))
+
+	assert.Equal(t, http.MethodPost, got.Method)
+	assert.Equal(t, "application/json", got.Header.Get("Content-Type"))
+	assert.Equal(t, "e1", got.Header.Get("X-Event-ID"))
+	assert.Equal(t, "contract.terminated", got.Header.Get("X-Event-Type"))
+	assert.Equal(t, "e1", body["id"])
+	assert.Equal(t, "contract.terminated", body["type"])
+	assert.Equal(t, "2025-06-01T12:00:00Z", body["occurred_at"])
+	assert.Equal(t, map[string]any{"contract_id": "c1"}, body["payload"])
+}
+
+func TestWebhookPublisher_Publish_FailsOnErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	p := publisher.NewWebhookPublisher(srv.URL, srv.Client())
+	err := p.Publish(t.Context(), &domain.Event{ID: "e1", Payload: json.RawMessage(`{}`)})
+	assert.ErrorContains(t, err, "503")
+}
@@ -0,0 +1,30 @@
+package webhook
+
+import (
+	"net/http"
+	"time"
+
+	"go.uber.org/fx"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+)
+
+const sendTimeout = 10 * time.Second
+
+// Module provides webhook deliveries to partner subscriptions: the signing sender,
+// and the dispatcher as an event publisher so every relayed event is scheduled for
+// delivery to the subscriptions for its type.
+func Module() fx.Option {
+	return fx.Module("webhook",
+		fx.Provide(
+			fx.Annotate(
+				func() *Sender { return NewSender(&http.Client{Timeout: sendTimeout}) },
+				fx.As(new(ports.WebhookSender)),
+			),
+			fx.Annotate(
+				func(d ports.WebhookDispatcher) ports.EventPublisher { return d },
+				fx.ResultTags(`group:"event_publishers"`),
+			),
+		),
+	)
+}
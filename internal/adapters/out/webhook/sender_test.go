@@ -0,0 +1,58 @@
+package webhook_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/adapters/out/webhook"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+)
+
+func TestSender_Send_SignsBody(t *testing.T) {
+	const secret = "0123456789abcdef"
+	var header http.Header
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	event := &domain.Event{
+		ID:         "e1",
+		Type:       domain.EventAssignmentReturned,
+		OccurredAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		Payload:    json.RawMessage(`{"assignment_id":"a1"}`),
+	}
+	status, err := webhook.NewSender(srv.Client()).Send(t.Context(), srv.URL, secret, event)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, status)
+
+	timestamp := header.Get(webhook.TimestampHeader)
+	require.NotEmpty(t, timestamp)
+	assert.Equal(t, webhook.Sign(secret, timestamp, body), header.Get(webhook.SignatureHeader))
+	assert.NotEqual(t, webhook.Sign("another-secret!!", timestamp, body), header.Get(webhook.SignatureHeader))
+	assert.Equal(t, "e1", header.Get("X-Event-ID"))
+	assert.JSONEq(t,
+		`{"id":"e1","type":"assignment.returned","occurred_at":"2025-06-01T12:00:00Z","payload":{"assignment_id":"a1"}}`,
+		string(body))
+}
+
+func TestSender_Send_ReturnsErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer srv.Close()
+
+	status, err := webhook.NewSender(srv.Client()).Send(t.Context(), srv.URL, "secret", &domain.Event{ID: "e1"})
+	assert.Equal(t, http.StatusGone, status)
+	assert.ErrorContains(t, err, "410")
+}
@@ -0,0 +1,8 @@
+package config
+
+// AdminConfig holds configuration for the admin HTTP server, which serves profiling,
+// runtime information and the log level apart from the public API.
+type AdminConfig struct {
+	// Addr is the address of the admin listener; it binds to localhost by default.
+	Addr string `yaml:"addr" toml:"addr" env:"ADMIN_HTTP_ADDR"`
+}
@@ -0,0 +1,23 @@
+package config
+
+// AuthConfig holds configuration for authenticating API requests.
+type AuthConfig struct {
+	// Enabled turns authentication on; it is meant to be off only in local development.
+	Enabled bool `yaml:"enabled" toml:"enabled" env:"AUTH_ENABLED"`
+	// JWTHS256SecretFile holds the shared secret of HS256 tokens.
+	JWTHS256SecretFile string `yaml:"jwt_hs256_secret_file" toml:"jwt_hs256_secret_file" env:"AUTH_JWT_HS256_SECRET_FILE"`
+	// JWTRS256PublicKeyFile holds the PEM public key of RS256 tokens.
+	JWTRS256PublicKeyFile string `yaml:"jwt_rs256_public_key_file" toml:"jwt_rs256_public_key_file" env:"AUTH_JWT_RS256_PUBLIC_KEY_FILE"`
+	// JWTJWKSFile holds a JSON Web Key Set; tokens pick their key by the kid header.
+	JWTJWKSFile string `yaml:"jwt_jwks_file" toml:"jwt_jwks_file" env:"AUTH_JWT_JWKS_FILE"`
+	// JWTIssuer and JWTAudience, when set, must match the iss and aud token claims.
+	JWTIssuer   string `yaml:"jwt_issuer" toml:"jwt_issuer" env:"AUTH_JWT_ISSUER"`
+	JWTAudience string `yaml:"jwt_audience" toml:"jwt_audience" env:"AUTH_JWT_AUDIENCE"`
+	// APIKeysEnabled accepts static API keys in the X-API-Key header.
+	APIKeysEnabled bool `yaml:"api_keys_enabled" toml:"api_keys_enabled" env:"AUTH_API_KEYS_ENABLED"`
+}
+
+// JWTEnabled reports whether any token verification key is configured.
+func (c *AuthConfig) JWTEnabled() bool {
+	return c.JWTHS256SecretFile != "" || c.JWTRS256PublicKeyFile != "" || c.JWTJWKSFile != ""
+}
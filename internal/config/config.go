@@ -3,71 +3,195 @@ package config
 import (
 	"errors"
 	"fmt"
-	"os"
-	"strconv"
+	"reflect"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
+// Config is the configuration of the service. It is built in layers: defaults, then
+// an optional YAML or TOML file, then environment variables. Every setting has the
+// file key of its yaml and toml tags and the variable of its env tag; settings tagged
+// secret can also be read from the file named by the variable with a _FILE suffix.
 type Config struct {
-	Telemetry         *TelemetryConfig
-	Database          *DatabaseConfig
-	HTTPServer        *HTTPServerConfig
-	DriverLicenseGRPC *DriverLicenseGRPCConfig
+	Telemetry         *TelemetryConfig         `yaml:"telemetry" toml:"telemetry"`
+	Database          *DatabaseConfig          `yaml:"database" toml:"database"`
+	HTTPServer        *HTTPServerConfig        `yaml:"http_server" toml:"http_server"`
+	DriverLicenseGRPC *DriverLicenseGRPCConfig `yaml:"driver_license_grpc" toml:"driver_license_grpc"`
+	Events            *EventsConfig            `yaml:"events" toml:"events"`
+	Auth              *AuthConfig              `yaml:"auth" toml:"auth"`
+	Health            *HealthConfig            `yaml:"health" toml:"health"`
+	Admin             *AdminConfig             `yaml:"admin" toml:"admin"`
 }
 
-func LoadFromEnv() (*Config, error) {
-	tlsEnabledEnv := getEnv("DRIVER_LICENSE_GRPC_TLS", "false")
-	tlsEnabled, err := strconv.ParseBool(tlsEnabledEnv)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse DRIVER_LICENSE_GRPC_TLS: %w", err)
-	}
-
-	cfg := &Config{
+// Defaults returns the config used for every setting that neither the config file nor
+// the environment sets.
+func Defaults() *Config {
+	return &Config{
 		Telemetry: &TelemetryConfig{
-			LogLevel: getEnv("LOG_LEVEL", "debug"),
+			LogLevel:           "debug",
+			ServiceName:        "fleet-service",
+			TracingExporter:    TracingExporterNone,
+			TracingSampleRatio: 1,
 		},
 		Database: &DatabaseConfig{
-			MasterURL:  getEnv("DATABASE_MASTER_URL", ""),
-			ReplicaURL: getEnv("DATABASE_REPLICA_URL", ""),
+			ReplicaMaxLag:  30 * time.Second,
+			ConnectTimeout: 10 * time.Second,
+			Master:         defaultDatabasePool(),
+			Replica:        defaultDatabasePool(),
 		},
 		HTTPServer: &HTTPServerConfig{
-			Addr: getEnv("HTTP_ADDR", ":8080"),
+			Addr: ":8080",
+		},
+		DriverLicenseGRPC: &DriverLicenseGRPCConfig{},
+		Events: &EventsConfig{
+			RelayInterval: time.Second,
+		},
+		Auth: &AuthConfig{
+			Enabled:        true,
+			APIKeysEnabled: true,
 		},
-		DriverLicenseGRPC: &DriverLicenseGRPCConfig{
-			Addr:       getEnv("DRIVER_LICENSE_GRPC_ADDR", ""),
-			TLSEnabled: tlsEnabled,
+		Health: &HealthConfig{
+			CheckTimeout: 2 * time.Second,
+		},
+		Admin: &AdminConfig{
+			Addr: "localhost:9091",
 		},
 	}
+}
 
+// Load builds the config from defaults, the config file at path unless path is empty,
+// and environment variables, each layer overriding the previous one. It does not
+// validate the result.
+func Load(path string) (*Config, error) {
+	cfg := Defaults()
+	if path != "" {
+		if err := loadFile(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+	if err := loadEnv(reflect.ValueOf(cfg).Elem(), ""); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
 
-// Validate checks config consistency and required fields. Logs each error and returns
+func defaultDatabasePool() DatabasePoolConfig {
+	return DatabasePoolConfig{
+		MaxOpenConns:     25,
+		MaxIdleConns:     5,
+		ConnMaxLifetime:  30 * time.Minute,
+		ConnMaxIdleTime:  5 * time.Minute,
+		QueryTimeout:     10 * time.Second,
+		StatementTimeout: 30 * time.Second,
+		ApplicationName:  "fleet-service",
+	}
+}
+
+// LoadFromEnv builds the config from defaults and environment variables only.
+func LoadFromEnv() (*Config, error) {
+	return Load("")
+}
+
+// Validate checks every section of the config that is set. Logs each error and returns
 // an aggregated error if any validations failed (app will stop).
 func (c *Config) Validate(logger *zap.Logger) error {
 	var errs []error
+	invalid := func(setting string, value any, err error) {
+		logger.Error("invalid "+setting, zap.Any("value", value), zap.Error(err))
+		errs = append(errs, fmt.Errorf("invalid %s: %w", setting, err))
+	}
 
-	// Validate LOG_LEVEL
-	var lvl zapcore.Level
-	if err := lvl.UnmarshalText([]byte(c.Telemetry.LogLevel)); err != nil {
-		logger.Error("invalid LOG_LEVEL", zap.String("value", c.Telemetry.LogLevel), zap.Error(err))
-		errs = append(errs, err)
+	if t := c.Telemetry; t != nil {
+		var lvl zapcore.Level
+		if err := lvl.UnmarshalText([]byte(t.LogLevel)); err != nil {
+			invalid("LOG_LEVEL", t.LogLevel, err)
+		}
+		if t.ServiceName == "" {
+			invalid("SERVICE_NAME", t.ServiceName, errRequired)
+		}
+		switch t.TracingExporter {
+		case "", TracingExporterNone, TracingExporterStdout, TracingExporterOTLP:
+		default:
+			invalid("TRACING_EXPORTER", t.TracingExporter, fmt.Errorf("unknown tracing exporter %q", t.TracingExporter))
+		}
+		if t.TracingOTLPEndpoint != "" {
+			if err := validateHostPort(t.TracingOTLPEndpoint); err != nil {
+				invalid("TRACING_OTLP_ENDPOINT", t.TracingOTLPEndpoint, err)
+			}
+		}
+		if r := t.TracingSampleRatio; r < 0 || r > 1 {
+			invalid("TRACING_SAMPLE_RATIO", r, errors.New("must be between 0 and 1"))
+		}
 	}
 
-	if len(errs) > 0 {
-		return errors.Join(errs...)
+	if d := c.Database; d != nil {
+		if d.MasterURL == "" {
+			invalid("DATABASE_MASTER_URL", d.MasterURL, errRequired)
+		} else if err := validateDatabaseURL(d.MasterURL); err != nil {
+			invalid("DATABASE_MASTER_URL", redactSecret(d.MasterURL), err)
+		}
+		if d.ReplicaURL != "" {
+			if err := validateDatabaseURL(d.ReplicaURL); err != nil {
+				invalid("DATABASE_REPLICA_URL", redactSecret(d.ReplicaURL), err)
+			}
+			if d.ReplicaMaxLag <= 0 {
+				invalid("DATABASE_REPLICA_MAX_LAG", d.ReplicaMaxLag.String(), errNotPositive)
+			}
+			validateDatabasePool("DATABASE_REPLICA_", &d.Replica, invalid)
+		}
+		if d.ConnectTimeout <= 0 {
+			invalid("DATABASE_CONNECT_TIMEOUT", d.ConnectTimeout.String(), errNotPositive)
+		}
+		validateDatabasePool("DATABASE_MASTER_", &d.Master, invalid)
 	}
 
-	return nil
-}
+	if h := c.HTTPServer; h != nil {
+		if err := validateAddr(h.Addr); err != nil {
+			invalid("HTTP_ADDR", h.Addr, err)
+		}
+	}
 
-func getEnv(env, defaultValue string) string {
-	envValue := os.Getenv(env)
-	if envValue == "" {
-		return defaultValue
+	if g := c.DriverLicenseGRPC; g != nil && g.Addr != "" {
+		if err := validateGRPCTarget(g.Addr); err != nil {
+			invalid("DRIVER_LICENSE_GRPC_ADDR", g.Addr, err)
+		}
 	}
 
-	return envValue
+	if e := c.Events; e != nil {
+		if e.RelayInterval <= 0 {
+			invalid("EVENTS_RELAY_INTERVAL", e.RelayInterval.String(), errNotPositive)
+		}
+		if e.WebhookURL != "" {
+			if err := validateHTTPURL(e.WebhookURL); err != nil {
+				invalid("EVENTS_WEBHOOK_URL", redactSecret(e.WebhookURL), err)
+			}
+		}
+	}
+
+	if a := c.Auth; a != nil && a.Enabled && !a.JWTEnabled() && !a.APIKeysEnabled {
+		invalid("AUTH_* settings", nil, errors.New("no authentication method configured"))
+	}
+
+	if h := c.Health; h != nil {
+		if h.CheckTimeout <= 0 {
+			invalid("HEALTH_CHECK_TIMEOUT", h.CheckTimeout.String(), errNotPositive)
+		}
+		if h.DrainDelay < 0 {
+			invalid("HEALTH_DRAIN_DELAY", h.DrainDelay.String(), errNegative)
+		}
+	}
+
+	if a := c.Admin; a != nil {
+		if err := validateAddr(a.Addr); err != nil {
+			invalid("ADMIN_HTTP_ADDR", a.Addr, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	return nil
 }
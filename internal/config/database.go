@@ -1,6 +1,39 @@
 package config
 
+import "time"
+
 type DatabaseConfig struct {
-	MasterURL  string
-	ReplicaURL string
+	MasterURL  string `yaml:"master_url" toml:"master_url" env:"DATABASE_MASTER_URL" secret:"true"`
+	ReplicaURL string `yaml:"replica_url" toml:"replica_url" env:"DATABASE_REPLICA_URL" secret:"true"`
+	// ReplicaMaxLag is the replication lag beyond which the replica fails readiness.
+	ReplicaMaxLag time.Duration `yaml:"replica_max_lag" toml:"replica_max_lag" env:"DATABASE_REPLICA_MAX_LAG"`
+	// ConnectTimeout bounds opening and pinging the pools at startup.
+	ConnectTimeout time.Duration `yaml:"connect_timeout" toml:"connect_timeout" env:"DATABASE_CONNECT_TIMEOUT"`
+	// Master tunes the pool of MasterURL, as DATABASE_MASTER_* variables.
+	Master DatabasePoolConfig `yaml:"master" toml:"master" env:"DATABASE_MASTER_"`
+	// Replica tunes the pool of ReplicaURL, as DATABASE_REPLICA_* variables. It is
+	// unused when there is no replica.
+	Replica DatabasePoolConfig `yaml:"replica" toml:"replica" env:"DATABASE_REPLICA_"`
+}
+
+// DatabasePoolConfig tunes a connection pool and the sessions it opens.
+type DatabasePoolConfig struct {
+	// MaxOpenConns caps the connections of the pool; 0 means no limit.
+	MaxOpenConns int `yaml:"max_open_conns" toml:"max_open_conns" env:"MAX_OPEN_CONNS"`
+	// MaxIdleConns is how many idle connections the pool keeps; 0 keeps none.
+	MaxIdleConns int `yaml:"max_idle_conns" toml:"max_idle_conns" env:"MAX_IDLE_CONNS"`
+	// ConnMaxLifetime closes connections once they are this old; 0 keeps them forever.
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime" env:"CONN_MAX_LIFETIME"`
+	// ConnMaxIdleTime closes connections idle for this long; 0 keeps them forever.
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" toml:"conn_max_idle_time" env:"CONN_MAX_IDLE_TIME"`
+	// QueryTimeout is the deadline of every statement run by a repository; 0 leaves
+	// statements bounded by the caller's context only.
+	QueryTimeout time.Duration `yaml:"query_timeout" toml:"query_timeout" env:"QUERY_TIMEOUT"`
+	// StatementTimeout is set as the statement_timeout of every session, so that the
+	// server aborts runaway statements even when the client is gone; 0 keeps the
+	// server's setting.
+	StatementTimeout time.Duration `yaml:"statement_timeout" toml:"statement_timeout" env:"STATEMENT_TIMEOUT"`
+	// ApplicationName is set as the application_name of every session; empty keeps the
+	// one of the URL, if any.
+	ApplicationName string `yaml:"application_name" toml:"application_name" env:"APPLICATION_NAME"`
 }
@@ -0,0 +1,11 @@
+package config
+
+import "time"
+
+// EventsConfig holds configuration for domain event delivery.
+type EventsConfig struct {
+	// RelayInterval is how long the relay waits before polling an empty outbox again.
+	RelayInterval time.Duration `yaml:"relay_interval" toml:"relay_interval" env:"EVENTS_RELAY_INTERVAL"`
+	// WebhookURL receives every event as a JSON POST; delivery by webhook is off when empty.
+	WebhookURL string `yaml:"webhook_url" toml:"webhook_url" env:"EVENTS_WEBHOOK_URL" secret:"true"`
+}
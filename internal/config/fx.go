@@ -5,14 +5,26 @@ import (
 	"go.uber.org/zap"
 )
 
-func Module() fx.Option {
+// Module provides the configuration loaded from the config file at path, when path is
+// not empty, and the environment.
+func Module(path string) fx.Option {
 	return fx.Module("config",
-		fx.Provide(LoadFromEnv, fx.Private),
+		fx.Provide(func() (*Config, error) { return Load(path) }, fx.Private),
 		fx.Provide(splitConfig),
+		fx.Provide(
+			fx.Annotate(
+				(*Config).Redacted,
+				fx.ResultTags(`name:"redacted"`),
+			),
+		),
 		fx.Invoke(func(c *Config, l *zap.Logger) error { return c.Validate(l) }),
 	)
 }
 
-func splitConfig(conf *Config) (*TelemetryConfig, *DatabaseConfig, *HTTPServerConfig, *DriverLicenseGRPCConfig) {
-	return conf.Telemetry, conf.Database, conf.HTTPServer, conf.DriverLicenseGRPC
+func splitConfig(conf *Config) (
+	*TelemetryConfig, *DatabaseConfig, *HTTPServerConfig, *DriverLicenseGRPCConfig, *EventsConfig, *AuthConfig,
+	*HealthConfig, *AdminConfig,
+) {
+	return conf.Telemetry, conf.Database, conf.HTTPServer, conf.DriverLicenseGRPC, conf.Events, conf.Auth,
+		conf.Health, conf.Admin
 }
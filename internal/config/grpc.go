@@ -2,6 +2,6 @@ package config
 
 // DriverLicenseGRPCConfig holds configuration for the driver license validation gRPC client.
 type DriverLicenseGRPCConfig struct {
-	Addr       string
-	TLSEnabled bool
+	Addr       string `yaml:"addr" toml:"addr" env:"DRIVER_LICENSE_GRPC_ADDR"`
+	TLSEnabled bool   `yaml:"tls_enabled" toml:"tls_enabled" env:"DRIVER_LICENSE_GRPC_TLS"`
 }
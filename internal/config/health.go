@@ -0,0 +1,12 @@
+package config
+
+import "time"
+
+// HealthConfig holds configuration for the liveness and readiness probes.
+type HealthConfig struct {
+	// CheckTimeout bounds each dependency check run by a readiness probe.
+	CheckTimeout time.Duration `yaml:"check_timeout" toml:"check_timeout" env:"HEALTH_CHECK_TIMEOUT"`
+	// DrainDelay is how long the app keeps serving with a failing readiness probe once
+	// it starts stopping, so that load balancers take it out of rotation first.
+	DrainDelay time.Duration `yaml:"drain_delay" toml:"drain_delay" env:"HEALTH_DRAIN_DELAY"`
+}
@@ -1,5 +1,5 @@
 package config
 
 type HTTPServerConfig struct {
-	Addr string
+	Addr string `yaml:"addr" toml:"addr" env:"HTTP_ADDR"`
 }
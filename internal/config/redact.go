@@ -0,0 +1,55 @@
+package config
+
+import (
+	"net/url"
+	"reflect"
+	"regexp"
+)
+
+// redactedPassword replaces passwords in redacted settings.
+const redactedPassword = "xxxxx"
+
+// dsnPassword matches the password of a key/value PostgreSQL connection string.
+var dsnPassword = regexp.MustCompile(`(password\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+
+// Redacted returns a copy of c that is safe to show: settings tagged secret are
+// masked. Settings that name secret files are kept, the secrets themselves are never
+// part of the config.
+func (c *Config) Redacted() *Config {
+	out := &Config{}
+	src, dst := reflect.ValueOf(c).Elem(), reflect.ValueOf(out).Elem()
+	for i := range src.NumField() {
+		section := src.Field(i)
+		if section.IsNil() {
+			continue
+		}
+		masked := reflect.New(section.Type().Elem())
+		masked.Elem().Set(section.Elem())
+		for j := range masked.Elem().NumField() {
+			if masked.Elem().Type().Field(j).Tag.Get("secret") == "true" {
+				f := masked.Elem().Field(j)
+				f.SetString(redactSecret(f.String()))
+			}
+		}
+		dst.Field(i).Set(masked)
+	}
+	return out
+}
+
+// redactSecret masks the password of a URL or of a key/value connection string, so
+// that the rest of it can still be checked. Any other secret is masked entirely.
+func redactSecret(s string) string {
+	if s == "" {
+		return s
+	}
+	if u, err := url.Parse(s); err == nil && u.Scheme != "" && u.Host != "" {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), redactedPassword)
+		}
+		return u.String()
+	}
+	if dsnPassword.MatchString(s) {
+		return dsnPassword.ReplaceAllString(s, "${1}"+redactedPassword)
+	}
+	return redactedPassword
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"go.yaml.in/yaml/v3"
+)
+
+// secretFileSuffix names the variable that holds the path of a file with the value of
+// a secret setting, as mounted by Docker and Kubernetes secrets.
+const secretFileSuffix = "_FILE"
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// loadFile decodes the config file at path over cfg. The format is chosen by the file
+// extension; keys that match no setting are rejected, so that typos do not go
+// unnoticed.
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	case ".toml":
+		md, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("failed to parse config file %s: unknown keys %v", path, undecoded)
+		}
+	default:
+		return fmt.Errorf("config file %s: unsupported format %q, want .yaml, .yml or .toml", path, ext)
+	}
+	return nil
+}
+
+// loadEnv sets every setting of the struct v whose env variable, prefixed with prefix,
+// is set. Empty variables count as unset. The env tag of a nested struct is the prefix
+// of the variables of its settings.
+func loadEnv(v reflect.Value, prefix string) error {
+	for i := range v.NumField() {
+		field, f := v.Type().Field(i), v.Field(i)
+		name := field.Tag.Get("env")
+		switch {
+		case f.Kind() == reflect.Pointer && f.Type().Elem().Kind() == reflect.Struct:
+			if f.IsNil() {
+				f.Set(reflect.New(f.Type().Elem()))
+			}
+			if err := loadEnv(f.Elem(), prefix+name); err != nil {
+				return err
+			}
+			continue
+		case f.Kind() == reflect.Struct:
+			if err := loadEnv(f, prefix+name); err != nil {
+				return err
+			}
+			continue
+		case name == "":
+			continue
+		}
+		name = prefix + name
+		value, err := lookupEnv(name, field.Tag.Get("secret") == "true")
+		if err != nil {
+			return err
+		}
+		if value == "" {
+			continue
+		}
+		if err := setValue(f, value); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// lookupEnv returns the value of the variable name or, for secrets, the content of the
+// file named by name_FILE.
+func lookupEnv(name string, secret bool) (string, error) {
+	value := os.Getenv(name)
+	if !secret {
+		return value, nil
+	}
+	path := os.Getenv(name + secretFileSuffix)
+	if path == "" {
+		return value, nil
+	}
+	if value != "" {
+		return "", fmt.Errorf("%s and %s%s are both set", name, name, secretFileSuffix)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s%s: %w", name, secretFileSuffix, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+func setValue(f reflect.Value, value string) error {
+	switch {
+	case f.Type() == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		f.SetInt(int64(d))
+	case f.Kind() == reflect.String:
+		f.SetString(value)
+	case f.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case f.Kind() == reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		f.SetInt(int64(n))
+	case f.Kind() == reflect.Float64:
+		x, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		f.SetFloat(x)
+	default:
+		return fmt.Errorf("unsupported setting type %s", f.Type())
+	}
+	return nil
+}
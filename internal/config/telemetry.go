@@ -1,5 +1,26 @@
 package config
 
+// Tracing exporters accepted by TRACING_EXPORTER.
+const (
+	TracingExporterNone   = "none"
+	TracingExporterStdout = "stdout"
+	TracingExporterOTLP   = "otlp"
+)
+
 type TelemetryConfig struct {
-	LogLevel string
+	LogLevel string `yaml:"log_level" toml:"log_level" env:"LOG_LEVEL"`
+	// ServiceName identifies the service in exported traces.
+	ServiceName string `yaml:"service_name" toml:"service_name" env:"SERVICE_NAME"`
+	// TracingExporter selects where spans are sent: "none" disables tracing, "stdout"
+	// writes them as JSON to TracingFile or standard output, and "otlp" sends them
+	// over gRPC to TracingOTLPEndpoint.
+	TracingExporter string `yaml:"tracing_exporter" toml:"tracing_exporter" env:"TRACING_EXPORTER"`
+	// TracingOTLPEndpoint is the host:port of the OTLP collector. When empty, the
+	// OTEL_EXPORTER_OTLP_* environment variables apply.
+	TracingOTLPEndpoint string `yaml:"tracing_otlp_endpoint" toml:"tracing_otlp_endpoint" env:"TRACING_OTLP_ENDPOINT"`
+	TracingOTLPInsecure bool   `yaml:"tracing_otlp_insecure" toml:"tracing_otlp_insecure" env:"TRACING_OTLP_INSECURE"`
+	TracingFile         string `yaml:"tracing_file" toml:"tracing_file" env:"TRACING_FILE"`
+	// TracingSampleRatio is the fraction of new traces that are sampled. Requests that
+	// carry a trace context follow the sampling decision of their caller.
+	TracingSampleRatio float64 `yaml:"tracing_sample_ratio" toml:"tracing_sample_ratio" env:"TRACING_SAMPLE_RATIO"`
 }
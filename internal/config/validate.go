@@ -0,0 +1,111 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	errRequired    = errors.New("is required")
+	errNotPositive = errors.New("must be positive")
+	errNegative    = errors.New("must not be negative")
+)
+
+// maxApplicationNameLength is the length beyond which PostgreSQL truncates
+// application_name.
+const maxApplicationNameLength = 63
+
+// validateAddr checks a listen address: an optional host and a numeric port.
+func validateAddr(addr string) error {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return fmt.Errorf("port %q is not a number between 0 and 65535", port)
+	}
+	return nil
+}
+
+// validateHostPort checks the address of a server to connect to, which needs a host.
+func validateHostPort(addr string) error {
+	if err := validateAddr(addr); err != nil {
+		return err
+	}
+	if host, _, _ := net.SplitHostPort(addr); host == "" {
+		return errors.New("host is missing")
+	}
+	return nil
+}
+
+// validateGRPCTarget checks a gRPC dial target: host:port or a URI with a resolver
+// scheme such as dns:///host:port.
+func validateGRPCTarget(target string) error {
+	if scheme, _, ok := strings.Cut(target, "://"); ok {
+		switch scheme {
+		case "dns", "unix", "unix-abstract", "passthrough":
+			_, err := url.Parse(target)
+			return err
+		default:
+			return fmt.Errorf("unsupported resolver scheme %q", scheme)
+		}
+	}
+	return validateHostPort(target)
+}
+
+// validateDatabaseURL checks a PostgreSQL connection URL or key/value string without
+// connecting.
+func validateDatabaseURL(s string) error {
+	if _, err := pgconn.ParseConfig(s); err != nil {
+		return errors.New("is not a valid PostgreSQL connection string")
+	}
+	return nil
+}
+
+// validateHTTPURL checks an absolute http or https URL.
+func validateHTTPURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return errors.New("is not a valid URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("must be an http or https URL")
+	}
+	if u.Host == "" {
+		return errors.New("host is missing")
+	}
+	return nil
+}
+
+// validateDatabasePool checks the settings of a pool, whose variables start with
+// prefix.
+func validateDatabasePool(prefix string, p *DatabasePoolConfig, invalid func(string, any, error)) {
+	if p.MaxOpenConns < 0 {
+		invalid(prefix+"MAX_OPEN_CONNS", p.MaxOpenConns, errNegative)
+	}
+	if p.MaxIdleConns < 0 {
+		invalid(prefix+"MAX_IDLE_CONNS", p.MaxIdleConns, errNegative)
+	}
+	if p.ConnMaxLifetime < 0 {
+		invalid(prefix+"CONN_MAX_LIFETIME", p.ConnMaxLifetime.String(), errNegative)
+	}
+	if p.ConnMaxIdleTime < 0 {
+		invalid(prefix+"CONN_MAX_IDLE_TIME", p.ConnMaxIdleTime.String(), errNegative)
+	}
+	if p.QueryTimeout < 0 {
+		invalid(prefix+"QUERY_TIMEOUT", p.QueryTimeout.String(), errNegative)
+	}
+	if p.StatementTimeout < 0 {
+		invalid(prefix+"STATEMENT_TIMEOUT", p.StatementTimeout.String(), errNegative)
+	}
+	if len(p.ApplicationName) > maxApplicationNameLength {
+		invalid(prefix+"APPLICATION_NAME", p.ApplicationName,
+			fmt.Errorf("must not be longer than %d bytes", maxApplicationNameLength))
+	}
+}
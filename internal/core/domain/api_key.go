@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// APIKey is a static credential of a machine client. Only the SHA-256 hash of the key is
+// stored; the key itself is handed to the client once, when it is provisioned.
+type APIKey struct {
+	ID          string
+	Name        string
+	KeyHash     string
+	PrincipalID string
+	// Role and LegalEntityIDs are the authorization of the client, see Principal.
+	Role           Role
+	LegalEntityIDs []string
+	CreatedAt      time.Time
+	ExpiresAt      *time.Time
+	RevokedAt      *time.Time
+}
+
+// Usable reports whether the key may authenticate requests at the given time.
+func (k *APIKey) Usable(at time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || at.Before(*k.ExpiresAt))
+}
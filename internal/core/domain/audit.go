@@ -0,0 +1,71 @@
+package domain
+
+import "time"
+
+// EntityType names the kind of entity an AuditEntry refers to.
+type EntityType string
+
+const (
+	EntityLegalEntity       EntityType = "legal_entity"
+	EntityFleet             EntityType = "fleet"
+	EntityVehicle           EntityType = "vehicle"
+	EntityDriver            EntityType = "driver"
+	EntityContract          EntityType = "contract"
+	EntityVehicleAssignment EntityType = "vehicle_assignment"
+	EntityWebhook           EntityType = "webhook_subscription"
+)
+
+// AuditAction names the mutation recorded by an AuditEntry.
+type AuditAction string
+
+const (
+	AuditCreate    AuditAction = "create"
+	AuditUpdate    AuditAction = "update"
+	AuditDelete    AuditAction = "delete"
+	AuditUndelete  AuditAction = "undelete"
+	AuditTerminate AuditAction = "terminate"
+	AuditAssign    AuditAction = "assign"
+	AuditReassign  AuditAction = "reassign"
+	AuditReturn    AuditAction = "return"
+)
+
+// AuditEntry records one mutation of an entity and the principal who made it.
+//
+// Before and After hold the entity state around the mutation and are nil where the
+// entity did not exist or was not live. They are stored as JSON; entries read back
+// from the audit log carry them as json.RawMessage.
+type AuditEntry struct {
+	ID         string
+	EntityType EntityType
+	EntityID   string
+	Action     AuditAction
+	Actor      string
+	At         time.Time
+	// DeletionID identifies the deletion a delete entry records or an undelete entry undoes.
+	// Entities deleted or restored together by a cascade share it.
+	DeletionID string
+	Before     any
+	After      any
+}
+
+// DeletionEntries returns an entry of entityType with the given action and deletionID for
+// each entity, using id to get its ID. Deleted entities are recorded as Before and restored
+// ones as After. The caller sets ID, Actor and At.
+func DeletionEntries[T any](
+	entityType EntityType,
+	action AuditAction,
+	deletionID string,
+	entities []*T,
+	id func(*T) string,
+) []*AuditEntry {
+	entries := make([]*AuditEntry, len(entities))
+	for i, e := range entities {
+		entries[i] = &AuditEntry{EntityType: entityType, EntityID: id(e), Action: action, DeletionID: deletionID}
+		if action == AuditDelete {
+			entries[i].Before = *e
+		} else {
+			entries[i].After = *e
+		}
+	}
+	return entries
+}
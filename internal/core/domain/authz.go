@@ -0,0 +1,48 @@
+package domain
+
+import "slices"
+
+// Role is the set of permissions granted to a Principal.
+type Role string
+
+const (
+	// RoleAdmin may do anything.
+	RoleAdmin Role = "admin"
+	// RoleFleetManager manages the fleets, vehicles, contracts and assignments of the
+	// legal entities in Principal.LegalEntityIDs.
+	RoleFleetManager Role = "fleet_manager"
+	// RoleDriver may read the contracts of the driver in Principal.DriverID.
+	RoleDriver Role = "driver"
+)
+
+// Resources that are not entities, and so never the subject of an AuditEntry.
+const (
+	ResourceAuditLog EntityType = "audit_log"
+	// ResourceEvents is the stream of domain events; its LegalEntityID is that of the
+	// events the stream is filtered to.
+	ResourceEvents EntityType = "events"
+)
+
+// Action is what a principal asks to do with a Resource.
+type Action string
+
+const (
+	ActionRead   Action = "read"
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Resource describes the entity an action applies to, as far as authorization is
+// concerned. LegalEntityID and DriverID name its owners; they are empty when the entity
+// has no such owner, or when a list is not restricted to one.
+type Resource struct {
+	Type          EntityType
+	LegalEntityID string
+	DriverID      string
+}
+
+// Manages reports whether the principal is scoped to the legal entity with the given ID.
+func (p Principal) Manages(legalEntityID string) bool {
+	return legalEntityID != "" && slices.Contains(p.LegalEntityIDs, legalEntityID)
+}
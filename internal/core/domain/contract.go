@@ -3,13 +3,40 @@ package domain
 import "time"
 
 type Contract struct {
-	ID             string
-	DriverID       string
-	LegalEntityID  string
-	FleetID        string
-	StartDate      time.Time
-	EndDate        time.Time
-	TerminatedAt   *time.Time
-	TerminatedBy   string
-	DeletedAt      *time.Time
+	ID            string
+	DriverID      string
+	LegalEntityID string
+	FleetID       string
+	StartDate     time.Time
+	EndDate       time.Time
+	TerminatedAt  *time.Time
+	TerminatedBy  string
+	Version       int64
+	CreatedAt     time.Time
+	CreatedBy     string
+	UpdatedAt     time.Time
+	UpdatedBy     string
+	DeletedAt     *time.Time
+	DeletedBy     string
+	DeleteReason  string
+	DeletionID    string
+}
+
+// ContractPatch holds the fields of a Contract update; nil fields are left unchanged.
+type ContractPatch struct {
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
+// ValidateContractParties records in invalid which parties of a new contract are missing.
+func ValidateContractParties(invalid *ValidationError, driverID, legalEntityID, fleetID string) {
+	if driverID == "" {
+		invalid.Add("driver_id", RuleRequired, "is required")
+	}
+	if legalEntityID == "" {
+		invalid.Add("legal_entity_id", RuleRequired, "is required")
+	}
+	if fleetID == "" {
+		invalid.Add("fleet_id", RuleRequired, "is required")
+	}
 }
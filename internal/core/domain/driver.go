@@ -7,5 +7,20 @@ type Driver struct {
 	FirstName     string
 	LastName      string
 	LicenseNumber string
+	Version       int64
+	CreatedAt     time.Time
+	CreatedBy     string
+	UpdatedAt     time.Time
+	UpdatedBy     string
 	DeletedAt     *time.Time
+	DeletedBy     string
+	DeleteReason  string
+	DeletionID    string
+}
+
+// DriverPatch holds the fields of a Driver update; nil fields are left unchanged.
+type DriverPatch struct {
+	FirstName     *string
+	LastName      *string
+	LicenseNumber *string
 }
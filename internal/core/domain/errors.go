@@ -6,35 +6,103 @@ import "errors"
 // Only errors implementing this interface have their message returned in HTTP responses.
 type Exposable interface {
 	Exposable()
+	// Code identifies the error to clients. Unlike the message, it never changes.
+	Code() string
 }
 
 type exposableError struct {
-	msg string
+	code string
+	msg  string
 }
 
 func (e *exposableError) Error() string { return e.msg }
 
 func (e *exposableError) Exposable() {}
 
+func (e *exposableError) Code() string { return e.code }
+
 // IsExposable reports whether err (or any error in its chain) implements Exposable.
 func IsExposable(err error) bool {
 	var e Exposable
 	return errors.As(err, &e)
 }
 
-func exposable(msg string) error {
-	return &exposableError{msg: msg}
+// ErrorCode returns the code of the first Exposable error in the chain of err, or ""
+// when there is none.
+func ErrorCode(err error) string {
+	var e Exposable
+	if errors.As(err, &e) {
+		return e.Code()
+	}
+	return ""
+}
+
+func exposable(code, msg string) error {
+	return &exposableError{code: code, msg: msg}
 }
 
 var (
-	ErrNotFound                     = exposable("entity not found")
-	ErrInvalidInput                 = exposable("invalid input")
-	ErrConflict                     = exposable("conflict")
-	ErrContractNotActive            = exposable("contract is not active")
-	ErrDriverAlreadyAssignedInFleet = exposable("driver already has an active vehicle assignment for this fleet")
-	ErrDriverHasActiveContracts     = exposable("driver has active contracts; terminate them before deletion")
-	ErrDriverHasActiveAssignments   = exposable("driver has active vehicle assignments; return vehicles before deletion")
-	ErrAlreadyDeleted               = exposable("entity is already deleted")
-	ErrValidationServiceUnavailable = exposable("driver license validation service not available")
-	ErrLicenseValidationFailed      = exposable("driver license validation failed")
+	ErrNotFound                        = exposable("not_found", "entity not found")
+	ErrInvalidInput                    = exposable("invalid_input", "invalid input")
+	ErrConflict                        = exposable("conflict", "conflict")
+	ErrDuplicateValue                  = exposable("duplicate_value", "value is already in use")
+	ErrPreconditionFailed              = exposable("precondition_failed", "entity version does not match")
+	ErrContractNotActive               = exposable("contract_not_active", "contract is not active")
+	ErrDriverAlreadyAssignedInFleet    = exposable("driver_already_assigned_in_fleet", "driver already has an active vehicle assignment for this fleet")
+	ErrDriverHasActiveContracts        = exposable("driver_has_active_contracts", "driver has active contracts; terminate them before deletion")
+	ErrDriverHasActiveAssignments      = exposable("driver_has_active_assignments", "driver has active vehicle assignments; return vehicles before deletion")
+	ErrLegalEntityHasFleets            = exposable("legal_entity_has_fleets", "legal entity has fleets; delete them before deletion")
+	ErrLegalEntityHasActiveContracts   = exposable("legal_entity_has_active_contracts", "legal entity has active contracts; terminate them before deletion")
+	ErrLegalEntityHasActiveAssignments = exposable("legal_entity_has_active_assignments", "legal entity has active vehicle assignments; return vehicles before deletion")
+	ErrFleetHasVehicles                = exposable("fleet_has_vehicles", "fleet has vehicles; delete them before deletion")
+	ErrFleetHasActiveContracts         = exposable("fleet_has_active_contracts", "fleet has active contracts; terminate them before deletion")
+	ErrFleetHasActiveAssignments       = exposable("fleet_has_active_assignments", "fleet has active vehicle assignments; return vehicles before deletion")
+	ErrVehicleHasActiveAssignments     = exposable("vehicle_has_active_assignments", "vehicle has active assignments; return it before deletion")
+	ErrAlreadyDeleted                  = exposable("already_deleted", "entity is already deleted")
+	ErrParentDeleted                   = exposable("parent_deleted", "parent entity is deleted")
+	ErrValidationServiceUnavailable    = exposable("license_validation_unavailable", "driver license validation service not available")
+	ErrLicenseValidationFailed         = exposable("license_validation_failed", "driver license validation failed")
+	ErrUnauthenticated                 = exposable("unauthenticated", "authentication required")
+	ErrForbidden                       = exposable("forbidden", "permission denied")
+	ErrIdempotencyKeyReused            = exposable("idempotency_key_reused", "idempotency key was already used for another request")
 )
+
+// UniqueViolationError reports that Field must be unique among live entities
+// and the requested value is already taken. It matches ErrDuplicateValue.
+type UniqueViolationError struct {
+	Field string
+}
+
+func (e *UniqueViolationError) Error() string { return e.Field + " is already in use" }
+
+func (e *UniqueViolationError) Exposable() {}
+
+func (e *UniqueViolationError) Code() string { return ErrorCode(ErrDuplicateValue) }
+
+func (e *UniqueViolationError) Unwrap() error { return ErrDuplicateValue }
+
+// ParentDeletedError reports that an entity cannot be restored because the Parent
+// entity it references, identified by ID, is deleted. It matches ErrParentDeleted.
+type ParentDeletedError struct {
+	Parent string
+	ID     string
+}
+
+func (e *ParentDeletedError) Error() string {
+	return e.Parent + " " + e.ID + " is deleted; restore it first"
+}
+
+func (e *ParentDeletedError) Exposable() {}
+
+func (e *ParentDeletedError) Code() string { return ErrorCode(ErrParentDeleted) }
+
+func (e *ParentDeletedError) Unwrap() error { return ErrParentDeleted }
+
+// ParentError reports err from looking up the parent entity identified by id as a
+// *ParentDeletedError when no live parent was found; other errors pass through.
+func ParentError(err error, parent, id string) error {
+	if errors.Is(err, ErrNotFound) {
+		return &ParentDeletedError{Parent: parent, ID: id}
+	}
+	return err
+}
@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventType names a domain event published to downstream systems.
+type EventType string
+
+const (
+	EventContractCreated    EventType = "contract.created"
+	EventContractTerminated EventType = "contract.terminated"
+	EventAssignmentCreated  EventType = "assignment.created"
+	EventAssignmentReturned EventType = "assignment.returned"
+)
+
+// Valid reports whether t is one of the known event types.
+func (t EventType) Valid() bool {
+	switch t {
+	case EventContractCreated, EventContractTerminated, EventAssignmentCreated, EventAssignmentReturned:
+		return true
+	default:
+		return false
+	}
+}
+
+// Event is a domain event. Events are stored together with the change they describe
+// and delivered after it is committed, at least once: consumers must deduplicate by ID.
+//
+// Payload is a ContractEvent or an AssignmentEvent; events read back from storage carry
+// it as json.RawMessage.
+type Event struct {
+	ID         string
+	Type       EventType
+	OccurredAt time.Time
+	Payload    any
+}
+
+// ContractEvent is the payload of contract events. Payloads are part of the contract with
+// downstream systems, so unlike entities they define their JSON form.
+type ContractEvent struct {
+	ContractID    string     `json:"contract_id"`
+	DriverID      string     `json:"driver_id"`
+	LegalEntityID string     `json:"legal_entity_id"`
+	FleetID       string     `json:"fleet_id"`
+	StartDate     time.Time  `json:"start_date"`
+	EndDate       time.Time  `json:"end_date"`
+	TerminatedAt  *time.Time `json:"terminated_at,omitempty"`
+	TerminatedBy  string     `json:"terminated_by,omitempty"`
+}
+
+// NewContractEvent returns the payload of an event about c.
+func NewContractEvent(c *Contract) ContractEvent {
+	return ContractEvent{
+		ContractID:    c.ID,
+		DriverID:      c.DriverID,
+		LegalEntityID: c.LegalEntityID,
+		FleetID:       c.FleetID,
+		StartDate:     c.StartDate,
+		EndDate:       c.EndDate,
+		TerminatedAt:  c.TerminatedAt,
+		TerminatedBy:  c.TerminatedBy,
+	}
+}
+
+// AssignmentEvent is the payload of vehicle assignment events.
+type AssignmentEvent struct {
+	AssignmentID  string     `json:"assignment_id"`
+	ContractID    string     `json:"contract_id"`
+	DriverID      string     `json:"driver_id"`
+	VehicleID     string     `json:"vehicle_id"`
+	LegalEntityID string     `json:"legal_entity_id"`
+	FleetID       string     `json:"fleet_id"`
+	StartTime     time.Time  `json:"start_time"`
+	EndTime       *time.Time `json:"end_time,omitempty"`
+}
+
+// NewAssignmentEvent returns the payload of an event about a, which belongs to contract c.
+func NewAssignmentEvent(a *VehicleAssignment, c *Contract) AssignmentEvent {
+	return AssignmentEvent{
+		AssignmentID:  a.ID,
+		ContractID:    a.ContractID,
+		DriverID:      a.DriverID,
+		VehicleID:     a.VehicleID,
+		LegalEntityID: c.LegalEntityID,
+		FleetID:       c.FleetID,
+		StartTime:     a.StartTime,
+		EndTime:       a.EndTime,
+	}
+}
+
+// EventFilter selects events by the fleet and legal entity they concern. Empty fields
+// match any event.
+type EventFilter struct {
+	FleetID       string
+	LegalEntityID string
+}
+
+// Matches reports whether e passes the filter.
+func (f EventFilter) Matches(e *Event) bool {
+	if f.FleetID == "" && f.LegalEntityID == "" {
+		return true
+	}
+	var scope struct {
+		FleetID       string `json:"fleet_id"`
+		LegalEntityID string `json:"legal_entity_id"`
+	}
+	switch p := e.Payload.(type) {
+	case ContractEvent:
+		scope.FleetID, scope.LegalEntityID = p.FleetID, p.LegalEntityID
+	case AssignmentEvent:
+		scope.FleetID, scope.LegalEntityID = p.FleetID, p.LegalEntityID
+	case json.RawMessage:
+		if err := json.Unmarshal(p, &scope); err != nil {
+			return false
+		}
+	default:
+		return false
+	}
+	return (f.FleetID == "" || f.FleetID == scope.FleetID) &&
+		(f.LegalEntityID == "" || f.LegalEntityID == scope.LegalEntityID)
+}
@@ -3,8 +3,21 @@ package domain
 import "time"
 
 type Fleet struct {
-	ID           string
+	ID            string
 	LegalEntityID string
-	Name         string
-	DeletedAt    *time.Time
+	Name          string
+	Version       int64
+	CreatedAt     time.Time
+	CreatedBy     string
+	UpdatedAt     time.Time
+	UpdatedBy     string
+	DeletedAt     *time.Time
+	DeletedBy     string
+	DeleteReason  string
+	DeletionID    string
+}
+
+// FleetPatch holds the fields of a Fleet update; nil fields are left unchanged.
+type FleetPatch struct {
+	Name *string
 }
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// IdempotencyRecord remembers a request made with an idempotency key, so that a retry
+// with the same key gets the response of the first request instead of repeating it.
+// Keys are scoped to the principal that used them.
+type IdempotencyRecord struct {
+	PrincipalID string
+	Key         string
+	// Fingerprint identifies the request; a key may not be reused for another one.
+	Fingerprint string
+	// Response is nil while the first request is still being processed.
+	Response  *IdempotentResponse
+	CreatedAt time.Time
+	// ExpiresAt is when the key may be used for a new request again.
+	ExpiresAt time.Time
+}
+
+// IdempotentResponse is the stored response to a request made with an idempotency key.
+type IdempotentResponse struct {
+	StatusCode int
+	Header     map[string][]string
+	Body       []byte
+}
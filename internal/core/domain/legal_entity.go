@@ -3,8 +3,22 @@ package domain
 import "time"
 
 type LegalEntity struct {
-	ID        string
-	Name      string
-	TaxID     string
-	DeletedAt *time.Time
+	ID           string
+	Name         string
+	TaxID        string
+	Version      int64
+	CreatedAt    time.Time
+	CreatedBy    string
+	UpdatedAt    time.Time
+	UpdatedBy    string
+	DeletedAt    *time.Time
+	DeletedBy    string
+	DeleteReason string
+	DeletionID   string
+}
+
+// LegalEntityPatch holds the fields of a LegalEntity update; nil fields are left unchanged.
+type LegalEntityPatch struct {
+	Name  *string
+	TaxID *string
 }
@@ -0,0 +1,27 @@
+package domain
+
+import "context"
+
+// Principal is the caller on whose behalf an operation runs.
+type Principal struct {
+	ID   string
+	Role Role
+	// LegalEntityIDs are the legal entities a fleet manager manages.
+	LegalEntityIDs []string
+	// DriverID is the Driver a principal with the driver role is.
+	DriverID string
+}
+
+type principalKey struct{}
+
+// ContextWithPrincipal returns a copy of ctx carrying p.
+func ContextWithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal carried by ctx, or the zero Principal
+// when the operation does not run on behalf of an identified caller.
+func PrincipalFromContext(ctx context.Context) Principal {
+	p, _ := ctx.Value(principalKey{}).(Principal)
+	return p
+}
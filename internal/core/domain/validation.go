@@ -0,0 +1,53 @@
+package domain
+
+import "strings"
+
+// Rules a field may violate. Clients may rely on them, like on error codes.
+const (
+	RuleRequired = "required"
+	RuleRange    = "range"
+	RuleAfter    = "after"
+	RuleFormat   = "format"
+	RuleUnique   = "unique"
+	RuleUnknown  = "unknown"
+)
+
+// Violation describes why a field of an input is invalid.
+type Violation struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+// ValidationError reports every invalid field of an input at once, so that clients
+// can fix them all before trying again. It matches ErrInvalidInput.
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Add records that field violates rule.
+func (e *ValidationError) Add(field, rule, message string) {
+	e.Violations = append(e.Violations, Violation{Field: field, Rule: rule, Message: message})
+}
+
+// Err returns e when any violation was recorded and nil otherwise.
+func (e *ValidationError) Err() error {
+	if len(e.Violations) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		msgs = append(msgs, v.Field+" "+v.Message)
+	}
+	return ErrInvalidInput.Error() + ": " + strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) Exposable() {}
+
+func (e *ValidationError) Code() string { return ErrorCode(ErrInvalidInput) }
+
+func (e *ValidationError) Unwrap() error { return ErrInvalidInput }
@@ -9,5 +9,21 @@ type Vehicle struct {
 	Model        string
 	Year         int
 	LicensePlate string
+	Version      int64
+	CreatedAt    time.Time
+	CreatedBy    string
+	UpdatedAt    time.Time
+	UpdatedBy    string
 	DeletedAt    *time.Time
+	DeletedBy    string
+	DeleteReason string
+	DeletionID   string
+}
+
+// VehiclePatch holds the fields of a Vehicle update; nil fields are left unchanged.
+type VehiclePatch struct {
+	Make         *string
+	Model        *string
+	Year         *int
+	LicensePlate *string
 }
@@ -3,11 +3,24 @@ package domain
 import "time"
 
 type VehicleAssignment struct {
-	ID         string
-	DriverID   string
-	VehicleID  string
-	ContractID string
-	StartTime  time.Time
-	EndTime    *time.Time
-	DeletedAt  *time.Time
+	ID           string
+	DriverID     string
+	VehicleID    string
+	ContractID   string
+	StartTime    time.Time
+	EndTime      *time.Time
+	Version      int64
+	CreatedAt    time.Time
+	CreatedBy    string
+	UpdatedAt    time.Time
+	UpdatedBy    string
+	DeletedAt    *time.Time
+	DeletedBy    string
+	DeleteReason string
+	DeletionID   string
+}
+
+// VehicleAssignmentPatch holds the fields of a VehicleAssignment update; nil fields are left unchanged.
+type VehicleAssignmentPatch struct {
+	VehicleID *string
 }
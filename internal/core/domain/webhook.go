@@ -0,0 +1,61 @@
+package domain
+
+import "time"
+
+// WebhookSubscription registers a partner URL that receives a signed HTTP callback
+// for every event of the subscribed types.
+type WebhookSubscription struct {
+	ID         string
+	URL        string
+	EventTypes []EventType
+	// Secret is the key of the HMAC-SHA256 signature sent with every delivery.
+	Secret string
+	// Active subscriptions receive deliveries. A subscription is deactivated automatically
+	// after too many consecutive failed delivery attempts.
+	Active bool
+	// ConsecutiveFailures counts failed delivery attempts since the last successful one.
+	ConsecutiveFailures int
+	// DisabledAt is set when the subscription was deactivated automatically.
+	DisabledAt *time.Time
+	Version    int64
+	CreatedAt  time.Time
+	CreatedBy  string
+	UpdatedAt  time.Time
+	UpdatedBy  string
+}
+
+// WebhookSubscriptionPatch holds the fields of a WebhookSubscription update; nil fields
+// are left unchanged. Activating a subscription resets its failure count.
+type WebhookSubscriptionPatch struct {
+	URL        *string
+	EventTypes []EventType
+	Secret     *string
+	Active     *bool
+}
+
+// WebhookDeliveryStatus is the state of a WebhookDelivery.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is one event sent, or to be sent, to one subscription. Pending
+// deliveries are retried with exponential backoff until they succeed or run out of
+// attempts; finished deliveries stay in the delivery log and can be replayed.
+type WebhookDelivery struct {
+	ID             string
+	SubscriptionID string
+	Event          Event
+	Status         WebhookDeliveryStatus
+	// Attempts is the number of delivery attempts so far.
+	Attempts int
+	// LastStatusCode is the HTTP status of the last response, 0 when none was received.
+	LastStatusCode int
+	LastError      string
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
@@ -0,0 +1,19 @@
+package ports
+
+//go:generate go tool mockgen -destination=mocks/mock_audit.go -package=mocks . AuditLog
+
+import (
+	"context"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+)
+
+// AuditLog is the output port for the audit trail of entity mutations.
+type AuditLog interface {
+	// Append records entry. Services call it with the context of the transaction
+	// that makes the mutation, so the entry is committed or rolled back with it.
+	Append(ctx context.Context, entry *domain.AuditEntry) error
+	// FindAll returns a page of entries, oldest first unless q says otherwise.
+	// It supports the entity_type, entity_id, action, actor and deletion_id filters.
+	FindAll(ctx context.Context, q ListQuery) (*Page[*domain.AuditEntry], error)
+}
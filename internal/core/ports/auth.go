@@ -0,0 +1,23 @@
+package ports
+
+//go:generate go tool mockgen -destination=mocks/mock_auth.go -package=mocks . APIKeyRepository,Authorizer
+
+import (
+	"context"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+)
+
+// APIKeyRepository is the output port for API key lookup.
+type APIKeyRepository interface {
+	// FindByHash returns the key with the given hex SHA-256 hash, including revoked and
+	// expired ones, or domain.ErrNotFound.
+	FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+}
+
+// Authorizer decides whether the principal in the context may act on a resource.
+// Services call it before reading or changing an entity.
+type Authorizer interface {
+	// Authorize fails with domain.ErrForbidden unless the action is allowed.
+	Authorize(ctx context.Context, action domain.Action, resource domain.Resource) error
+}
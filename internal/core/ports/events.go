@@ -0,0 +1,52 @@
+package ports
+
+//go:generate go tool mockgen -destination=mocks/mock_events.go -package=mocks . Outbox,EventPublisher,EventFeed
+
+import (
+	"context"
+	"time"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+)
+
+// OutboxMessage is an event waiting in the outbox for delivery.
+type OutboxMessage struct {
+	Event *domain.Event
+	// Attempts is the number of failed delivery attempts so far.
+	Attempts int
+}
+
+// Outbox is the output port for domain events awaiting delivery.
+type Outbox interface {
+	// Enqueue stores event for delivery. Services call it with the context of the
+	// transaction that makes the change, so the event is committed or rolled back with it.
+	Enqueue(ctx context.Context, event *domain.Event) error
+	// Claim leases up to limit undelivered events due at now, oldest first, until leaseUntil.
+	// Leased events are not claimed again before the lease expires, so an event whose
+	// delivery was interrupted is retried once its lease is over.
+	Claim(ctx context.Context, limit int, now, leaseUntil time.Time) ([]*OutboxMessage, error)
+	// MarkDelivered records that the event was delivered at the given time.
+	MarkDelivered(ctx context.Context, id string, at time.Time) error
+	// MarkFailed records a failed delivery attempt and schedules the next one at retryAt.
+	MarkFailed(ctx context.Context, id, cause string, retryAt time.Time) error
+	// FindAfter returns up to limit events following the event with ID afterID, whether
+	// delivered or not, in the order EventFeed delivers them: resuming after any event
+	// misses none committed later. It fails with domain.ErrNotFound when there is no
+	// such event.
+	FindAfter(ctx context.Context, afterID string, limit int) ([]*domain.Event, error)
+}
+
+// EventPublisher is the output port delivering domain events to a downstream system.
+type EventPublisher interface {
+	Publish(ctx context.Context, event *domain.Event) error
+}
+
+// EventFeed is the output port for the live feed of committed domain events, shared by
+// all instances of the service.
+type EventFeed interface {
+	// Subscribe returns a channel receiving every event committed from now on, in the
+	// order Outbox.FindAfter reads them. The channel is closed when ctx is done, and also
+	// when the subscriber falls behind or the feed is interrupted; subscribers then resume
+	// from the outbox.
+	Subscribe(ctx context.Context) (<-chan *domain.Event, error)
+}
@@ -0,0 +1,26 @@
+package ports
+
+//go:generate go tool mockgen -destination=mocks/mock_idempotency.go -package=mocks . IdempotencyRepository
+
+import (
+	"context"
+	"time"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+)
+
+// IdempotencyRepository is the output port for idempotency records. Records are
+// identified by principal ID and key.
+type IdempotencyRepository interface {
+	// Claim stores rec unless a record with the same key exists that has not expired at
+	// now. It returns that record, or nil when rec was stored. An expired record is
+	// replaced by rec.
+	Claim(ctx context.Context, rec *domain.IdempotencyRecord, now time.Time) (*domain.IdempotencyRecord, error)
+	// SaveResponse completes a claimed record with the response, keeping it until expiresAt.
+	SaveResponse(ctx context.Context, principalID, key string, resp *domain.IdempotentResponse, expiresAt time.Time) error
+	// Delete removes a record, if any.
+	Delete(ctx context.Context, principalID, key string) error
+	// DeleteExpired removes up to limit records that expired before the given time and
+	// returns how many it removed.
+	DeleteExpired(ctx context.Context, before time.Time, limit int) (int, error)
+}
@@ -0,0 +1,60 @@
+package ports
+
+import (
+	"fmt"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+)
+
+const (
+	// DefaultListLimit is the page size used when a ListQuery does not specify one.
+	DefaultListLimit = 50
+	// MaxListLimit is the largest page size a ListQuery may request.
+	MaxListLimit = 500
+)
+
+// SortDirection is the ordering direction of a list query.
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+)
+
+// ListQuery describes a single page request for list operations.
+//
+// Cursor is opaque to callers: it is produced by the repository as Page.NextCursor
+// and must be passed back unchanged together with the same sort and filters.
+// Which sort fields and filters are supported is decided by each repository;
+// unsupported ones are rejected with domain.ErrInvalidInput.
+type ListQuery struct {
+	Limit   int
+	Cursor  string
+	SortBy  string
+	SortDir SortDirection
+	Filters map[string]string
+}
+
+// Normalize applies defaults and checks the generic parts of the query.
+func (q ListQuery) Normalize() (ListQuery, error) {
+	switch {
+	case q.Limit == 0:
+		q.Limit = DefaultListLimit
+	case q.Limit < 0 || q.Limit > MaxListLimit:
+		return q, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidInput, MaxListLimit)
+	}
+	switch q.SortDir {
+	case "":
+		q.SortDir = SortAsc
+	case SortAsc, SortDesc:
+	default:
+		return q, fmt.Errorf("%w: sort direction must be %q or %q", domain.ErrInvalidInput, SortAsc, SortDesc)
+	}
+	return q, nil
+}
+
+// Page is a single page of list results. NextCursor is empty on the last page.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+}
@@ -15,6 +15,7 @@ import (
 	time "time"
 
 	domain "github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	ports "github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -42,19 +43,34 @@ func (m *MockLegalEntityRepository) EXPECT() *MockLegalEntityRepositoryMockRecor
 	return m.recorder
 }
 
+// ExistsByTaxID mocks base method.
+func (m *MockLegalEntityRepository) ExistsByTaxID(ctx context.Context, taxID, excludeID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsByTaxID", ctx, taxID, excludeID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsByTaxID indicates an expected call of ExistsByTaxID.
+func (mr *MockLegalEntityRepositoryMockRecorder) ExistsByTaxID(ctx, taxID, excludeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsByTaxID", reflect.TypeOf((*MockLegalEntityRepository)(nil).ExistsByTaxID), ctx, taxID, excludeID)
+}
+
 // FindAll mocks base method.
-func (m *MockLegalEntityRepository) FindAll(ctx context.Context) ([]*domain.LegalEntity, error) {
+func (m *MockLegalEntityRepository) FindAll(ctx context.Context, q ports.ListQuery) (*ports.Page[*domain.LegalEntity], error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAll", ctx)
-	ret0, _ := ret[0].([]*domain.LegalEntity)
+	ret := m.ctrl.Call(m, "FindAll", ctx, q)
+	ret0, _ := ret[0].(*ports.Page[*domain.LegalEntity])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindAll indicates an expected call of FindAll.
-func (mr *MockLegalEntityRepositoryMockRecorder) FindAll(ctx any) *gomock.Call {
+func (mr *MockLegalEntityRepositoryMockRecorder) FindAll(ctx, q any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockLegalEntityRepository)(nil).FindAll), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockLegalEntityRepository)(nil).FindAll), ctx, q)
 }
 
 // FindByID mocks base method.
@@ -72,6 +88,21 @@ func (mr *MockLegalEntityRepositoryMockRecorder) FindByID(ctx, id any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockLegalEntityRepository)(nil).FindByID), ctx, id)
 }
 
+// FindDeletedByID mocks base method.
+func (m *MockLegalEntityRepository) FindDeletedByID(ctx context.Context, id string) (*domain.LegalEntity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDeletedByID", ctx, id)
+	ret0, _ := ret[0].(*domain.LegalEntity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDeletedByID indicates an expected call of FindDeletedByID.
+func (mr *MockLegalEntityRepositoryMockRecorder) FindDeletedByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeletedByID", reflect.TypeOf((*MockLegalEntityRepository)(nil).FindDeletedByID), ctx, id)
+}
+
 // Save mocks base method.
 func (m *MockLegalEntityRepository) Save(ctx context.Context, entity *domain.LegalEntity) error {
 	m.ctrl.T.Helper()
@@ -87,31 +118,31 @@ func (mr *MockLegalEntityRepositoryMockRecorder) Save(ctx, entity any) *gomock.C
 }
 
 // SoftDelete mocks base method.
-func (m *MockLegalEntityRepository) SoftDelete(ctx context.Context, id string) error {
+func (m *MockLegalEntityRepository) SoftDelete(ctx context.Context, id string, d ports.Deletion) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SoftDelete", ctx, id)
+	ret := m.ctrl.Call(m, "SoftDelete", ctx, id, d)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SoftDelete indicates an expected call of SoftDelete.
-func (mr *MockLegalEntityRepositoryMockRecorder) SoftDelete(ctx, id any) *gomock.Call {
+func (mr *MockLegalEntityRepositoryMockRecorder) SoftDelete(ctx, id, d any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDelete", reflect.TypeOf((*MockLegalEntityRepository)(nil).SoftDelete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDelete", reflect.TypeOf((*MockLegalEntityRepository)(nil).SoftDelete), ctx, id, d)
 }
 
 // Undelete mocks base method.
-func (m *MockLegalEntityRepository) Undelete(ctx context.Context, id string) error {
+func (m *MockLegalEntityRepository) Undelete(ctx context.Context, id string, at time.Time, by string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Undelete", ctx, id)
+	ret := m.ctrl.Call(m, "Undelete", ctx, id, at, by)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Undelete indicates an expected call of Undelete.
-func (mr *MockLegalEntityRepositoryMockRecorder) Undelete(ctx, id any) *gomock.Call {
+func (mr *MockLegalEntityRepositoryMockRecorder) Undelete(ctx, id, at, by any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockLegalEntityRepository)(nil).Undelete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockLegalEntityRepository)(nil).Undelete), ctx, id, at, by)
 }
 
 // MockFleetRepository is a mock of FleetRepository interface.
@@ -138,6 +169,21 @@ func (m *MockFleetRepository) EXPECT() *MockFleetRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ExistsByLegalEntityID mocks base method.
+func (m *MockFleetRepository) ExistsByLegalEntityID(ctx context.Context, legalEntityID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsByLegalEntityID", ctx, legalEntityID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsByLegalEntityID indicates an expected call of ExistsByLegalEntityID.
+func (mr *MockFleetRepositoryMockRecorder) ExistsByLegalEntityID(ctx, legalEntityID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsByLegalEntityID", reflect.TypeOf((*MockFleetRepository)(nil).ExistsByLegalEntityID), ctx, legalEntityID)
+}
+
 // FindByID mocks base method.
 func (m *MockFleetRepository) FindByID(ctx context.Context, id string) (*domain.Fleet, error) {
 	m.ctrl.T.Helper()
@@ -154,18 +200,33 @@ func (mr *MockFleetRepositoryMockRecorder) FindByID(ctx, id any) *gomock.Call {
 }
 
 // FindByLegalEntityID mocks base method.
-func (m *MockFleetRepository) FindByLegalEntityID(ctx context.Context, legalEntityID string) ([]*domain.Fleet, error) {
+func (m *MockFleetRepository) FindByLegalEntityID(ctx context.Context, legalEntityID string, q ports.ListQuery) (*ports.Page[*domain.Fleet], error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByLegalEntityID", ctx, legalEntityID)
-	ret0, _ := ret[0].([]*domain.Fleet)
+	ret := m.ctrl.Call(m, "FindByLegalEntityID", ctx, legalEntityID, q)
+	ret0, _ := ret[0].(*ports.Page[*domain.Fleet])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByLegalEntityID indicates an expected call of FindByLegalEntityID.
-func (mr *MockFleetRepositoryMockRecorder) FindByLegalEntityID(ctx, legalEntityID any) *gomock.Call {
+func (mr *MockFleetRepositoryMockRecorder) FindByLegalEntityID(ctx, legalEntityID, q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByLegalEntityID", reflect.TypeOf((*MockFleetRepository)(nil).FindByLegalEntityID), ctx, legalEntityID, q)
+}
+
+// FindDeletedByID mocks base method.
+func (m *MockFleetRepository) FindDeletedByID(ctx context.Context, id string) (*domain.Fleet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDeletedByID", ctx, id)
+	ret0, _ := ret[0].(*domain.Fleet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDeletedByID indicates an expected call of FindDeletedByID.
+func (mr *MockFleetRepositoryMockRecorder) FindDeletedByID(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByLegalEntityID", reflect.TypeOf((*MockFleetRepository)(nil).FindByLegalEntityID), ctx, legalEntityID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeletedByID", reflect.TypeOf((*MockFleetRepository)(nil).FindDeletedByID), ctx, id)
 }
 
 // Save mocks base method.
@@ -183,31 +244,61 @@ func (mr *MockFleetRepositoryMockRecorder) Save(ctx, entity any) *gomock.Call {
 }
 
 // SoftDelete mocks base method.
-func (m *MockFleetRepository) SoftDelete(ctx context.Context, id string) error {
+func (m *MockFleetRepository) SoftDelete(ctx context.Context, id string, d ports.Deletion) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SoftDelete", ctx, id)
+	ret := m.ctrl.Call(m, "SoftDelete", ctx, id, d)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SoftDelete indicates an expected call of SoftDelete.
-func (mr *MockFleetRepositoryMockRecorder) SoftDelete(ctx, id any) *gomock.Call {
+func (mr *MockFleetRepositoryMockRecorder) SoftDelete(ctx, id, d any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDelete", reflect.TypeOf((*MockFleetRepository)(nil).SoftDelete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDelete", reflect.TypeOf((*MockFleetRepository)(nil).SoftDelete), ctx, id, d)
+}
+
+// SoftDeleteByLegalEntityID mocks base method.
+func (m *MockFleetRepository) SoftDeleteByLegalEntityID(ctx context.Context, legalEntityID string, d ports.Deletion) ([]*domain.Fleet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDeleteByLegalEntityID", ctx, legalEntityID, d)
+	ret0, _ := ret[0].([]*domain.Fleet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SoftDeleteByLegalEntityID indicates an expected call of SoftDeleteByLegalEntityID.
+func (mr *MockFleetRepositoryMockRecorder) SoftDeleteByLegalEntityID(ctx, legalEntityID, d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDeleteByLegalEntityID", reflect.TypeOf((*MockFleetRepository)(nil).SoftDeleteByLegalEntityID), ctx, legalEntityID, d)
 }
 
 // Undelete mocks base method.
-func (m *MockFleetRepository) Undelete(ctx context.Context, id string) error {
+func (m *MockFleetRepository) Undelete(ctx context.Context, id string, at time.Time, by string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Undelete", ctx, id)
+	ret := m.ctrl.Call(m, "Undelete", ctx, id, at, by)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Undelete indicates an expected call of Undelete.
-func (mr *MockFleetRepositoryMockRecorder) Undelete(ctx, id any) *gomock.Call {
+func (mr *MockFleetRepositoryMockRecorder) Undelete(ctx, id, at, by any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockFleetRepository)(nil).Undelete), ctx, id, at, by)
+}
+
+// UndeleteByLegalEntityID mocks base method.
+func (m *MockFleetRepository) UndeleteByLegalEntityID(ctx context.Context, legalEntityID, deletionID string, at time.Time, by string) ([]*domain.Fleet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndeleteByLegalEntityID", ctx, legalEntityID, deletionID, at, by)
+	ret0, _ := ret[0].([]*domain.Fleet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UndeleteByLegalEntityID indicates an expected call of UndeleteByLegalEntityID.
+func (mr *MockFleetRepositoryMockRecorder) UndeleteByLegalEntityID(ctx, legalEntityID, deletionID, at, by any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockFleetRepository)(nil).Undelete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndeleteByLegalEntityID", reflect.TypeOf((*MockFleetRepository)(nil).UndeleteByLegalEntityID), ctx, legalEntityID, deletionID, at, by)
 }
 
 // MockVehicleRepository is a mock of VehicleRepository interface.
@@ -234,19 +325,49 @@ func (m *MockVehicleRepository) EXPECT() *MockVehicleRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ExistsByFleetID mocks base method.
+func (m *MockVehicleRepository) ExistsByFleetID(ctx context.Context, fleetID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsByFleetID", ctx, fleetID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsByFleetID indicates an expected call of ExistsByFleetID.
+func (mr *MockVehicleRepositoryMockRecorder) ExistsByFleetID(ctx, fleetID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsByFleetID", reflect.TypeOf((*MockVehicleRepository)(nil).ExistsByFleetID), ctx, fleetID)
+}
+
+// ExistsByLicensePlate mocks base method.
+func (m *MockVehicleRepository) ExistsByLicensePlate(ctx context.Context, licensePlate, excludeID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsByLicensePlate", ctx, licensePlate, excludeID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsByLicensePlate indicates an expected call of ExistsByLicensePlate.
+func (mr *MockVehicleRepositoryMockRecorder) ExistsByLicensePlate(ctx, licensePlate, excludeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsByLicensePlate", reflect.TypeOf((*MockVehicleRepository)(nil).ExistsByLicensePlate), ctx, licensePlate, excludeID)
+}
+
 // FindByFleetID mocks base method.
-func (m *MockVehicleRepository) FindByFleetID(ctx context.Context, fleetID string) ([]*domain.Vehicle, error) {
+func (m *MockVehicleRepository) FindByFleetID(ctx context.Context, fleetID string, q ports.ListQuery) (*ports.Page[*domain.Vehicle], error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByFleetID", ctx, fleetID)
-	ret0, _ := ret[0].([]*domain.Vehicle)
+	ret := m.ctrl.Call(m, "FindByFleetID", ctx, fleetID, q)
+	ret0, _ := ret[0].(*ports.Page[*domain.Vehicle])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByFleetID indicates an expected call of FindByFleetID.
-func (mr *MockVehicleRepositoryMockRecorder) FindByFleetID(ctx, fleetID any) *gomock.Call {
+func (mr *MockVehicleRepositoryMockRecorder) FindByFleetID(ctx, fleetID, q any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByFleetID", reflect.TypeOf((*MockVehicleRepository)(nil).FindByFleetID), ctx, fleetID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByFleetID", reflect.TypeOf((*MockVehicleRepository)(nil).FindByFleetID), ctx, fleetID, q)
 }
 
 // FindByID mocks base method.
@@ -264,6 +385,21 @@ func (mr *MockVehicleRepositoryMockRecorder) FindByID(ctx, id any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockVehicleRepository)(nil).FindByID), ctx, id)
 }
 
+// FindDeletedByID mocks base method.
+func (m *MockVehicleRepository) FindDeletedByID(ctx context.Context, id string) (*domain.Vehicle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDeletedByID", ctx, id)
+	ret0, _ := ret[0].(*domain.Vehicle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDeletedByID indicates an expected call of FindDeletedByID.
+func (mr *MockVehicleRepositoryMockRecorder) FindDeletedByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeletedByID", reflect.TypeOf((*MockVehicleRepository)(nil).FindDeletedByID), ctx, id)
+}
+
 // Save mocks base method.
 func (m *MockVehicleRepository) Save(ctx context.Context, entity *domain.Vehicle) error {
 	m.ctrl.T.Helper()
@@ -279,31 +415,91 @@ func (mr *MockVehicleRepositoryMockRecorder) Save(ctx, entity any) *gomock.Call
 }
 
 // SoftDelete mocks base method.
-func (m *MockVehicleRepository) SoftDelete(ctx context.Context, id string) error {
+func (m *MockVehicleRepository) SoftDelete(ctx context.Context, id string, d ports.Deletion) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SoftDelete", ctx, id)
+	ret := m.ctrl.Call(m, "SoftDelete", ctx, id, d)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SoftDelete indicates an expected call of SoftDelete.
-func (mr *MockVehicleRepositoryMockRecorder) SoftDelete(ctx, id any) *gomock.Call {
+func (mr *MockVehicleRepositoryMockRecorder) SoftDelete(ctx, id, d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDelete", reflect.TypeOf((*MockVehicleRepository)(nil).SoftDelete), ctx, id, d)
+}
+
+// SoftDeleteByFleetID mocks base method.
+func (m *MockVehicleRepository) SoftDeleteByFleetID(ctx context.Context, fleetID string, d ports.Deletion) ([]*domain.Vehicle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDeleteByFleetID", ctx, fleetID, d)
+	ret0, _ := ret[0].([]*domain.Vehicle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SoftDeleteByFleetID indicates an expected call of SoftDeleteByFleetID.
+func (mr *MockVehicleRepositoryMockRecorder) SoftDeleteByFleetID(ctx, fleetID, d any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDelete", reflect.TypeOf((*MockVehicleRepository)(nil).SoftDelete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDeleteByFleetID", reflect.TypeOf((*MockVehicleRepository)(nil).SoftDeleteByFleetID), ctx, fleetID, d)
+}
+
+// SoftDeleteByLegalEntityID mocks base method.
+func (m *MockVehicleRepository) SoftDeleteByLegalEntityID(ctx context.Context, legalEntityID string, d ports.Deletion) ([]*domain.Vehicle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDeleteByLegalEntityID", ctx, legalEntityID, d)
+	ret0, _ := ret[0].([]*domain.Vehicle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SoftDeleteByLegalEntityID indicates an expected call of SoftDeleteByLegalEntityID.
+func (mr *MockVehicleRepositoryMockRecorder) SoftDeleteByLegalEntityID(ctx, legalEntityID, d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDeleteByLegalEntityID", reflect.TypeOf((*MockVehicleRepository)(nil).SoftDeleteByLegalEntityID), ctx, legalEntityID, d)
 }
 
 // Undelete mocks base method.
-func (m *MockVehicleRepository) Undelete(ctx context.Context, id string) error {
+func (m *MockVehicleRepository) Undelete(ctx context.Context, id string, at time.Time, by string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Undelete", ctx, id)
+	ret := m.ctrl.Call(m, "Undelete", ctx, id, at, by)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Undelete indicates an expected call of Undelete.
-func (mr *MockVehicleRepositoryMockRecorder) Undelete(ctx, id any) *gomock.Call {
+func (mr *MockVehicleRepositoryMockRecorder) Undelete(ctx, id, at, by any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockVehicleRepository)(nil).Undelete), ctx, id, at, by)
+}
+
+// UndeleteByFleetID mocks base method.
+func (m *MockVehicleRepository) UndeleteByFleetID(ctx context.Context, fleetID, deletionID string, at time.Time, by string) ([]*domain.Vehicle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndeleteByFleetID", ctx, fleetID, deletionID, at, by)
+	ret0, _ := ret[0].([]*domain.Vehicle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UndeleteByFleetID indicates an expected call of UndeleteByFleetID.
+func (mr *MockVehicleRepositoryMockRecorder) UndeleteByFleetID(ctx, fleetID, deletionID, at, by any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockVehicleRepository)(nil).Undelete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndeleteByFleetID", reflect.TypeOf((*MockVehicleRepository)(nil).UndeleteByFleetID), ctx, fleetID, deletionID, at, by)
+}
+
+// UndeleteByLegalEntityID mocks base method.
+func (m *MockVehicleRepository) UndeleteByLegalEntityID(ctx context.Context, legalEntityID, deletionID string, at time.Time, by string) ([]*domain.Vehicle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndeleteByLegalEntityID", ctx, legalEntityID, deletionID, at, by)
+	ret0, _ := ret[0].([]*domain.Vehicle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UndeleteByLegalEntityID indicates an expected call of UndeleteByLegalEntityID.
+func (mr *MockVehicleRepositoryMockRecorder) UndeleteByLegalEntityID(ctx, legalEntityID, deletionID, at, by any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndeleteByLegalEntityID", reflect.TypeOf((*MockVehicleRepository)(nil).UndeleteByLegalEntityID), ctx, legalEntityID, deletionID, at, by)
 }
 
 // MockDriverRepository is a mock of DriverRepository interface.
@@ -330,19 +526,34 @@ func (m *MockDriverRepository) EXPECT() *MockDriverRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ExistsByLicenseNumber mocks base method.
+func (m *MockDriverRepository) ExistsByLicenseNumber(ctx context.Context, licenseNumber, excludeID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsByLicenseNumber", ctx, licenseNumber, excludeID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsByLicenseNumber indicates an expected call of ExistsByLicenseNumber.
+func (mr *MockDriverRepositoryMockRecorder) ExistsByLicenseNumber(ctx, licenseNumber, excludeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsByLicenseNumber", reflect.TypeOf((*MockDriverRepository)(nil).ExistsByLicenseNumber), ctx, licenseNumber, excludeID)
+}
+
 // FindAll mocks base method.
-func (m *MockDriverRepository) FindAll(ctx context.Context) ([]*domain.Driver, error) {
+func (m *MockDriverRepository) FindAll(ctx context.Context, q ports.ListQuery) (*ports.Page[*domain.Driver], error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAll", ctx)
-	ret0, _ := ret[0].([]*domain.Driver)
+	ret := m.ctrl.Call(m, "FindAll", ctx, q)
+	ret0, _ := ret[0].(*ports.Page[*domain.Driver])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindAll indicates an expected call of FindAll.
-func (mr *MockDriverRepositoryMockRecorder) FindAll(ctx any) *gomock.Call {
+func (mr *MockDriverRepositoryMockRecorder) FindAll(ctx, q any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockDriverRepository)(nil).FindAll), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockDriverRepository)(nil).FindAll), ctx, q)
 }
 
 // FindByID mocks base method.
@@ -360,6 +571,21 @@ func (mr *MockDriverRepositoryMockRecorder) FindByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockDriverRepository)(nil).FindByID), ctx, id)
 }
 
+// FindDeletedByID mocks base method.
+func (m *MockDriverRepository) FindDeletedByID(ctx context.Context, id string) (*domain.Driver, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDeletedByID", ctx, id)
+	ret0, _ := ret[0].(*domain.Driver)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDeletedByID indicates an expected call of FindDeletedByID.
+func (mr *MockDriverRepositoryMockRecorder) FindDeletedByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeletedByID", reflect.TypeOf((*MockDriverRepository)(nil).FindDeletedByID), ctx, id)
+}
+
 // Save mocks base method.
 func (m *MockDriverRepository) Save(ctx context.Context, entity *domain.Driver) error {
 	m.ctrl.T.Helper()
@@ -375,31 +601,31 @@ func (mr *MockDriverRepositoryMockRecorder) Save(ctx, entity any) *gomock.Call {
 }
 
 // SoftDelete mocks base method.
-func (m *MockDriverRepository) SoftDelete(ctx context.Context, id string) error {
+func (m *MockDriverRepository) SoftDelete(ctx context.Context, id string, d ports.Deletion) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SoftDelete", ctx, id)
+	ret := m.ctrl.Call(m, "SoftDelete", ctx, id, d)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SoftDelete indicates an expected call of SoftDelete.
-func (mr *MockDriverRepositoryMockRecorder) SoftDelete(ctx, id any) *gomock.Call {
+func (mr *MockDriverRepositoryMockRecorder) SoftDelete(ctx, id, d any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDelete", reflect.TypeOf((*MockDriverRepository)(nil).SoftDelete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDelete", reflect.TypeOf((*MockDriverRepository)(nil).SoftDelete), ctx, id, d)
 }
 
 // Undelete mocks base method.
-func (m *MockDriverRepository) Undelete(ctx context.Context, id string) error {
+func (m *MockDriverRepository) Undelete(ctx context.Context, id string, at time.Time, by string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Undelete", ctx, id)
+	ret := m.ctrl.Call(m, "Undelete", ctx, id, at, by)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Undelete indicates an expected call of Undelete.
-func (mr *MockDriverRepositoryMockRecorder) Undelete(ctx, id any) *gomock.Call {
+func (mr *MockDriverRepositoryMockRecorder) Undelete(ctx, id, at, by any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockDriverRepository)(nil).Undelete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockDriverRepository)(nil).Undelete), ctx, id, at, by)
 }
 
 // MockContractRepository is a mock of ContractRepository interface.
@@ -426,19 +652,49 @@ func (m *MockContractRepository) EXPECT() *MockContractRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ExistsActiveByFleetID mocks base method.
+func (m *MockContractRepository) ExistsActiveByFleetID(ctx context.Context, fleetID string, at time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsActiveByFleetID", ctx, fleetID, at)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsActiveByFleetID indicates an expected call of ExistsActiveByFleetID.
+func (mr *MockContractRepositoryMockRecorder) ExistsActiveByFleetID(ctx, fleetID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsActiveByFleetID", reflect.TypeOf((*MockContractRepository)(nil).ExistsActiveByFleetID), ctx, fleetID, at)
+}
+
+// ExistsActiveByLegalEntityID mocks base method.
+func (m *MockContractRepository) ExistsActiveByLegalEntityID(ctx context.Context, legalEntityID string, at time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsActiveByLegalEntityID", ctx, legalEntityID, at)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsActiveByLegalEntityID indicates an expected call of ExistsActiveByLegalEntityID.
+func (mr *MockContractRepositoryMockRecorder) ExistsActiveByLegalEntityID(ctx, legalEntityID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsActiveByLegalEntityID", reflect.TypeOf((*MockContractRepository)(nil).ExistsActiveByLegalEntityID), ctx, legalEntityID, at)
+}
+
 // FindByDriverID mocks base method.
-func (m *MockContractRepository) FindByDriverID(ctx context.Context, driverID string) ([]*domain.Contract, error) {
+func (m *MockContractRepository) FindByDriverID(ctx context.Context, driverID string, q ports.ListQuery) (*ports.Page[*domain.Contract], error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByDriverID", ctx, driverID)
-	ret0, _ := ret[0].([]*domain.Contract)
+	ret := m.ctrl.Call(m, "FindByDriverID", ctx, driverID, q)
+	ret0, _ := ret[0].(*ports.Page[*domain.Contract])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByDriverID indicates an expected call of FindByDriverID.
-func (mr *MockContractRepositoryMockRecorder) FindByDriverID(ctx, driverID any) *gomock.Call {
+func (mr *MockContractRepositoryMockRecorder) FindByDriverID(ctx, driverID, q any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByDriverID", reflect.TypeOf((*MockContractRepository)(nil).FindByDriverID), ctx, driverID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByDriverID", reflect.TypeOf((*MockContractRepository)(nil).FindByDriverID), ctx, driverID, q)
 }
 
 // FindByID mocks base method.
@@ -456,6 +712,21 @@ func (mr *MockContractRepositoryMockRecorder) FindByID(ctx, id any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockContractRepository)(nil).FindByID), ctx, id)
 }
 
+// FindDeletedByID mocks base method.
+func (m *MockContractRepository) FindDeletedByID(ctx context.Context, id string) (*domain.Contract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDeletedByID", ctx, id)
+	ret0, _ := ret[0].(*domain.Contract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDeletedByID indicates an expected call of FindDeletedByID.
+func (mr *MockContractRepositoryMockRecorder) FindDeletedByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeletedByID", reflect.TypeOf((*MockContractRepository)(nil).FindDeletedByID), ctx, id)
+}
+
 // FindOverlapping mocks base method.
 func (m *MockContractRepository) FindOverlapping(ctx context.Context, driverID, legalEntityID, fleetID string, startDate, endDate time.Time, excludeID string) ([]*domain.Contract, error) {
 	m.ctrl.T.Helper()
@@ -486,31 +757,91 @@ func (mr *MockContractRepositoryMockRecorder) Save(ctx, entity any) *gomock.Call
 }
 
 // SoftDelete mocks base method.
-func (m *MockContractRepository) SoftDelete(ctx context.Context, id string) error {
+func (m *MockContractRepository) SoftDelete(ctx context.Context, id string, d ports.Deletion) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SoftDelete", ctx, id)
+	ret := m.ctrl.Call(m, "SoftDelete", ctx, id, d)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SoftDelete indicates an expected call of SoftDelete.
-func (mr *MockContractRepositoryMockRecorder) SoftDelete(ctx, id any) *gomock.Call {
+func (mr *MockContractRepositoryMockRecorder) SoftDelete(ctx, id, d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDelete", reflect.TypeOf((*MockContractRepository)(nil).SoftDelete), ctx, id, d)
+}
+
+// SoftDeleteByFleetID mocks base method.
+func (m *MockContractRepository) SoftDeleteByFleetID(ctx context.Context, fleetID string, d ports.Deletion) ([]*domain.Contract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDeleteByFleetID", ctx, fleetID, d)
+	ret0, _ := ret[0].([]*domain.Contract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SoftDeleteByFleetID indicates an expected call of SoftDeleteByFleetID.
+func (mr *MockContractRepositoryMockRecorder) SoftDeleteByFleetID(ctx, fleetID, d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDeleteByFleetID", reflect.TypeOf((*MockContractRepository)(nil).SoftDeleteByFleetID), ctx, fleetID, d)
+}
+
+// SoftDeleteByLegalEntityID mocks base method.
+func (m *MockContractRepository) SoftDeleteByLegalEntityID(ctx context.Context, legalEntityID string, d ports.Deletion) ([]*domain.Contract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDeleteByLegalEntityID", ctx, legalEntityID, d)
+	ret0, _ := ret[0].([]*domain.Contract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SoftDeleteByLegalEntityID indicates an expected call of SoftDeleteByLegalEntityID.
+func (mr *MockContractRepositoryMockRecorder) SoftDeleteByLegalEntityID(ctx, legalEntityID, d any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDelete", reflect.TypeOf((*MockContractRepository)(nil).SoftDelete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDeleteByLegalEntityID", reflect.TypeOf((*MockContractRepository)(nil).SoftDeleteByLegalEntityID), ctx, legalEntityID, d)
 }
 
 // Undelete mocks base method.
-func (m *MockContractRepository) Undelete(ctx context.Context, id string) error {
+func (m *MockContractRepository) Undelete(ctx context.Context, id string, at time.Time, by string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Undelete", ctx, id)
+	ret := m.ctrl.Call(m, "Undelete", ctx, id, at, by)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Undelete indicates an expected call of Undelete.
-func (mr *MockContractRepositoryMockRecorder) Undelete(ctx, id any) *gomock.Call {
+func (mr *MockContractRepositoryMockRecorder) Undelete(ctx, id, at, by any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockContractRepository)(nil).Undelete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockContractRepository)(nil).Undelete), ctx, id, at, by)
+}
+
+// UndeleteByFleetID mocks base method.
+func (m *MockContractRepository) UndeleteByFleetID(ctx context.Context, fleetID, deletionID string, at time.Time, by string) ([]*domain.Contract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndeleteByFleetID", ctx, fleetID, deletionID, at, by)
+	ret0, _ := ret[0].([]*domain.Contract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UndeleteByFleetID indicates an expected call of UndeleteByFleetID.
+func (mr *MockContractRepositoryMockRecorder) UndeleteByFleetID(ctx, fleetID, deletionID, at, by any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndeleteByFleetID", reflect.TypeOf((*MockContractRepository)(nil).UndeleteByFleetID), ctx, fleetID, deletionID, at, by)
+}
+
+// UndeleteByLegalEntityID mocks base method.
+func (m *MockContractRepository) UndeleteByLegalEntityID(ctx context.Context, legalEntityID, deletionID string, at time.Time, by string) ([]*domain.Contract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndeleteByLegalEntityID", ctx, legalEntityID, deletionID, at, by)
+	ret0, _ := ret[0].([]*domain.Contract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UndeleteByLegalEntityID indicates an expected call of UndeleteByLegalEntityID.
+func (mr *MockContractRepositoryMockRecorder) UndeleteByLegalEntityID(ctx, legalEntityID, deletionID, at, by any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndeleteByLegalEntityID", reflect.TypeOf((*MockContractRepository)(nil).UndeleteByLegalEntityID), ctx, legalEntityID, deletionID, at, by)
 }
 
 // MockVehicleAssignmentRepository is a mock of VehicleAssignmentRepository interface.
@@ -537,6 +868,51 @@ func (m *MockVehicleAssignmentRepository) EXPECT() *MockVehicleAssignmentReposit
 	return m.recorder
 }
 
+// ExistsActiveByFleetID mocks base method.
+func (m *MockVehicleAssignmentRepository) ExistsActiveByFleetID(ctx context.Context, fleetID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsActiveByFleetID", ctx, fleetID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsActiveByFleetID indicates an expected call of ExistsActiveByFleetID.
+func (mr *MockVehicleAssignmentRepositoryMockRecorder) ExistsActiveByFleetID(ctx, fleetID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsActiveByFleetID", reflect.TypeOf((*MockVehicleAssignmentRepository)(nil).ExistsActiveByFleetID), ctx, fleetID)
+}
+
+// ExistsActiveByLegalEntityID mocks base method.
+func (m *MockVehicleAssignmentRepository) ExistsActiveByLegalEntityID(ctx context.Context, legalEntityID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsActiveByLegalEntityID", ctx, legalEntityID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsActiveByLegalEntityID indicates an expected call of ExistsActiveByLegalEntityID.
+func (mr *MockVehicleAssignmentRepositoryMockRecorder) ExistsActiveByLegalEntityID(ctx, legalEntityID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsActiveByLegalEntityID", reflect.TypeOf((*MockVehicleAssignmentRepository)(nil).ExistsActiveByLegalEntityID), ctx, legalEntityID)
+}
+
+// ExistsActiveByVehicleID mocks base method.
+func (m *MockVehicleAssignmentRepository) ExistsActiveByVehicleID(ctx context.Context, vehicleID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsActiveByVehicleID", ctx, vehicleID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsActiveByVehicleID indicates an expected call of ExistsActiveByVehicleID.
+func (mr *MockVehicleAssignmentRepositoryMockRecorder) ExistsActiveByVehicleID(ctx, vehicleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsActiveByVehicleID", reflect.TypeOf((*MockVehicleAssignmentRepository)(nil).ExistsActiveByVehicleID), ctx, vehicleID)
+}
+
 // FindActiveByDriverID mocks base method.
 func (m *MockVehicleAssignmentRepository) FindActiveByDriverID(ctx context.Context, driverID string) ([]*domain.VehicleAssignment, error) {
 	m.ctrl.T.Helper()
@@ -568,18 +944,18 @@ func (mr *MockVehicleAssignmentRepositoryMockRecorder) FindActiveByDriverIDAndFl
 }
 
 // FindByContractID mocks base method.
-func (m *MockVehicleAssignmentRepository) FindByContractID(ctx context.Context, contractID string) ([]*domain.VehicleAssignment, error) {
+func (m *MockVehicleAssignmentRepository) FindByContractID(ctx context.Context, contractID string, q ports.ListQuery) (*ports.Page[*domain.VehicleAssignment], error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByContractID", ctx, contractID)
-	ret0, _ := ret[0].([]*domain.VehicleAssignment)
+	ret := m.ctrl.Call(m, "FindByContractID", ctx, contractID, q)
+	ret0, _ := ret[0].(*ports.Page[*domain.VehicleAssignment])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByContractID indicates an expected call of FindByContractID.
-func (mr *MockVehicleAssignmentRepositoryMockRecorder) FindByContractID(ctx, contractID any) *gomock.Call {
+func (mr *MockVehicleAssignmentRepositoryMockRecorder) FindByContractID(ctx, contractID, q any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByContractID", reflect.TypeOf((*MockVehicleAssignmentRepository)(nil).FindByContractID), ctx, contractID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByContractID", reflect.TypeOf((*MockVehicleAssignmentRepository)(nil).FindByContractID), ctx, contractID, q)
 }
 
 // FindByID mocks base method.
@@ -597,6 +973,21 @@ func (mr *MockVehicleAssignmentRepositoryMockRecorder) FindByID(ctx, id any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockVehicleAssignmentRepository)(nil).FindByID), ctx, id)
 }
 
+// FindDeletedByID mocks base method.
+func (m *MockVehicleAssignmentRepository) FindDeletedByID(ctx context.Context, id string) (*domain.VehicleAssignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDeletedByID", ctx, id)
+	ret0, _ := ret[0].(*domain.VehicleAssignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDeletedByID indicates an expected call of FindDeletedByID.
+func (mr *MockVehicleAssignmentRepositoryMockRecorder) FindDeletedByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeletedByID", reflect.TypeOf((*MockVehicleAssignmentRepository)(nil).FindDeletedByID), ctx, id)
+}
+
 // Save mocks base method.
 func (m *MockVehicleAssignmentRepository) Save(ctx context.Context, entity *domain.VehicleAssignment) error {
 	m.ctrl.T.Helper()
@@ -612,29 +1003,119 @@ func (mr *MockVehicleAssignmentRepositoryMockRecorder) Save(ctx, entity any) *go
 }
 
 // SoftDelete mocks base method.
-func (m *MockVehicleAssignmentRepository) SoftDelete(ctx context.Context, id string) error {
+func (m *MockVehicleAssignmentRepository) SoftDelete(ctx context.Context, id string, d ports.Deletion) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SoftDelete", ctx, id)
+	ret := m.ctrl.Call(m, "SoftDelete", ctx, id, d)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SoftDelete indicates an expected call of SoftDelete.
-func (mr *MockVehicleAssignmentRepositoryMockRecorder) SoftDelete(ctx, id any) *gomock.Call {
+func (mr *MockVehicleAssignmentRepositoryMockRecorder) SoftDelete(ctx, id, d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDelete", reflect.TypeOf((*MockVehicleAssignmentRepository)(nil).SoftDelete), ctx, id, d)
+}
+
+// SoftDeleteByFleetID mocks base method.
+func (m *MockVehicleAssignmentRepository) SoftDeleteByFleetID(ctx context.Context, fleetID string, d ports.Deletion) ([]*domain.VehicleAssignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDeleteByFleetID", ctx, fleetID, d)
+	ret0, _ := ret[0].([]*domain.VehicleAssignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SoftDeleteByFleetID indicates an expected call of SoftDeleteByFleetID.
+func (mr *MockVehicleAssignmentRepositoryMockRecorder) SoftDeleteByFleetID(ctx, fleetID, d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDeleteByFleetID", reflect.TypeOf((*MockVehicleAssignmentRepository)(nil).SoftDeleteByFleetID), ctx, fleetID, d)
+}
+
+// SoftDeleteByLegalEntityID mocks base method.
+func (m *MockVehicleAssignmentRepository) SoftDeleteByLegalEntityID(ctx context.Context, legalEntityID string, d ports.Deletion) ([]*domain.VehicleAssignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDeleteByLegalEntityID", ctx, legalEntityID, d)
+	ret0, _ := ret[0].([]*domain.VehicleAssignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SoftDeleteByLegalEntityID indicates an expected call of SoftDeleteByLegalEntityID.
+func (mr *MockVehicleAssignmentRepositoryMockRecorder) SoftDeleteByLegalEntityID(ctx, legalEntityID, d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDeleteByLegalEntityID", reflect.TypeOf((*MockVehicleAssignmentRepository)(nil).SoftDeleteByLegalEntityID), ctx, legalEntityID, d)
+}
+
+// SoftDeleteByVehicleID mocks base method.
+func (m *MockVehicleAssignmentRepository) SoftDeleteByVehicleID(ctx context.Context, vehicleID string, d ports.Deletion) ([]*domain.VehicleAssignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDeleteByVehicleID", ctx, vehicleID, d)
+	ret0, _ := ret[0].([]*domain.VehicleAssignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SoftDeleteByVehicleID indicates an expected call of SoftDeleteByVehicleID.
+func (mr *MockVehicleAssignmentRepositoryMockRecorder) SoftDeleteByVehicleID(ctx, vehicleID, d any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDelete", reflect.TypeOf((*MockVehicleAssignmentRepository)(nil).SoftDelete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDeleteByVehicleID", reflect.TypeOf((*MockVehicleAssignmentRepository)(nil).SoftDeleteByVehicleID), ctx, vehicleID, d)
 }
 
 // Undelete mocks base method.
-func (m *MockVehicleAssignmentRepository) Undelete(ctx context.Context, id string) error {
+func (m *MockVehicleAssignmentRepository) Undelete(ctx context.Context, id string, at time.Time, by string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Undelete", ctx, id)
+	ret := m.ctrl.Call(m, "Undelete", ctx, id, at, by)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Undelete indicates an expected call of Undelete.
-func (mr *MockVehicleAssignmentRepositoryMockRecorder) Undelete(ctx, id any) *gomock.Call {
+func (mr *MockVehicleAssignmentRepositoryMockRecorder) Undelete(ctx, id, at, by any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockVehicleAssignmentRepository)(nil).Undelete), ctx, id, at, by)
+}
+
+// UndeleteByFleetID mocks base method.
+func (m *MockVehicleAssignmentRepository) UndeleteByFleetID(ctx context.Context, fleetID, deletionID string, at time.Time, by string) ([]*domain.VehicleAssignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndeleteByFleetID", ctx, fleetID, deletionID, at, by)
+	ret0, _ := ret[0].([]*domain.VehicleAssignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UndeleteByFleetID indicates an expected call of UndeleteByFleetID.
+func (mr *MockVehicleAssignmentRepositoryMockRecorder) UndeleteByFleetID(ctx, fleetID, deletionID, at, by any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndeleteByFleetID", reflect.TypeOf((*MockVehicleAssignmentRepository)(nil).UndeleteByFleetID), ctx, fleetID, deletionID, at, by)
+}
+
+// UndeleteByLegalEntityID mocks base method.
+func (m *MockVehicleAssignmentRepository) UndeleteByLegalEntityID(ctx context.Context, legalEntityID, deletionID string, at time.Time, by string) ([]*domain.VehicleAssignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndeleteByLegalEntityID", ctx, legalEntityID, deletionID, at, by)
+	ret0, _ := ret[0].([]*domain.VehicleAssignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UndeleteByLegalEntityID indicates an expected call of UndeleteByLegalEntityID.
+func (mr *MockVehicleAssignmentRepositoryMockRecorder) UndeleteByLegalEntityID(ctx, legalEntityID, deletionID, at, by any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndeleteByLegalEntityID", reflect.TypeOf((*MockVehicleAssignmentRepository)(nil).UndeleteByLegalEntityID), ctx, legalEntityID, deletionID, at, by)
+}
+
+// UndeleteByVehicleID mocks base method.
+func (m *MockVehicleAssignmentRepository) UndeleteByVehicleID(ctx context.Context, vehicleID, deletionID string, at time.Time, by string) ([]*domain.VehicleAssignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndeleteByVehicleID", ctx, vehicleID, deletionID, at, by)
+	ret0, _ := ret[0].([]*domain.VehicleAssignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UndeleteByVehicleID indicates an expected call of UndeleteByVehicleID.
+func (mr *MockVehicleAssignmentRepositoryMockRecorder) UndeleteByVehicleID(ctx, vehicleID, deletionID, at, by any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockVehicleAssignmentRepository)(nil).Undelete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndeleteByVehicleID", reflect.TypeOf((*MockVehicleAssignmentRepository)(nil).UndeleteByVehicleID), ctx, vehicleID, deletionID, at, by)
 }
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/albenik/uber-fx-based-service-example/internal/core/ports (interfaces: LegalEntityService,FleetService,VehicleService,DriverService,ContractService,VehicleAssignmentService)
+// Source: github.com/albenik/uber-fx-based-service-example/internal/core/ports (interfaces: LegalEntityService,FleetService,VehicleService,DriverService,ContractService,VehicleAssignmentService,AuditService,EventRelay,WebhookService,WebhookDispatcher,EventStreamService,APIKeyService,IdempotencyService)
 //
 // Generated by this command:
 //
-//	mockgen -destination=mocks/mock_services.go -package=mocks . LegalEntityService,FleetService,VehicleService,DriverService,ContractService,VehicleAssignmentService
+//	mockgen -destination=mocks/mock_services.go -package=mocks . LegalEntityService,FleetService,VehicleService,DriverService,ContractService,VehicleAssignmentService,AuditService,EventRelay,WebhookService,WebhookDispatcher,EventStreamService,APIKeyService,IdempotencyService
 //
 
 // Package mocks is a generated GoMock package.
@@ -15,6 +15,7 @@ import (
 	time "time"
 
 	domain "github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	ports "github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -58,17 +59,17 @@ func (mr *MockLegalEntityServiceMockRecorder) Create(ctx, name, taxID any) *gomo
 }
 
 // Delete mocks base method.
-func (m *MockLegalEntityService) Delete(ctx context.Context, id string) error {
+func (m *MockLegalEntityService) Delete(ctx context.Context, id string, opts ports.DeleteOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, opts)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockLegalEntityServiceMockRecorder) Delete(ctx, id any) *gomock.Call {
+func (mr *MockLegalEntityServiceMockRecorder) Delete(ctx, id, opts any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockLegalEntityService)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockLegalEntityService)(nil).Delete), ctx, id, opts)
 }
 
 // Get mocks base method.
@@ -87,32 +88,47 @@ func (mr *MockLegalEntityServiceMockRecorder) Get(ctx, id any) *gomock.Call {
 }
 
 // List mocks base method.
-func (m *MockLegalEntityService) List(ctx context.Context) ([]*domain.LegalEntity, error) {
+func (m *MockLegalEntityService) List(ctx context.Context, q ports.ListQuery) (*ports.Page[*domain.LegalEntity], error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx)
-	ret0, _ := ret[0].([]*domain.LegalEntity)
+	ret := m.ctrl.Call(m, "List", ctx, q)
+	ret0, _ := ret[0].(*ports.Page[*domain.LegalEntity])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockLegalEntityServiceMockRecorder) List(ctx any) *gomock.Call {
+func (mr *MockLegalEntityServiceMockRecorder) List(ctx, q any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockLegalEntityService)(nil).List), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockLegalEntityService)(nil).List), ctx, q)
 }
 
 // Undelete mocks base method.
-func (m *MockLegalEntityService) Undelete(ctx context.Context, id string) error {
+func (m *MockLegalEntityService) Undelete(ctx context.Context, id string, opts ports.UndeleteOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Undelete", ctx, id)
+	ret := m.ctrl.Call(m, "Undelete", ctx, id, opts)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Undelete indicates an expected call of Undelete.
-func (mr *MockLegalEntityServiceMockRecorder) Undelete(ctx, id any) *gomock.Call {
+func (mr *MockLegalEntityServiceMockRecorder) Undelete(ctx, id, opts any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockLegalEntityService)(nil).Undelete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockLegalEntityService)(nil).Undelete), ctx, id, opts)
+}
+
+// Update mocks base method.
+func (m *MockLegalEntityService) Update(ctx context.Context, id string, version int64, patch domain.LegalEntityPatch) (*domain.LegalEntity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, version, patch)
+	ret0, _ := ret[0].(*domain.LegalEntity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockLegalEntityServiceMockRecorder) Update(ctx, id, version, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockLegalEntityService)(nil).Update), ctx, id, version, patch)
 }
 
 // MockFleetService is a mock of FleetService interface.
@@ -155,17 +171,17 @@ func (mr *MockFleetServiceMockRecorder) Create(ctx, legalEntityID, name any) *go
 }
 
 // Delete mocks base method.
-func (m *MockFleetService) Delete(ctx context.Context, id string) error {
+func (m *MockFleetService) Delete(ctx context.Context, id string, opts ports.DeleteOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, opts)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockFleetServiceMockRecorder) Delete(ctx, id any) *gomock.Call {
+func (mr *MockFleetServiceMockRecorder) Delete(ctx, id, opts any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFleetService)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFleetService)(nil).Delete), ctx, id, opts)
 }
 
 // Get mocks base method.
@@ -184,32 +200,47 @@ func (mr *MockFleetServiceMockRecorder) Get(ctx, id any) *gomock.Call {
 }
 
 // ListByLegalEntity mocks base method.
-func (m *MockFleetService) ListByLegalEntity(ctx context.Context, legalEntityID string) ([]*domain.Fleet, error) {
+func (m *MockFleetService) ListByLegalEntity(ctx context.Context, legalEntityID string, q ports.ListQuery) (*ports.Page[*domain.Fleet], error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListByLegalEntity", ctx, legalEntityID)
-	ret0, _ := ret[0].([]*domain.Fleet)
+	ret := m.ctrl.Call(m, "ListByLegalEntity", ctx, legalEntityID, q)
+	ret0, _ := ret[0].(*ports.Page[*domain.Fleet])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListByLegalEntity indicates an expected call of ListByLegalEntity.
-func (mr *MockFleetServiceMockRecorder) ListByLegalEntity(ctx, legalEntityID any) *gomock.Call {
+func (mr *MockFleetServiceMockRecorder) ListByLegalEntity(ctx, legalEntityID, q any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByLegalEntity", reflect.TypeOf((*MockFleetService)(nil).ListByLegalEntity), ctx, legalEntityID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByLegalEntity", reflect.TypeOf((*MockFleetService)(nil).ListByLegalEntity), ctx, legalEntityID, q)
 }
 
 // Undelete mocks base method.
-func (m *MockFleetService) Undelete(ctx context.Context, id string) error {
+func (m *MockFleetService) Undelete(ctx context.Context, id string, opts ports.UndeleteOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Undelete", ctx, id)
+	ret := m.ctrl.Call(m, "Undelete", ctx, id, opts)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Undelete indicates an expected call of Undelete.
-func (mr *MockFleetServiceMockRecorder) Undelete(ctx, id any) *gomock.Call {
+func (mr *MockFleetServiceMockRecorder) Undelete(ctx, id, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockFleetService)(nil).Undelete), ctx, id, opts)
+}
+
+// Update mocks base method.
+func (m *MockFleetService) Update(ctx context.Context, id string, version int64, patch domain.FleetPatch) (*domain.Fleet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, version, patch)
+	ret0, _ := ret[0].(*domain.Fleet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockFleetServiceMockRecorder) Update(ctx, id, version, patch any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockFleetService)(nil).Undelete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockFleetService)(nil).Update), ctx, id, version, patch)
 }
 
 // MockVehicleService is a mock of VehicleService interface.
@@ -252,17 +283,17 @@ func (mr *MockVehicleServiceMockRecorder) Create(ctx, fleetID, make, model, lice
 }
 
 // Delete mocks base method.
-func (m *MockVehicleService) Delete(ctx context.Context, id string) error {
+func (m *MockVehicleService) Delete(ctx context.Context, id string, opts ports.DeleteOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, opts)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockVehicleServiceMockRecorder) Delete(ctx, id any) *gomock.Call {
+func (mr *MockVehicleServiceMockRecorder) Delete(ctx, id, opts any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockVehicleService)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockVehicleService)(nil).Delete), ctx, id, opts)
 }
 
 // Get mocks base method.
@@ -281,32 +312,47 @@ func (mr *MockVehicleServiceMockRecorder) Get(ctx, id any) *gomock.Call {
 }
 
 // ListByFleet mocks base method.
-func (m *MockVehicleService) ListByFleet(ctx context.Context, fleetID string) ([]*domain.Vehicle, error) {
+func (m *MockVehicleService) ListByFleet(ctx context.Context, fleetID string, q ports.ListQuery) (*ports.Page[*domain.Vehicle], error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListByFleet", ctx, fleetID)
-	ret0, _ := ret[0].([]*domain.Vehicle)
+	ret := m.ctrl.Call(m, "ListByFleet", ctx, fleetID, q)
+	ret0, _ := ret[0].(*ports.Page[*domain.Vehicle])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListByFleet indicates an expected call of ListByFleet.
-func (mr *MockVehicleServiceMockRecorder) ListByFleet(ctx, fleetID any) *gomock.Call {
+func (mr *MockVehicleServiceMockRecorder) ListByFleet(ctx, fleetID, q any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByFleet", reflect.TypeOf((*MockVehicleService)(nil).ListByFleet), ctx, fleetID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByFleet", reflect.TypeOf((*MockVehicleService)(nil).ListByFleet), ctx, fleetID, q)
 }
 
 // Undelete mocks base method.
-func (m *MockVehicleService) Undelete(ctx context.Context, id string) error {
+func (m *MockVehicleService) Undelete(ctx context.Context, id string, opts ports.UndeleteOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Undelete", ctx, id)
+	ret := m.ctrl.Call(m, "Undelete", ctx, id, opts)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Undelete indicates an expected call of Undelete.
-func (mr *MockVehicleServiceMockRecorder) Undelete(ctx, id any) *gomock.Call {
+func (mr *MockVehicleServiceMockRecorder) Undelete(ctx, id, opts any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockVehicleService)(nil).Undelete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockVehicleService)(nil).Undelete), ctx, id, opts)
+}
+
+// Update mocks base method.
+func (m *MockVehicleService) Update(ctx context.Context, id string, version int64, patch domain.VehiclePatch) (*domain.Vehicle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, version, patch)
+	ret0, _ := ret[0].(*domain.Vehicle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockVehicleServiceMockRecorder) Update(ctx, id, version, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockVehicleService)(nil).Update), ctx, id, version, patch)
 }
 
 // MockDriverService is a mock of DriverService interface.
@@ -349,17 +395,17 @@ func (mr *MockDriverServiceMockRecorder) Create(ctx, firstName, lastName, licens
 }
 
 // Delete mocks base method.
-func (m *MockDriverService) Delete(ctx context.Context, id string) error {
+func (m *MockDriverService) Delete(ctx context.Context, id string, opts ports.DeleteOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, opts)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockDriverServiceMockRecorder) Delete(ctx, id any) *gomock.Call {
+func (mr *MockDriverServiceMockRecorder) Delete(ctx, id, opts any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDriverService)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDriverService)(nil).Delete), ctx, id, opts)
 }
 
 // Get mocks base method.
@@ -378,18 +424,18 @@ func (mr *MockDriverServiceMockRecorder) Get(ctx, id any) *gomock.Call {
 }
 
 // List mocks base method.
-func (m *MockDriverService) List(ctx context.Context) ([]*domain.Driver, error) {
+func (m *MockDriverService) List(ctx context.Context, q ports.ListQuery) (*ports.Page[*domain.Driver], error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx)
-	ret0, _ := ret[0].([]*domain.Driver)
+	ret := m.ctrl.Call(m, "List", ctx, q)
+	ret0, _ := ret[0].(*ports.Page[*domain.Driver])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockDriverServiceMockRecorder) List(ctx any) *gomock.Call {
+func (mr *MockDriverServiceMockRecorder) List(ctx, q any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDriverService)(nil).List), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDriverService)(nil).List), ctx, q)
 }
 
 // Undelete mocks base method.
@@ -406,6 +452,21 @@ func (mr *MockDriverServiceMockRecorder) Undelete(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockDriverService)(nil).Undelete), ctx, id)
 }
 
+// Update mocks base method.
+func (m *MockDriverService) Update(ctx context.Context, id string, version int64, patch domain.DriverPatch) (*domain.Driver, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, version, patch)
+	ret0, _ := ret[0].(*domain.Driver)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDriverServiceMockRecorder) Update(ctx, id, version, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDriverService)(nil).Update), ctx, id, version, patch)
+}
+
 // ValidateLicense mocks base method.
 func (m *MockDriverService) ValidateLicense(ctx context.Context, id string) (domain.LicenseValidationResult, error) {
 	m.ctrl.T.Helper()
@@ -461,17 +522,17 @@ func (mr *MockContractServiceMockRecorder) Create(ctx, driverID, legalEntityID,
 }
 
 // Delete mocks base method.
-func (m *MockContractService) Delete(ctx context.Context, id string) error {
+func (m *MockContractService) Delete(ctx context.Context, id string, opts ports.DeleteOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, opts)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockContractServiceMockRecorder) Delete(ctx, id any) *gomock.Call {
+func (mr *MockContractServiceMockRecorder) Delete(ctx, id, opts any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockContractService)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockContractService)(nil).Delete), ctx, id, opts)
 }
 
 // Get mocks base method.
@@ -490,18 +551,18 @@ func (mr *MockContractServiceMockRecorder) Get(ctx, id any) *gomock.Call {
 }
 
 // ListByDriver mocks base method.
-func (m *MockContractService) ListByDriver(ctx context.Context, driverID string) ([]*domain.Contract, error) {
+func (m *MockContractService) ListByDriver(ctx context.Context, driverID string, q ports.ListQuery) (*ports.Page[*domain.Contract], error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListByDriver", ctx, driverID)
-	ret0, _ := ret[0].([]*domain.Contract)
+	ret := m.ctrl.Call(m, "ListByDriver", ctx, driverID, q)
+	ret0, _ := ret[0].(*ports.Page[*domain.Contract])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListByDriver indicates an expected call of ListByDriver.
-func (mr *MockContractServiceMockRecorder) ListByDriver(ctx, driverID any) *gomock.Call {
+func (mr *MockContractServiceMockRecorder) ListByDriver(ctx, driverID, q any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByDriver", reflect.TypeOf((*MockContractService)(nil).ListByDriver), ctx, driverID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByDriver", reflect.TypeOf((*MockContractService)(nil).ListByDriver), ctx, driverID, q)
 }
 
 // Terminate mocks base method.
@@ -533,6 +594,21 @@ func (mr *MockContractServiceMockRecorder) Undelete(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockContractService)(nil).Undelete), ctx, id)
 }
 
+// Update mocks base method.
+func (m *MockContractService) Update(ctx context.Context, id string, version int64, patch domain.ContractPatch) (*domain.Contract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, version, patch)
+	ret0, _ := ret[0].(*domain.Contract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockContractServiceMockRecorder) Update(ctx, id, version, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockContractService)(nil).Update), ctx, id, version, patch)
+}
+
 // MockVehicleAssignmentService is a mock of VehicleAssignmentService interface.
 type MockVehicleAssignmentService struct {
 	ctrl     *gomock.Controller
@@ -573,17 +649,17 @@ func (mr *MockVehicleAssignmentServiceMockRecorder) Assign(ctx, contractID, vehi
 }
 
 // Delete mocks base method.
-func (m *MockVehicleAssignmentService) Delete(ctx context.Context, id string) error {
+func (m *MockVehicleAssignmentService) Delete(ctx context.Context, id string, opts ports.DeleteOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, opts)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockVehicleAssignmentServiceMockRecorder) Delete(ctx, id any) *gomock.Call {
+func (mr *MockVehicleAssignmentServiceMockRecorder) Delete(ctx, id, opts any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockVehicleAssignmentService)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockVehicleAssignmentService)(nil).Delete), ctx, id, opts)
 }
 
 // Get mocks base method.
@@ -602,18 +678,18 @@ func (mr *MockVehicleAssignmentServiceMockRecorder) Get(ctx, id any) *gomock.Cal
 }
 
 // ListByContract mocks base method.
-func (m *MockVehicleAssignmentService) ListByContract(ctx context.Context, contractID string) ([]*domain.VehicleAssignment, error) {
+func (m *MockVehicleAssignmentService) ListByContract(ctx context.Context, contractID string, q ports.ListQuery) (*ports.Page[*domain.VehicleAssignment], error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListByContract", ctx, contractID)
-	ret0, _ := ret[0].([]*domain.VehicleAssignment)
+	ret := m.ctrl.Call(m, "ListByContract", ctx, contractID, q)
+	ret0, _ := ret[0].(*ports.Page[*domain.VehicleAssignment])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListByContract indicates an expected call of ListByContract.
-func (mr *MockVehicleAssignmentServiceMockRecorder) ListByContract(ctx, contractID any) *gomock.Call {
+func (mr *MockVehicleAssignmentServiceMockRecorder) ListByContract(ctx, contractID, q any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByContract", reflect.TypeOf((*MockVehicleAssignmentService)(nil).ListByContract), ctx, contractID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByContract", reflect.TypeOf((*MockVehicleAssignmentService)(nil).ListByContract), ctx, contractID, q)
 }
 
 // Return mocks base method.
@@ -644,3 +720,437 @@ func (mr *MockVehicleAssignmentServiceMockRecorder) Undelete(ctx, id any) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockVehicleAssignmentService)(nil).Undelete), ctx, id)
 }
+
+// Update mocks base method.
+func (m *MockVehicleAssignmentService) Update(ctx context.Context, id string, version int64, patch domain.VehicleAssignmentPatch) (*domain.VehicleAssignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, version, patch)
+	ret0, _ := ret[0].(*domain.VehicleAssignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockVehicleAssignmentServiceMockRecorder) Update(ctx, id, version, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockVehicleAssignmentService)(nil).Update), ctx, id, version, patch)
+}
+
+// MockAuditService is a mock of AuditService interface.
+type MockAuditService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditServiceMockRecorder is the mock recorder for MockAuditService.
+type MockAuditServiceMockRecorder struct {
+	mock *MockAuditService
+}
+
+// NewMockAuditService creates a new mock instance.
+func NewMockAuditService(ctrl *gomock.Controller) *MockAuditService {
+	mock := &MockAuditService{ctrl: ctrl}
+	mock.recorder = &MockAuditServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditService) EXPECT() *MockAuditServiceMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockAuditService) List(ctx context.Context, q ports.ListQuery) (*ports.Page[*domain.AuditEntry], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, q)
+	ret0, _ := ret[0].(*ports.Page[*domain.AuditEntry])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAuditServiceMockRecorder) List(ctx, q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAuditService)(nil).List), ctx, q)
+}
+
+// MockEventRelay is a mock of EventRelay interface.
+type MockEventRelay struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventRelayMockRecorder
+	isgomock struct{}
+}
+
+// MockEventRelayMockRecorder is the mock recorder for MockEventRelay.
+type MockEventRelayMockRecorder struct {
+	mock *MockEventRelay
+}
+
+// NewMockEventRelay creates a new mock instance.
+func NewMockEventRelay(ctrl *gomock.Controller) *MockEventRelay {
+	mock := &MockEventRelay{ctrl: ctrl}
+	mock.recorder = &MockEventRelayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventRelay) EXPECT() *MockEventRelayMockRecorder {
+	return m.recorder
+}
+
+// RelayBatch mocks base method.
+func (m *MockEventRelay) RelayBatch(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RelayBatch", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RelayBatch indicates an expected call of RelayBatch.
+func (mr *MockEventRelayMockRecorder) RelayBatch(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RelayBatch", reflect.TypeOf((*MockEventRelay)(nil).RelayBatch), ctx)
+}
+
+// MockWebhookService is a mock of WebhookService interface.
+type MockWebhookService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhookServiceMockRecorder is the mock recorder for MockWebhookService.
+type MockWebhookServiceMockRecorder struct {
+	mock *MockWebhookService
+}
+
+// NewMockWebhookService creates a new mock instance.
+func NewMockWebhookService(ctrl *gomock.Controller) *MockWebhookService {
+	mock := &MockWebhookService{ctrl: ctrl}
+	mock.recorder = &MockWebhookServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookService) EXPECT() *MockWebhookServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWebhookService) Create(ctx context.Context, url string, eventTypes []domain.EventType, secret string) (*domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, url, eventTypes, secret)
+	ret0, _ := ret[0].(*domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhookServiceMockRecorder) Create(ctx, url, eventTypes, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhookService)(nil).Create), ctx, url, eventTypes, secret)
+}
+
+// Delete mocks base method.
+func (m *MockWebhookService) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebhookServiceMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebhookService)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockWebhookService) Get(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockWebhookServiceMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWebhookService)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockWebhookService) List(ctx context.Context, q ports.ListQuery) (*ports.Page[*domain.WebhookSubscription], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, q)
+	ret0, _ := ret[0].(*ports.Page[*domain.WebhookSubscription])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockWebhookServiceMockRecorder) List(ctx, q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWebhookService)(nil).List), ctx, q)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockWebhookService) ListDeliveries(ctx context.Context, subscriptionID string, q ports.ListQuery) (*ports.Page[*domain.WebhookDelivery], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", ctx, subscriptionID, q)
+	ret0, _ := ret[0].(*ports.Page[*domain.WebhookDelivery])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockWebhookServiceMockRecorder) ListDeliveries(ctx, subscriptionID, q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockWebhookService)(nil).ListDeliveries), ctx, subscriptionID, q)
+}
+
+// Replay mocks base method.
+func (m *MockWebhookService) Replay(ctx context.Context, subscriptionID, deliveryID string) (*domain.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replay", ctx, subscriptionID, deliveryID)
+	ret0, _ := ret[0].(*domain.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Replay indicates an expected call of Replay.
+func (mr *MockWebhookServiceMockRecorder) Replay(ctx, subscriptionID, deliveryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replay", reflect.TypeOf((*MockWebhookService)(nil).Replay), ctx, subscriptionID, deliveryID)
+}
+
+// Update mocks base method.
+func (m *MockWebhookService) Update(ctx context.Context, id string, version int64, patch domain.WebhookSubscriptionPatch) (*domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, version, patch)
+	ret0, _ := ret[0].(*domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWebhookServiceMockRecorder) Update(ctx, id, version, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWebhookService)(nil).Update), ctx, id, version, patch)
+}
+
+// MockWebhookDispatcher is a mock of WebhookDispatcher interface.
+type MockWebhookDispatcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookDispatcherMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhookDispatcherMockRecorder is the mock recorder for MockWebhookDispatcher.
+type MockWebhookDispatcherMockRecorder struct {
+	mock *MockWebhookDispatcher
+}
+
+// NewMockWebhookDispatcher creates a new mock instance.
+func NewMockWebhookDispatcher(ctrl *gomock.Controller) *MockWebhookDispatcher {
+	mock := &MockWebhookDispatcher{ctrl: ctrl}
+	mock.recorder = &MockWebhookDispatcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookDispatcher) EXPECT() *MockWebhookDispatcherMockRecorder {
+	return m.recorder
+}
+
+// DeliverBatch mocks base method.
+func (m *MockWebhookDispatcher) DeliverBatch(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeliverBatch", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeliverBatch indicates an expected call of DeliverBatch.
+func (mr *MockWebhookDispatcherMockRecorder) DeliverBatch(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliverBatch", reflect.TypeOf((*MockWebhookDispatcher)(nil).DeliverBatch), ctx)
+}
+
+// Publish mocks base method.
+func (m *MockWebhookDispatcher) Publish(ctx context.Context, event *domain.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockWebhookDispatcherMockRecorder) Publish(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockWebhookDispatcher)(nil).Publish), ctx, event)
+}
+
+// MockEventStreamService is a mock of EventStreamService interface.
+type MockEventStreamService struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventStreamServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockEventStreamServiceMockRecorder is the mock recorder for MockEventStreamService.
+type MockEventStreamServiceMockRecorder struct {
+	mock *MockEventStreamService
+}
+
+// NewMockEventStreamService creates a new mock instance.
+func NewMockEventStreamService(ctrl *gomock.Controller) *MockEventStreamService {
+	mock := &MockEventStreamService{ctrl: ctrl}
+	mock.recorder = &MockEventStreamServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventStreamService) EXPECT() *MockEventStreamServiceMockRecorder {
+	return m.recorder
+}
+
+// Stream mocks base method.
+func (m *MockEventStreamService) Stream(ctx context.Context, filter domain.EventFilter, lastEventID string) (<-chan *domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", ctx, filter, lastEventID)
+	ret0, _ := ret[0].(<-chan *domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockEventStreamServiceMockRecorder) Stream(ctx, filter, lastEventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockEventStreamService)(nil).Stream), ctx, filter, lastEventID)
+}
+
+// MockAPIKeyService is a mock of APIKeyService interface.
+type MockAPIKeyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockAPIKeyServiceMockRecorder is the mock recorder for MockAPIKeyService.
+type MockAPIKeyServiceMockRecorder struct {
+	mock *MockAPIKeyService
+}
+
+// NewMockAPIKeyService creates a new mock instance.
+func NewMockAPIKeyService(ctrl *gomock.Controller) *MockAPIKeyService {
+	mock := &MockAPIKeyService{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyService) EXPECT() *MockAPIKeyServiceMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAPIKeyService) Authenticate(ctx context.Context, key string) (domain.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, key)
+	ret0, _ := ret[0].(domain.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAPIKeyServiceMockRecorder) Authenticate(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAPIKeyService)(nil).Authenticate), ctx, key)
+}
+
+// MockIdempotencyService is a mock of IdempotencyService interface.
+type MockIdempotencyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyServiceMockRecorder is the mock recorder for MockIdempotencyService.
+type MockIdempotencyServiceMockRecorder struct {
+	mock *MockIdempotencyService
+}
+
+// NewMockIdempotencyService creates a new mock instance.
+func NewMockIdempotencyService(ctrl *gomock.Controller) *MockIdempotencyService {
+	mock := &MockIdempotencyService{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyService) EXPECT() *MockIdempotencyServiceMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockIdempotencyService) Begin(ctx context.Context, key, fingerprint string) (*domain.IdempotentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx, key, fingerprint)
+	ret0, _ := ret[0].(*domain.IdempotentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockIdempotencyServiceMockRecorder) Begin(ctx, key, fingerprint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockIdempotencyService)(nil).Begin), ctx, key, fingerprint)
+}
+
+// Complete mocks base method.
+func (m *MockIdempotencyService) Complete(ctx context.Context, key string, resp *domain.IdempotentResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, key, resp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockIdempotencyServiceMockRecorder) Complete(ctx, key, resp any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockIdempotencyService)(nil).Complete), ctx, key, resp)
+}
+
+// PurgeExpired mocks base method.
+func (m *MockIdempotencyService) PurgeExpired(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpired", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpired indicates an expected call of PurgeExpired.
+func (mr *MockIdempotencyServiceMockRecorder) PurgeExpired(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpired", reflect.TypeOf((*MockIdempotencyService)(nil).PurgeExpired), ctx)
+}
+
+// Release mocks base method.
+func (m *MockIdempotencyService) Release(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockIdempotencyServiceMockRecorder) Release(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockIdempotencyService)(nil).Release), ctx, key)
+}
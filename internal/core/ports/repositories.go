@@ -9,59 +9,130 @@ import (
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
 )
 
+// Save methods insert entities with Version 0 and otherwise update the stored row only
+// if its version still equals entity.Version, returning domain.ErrConflict when it does
+// not. On success entity.Version is set to the new stored version. CreatedAt and CreatedBy
+// are written on insert only; UpdatedAt and UpdatedBy are taken from the entity as is.
+//
+// Save and Undelete return a *domain.UniqueViolationError when the write would give two
+// live entities the same tax ID, license number or license plate.
+//
+// SoftDelete and SoftDeleteBy* methods record the Deletion on every entity they delete;
+// Undelete methods clear it and record at and by as the entity's UpdatedAt and UpdatedBy.
+// SoftDeleteBy* methods soft-delete all live entities under the given parent and return
+// them as they were before the delete; they are used for cascading deletes and succeed
+// when there is nothing to delete.
+//
+// FindDeletedByID returns a soft-deleted entity, with its DeletionID set, and fails with
+// domain.ErrConflict when the entity is live. UndeleteBy* methods restore the entities
+// under the given parent that were deleted by the given deletion and whose other parents
+// are live, and return the restored entities; they are used for cascading restores and
+// succeed when there is nothing to restore.
+
+// Deletion describes one soft-delete operation; entities deleted together by a cascade share it.
+type Deletion struct {
+	ID     string
+	At     time.Time
+	By     string
+	Reason string
+}
+
 // LegalEntityRepository is the output port for LegalEntity persistence.
 type LegalEntityRepository interface {
 	Save(ctx context.Context, entity *domain.LegalEntity) error
 	FindByID(ctx context.Context, id string) (*domain.LegalEntity, error)
-	FindAll(ctx context.Context) ([]*domain.LegalEntity, error)
-	SoftDelete(ctx context.Context, id string) error
-	Undelete(ctx context.Context, id string) error
+	FindDeletedByID(ctx context.Context, id string) (*domain.LegalEntity, error)
+	FindAll(ctx context.Context, q ListQuery) (*Page[*domain.LegalEntity], error)
+	// ExistsByTaxID reports whether a live legal entity other than excludeID has taxID.
+	ExistsByTaxID(ctx context.Context, taxID, excludeID string) (bool, error)
+	SoftDelete(ctx context.Context, id string, d Deletion) error
+	Undelete(ctx context.Context, id string, at time.Time, by string) error
 }
 
 // FleetRepository is the output port for Fleet persistence.
 type FleetRepository interface {
 	Save(ctx context.Context, entity *domain.Fleet) error
 	FindByID(ctx context.Context, id string) (*domain.Fleet, error)
-	FindByLegalEntityID(ctx context.Context, legalEntityID string) ([]*domain.Fleet, error)
-	SoftDelete(ctx context.Context, id string) error
-	Undelete(ctx context.Context, id string) error
+	FindDeletedByID(ctx context.Context, id string) (*domain.Fleet, error)
+	FindByLegalEntityID(ctx context.Context, legalEntityID string, q ListQuery) (*Page[*domain.Fleet], error)
+	ExistsByLegalEntityID(ctx context.Context, legalEntityID string) (bool, error)
+	SoftDelete(ctx context.Context, id string, d Deletion) error
+	SoftDeleteByLegalEntityID(ctx context.Context, legalEntityID string, d Deletion) ([]*domain.Fleet, error)
+	Undelete(ctx context.Context, id string, at time.Time, by string) error
+	UndeleteByLegalEntityID(ctx context.Context, legalEntityID, deletionID string, at time.Time, by string) ([]*domain.Fleet, error)
 }
 
 // VehicleRepository is the output port for Vehicle persistence.
 type VehicleRepository interface {
 	Save(ctx context.Context, entity *domain.Vehicle) error
 	FindByID(ctx context.Context, id string) (*domain.Vehicle, error)
-	FindByFleetID(ctx context.Context, fleetID string) ([]*domain.Vehicle, error)
-	SoftDelete(ctx context.Context, id string) error
-	Undelete(ctx context.Context, id string) error
+	FindDeletedByID(ctx context.Context, id string) (*domain.Vehicle, error)
+	FindByFleetID(ctx context.Context, fleetID string, q ListQuery) (*Page[*domain.Vehicle], error)
+	// ExistsByLicensePlate reports whether a live vehicle other than excludeID has licensePlate.
+	ExistsByLicensePlate(ctx context.Context, licensePlate, excludeID string) (bool, error)
+	ExistsByFleetID(ctx context.Context, fleetID string) (bool, error)
+	SoftDelete(ctx context.Context, id string, d Deletion) error
+	SoftDeleteByFleetID(ctx context.Context, fleetID string, d Deletion) ([]*domain.Vehicle, error)
+	SoftDeleteByLegalEntityID(ctx context.Context, legalEntityID string, d Deletion) ([]*domain.Vehicle, error)
+	Undelete(ctx context.Context, id string, at time.Time, by string) error
+	UndeleteByFleetID(ctx context.Context, fleetID, deletionID string, at time.Time, by string) ([]*domain.Vehicle, error)
+	UndeleteByLegalEntityID(ctx context.Context, legalEntityID, deletionID string, at time.Time, by string) ([]*domain.Vehicle, error)
 }
 
 // DriverRepository is the output port for Driver persistence.
 type DriverRepository interface {
 	Save(ctx context.Context, entity *domain.Driver) error
 	FindByID(ctx context.Context, id string) (*domain.Driver, error)
-	FindAll(ctx context.Context) ([]*domain.Driver, error)
-	SoftDelete(ctx context.Context, id string) error
-	Undelete(ctx context.Context, id string) error
+	FindDeletedByID(ctx context.Context, id string) (*domain.Driver, error)
+	FindAll(ctx context.Context, q ListQuery) (*Page[*domain.Driver], error)
+	// ExistsByLicenseNumber reports whether a live driver other than excludeID has licenseNumber.
+	ExistsByLicenseNumber(ctx context.Context, licenseNumber, excludeID string) (bool, error)
+	SoftDelete(ctx context.Context, id string, d Deletion) error
+	Undelete(ctx context.Context, id string, at time.Time, by string) error
 }
 
 // ContractRepository is the output port for Contract persistence.
 type ContractRepository interface {
 	Save(ctx context.Context, entity *domain.Contract) error
 	FindByID(ctx context.Context, id string) (*domain.Contract, error)
-	FindByDriverID(ctx context.Context, driverID string) ([]*domain.Contract, error)
+	FindDeletedByID(ctx context.Context, id string) (*domain.Contract, error)
+	FindByDriverID(ctx context.Context, driverID string, q ListQuery) (*Page[*domain.Contract], error)
 	FindOverlapping(ctx context.Context, driverID, legalEntityID, fleetID string, startDate, endDate time.Time, excludeID string) ([]*domain.Contract, error)
-	SoftDelete(ctx context.Context, id string) error
-	Undelete(ctx context.Context, id string) error
+	// ExistsActiveByLegalEntityID reports whether the legal entity has a live contract that is
+	// not terminated and has not ended before at.
+	ExistsActiveByLegalEntityID(ctx context.Context, legalEntityID string, at time.Time) (bool, error)
+	// ExistsActiveByFleetID is ExistsActiveByLegalEntityID for contracts of a fleet.
+	ExistsActiveByFleetID(ctx context.Context, fleetID string, at time.Time) (bool, error)
+	SoftDelete(ctx context.Context, id string, d Deletion) error
+	SoftDeleteByFleetID(ctx context.Context, fleetID string, d Deletion) ([]*domain.Contract, error)
+	SoftDeleteByLegalEntityID(ctx context.Context, legalEntityID string, d Deletion) ([]*domain.Contract, error)
+	Undelete(ctx context.Context, id string, at time.Time, by string) error
+	UndeleteByFleetID(ctx context.Context, fleetID, deletionID string, at time.Time, by string) ([]*domain.Contract, error)
+	UndeleteByLegalEntityID(ctx context.Context, legalEntityID, deletionID string, at time.Time, by string) ([]*domain.Contract, error)
 }
 
 // VehicleAssignmentRepository is the output port for VehicleAssignment persistence.
 type VehicleAssignmentRepository interface {
 	Save(ctx context.Context, entity *domain.VehicleAssignment) error
 	FindByID(ctx context.Context, id string) (*domain.VehicleAssignment, error)
-	FindByContractID(ctx context.Context, contractID string) ([]*domain.VehicleAssignment, error)
+	FindDeletedByID(ctx context.Context, id string) (*domain.VehicleAssignment, error)
+	FindByContractID(ctx context.Context, contractID string, q ListQuery) (*Page[*domain.VehicleAssignment], error)
 	FindActiveByDriverID(ctx context.Context, driverID string) ([]*domain.VehicleAssignment, error)
 	FindActiveByDriverIDAndFleetID(ctx context.Context, driverID, fleetID string) (*domain.VehicleAssignment, error)
-	SoftDelete(ctx context.Context, id string) error
-	Undelete(ctx context.Context, id string) error
+	// ExistsActiveByVehicleID reports whether the vehicle has a live assignment that has
+	// not been returned.
+	ExistsActiveByVehicleID(ctx context.Context, vehicleID string) (bool, error)
+	// ExistsActiveByFleetID is ExistsActiveByVehicleID for assignments in a fleet.
+	ExistsActiveByFleetID(ctx context.Context, fleetID string) (bool, error)
+	// ExistsActiveByLegalEntityID is ExistsActiveByVehicleID for assignments in the fleets
+	// or under the contracts of a legal entity.
+	ExistsActiveByLegalEntityID(ctx context.Context, legalEntityID string) (bool, error)
+	SoftDelete(ctx context.Context, id string, d Deletion) error
+	SoftDeleteByVehicleID(ctx context.Context, vehicleID string, d Deletion) ([]*domain.VehicleAssignment, error)
+	SoftDeleteByFleetID(ctx context.Context, fleetID string, d Deletion) ([]*domain.VehicleAssignment, error)
+	SoftDeleteByLegalEntityID(ctx context.Context, legalEntityID string, d Deletion) ([]*domain.VehicleAssignment, error)
+	Undelete(ctx context.Context, id string, at time.Time, by string) error
+	UndeleteByVehicleID(ctx context.Context, vehicleID, deletionID string, at time.Time, by string) ([]*domain.VehicleAssignment, error)
+	UndeleteByFleetID(ctx context.Context, fleetID, deletionID string, at time.Time, by string) ([]*domain.VehicleAssignment, error)
+	UndeleteByLegalEntityID(ctx context.Context, legalEntityID, deletionID string, at time.Time, by string) ([]*domain.VehicleAssignment, error)
 }
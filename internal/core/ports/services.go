@@ -1,6 +1,6 @@
 package ports
 
-//go:generate go tool mockgen -destination=mocks/mock_services.go -package=mocks . LegalEntityService,FleetService,VehicleService,DriverService,ContractService,VehicleAssignmentService
+//go:generate go tool mockgen -destination=mocks/mock_services.go -package=mocks . LegalEntityService,FleetService,VehicleService,DriverService,ContractService,VehicleAssignmentService,AuditService,EventRelay,WebhookService,WebhookDispatcher,EventStreamService,APIKeyService,IdempotencyService
 
 import (
 	"context"
@@ -9,39 +9,70 @@ import (
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
 )
 
+// Update methods apply a partial change to an entity. A non-zero version makes the
+// update conditional: it fails with domain.ErrPreconditionFailed unless the stored
+// entity still has that version. Concurrent writes are rejected with domain.ErrConflict.
+//
+// Mutations record the principal from the context (see domain.PrincipalFromContext)
+// in the audit fields of the entities they change, and append an entry to the AuditLog
+// in the same transaction as the change.
+
+// DeleteOptions controls how Delete treats live entities that depend on the deleted one.
+type DeleteOptions struct {
+	// Cascade soft-deletes all dependent entities in the same transaction instead of
+	// rejecting the delete while dependents exist. Active contracts and vehicle
+	// assignments still reject it: they must be terminated or returned first.
+	// Drivers, contracts and vehicle assignments take no dependents along and ignore it.
+	Cascade bool
+	// Reason is recorded on every deleted entity.
+	Reason string
+}
+
+// UndeleteOptions controls how Undelete treats dependents deleted together with the entity.
+// Undelete always fails with a *domain.ParentDeletedError while a parent entity is deleted.
+type UndeleteOptions struct {
+	// Cascade also restores the dependent entities that were soft-deleted by the same
+	// cascading delete as the entity itself.
+	Cascade bool
+}
+
 // LegalEntityService is the input port for LegalEntity operations.
 type LegalEntityService interface {
 	Create(ctx context.Context, name, taxID string) (*domain.LegalEntity, error)
 	Get(ctx context.Context, id string) (*domain.LegalEntity, error)
-	List(ctx context.Context) ([]*domain.LegalEntity, error)
-	Delete(ctx context.Context, id string) error
-	Undelete(ctx context.Context, id string) error
+	List(ctx context.Context, q ListQuery) (*Page[*domain.LegalEntity], error)
+	Update(ctx context.Context, id string, version int64, patch domain.LegalEntityPatch) (*domain.LegalEntity, error)
+	Delete(ctx context.Context, id string, opts DeleteOptions) error
+	Undelete(ctx context.Context, id string, opts UndeleteOptions) error
 }
 
 // FleetService is the input port for Fleet operations.
 type FleetService interface {
 	Create(ctx context.Context, legalEntityID, name string) (*domain.Fleet, error)
 	Get(ctx context.Context, id string) (*domain.Fleet, error)
-	ListByLegalEntity(ctx context.Context, legalEntityID string) ([]*domain.Fleet, error)
-	Delete(ctx context.Context, id string) error
-	Undelete(ctx context.Context, id string) error
+	ListByLegalEntity(ctx context.Context, legalEntityID string, q ListQuery) (*Page[*domain.Fleet], error)
+	Update(ctx context.Context, id string, version int64, patch domain.FleetPatch) (*domain.Fleet, error)
+	Delete(ctx context.Context, id string, opts DeleteOptions) error
+	Undelete(ctx context.Context, id string, opts UndeleteOptions) error
 }
 
 // VehicleService is the input port for Vehicle operations.
 type VehicleService interface {
 	Create(ctx context.Context, fleetID, make, model, licensePlate string, year int) (*domain.Vehicle, error)
 	Get(ctx context.Context, id string) (*domain.Vehicle, error)
-	ListByFleet(ctx context.Context, fleetID string) ([]*domain.Vehicle, error)
-	Delete(ctx context.Context, id string) error
-	Undelete(ctx context.Context, id string) error
+	ListByFleet(ctx context.Context, fleetID string, q ListQuery) (*Page[*domain.Vehicle], error)
+	Update(ctx context.Context, id string, version int64, patch domain.VehiclePatch) (*domain.Vehicle, error)
+	Delete(ctx context.Context, id string, opts DeleteOptions) error
+	Undelete(ctx context.Context, id string, opts UndeleteOptions) error
 }
 
 // DriverService is the input port for Driver operations.
 type DriverService interface {
 	Create(ctx context.Context, firstName, lastName, licenseNumber string) (*domain.Driver, error)
 	Get(ctx context.Context, id string) (*domain.Driver, error)
-	List(ctx context.Context) ([]*domain.Driver, error)
-	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, q ListQuery) (*Page[*domain.Driver], error)
+	Update(ctx context.Context, id string, version int64, patch domain.DriverPatch) (*domain.Driver, error)
+	Delete(ctx context.Context, id string, opts DeleteOptions) error
 	Undelete(ctx context.Context, id string) error
 	ValidateLicense(ctx context.Context, id string) (domain.LicenseValidationResult, error)
 }
@@ -50,9 +81,10 @@ type DriverService interface {
 type ContractService interface {
 	Create(ctx context.Context, driverID, legalEntityID, fleetID string, startDate, endDate time.Time) (*domain.Contract, error)
 	Get(ctx context.Context, id string) (*domain.Contract, error)
-	ListByDriver(ctx context.Context, driverID string) ([]*domain.Contract, error)
+	ListByDriver(ctx context.Context, driverID string, q ListQuery) (*Page[*domain.Contract], error)
+	Update(ctx context.Context, id string, version int64, patch domain.ContractPatch) (*domain.Contract, error)
 	Terminate(ctx context.Context, id, terminatedBy string) (*domain.Contract, error)
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, opts DeleteOptions) error
 	Undelete(ctx context.Context, id string) error
 }
 
@@ -60,8 +92,76 @@ type ContractService interface {
 type VehicleAssignmentService interface {
 	Assign(ctx context.Context, contractID, vehicleID string) (*domain.VehicleAssignment, error)
 	Get(ctx context.Context, id string) (*domain.VehicleAssignment, error)
-	ListByContract(ctx context.Context, contractID string) ([]*domain.VehicleAssignment, error)
+	ListByContract(ctx context.Context, contractID string, q ListQuery) (*Page[*domain.VehicleAssignment], error)
+	Update(ctx context.Context, id string, version int64, patch domain.VehicleAssignmentPatch) (*domain.VehicleAssignment, error)
 	Return(ctx context.Context, id string) (*domain.VehicleAssignment, error)
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, opts DeleteOptions) error
 	Undelete(ctx context.Context, id string) error
 }
+
+// AuditService is the input port for querying the audit trail.
+type AuditService interface {
+	List(ctx context.Context, q ListQuery) (*Page[*domain.AuditEntry], error)
+}
+
+// EventRelay is the input port delivering domain events from the outbox to the publishers.
+type EventRelay interface {
+	// RelayBatch delivers one batch of due events and returns how many it processed.
+	// Events that fail to publish are scheduled for a retry.
+	RelayBatch(ctx context.Context) (int, error)
+}
+
+// WebhookService is the input port for managing webhook subscriptions and their delivery log.
+type WebhookService interface {
+	Create(ctx context.Context, url string, eventTypes []domain.EventType, secret string) (*domain.WebhookSubscription, error)
+	Get(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+	List(ctx context.Context, q ListQuery) (*Page[*domain.WebhookSubscription], error)
+	Update(ctx context.Context, id string, version int64, patch domain.WebhookSubscriptionPatch) (*domain.WebhookSubscription, error)
+	Delete(ctx context.Context, id string) error
+	ListDeliveries(ctx context.Context, subscriptionID string, q ListQuery) (*Page[*domain.WebhookDelivery], error)
+	// Replay sends a finished delivery again. It fails with domain.ErrConflict while the
+	// delivery is pending or the subscription is inactive.
+	Replay(ctx context.Context, subscriptionID, deliveryID string) (*domain.WebhookDelivery, error)
+}
+
+// WebhookDispatcher is the input port delivering domain events to webhook subscriptions.
+type WebhookDispatcher interface {
+	// Publish schedules a delivery of event to every active subscription for its type.
+	// It lets the dispatcher act as an EventPublisher fed by the EventRelay.
+	Publish(ctx context.Context, event *domain.Event) error
+	// DeliverBatch sends one batch of due deliveries and returns how many it processed.
+	DeliverBatch(ctx context.Context) (int, error)
+}
+
+// EventStreamService is the input port for following domain events live.
+type EventStreamService interface {
+	// Stream returns a channel of the events matching filter: first those enqueued after
+	// the event with ID lastEventID, when given, then live ones. The channel is closed
+	// when ctx is done or the stream is interrupted; callers resume with the ID of the
+	// last event received.
+	Stream(ctx context.Context, filter domain.EventFilter, lastEventID string) (<-chan *domain.Event, error)
+}
+
+// APIKeyService is the input port authenticating machine clients by API key.
+type APIKeyService interface {
+	// Authenticate returns the principal the key belongs to. It fails with
+	// domain.ErrUnauthenticated when the key is unknown, revoked or expired.
+	Authenticate(ctx context.Context, key string) (domain.Principal, error)
+}
+
+// IdempotencyService is the input port making retried requests safe: a request sent
+// again with the same idempotency key gets the response of the first one.
+type IdempotencyService interface {
+	// Begin claims the key of the principal in the context for the request with the
+	// given fingerprint. It returns nil when the caller is to process the request and
+	// then Complete or Release the key, or the stored response of the first request.
+	// It fails with domain.ErrIdempotencyKeyReused when the key was used for another
+	// request, and with domain.ErrConflict while the first request is in progress.
+	Begin(ctx context.Context, key, fingerprint string) (*domain.IdempotentResponse, error)
+	// Complete stores the response to the request, to be returned on retries.
+	Complete(ctx context.Context, key string, resp *domain.IdempotentResponse) error
+	// Release frees the key, so that a retry processes the request anew.
+	Release(ctx context.Context, key string) error
+	// PurgeExpired removes one batch of expired keys and returns how many it removed.
+	PurgeExpired(ctx context.Context) (int, error)
+}
@@ -0,0 +1,18 @@
+package ports
+
+//go:generate go tool mockgen -destination=mocks/mock_transactions.go -package=mocks . TxManager
+
+import (
+	"context"
+)
+
+// TxManager is the output port for running a unit of work spanning several repositories.
+type TxManager interface {
+	// WithinTx runs fn in a single serializable transaction. Repository calls made with
+	// the context passed to fn take part in that transaction, which is committed when fn
+	// returns nil and rolled back otherwise. Calls nested in fn reuse the outer transaction.
+	// fn may be run again after a serialization failure, so it must not have side effects
+	// outside the repositories, and must undo changes a rolled-back attempt left on the
+	// values it saves, such as the version bumped by Save.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,59 @@
+package ports
+
+//go:generate go tool mockgen -destination=mocks/mock_webhooks.go -package=mocks . WebhookSubscriptionRepository,WebhookDeliveryRepository,WebhookSender
+
+import (
+	"context"
+	"time"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+)
+
+// WebhookSubscriptionRepository is the output port for WebhookSubscription persistence.
+// Save follows the same optimistic locking rules as the entity repositories.
+type WebhookSubscriptionRepository interface {
+	Save(ctx context.Context, sub *domain.WebhookSubscription) error
+	FindByID(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+	// FindAll returns a page of subscriptions. It supports the url and active filters.
+	FindAll(ctx context.Context, q ListQuery) (*Page[*domain.WebhookSubscription], error)
+	// FindActiveByEventType returns all active subscriptions for events of type t.
+	FindActiveByEventType(ctx context.Context, t domain.EventType) ([]*domain.WebhookSubscription, error)
+	// Delete removes the subscription together with its delivery log.
+	Delete(ctx context.Context, id string) error
+	// RecordFailure counts a failed delivery attempt and deactivates the subscription,
+	// setting DisabledAt to at, once it failed limit times in a row. It reports whether
+	// the subscription was deactivated by this call.
+	RecordFailure(ctx context.Context, id string, limit int, at time.Time) (bool, error)
+	// ResetFailures clears the failure count after a successful delivery.
+	ResetFailures(ctx context.Context, id string) error
+}
+
+// WebhookDeliveryRepository is the output port for the webhook delivery log.
+type WebhookDeliveryRepository interface {
+	// Create stores a pending delivery. It does nothing when the subscription already
+	// has a delivery of the same event, so an event can safely be scheduled twice.
+	Create(ctx context.Context, d *domain.WebhookDelivery) error
+	FindByID(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+	// FindBySubscriptionID returns a page of the deliveries to a subscription, oldest
+	// first unless q says otherwise. It supports the status and event_type filters.
+	FindBySubscriptionID(ctx context.Context, subscriptionID string, q ListQuery) (*Page[*domain.WebhookDelivery], error)
+	// Claim leases up to limit pending deliveries to active subscriptions that are due at
+	// now, oldest first, until leaseUntil. Leased deliveries are not claimed again before
+	// the lease expires.
+	Claim(ctx context.Context, limit int, now, leaseUntil time.Time) ([]*domain.WebhookDelivery, error)
+	// MarkSucceeded records a successful attempt.
+	MarkSucceeded(ctx context.Context, id string, statusCode int, at time.Time) error
+	// MarkFailed records a failed attempt. The delivery stays pending until retryAt;
+	// a nil retryAt gives it up as failed.
+	MarkFailed(ctx context.Context, id string, statusCode int, cause string, retryAt *time.Time) error
+	// Reschedule makes a finished delivery pending again, due at the given time, with
+	// its attempt count reset.
+	Reschedule(ctx context.Context, id string, at time.Time) error
+}
+
+// WebhookSender is the output port sending signed webhook deliveries.
+type WebhookSender interface {
+	// Send POSTs event to url, signed with secret, and returns the HTTP status of the
+	// response, or 0 when none was received. A response other than 2xx is an error.
+	Send(ctx context.Context, url, secret string, event *domain.Event) (int, error)
+}
@@ -0,0 +1,58 @@
+package apikey
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+)
+
+var tracer = otel.Tracer("github.com/albenik/uber-fx-based-service-example/internal/core/services/apikey")
+
+type Clock func() time.Time
+
+// Service authenticates machine clients by their static API keys.
+type Service struct {
+	repo  ports.APIKeyRepository
+	clock Clock
+}
+
+func New(repo ports.APIKeyRepository, clock Clock) *Service {
+	return &Service{repo: repo, clock: clock}
+}
+
+// Authenticate looks the key up by its hash; unknown, revoked and expired keys are not
+// told apart, so a caller cannot probe which keys exist.
+func (s *Service) Authenticate(ctx context.Context, key string) (domain.Principal, error) {
+	ctx, span := tracer.Start(ctx, "APIKeyService.Authenticate")
+	defer span.End()
+
+	if key == "" {
+		return domain.Principal{}, fmt.Errorf("%w: empty API key", domain.ErrUnauthenticated)
+	}
+	k, err := s.repo.FindByHash(ctx, Hash(key))
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Principal{}, fmt.Errorf("%w: invalid API key", domain.ErrUnauthenticated)
+	}
+	if err != nil {
+		return domain.Principal{}, err
+	}
+	if !k.Usable(s.clock()) {
+		return domain.Principal{}, fmt.Errorf("%w: invalid API key", domain.ErrUnauthenticated)
+	}
+	return domain.Principal{ID: k.PrincipalID, Role: k.Role, LegalEntityIDs: k.LegalEntityIDs}, nil
+}
+
+// Hash returns the hex SHA-256 of key, the form in which keys are stored. Keys are
+// random and long, so a plain digest is enough; a slow password hash is not needed.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
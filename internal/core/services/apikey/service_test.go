@@ -0,0 +1,70 @@
+package apikey_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/apikey"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/servicetest"
+)
+
+const key = "s3cr3t-api-key"
+
+func TestService_Authenticate(t *testing.T) {
+	past, future := servicetest.Now.Add(-time.Hour), servicetest.Now.Add(time.Hour)
+	tests := []struct {
+		name    string
+		key     *domain.APIKey
+		findErr error
+		want    domain.Principal
+		wantErr error
+	}{
+		{"valid", &domain.APIKey{PrincipalID: "svc-payroll"}, nil, domain.Principal{ID: "svc-payroll"}, nil},
+		{
+			"scoped",
+			&domain.APIKey{PrincipalID: "svc-dispatch", Role: domain.RoleFleetManager, LegalEntityIDs: []string{"le1"}},
+			nil,
+			domain.Principal{ID: "svc-dispatch", Role: domain.RoleFleetManager, LegalEntityIDs: []string{"le1"}},
+			nil,
+		},
+		{"not yet expired", &domain.APIKey{PrincipalID: "svc-payroll", ExpiresAt: &future}, nil, domain.Principal{ID: "svc-payroll"}, nil},
+		{"unknown", nil, domain.ErrNotFound, domain.Principal{}, domain.ErrUnauthenticated},
+		{"expired", &domain.APIKey{PrincipalID: "svc-payroll", ExpiresAt: &past}, nil, domain.Principal{}, domain.ErrUnauthenticated},
+		{"revoked", &domain.APIKey{PrincipalID: "svc-payroll", RevokedAt: &past}, nil, domain.Principal{}, domain.ErrUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockAPIKeyRepository(gomock.NewController(t))
+			repo.EXPECT().FindByHash(gomock.Any(), apikey.Hash(key)).Return(tt.key, tt.findErr)
+
+			p, err := apikey.New(repo, servicetest.Clock).Authenticate(t.Context(), key)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+		})
+	}
+}
+
+func TestService_Authenticate_RepositoryError(t *testing.T) {
+	repo := mocks.NewMockAPIKeyRepository(gomock.NewController(t))
+	repo.EXPECT().FindByHash(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused"))
+
+	_, err := apikey.New(repo, servicetest.Clock).Authenticate(t.Context(), key)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, domain.ErrUnauthenticated)
+}
+
+func TestHash(t *testing.T) {
+	// Must match encode(sha256(key::bytea), 'hex') used to provision keys.
+	assert.Equal(t, "46592335983beec12e2d38c2caf2efc75ade7f9927171d32408229c021c94659", apikey.Hash(key))
+}
@@ -2,15 +2,21 @@ package assignment
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
 
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/logctx"
 )
 
+var tracer = otel.Tracer("github.com/albenik/uber-fx-based-service-example/internal/core/services/assignment")
+
 type IDGenerator func() string
 
 type Clock func() time.Time
@@ -19,16 +25,35 @@ type Service struct {
 	contractRepo ports.ContractRepository
 	vehicleRepo  ports.VehicleRepository
 	repo         ports.VehicleAssignmentRepository
+	tx           ports.TxManager
+	auditLog     ports.AuditLog
+	outbox       ports.Outbox
+	authz        ports.Authorizer
 	logger       *zap.Logger
 	idGen        IDGenerator
 	clock        Clock
 }
 
-func New(contractRepo ports.ContractRepository, vehicleRepo ports.VehicleRepository, repo ports.VehicleAssignmentRepository, logger *zap.Logger, idGen IDGenerator, clock Clock) *Service {
+func New(
+	contractRepo ports.ContractRepository,
+	vehicleRepo ports.VehicleRepository,
+	repo ports.VehicleAssignmentRepository,
+	tx ports.TxManager,
+	auditLog ports.AuditLog,
+	outbox ports.Outbox,
+	authz ports.Authorizer,
+	logger *zap.Logger,
+	idGen IDGenerator,
+	clock Clock,
+) *Service {
 	return &Service{
 		contractRepo: contractRepo,
 		vehicleRepo:  vehicleRepo,
 		repo:         repo,
+		tx:           tx,
+		auditLog:     auditLog,
+		outbox:       outbox,
+		authz:        authz,
 		logger:       logger,
 		idGen:        idGen,
 		clock:        clock,
@@ -36,101 +61,353 @@ func New(contractRepo ports.ContractRepository, vehicleRepo ports.VehicleReposit
 }
 
 func (s *Service) Assign(ctx context.Context, contractID, vehicleID string) (*domain.VehicleAssignment, error) {
+	ctx, span := tracer.Start(ctx, "VehicleAssignmentService.Assign")
+	defer span.End()
+
 	if contractID == "" || vehicleID == "" {
 		return nil, fmt.Errorf("%w: contract_id and vehicle_id are required", domain.ErrInvalidInput)
 	}
-	contract, err := s.contractRepo.FindByID(ctx, contractID)
-	if err != nil {
-		return nil, err
-	}
-	vehicle, err := s.vehicleRepo.FindByID(ctx, vehicleID)
+	var result domain.VehicleAssignment
+	// The active assignment check and the insert must run in one transaction,
+	// otherwise concurrent requests could both assign the driver in the same fleet.
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		contract, err := s.contractRepo.FindByID(ctx, contractID)
+		if err != nil {
+			return err
+		}
+		if err := s.authorize(ctx, domain.ActionCreate, contract); err != nil {
+			return err
+		}
+		vehicle, err := s.vehicleRepo.FindByID(ctx, vehicleID)
+		if err != nil {
+			return err
+		}
+		if vehicle.FleetID != contract.FleetID {
+			return fmt.Errorf("%w: vehicle must belong to the contract's fleet", domain.ErrInvalidInput)
+		}
+		now := s.clock()
+		// contract is active through the entire EndDate day (inclusive)
+		if now.Before(contract.StartDate) || !now.Before(contract.EndDate.AddDate(0, 0, 1)) {
+			return domain.ErrContractNotActive
+		}
+		if contract.TerminatedAt != nil && now.After(*contract.TerminatedAt) {
+			return domain.ErrContractNotActive
+		}
+		existing, err := s.repo.FindActiveByDriverIDAndFleetID(ctx, contract.DriverID, contract.FleetID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return domain.ErrDriverAlreadyAssignedInFleet
+		}
+		id := s.idGen()
+		if id == "" {
+			return fmt.Errorf("id generator returned empty ID")
+		}
+		actor := domain.PrincipalFromContext(ctx).ID
+		entity := &domain.VehicleAssignment{
+			ID:         id,
+			DriverID:   contract.DriverID,
+			VehicleID:  vehicleID,
+			ContractID: contractID,
+			StartTime:  now,
+			CreatedAt:  now,
+			CreatedBy:  actor,
+			UpdatedAt:  now,
+			UpdatedBy:  actor,
+		}
+		if err := s.repo.Save(ctx, entity); err != nil {
+			logctx.From(ctx, s.logger).Error("Failed to save vehicle assignment", zap.String("id", id), zap.Error(err))
+			return err
+		}
+		result = *entity
+		if err := s.record(ctx, domain.AuditAssign, id, nil, result); err != nil {
+			return err
+		}
+		return s.emit(ctx, domain.EventAssignmentCreated, domain.NewAssignmentEvent(&result, contract))
+	})
 	if err != nil {
 		return nil, err
 	}
-	if vehicle.FleetID != contract.FleetID {
-		return nil, fmt.Errorf("%w: vehicle must belong to the contract's fleet", domain.ErrInvalidInput)
-	}
-	now := s.clock()
-	// contract is active through the entire EndDate day (inclusive)
-	if now.Before(contract.StartDate) || !now.Before(contract.EndDate.AddDate(0, 0, 1)) {
-		return nil, domain.ErrContractNotActive
-	}
-	if contract.TerminatedAt != nil && now.After(*contract.TerminatedAt) {
-		return nil, domain.ErrContractNotActive
-	}
-	existing, err := s.repo.FindActiveByDriverIDAndFleetID(ctx, contract.DriverID, contract.FleetID)
-	if err != nil {
-		return nil, err
-	}
-	if existing != nil {
-		return nil, domain.ErrDriverAlreadyAssignedInFleet
-	}
-	id := s.idGen()
-	if id == "" {
-		return nil, fmt.Errorf("id generator returned empty ID")
-	}
-	entity := &domain.VehicleAssignment{
-		ID:         id,
-		DriverID:   contract.DriverID,
-		VehicleID:  vehicleID,
-		ContractID: contractID,
-		StartTime:  now,
-	}
-	if err := s.repo.Save(ctx, entity); err != nil {
-		s.logger.Error("Failed to save vehicle assignment", zap.String("id", id), zap.Error(err))
-		return nil, err
-	}
-	s.logger.Info("Created vehicle assignment", zap.String("id", id))
-	result := *entity
+	logctx.From(ctx, s.logger).Info("Created vehicle assignment", zap.String("id", result.ID))
 	return &result, nil
 }
 
 func (s *Service) Get(ctx context.Context, id string) (*domain.VehicleAssignment, error) {
+	ctx, span := tracer.Start(ctx, "VehicleAssignmentService.Get")
+	defer span.End()
+
 	if id == "" {
 		return nil, fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
 	}
-	return s.repo.FindByID(ctx, id)
+	entity, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeContract(ctx, domain.ActionRead, entity.ContractID); err != nil {
+		return nil, err
+	}
+	return entity, nil
 }
 
-func (s *Service) ListByContract(ctx context.Context, contractID string) ([]*domain.VehicleAssignment, error) {
+func (s *Service) ListByContract(
+	ctx context.Context,
+	contractID string,
+	q ports.ListQuery,
+) (*ports.Page[*domain.VehicleAssignment], error) {
+	ctx, span := tracer.Start(ctx, "VehicleAssignmentService.ListByContract")
+	defer span.End()
+
 	if contractID == "" {
 		return nil, fmt.Errorf("%w: contract_id is required", domain.ErrInvalidInput)
 	}
-	return s.repo.FindByContractID(ctx, contractID)
+	if err := s.authorizeContract(ctx, domain.ActionRead, contractID); err != nil {
+		return nil, err
+	}
+	q, err := q.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindByContractID(ctx, contractID, q)
 }
 
 func (s *Service) Return(ctx context.Context, id string) (*domain.VehicleAssignment, error) {
+	ctx, span := tracer.Start(ctx, "VehicleAssignmentService.Return")
+	defer span.End()
+
 	if id == "" {
 		return nil, fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
 	}
-	entity, err := s.repo.FindByID(ctx, id)
+	var result domain.VehicleAssignment
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		entity, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		contract, err := s.findContract(ctx, entity.ContractID)
+		if err != nil {
+			return err
+		}
+		if err := s.authorize(ctx, domain.ActionUpdate, contract); err != nil {
+			return err
+		}
+		if entity.EndTime != nil {
+			return fmt.Errorf("%w: vehicle already returned", domain.ErrConflict)
+		}
+		before := *entity
+		now := s.clock()
+		entity.EndTime = &now
+		entity.UpdatedAt, entity.UpdatedBy = now, domain.PrincipalFromContext(ctx).ID
+		if err := s.repo.Save(ctx, entity); err != nil {
+			logctx.From(ctx, s.logger).Error("Failed to save returned assignment", zap.String("id", id), zap.Error(err))
+			return err
+		}
+		result = *entity
+		if err := s.record(ctx, domain.AuditReturn, id, before, result); err != nil {
+			return err
+		}
+		return s.emit(ctx, domain.EventAssignmentReturned, domain.NewAssignmentEvent(&result, contract))
+	})
 	if err != nil {
 		return nil, err
 	}
-	if entity.EndTime != nil {
-		return nil, fmt.Errorf("%w: vehicle already returned", domain.ErrConflict)
+	logctx.From(ctx, s.logger).Info("Returned vehicle assignment", zap.String("id", id))
+	return &result, nil
+}
+
+func (s *Service) Update(
+	ctx context.Context,
+	id string,
+	version int64,
+	patch domain.VehicleAssignmentPatch,
+) (*domain.VehicleAssignment, error) {
+	ctx, span := tracer.Start(ctx, "VehicleAssignmentService.Update")
+	defer span.End()
+
+	if id == "" {
+		return nil, fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
 	}
-	now := s.clock()
-	entity.EndTime = &now
-	if err := s.repo.Save(ctx, entity); err != nil {
-		s.logger.Error("Failed to save returned assignment", zap.String("id", id), zap.Error(err))
+	if patch.VehicleID == nil {
+		return nil, fmt.Errorf("%w: nothing to update", domain.ErrInvalidInput)
+	}
+	if *patch.VehicleID == "" {
+		return nil, fmt.Errorf("%w: vehicle_id is required", domain.ErrInvalidInput)
+	}
+	var result domain.VehicleAssignment
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		entity, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		contract, err := s.contractRepo.FindByID(ctx, entity.ContractID)
+		if err != nil {
+			return err
+		}
+		if err := s.authorize(ctx, domain.ActionUpdate, contract); err != nil {
+			return err
+		}
+		if version != 0 && entity.Version != version {
+			return domain.ErrPreconditionFailed
+		}
+		if entity.EndTime != nil {
+			return fmt.Errorf("%w: returned assignment cannot be modified", domain.ErrConflict)
+		}
+		vehicle, err := s.vehicleRepo.FindByID(ctx, *patch.VehicleID)
+		if err != nil {
+			return err
+		}
+		if vehicle.FleetID != contract.FleetID {
+			return fmt.Errorf("%w: vehicle must belong to the contract's fleet", domain.ErrInvalidInput)
+		}
+		before := *entity
+		entity.VehicleID = vehicle.ID
+		entity.UpdatedAt, entity.UpdatedBy = s.clock(), domain.PrincipalFromContext(ctx).ID
+		if err := s.repo.Save(ctx, entity); err != nil {
+			logctx.From(ctx, s.logger).Error("Failed to save vehicle assignment", zap.String("id", id), zap.Error(err))
+			return err
+		}
+		result = *entity
+		return s.record(ctx, domain.AuditReassign, id, before, result)
+	})
+	if err != nil {
 		return nil, err
 	}
-	s.logger.Info("Returned vehicle assignment", zap.String("id", id))
-	result := *entity
+	logctx.From(ctx, s.logger).Info("Updated vehicle assignment", zap.String("id", id))
 	return &result, nil
 }
 
-func (s *Service) Delete(ctx context.Context, id string) error {
+func (s *Service) Delete(ctx context.Context, id string, opts ports.DeleteOptions) error {
+	ctx, span := tracer.Start(ctx, "VehicleAssignmentService.Delete")
+	defer span.End()
+
 	if id == "" {
 		return fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
 	}
-	return s.repo.SoftDelete(ctx, id)
+	d := ports.Deletion{
+		ID:     s.idGen(),
+		At:     s.clock(),
+		By:     domain.PrincipalFromContext(ctx).ID,
+		Reason: strings.TrimSpace(opts.Reason),
+	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.authorizeContract(ctx, domain.ActionDelete, before.ContractID); err != nil {
+			return err
+		}
+		if err := s.repo.SoftDelete(ctx, id, d); err != nil {
+			return err
+		}
+		return s.appendAudit(ctx, &domain.AuditEntry{
+			EntityType: domain.EntityVehicleAssignment,
+			EntityID:   id,
+			Action:     domain.AuditDelete,
+			DeletionID: d.ID,
+			Before:     *before,
+		})
+	})
 }
 
 func (s *Service) Undelete(ctx context.Context, id string) error {
+	ctx, span := tracer.Start(ctx, "VehicleAssignmentService.Undelete")
+	defer span.End()
+
 	if id == "" {
 		return fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
 	}
-	return s.repo.Undelete(ctx, id)
+	at, by := s.clock(), domain.PrincipalFromContext(ctx).ID
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		entity, err := s.repo.FindDeletedByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		contract, err := s.contractRepo.FindByID(ctx, entity.ContractID)
+		if err != nil {
+			return domain.ParentError(err, "contract", entity.ContractID)
+		}
+		if err := s.authorize(ctx, domain.ActionDelete, contract); err != nil {
+			return err
+		}
+		if _, err := s.vehicleRepo.FindByID(ctx, entity.VehicleID); err != nil {
+			return domain.ParentError(err, "vehicle", entity.VehicleID)
+		}
+		if err := s.repo.Undelete(ctx, id, at, by); err != nil {
+			return err
+		}
+		after, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.appendAudit(ctx, &domain.AuditEntry{
+			EntityType: domain.EntityVehicleAssignment,
+			EntityID:   id,
+			Action:     domain.AuditUndelete,
+			DeletionID: entity.DeletionID,
+			After:      *after,
+		})
+	})
+}
+
+// findContract returns the contract of an assignment. Assignments stay reachable after
+// their contract was deleted, e.g. to return the vehicle.
+func (s *Service) findContract(ctx context.Context, id string) (*domain.Contract, error) {
+	contract, err := s.contractRepo.FindByID(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		contract, err = s.contractRepo.FindDeletedByID(ctx, id)
+	}
+	return contract, err
+}
+
+// authorize checks the principal may act on the vehicle assignments of the contract.
+func (s *Service) authorize(ctx context.Context, action domain.Action, contract *domain.Contract) error {
+	return s.authz.Authorize(ctx, action, domain.Resource{
+		Type:          domain.EntityVehicleAssignment,
+		LegalEntityID: contract.LegalEntityID,
+		DriverID:      contract.DriverID,
+	})
+}
+
+// authorizeContract checks the principal may act on the vehicle assignments of the
+// contract with the given ID.
+func (s *Service) authorizeContract(ctx context.Context, action domain.Action, contractID string) error {
+	contract, err := s.findContract(ctx, contractID)
+	if err != nil {
+		return err
+	}
+	return s.authorize(ctx, action, contract)
+}
+
+// record appends an audit entry for a change of the vehicle assignment with the given ID.
+func (s *Service) record(ctx context.Context, action domain.AuditAction, id string, before, after any) error {
+	return s.appendAudit(ctx, &domain.AuditEntry{
+		EntityType: domain.EntityVehicleAssignment,
+		EntityID:   id,
+		Action:     action,
+		Before:     before,
+		After:      after,
+	})
+}
+
+// appendAudit sets the ID, actor and time of the entries and appends them to the audit log.
+func (s *Service) appendAudit(ctx context.Context, entries ...*domain.AuditEntry) error {
+	actor, at := domain.PrincipalFromContext(ctx).ID, s.clock()
+	for _, e := range entries {
+		e.ID, e.Actor, e.At = s.idGen(), actor, at
+		if err := s.auditLog.Append(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// emit enqueues an event of the given type to be published once the transaction commits.
+func (s *Service) emit(ctx context.Context, typ domain.EventType, payload any) error {
+	return s.outbox.Enqueue(ctx, &domain.Event{
+		ID:         s.idGen(),
+		Type:       typ,
+		OccurredAt: s.clock(),
+		Payload:    payload,
+	})
 }
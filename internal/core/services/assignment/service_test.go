@@ -1,6 +1,8 @@
 package assignment_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -12,10 +14,9 @@ import (
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/services/assignment"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/servicetest"
 )
 
-func stubIDGen() string { return "test-id" }
-
 func TestService_Assign_RejectsWhenContractInactive(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	contractRepo := mocks.NewMockContractRepository(ctrl)
@@ -32,7 +33,9 @@ func TestService_Assign_RejectsWhenContractInactive(t *testing.T) {
 	contractRepo.EXPECT().FindByID(gomock.Any(), "c1").Return(contract, nil)
 	vehicleRepo.EXPECT().FindByID(gomock.Any(), "v1").Return(&domain.Vehicle{ID: "v1", FleetID: "f1"}, nil)
 
-	svc := assignment.New(contractRepo, vehicleRepo, assignmentRepo, zaptest.NewLogger(t), stubIDGen, time.Now)
+	svc := assignment.New(contractRepo, vehicleRepo, assignmentRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), servicetest.NopOutbox(ctrl), servicetest.AllowAll(ctrl), zaptest.NewLogger(t),
+		servicetest.IDGen, time.Now)
 	_, err := svc.Assign(t.Context(), "c1", "v1")
 	assert.ErrorIs(t, err, domain.ErrContractNotActive)
 }
@@ -52,7 +55,9 @@ func TestService_Assign_RejectsWhenAlreadyAssigned(t *testing.T) {
 	vehicleRepo.EXPECT().FindByID(gomock.Any(), "v1").Return(&domain.Vehicle{ID: "v1", FleetID: "f1"}, nil)
 	assignmentRepo.EXPECT().FindActiveByDriverIDAndFleetID(gomock.Any(), "d1", "f1").Return(&domain.VehicleAssignment{ID: "a1"}, nil)
 
-	svc := assignment.New(contractRepo, vehicleRepo, assignmentRepo, zaptest.NewLogger(t), stubIDGen, time.Now)
+	svc := assignment.New(contractRepo, vehicleRepo, assignmentRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), servicetest.NopOutbox(ctrl), servicetest.AllowAll(ctrl), zaptest.NewLogger(t),
+		servicetest.IDGen, time.Now)
 	_, err := svc.Assign(t.Context(), "c1", "v1")
 	assert.ErrorIs(t, err, domain.ErrDriverAlreadyAssignedInFleet)
 }
@@ -73,9 +78,117 @@ func TestService_Assign_Success(t *testing.T) {
 	assignmentRepo.EXPECT().FindActiveByDriverIDAndFleetID(gomock.Any(), "d1", "f1").Return(nil, nil)
 	assignmentRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
 
-	svc := assignment.New(contractRepo, vehicleRepo, assignmentRepo, zaptest.NewLogger(t), stubIDGen, time.Now)
+	svc := assignment.New(contractRepo, vehicleRepo, assignmentRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), servicetest.NopOutbox(ctrl), servicetest.AllowAll(ctrl), zaptest.NewLogger(t),
+		servicetest.IDGen, time.Now)
 	entity, err := svc.Assign(t.Context(), "c1", "v1")
 	require.NoError(t, err)
 	assert.Equal(t, "test-id", entity.ID)
 	assert.Equal(t, "v1", entity.VehicleID)
 }
+
+func TestService_Assign_ReturnsTransactionError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	contractRepo := mocks.NewMockContractRepository(ctrl)
+	vehicleRepo := mocks.NewMockVehicleRepository(ctrl)
+	assignmentRepo := mocks.NewMockVehicleAssignmentRepository(ctrl)
+	tx := mocks.NewMockTxManager(ctrl)
+
+	tx.EXPECT().WithinTx(gomock.Any(), gomock.Any()).Return(domain.ErrConflict)
+
+	svc := assignment.New(contractRepo, vehicleRepo, assignmentRepo, tx, servicetest.NopAuditLog(ctrl),
+		servicetest.NopOutbox(ctrl), servicetest.AllowAll(ctrl), zaptest.NewLogger(t), servicetest.IDGen, time.Now)
+	_, err := svc.Assign(t.Context(), "c1", "v1")
+	assert.ErrorIs(t, err, domain.ErrConflict)
+}
+
+func TestService_Update_RecordsReassignment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	contractRepo := mocks.NewMockContractRepository(ctrl)
+	vehicleRepo := mocks.NewMockVehicleRepository(ctrl)
+	assignmentRepo := mocks.NewMockVehicleAssignmentRepository(ctrl)
+	auditLog := mocks.NewMockAuditLog(ctrl)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	assignmentRepo.EXPECT().FindByID(gomock.Any(), "a1").
+		Return(&domain.VehicleAssignment{ID: "a1", ContractID: "c1", VehicleID: "v1", Version: 1}, nil)
+	contractRepo.EXPECT().FindByID(gomock.Any(), "c1").Return(&domain.Contract{ID: "c1", FleetID: "f1"}, nil)
+	vehicleRepo.EXPECT().FindByID(gomock.Any(), "v2").Return(&domain.Vehicle{ID: "v2", FleetID: "f1"}, nil)
+	assignmentRepo.EXPECT().Save(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, e *domain.VehicleAssignment) error {
+			e.Version++
+			return nil
+		})
+	auditLog.EXPECT().Append(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, e *domain.AuditEntry) error {
+			assert.Equal(t, domain.EntityVehicleAssignment, e.EntityType)
+			assert.Equal(t, "a1", e.EntityID)
+			assert.Equal(t, domain.AuditReassign, e.Action)
+			assert.Equal(t, "alice", e.Actor)
+			assert.Equal(t, now, e.At)
+			require.IsType(t, domain.VehicleAssignment{}, e.Before)
+			require.IsType(t, domain.VehicleAssignment{}, e.After)
+			assert.Equal(t, "v1", e.Before.(domain.VehicleAssignment).VehicleID)
+			assert.Equal(t, "v2", e.After.(domain.VehicleAssignment).VehicleID)
+			assert.Equal(t, int64(2), e.After.(domain.VehicleAssignment).Version)
+			return nil
+		})
+
+	clock := func() time.Time { return now }
+	svc := assignment.New(contractRepo, vehicleRepo, assignmentRepo, servicetest.PassthroughTx(ctrl), auditLog,
+		servicetest.NopOutbox(ctrl), servicetest.AllowAll(ctrl), zaptest.NewLogger(t), servicetest.IDGen, clock)
+	ctx := domain.ContextWithPrincipal(t.Context(), domain.Principal{ID: "alice"})
+	vehicleID := "v2"
+	_, err := svc.Update(ctx, "a1", 1, domain.VehicleAssignmentPatch{VehicleID: &vehicleID})
+	require.NoError(t, err)
+}
+
+func TestService_Return_FailsWhenAuditLogFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	contractRepo := mocks.NewMockContractRepository(ctrl)
+	vehicleRepo := mocks.NewMockVehicleRepository(ctrl)
+	assignmentRepo := mocks.NewMockVehicleAssignmentRepository(ctrl)
+	auditLog := mocks.NewMockAuditLog(ctrl)
+
+	assignmentRepo.EXPECT().FindByID(gomock.Any(), "a1").Return(&domain.VehicleAssignment{ID: "a1", ContractID: "c1"}, nil)
+	contractRepo.EXPECT().FindByID(gomock.Any(), "c1").Return(&domain.Contract{ID: "c1"}, nil)
+	assignmentRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+	auditLog.EXPECT().Append(gomock.Any(), gomock.Any()).Return(errors.New("audit log unavailable"))
+
+	svc := assignment.New(contractRepo, vehicleRepo, assignmentRepo, servicetest.PassthroughTx(ctrl), auditLog,
+		servicetest.NopOutbox(ctrl), servicetest.AllowAll(ctrl), zaptest.NewLogger(t), servicetest.IDGen, time.Now)
+	_, err := svc.Return(t.Context(), "a1")
+	assert.Error(t, err)
+}
+
+func TestService_Return_EmitsEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	contractRepo := mocks.NewMockContractRepository(ctrl)
+	vehicleRepo := mocks.NewMockVehicleRepository(ctrl)
+	assignmentRepo := mocks.NewMockVehicleAssignmentRepository(ctrl)
+	outbox := mocks.NewMockOutbox(ctrl)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	assignmentRepo.EXPECT().FindByID(gomock.Any(), "a1").
+		Return(&domain.VehicleAssignment{ID: "a1", ContractID: "c1", DriverID: "d1", VehicleID: "v1"}, nil)
+	assignmentRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+	contractRepo.EXPECT().FindByID(gomock.Any(), "c1").Return(nil, domain.ErrNotFound)
+	contractRepo.EXPECT().FindDeletedByID(gomock.Any(), "c1").
+		Return(&domain.Contract{ID: "c1", LegalEntityID: "le1", FleetID: "f1"}, nil)
+	outbox.EXPECT().Enqueue(gomock.Any(), &domain.Event{
+		ID:         "test-id",
+		Type:       domain.EventAssignmentReturned,
+		OccurredAt: now,
+		Payload: domain.AssignmentEvent{
+			AssignmentID: "a1", ContractID: "c1", DriverID: "d1", VehicleID: "v1",
+			LegalEntityID: "le1", FleetID: "f1", EndTime: &now,
+		},
+	}).Return(nil)
+
+	clock := func() time.Time { return now }
+	svc := assignment.New(contractRepo, vehicleRepo, assignmentRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), outbox, servicetest.AllowAll(ctrl), zaptest.NewLogger(t), servicetest.IDGen,
+		clock)
+	_, err := svc.Return(t.Context(), "a1")
+	require.NoError(t, err)
+}
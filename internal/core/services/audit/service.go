@@ -0,0 +1,35 @@
+package audit
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+)
+
+var tracer = otel.Tracer("github.com/albenik/uber-fx-based-service-example/internal/core/services/audit")
+
+type Service struct {
+	log   ports.AuditLog
+	authz ports.Authorizer
+}
+
+func New(log ports.AuditLog, authz ports.Authorizer) *Service {
+	return &Service{log: log, authz: authz}
+}
+
+func (s *Service) List(ctx context.Context, q ports.ListQuery) (*ports.Page[*domain.AuditEntry], error) {
+	ctx, span := tracer.Start(ctx, "AuditService.List")
+	defer span.End()
+
+	if err := s.authz.Authorize(ctx, domain.ActionRead, domain.Resource{Type: domain.ResourceAuditLog}); err != nil {
+		return nil, err
+	}
+	q, err := q.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	return s.log.FindAll(ctx, q)
+}
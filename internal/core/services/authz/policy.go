@@ -0,0 +1,63 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+)
+
+// Policy implements ports.Authorizer with fixed role permissions:
+//
+//   - admins may do anything;
+//   - fleet managers may read the legal entities they manage, read and change their
+//     fleets, vehicles, contracts and vehicle assignments, and follow their events.
+//     Drivers are not owned by a legal entity: managers may read and create them, but
+//     not change them. Reads are deliberately not scoped, because a manager must find
+//     a driver, who may already work for other legal entities, before any contract
+//     with the managed ones exists; a driver's contracts stay scoped;
+//   - drivers may read their own contracts.
+//
+// Everything else, including any action of a principal without a known role, is denied.
+type Policy struct{}
+
+func New() *Policy {
+	return &Policy{}
+}
+
+func (p *Policy) Authorize(ctx context.Context, action domain.Action, resource domain.Resource) error {
+	principal := domain.PrincipalFromContext(ctx)
+	if allowed(principal, action, resource) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s may not %s %s", domain.ErrForbidden, roleName(principal.Role), action, resource.Type)
+}
+
+func allowed(p domain.Principal, action domain.Action, r domain.Resource) bool {
+	switch p.Role {
+	case domain.RoleAdmin:
+		return true
+	case domain.RoleFleetManager:
+		switch r.Type {
+		case domain.EntityLegalEntity:
+			return action == domain.ActionRead && p.Manages(r.LegalEntityID)
+		case domain.EntityFleet, domain.EntityVehicle, domain.EntityContract, domain.EntityVehicleAssignment:
+			return p.Manages(r.LegalEntityID)
+		case domain.ResourceEvents:
+			return action == domain.ActionRead && p.Manages(r.LegalEntityID)
+		case domain.EntityDriver:
+			return action == domain.ActionRead || action == domain.ActionCreate
+		}
+	case domain.RoleDriver:
+		return r.Type == domain.EntityContract && action == domain.ActionRead &&
+			p.DriverID != "" && r.DriverID == p.DriverID
+	}
+	return false
+}
+
+func roleName(r domain.Role) string {
+	if r == "" {
+		return "caller without role"
+	}
+	return string(r)
+}
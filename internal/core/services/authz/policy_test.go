@@ -0,0 +1,98 @@
+package authz_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/authz"
+)
+
+var (
+	admin   = domain.Principal{ID: "root", Role: domain.RoleAdmin}
+	manager = domain.Principal{ID: "mia", Role: domain.RoleFleetManager, LegalEntityIDs: []string{"le1", "le2"}}
+	driver  = domain.Principal{ID: "dan", Role: domain.RoleDriver, DriverID: "d1"}
+	noRole  = domain.Principal{ID: "nobody"}
+)
+
+func TestPolicy_Authorize(t *testing.T) {
+	own := func(typ domain.EntityType) domain.Resource { return domain.Resource{Type: typ, LegalEntityID: "le1"} }
+	other := func(typ domain.EntityType) domain.Resource { return domain.Resource{Type: typ, LegalEntityID: "le3"} }
+	all := []domain.Action{domain.ActionRead, domain.ActionCreate, domain.ActionUpdate, domain.ActionDelete}
+	writes := []domain.Action{domain.ActionCreate, domain.ActionUpdate, domain.ActionDelete}
+
+	tests := []struct {
+		name      string
+		principal domain.Principal
+		actions   []domain.Action
+		resource  domain.Resource
+		allowed   bool
+	}{
+		{"admin manages legal entities", admin, all, domain.Resource{Type: domain.EntityLegalEntity}, true},
+		{"admin manages webhooks", admin, all, domain.Resource{Type: domain.EntityWebhook}, true},
+		{"admin reads audit log", admin, all[:1], domain.Resource{Type: domain.ResourceAuditLog}, true},
+		{"admin follows all events", admin, all[:1], domain.Resource{Type: domain.ResourceEvents}, true},
+
+		{"manager reads own legal entity", manager, all[:1], own(domain.EntityLegalEntity), true},
+		{"manager changes own legal entity", manager, writes, own(domain.EntityLegalEntity), false},
+		{"manager reads other legal entity", manager, all[:1], other(domain.EntityLegalEntity), false},
+		{"manager lists legal entities", manager, all[:1], domain.Resource{Type: domain.EntityLegalEntity}, false},
+		{"manager manages own fleets", manager, all, own(domain.EntityFleet), true},
+		{"manager manages other fleets", manager, all, other(domain.EntityFleet), false},
+		{"manager manages own vehicles", manager, all, own(domain.EntityVehicle), true},
+		{"manager manages other vehicles", manager, all, other(domain.EntityVehicle), false},
+		{"manager manages own contracts", manager, all, own(domain.EntityContract), true},
+		{"manager manages other contracts", manager, all, other(domain.EntityContract), false},
+		{"manager lists contracts of any legal entity", manager, all[:1],
+			domain.Resource{Type: domain.EntityContract, DriverID: "d1"}, false},
+		{"manager manages own assignments", manager, all, own(domain.EntityVehicleAssignment), true},
+		{"manager manages other assignments", manager, all, other(domain.EntityVehicleAssignment), false},
+		{"manager reads and creates drivers", manager, all[:2], domain.Resource{Type: domain.EntityDriver, DriverID: "d1"}, true},
+		{"manager lists drivers of all legal entities", manager, all[:1], domain.Resource{Type: domain.EntityDriver}, true},
+		{"manager reads driver contracted elsewhere", manager, all[:1], other(domain.EntityDriver), true},
+		{"manager reads contracts of driver contracted elsewhere", manager, all[:1],
+			domain.Resource{Type: domain.EntityContract, LegalEntityID: "le3", DriverID: "d1"}, false},
+		{"manager changes drivers", manager, writes[1:], domain.Resource{Type: domain.EntityDriver, DriverID: "d1"}, false},
+		{"manager follows own events", manager, all[:1], own(domain.ResourceEvents), true},
+		{"manager follows all events", manager, all[:1], domain.Resource{Type: domain.ResourceEvents}, false},
+		{"manager reads audit log", manager, all[:1], domain.Resource{Type: domain.ResourceAuditLog}, false},
+		{"manager manages webhooks", manager, all, domain.Resource{Type: domain.EntityWebhook}, false},
+
+		{"driver reads own contracts", driver, all[:1],
+			domain.Resource{Type: domain.EntityContract, LegalEntityID: "le1", DriverID: "d1"}, true},
+		{"driver changes own contracts", driver, writes,
+			domain.Resource{Type: domain.EntityContract, LegalEntityID: "le1", DriverID: "d1"}, false},
+		{"driver reads other contracts", driver, all[:1],
+			domain.Resource{Type: domain.EntityContract, LegalEntityID: "le1", DriverID: "d2"}, false},
+		{"driver reads own assignments", driver, all[:1],
+			domain.Resource{Type: domain.EntityVehicleAssignment, LegalEntityID: "le1", DriverID: "d1"}, false},
+		{"driver reads own driver record", driver, all[:1], domain.Resource{Type: domain.EntityDriver, DriverID: "d1"}, false},
+		{"driver reads fleets", driver, all[:1], own(domain.EntityFleet), false},
+
+		{"no role reads fleets", noRole, all[:1], own(domain.EntityFleet), false},
+		{"no role reads contracts", noRole, all[:1], domain.Resource{Type: domain.EntityContract}, false},
+		{"driver role without driver", domain.Principal{Role: domain.RoleDriver}, all[:1],
+			domain.Resource{Type: domain.EntityContract}, false},
+		{"unknown role", domain.Principal{Role: "superuser"}, all, own(domain.EntityFleet), false},
+	}
+	policy := authz.New()
+	for _, tt := range tests {
+		for _, action := range tt.actions {
+			t.Run(tt.name+"/"+string(action), func(t *testing.T) {
+				ctx := domain.ContextWithPrincipal(t.Context(), tt.principal)
+				err := policy.Authorize(ctx, action, tt.resource)
+				if tt.allowed {
+					assert.NoError(t, err)
+				} else {
+					assert.ErrorIs(t, err, domain.ErrForbidden)
+				}
+			})
+		}
+	}
+}
+
+func TestPolicy_Authorize_Anonymous(t *testing.T) {
+	err := authz.New().Authorize(t.Context(), domain.ActionRead, domain.Resource{Type: domain.EntityDriver})
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
@@ -3,14 +3,19 @@ package contract
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
 
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/logctx"
 )
 
+var tracer = otel.Tracer("github.com/albenik/uber-fx-based-service-example/internal/core/services/contract")
+
 type IDGenerator func() string
 
 type Clock func() time.Time
@@ -20,6 +25,10 @@ type Service struct {
 	legalRepo  ports.LegalEntityRepository
 	fleetRepo  ports.FleetRepository
 	repo       ports.ContractRepository
+	tx         ports.TxManager
+	auditLog   ports.AuditLog
+	outbox     ports.Outbox
+	authz      ports.Authorizer
 
 	idGen IDGenerator
 	clock Clock
@@ -32,6 +41,10 @@ func New(
 	legalRepo ports.LegalEntityRepository,
 	fleetRepo ports.FleetRepository,
 	repo ports.ContractRepository,
+	tx ports.TxManager,
+	auditLog ports.AuditLog,
+	outbox ports.Outbox,
+	authz ports.Authorizer,
 	idGen IDGenerator,
 	clock Clock,
 	logger *zap.Logger,
@@ -41,6 +54,10 @@ func New(
 		legalRepo:  legalRepo,
 		fleetRepo:  fleetRepo,
 		repo:       repo,
+		tx:         tx,
+		auditLog:   auditLog,
+		outbox:     outbox,
+		authz:      authz,
 
 		idGen: idGen,
 		clock: clock,
@@ -54,99 +71,337 @@ func (s *Service) Create(
 	driverID, legalEntityID, fleetID string,
 	startDate, endDate time.Time,
 ) (*domain.Contract, error) {
-	if driverID == "" || legalEntityID == "" || fleetID == "" {
-		return nil, fmt.Errorf("%w: driver_id, legal_entity_id, and fleet_id are required", domain.ErrInvalidInput)
-	}
+	ctx, span := tracer.Start(ctx, "ContractService.Create")
+	defer span.End()
+
+	var invalid domain.ValidationError
+	domain.ValidateContractParties(&invalid, driverID, legalEntityID, fleetID)
 	if !endDate.After(startDate) {
-		return nil, fmt.Errorf("%w: end_date must be after start_date", domain.ErrInvalidInput)
-	}
-	if _, err := s.driverRepo.FindByID(ctx, driverID); err != nil {
-		return nil, err
-	}
-	if _, err := s.legalRepo.FindByID(ctx, legalEntityID); err != nil {
-		return nil, err
+		invalid.Add("end_date", domain.RuleAfter, "must be after start_date")
 	}
-	if _, err := s.fleetRepo.FindByID(ctx, fleetID); err != nil {
+	if err := invalid.Err(); err != nil {
 		return nil, err
 	}
-	overlapping, err := s.repo.FindOverlapping(ctx, driverID, legalEntityID, fleetID, startDate, endDate, "")
+	err := s.authorize(ctx, domain.ActionCreate, &domain.Contract{DriverID: driverID, LegalEntityID: legalEntityID})
 	if err != nil {
 		return nil, err
 	}
-	if len(overlapping) > 0 {
-		return nil, fmt.Errorf("%w: contract dates overlap with existing contract", domain.ErrConflict)
-	}
-	id := s.idGen()
-	if id == "" {
-		return nil, fmt.Errorf("id generator returned empty ID")
-	}
-	entity := &domain.Contract{
-		ID:            id,
-		DriverID:      driverID,
-		LegalEntityID: legalEntityID,
-		FleetID:       fleetID,
-		StartDate:     startDate,
-		EndDate:       endDate,
-	}
-	if err := s.repo.Save(ctx, entity); err != nil {
-		s.logger.Error("Failed to save contract", zap.String("id", id), zap.Error(err))
+	var result domain.Contract
+	// The overlap check and the insert must run in one transaction,
+	// otherwise concurrent requests could both create overlapping contracts.
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.driverRepo.FindByID(ctx, driverID); err != nil {
+			return err
+		}
+		if _, err := s.legalRepo.FindByID(ctx, legalEntityID); err != nil {
+			return err
+		}
+		fleet, err := s.fleetRepo.FindByID(ctx, fleetID)
+		if err != nil {
+			return err
+		}
+		// Authorization of the legal entity covers its own fleets only.
+		if fleet.LegalEntityID != legalEntityID {
+			return fmt.Errorf("%w: fleet does not belong to the legal entity", domain.ErrInvalidInput)
+		}
+		overlapping, err := s.repo.FindOverlapping(ctx, driverID, legalEntityID, fleetID, startDate, endDate, "")
+		if err != nil {
+			return err
+		}
+		if len(overlapping) > 0 {
+			return fmt.Errorf("%w: contract dates overlap with existing contract", domain.ErrConflict)
+		}
+		id := s.idGen()
+		if id == "" {
+			return fmt.Errorf("id generator returned empty ID")
+		}
+		now, actor := s.clock(), domain.PrincipalFromContext(ctx).ID
+		entity := &domain.Contract{
+			ID:            id,
+			DriverID:      driverID,
+			LegalEntityID: legalEntityID,
+			FleetID:       fleetID,
+			StartDate:     startDate,
+			EndDate:       endDate,
+			CreatedAt:     now,
+			CreatedBy:     actor,
+			UpdatedAt:     now,
+			UpdatedBy:     actor,
+		}
+		if err := s.repo.Save(ctx, entity); err != nil {
+			logctx.From(ctx, s.logger).Error("Failed to save contract", zap.String("id", id), zap.Error(err))
+			return err
+		}
+		result = *entity
+		if err := s.record(ctx, domain.AuditCreate, id, nil, result); err != nil {
+			return err
+		}
+		return s.emit(ctx, domain.EventContractCreated, domain.NewContractEvent(&result))
+	})
+	if err != nil {
 		return nil, err
 	}
-	s.logger.Info("Created contract", zap.String("id", id))
-	result := *entity
+	logctx.From(ctx, s.logger).Info("Created contract", zap.String("id", result.ID))
 	return &result, nil
 }
 
 func (s *Service) Get(ctx context.Context, id string) (*domain.Contract, error) {
+	ctx, span := tracer.Start(ctx, "ContractService.Get")
+	defer span.End()
+
 	if id == "" {
 		return nil, fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
 	}
-	return s.repo.FindByID(ctx, id)
+	entity, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, domain.ActionRead, entity); err != nil {
+		return nil, err
+	}
+	return entity, nil
 }
 
-func (s *Service) ListByDriver(ctx context.Context, driverID string) ([]*domain.Contract, error) {
+// ListByDriver lists the contracts of a driver. Callers allowed to read the contracts of
+// some legal entities only must filter the list by legal_entity_id.
+func (s *Service) ListByDriver(ctx context.Context, driverID string, q ports.ListQuery) (*ports.Page[*domain.Contract], error) {
+	ctx, span := tracer.Start(ctx, "ContractService.ListByDriver")
+	defer span.End()
+
 	if driverID == "" {
 		return nil, fmt.Errorf("%w: driver_id is required", domain.ErrInvalidInput)
 	}
-	return s.repo.FindByDriverID(ctx, driverID)
+	scope := &domain.Contract{DriverID: driverID, LegalEntityID: q.Filters["legal_entity_id"]}
+	if err := s.authorize(ctx, domain.ActionRead, scope); err != nil {
+		return nil, err
+	}
+	q, err := q.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindByDriverID(ctx, driverID, q)
 }
 
 func (s *Service) Terminate(ctx context.Context, id, terminatedBy string) (*domain.Contract, error) {
+	ctx, span := tracer.Start(ctx, "ContractService.Terminate")
+	defer span.End()
+
 	if id == "" {
 		return nil, fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
 	}
 	if terminatedBy == "" {
 		return nil, fmt.Errorf("%w: terminated_by is required", domain.ErrInvalidInput)
 	}
-	entity, err := s.repo.FindByID(ctx, id)
+	var result domain.Contract
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		entity, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.authorize(ctx, domain.ActionUpdate, entity); err != nil {
+			return err
+		}
+		if entity.TerminatedAt != nil {
+			return fmt.Errorf("%w: contract is already terminated", domain.ErrConflict)
+		}
+		before := *entity
+		now := s.clock()
+		entity.TerminatedAt = &now
+		entity.TerminatedBy = terminatedBy
+		entity.UpdatedAt, entity.UpdatedBy = now, domain.PrincipalFromContext(ctx).ID
+		if err := s.repo.Save(ctx, entity); err != nil {
+			logctx.From(ctx, s.logger).Error("Failed to save terminated contract", zap.String("id", id), zap.Error(err))
+			return err
+		}
+		result = *entity
+		if err := s.record(ctx, domain.AuditTerminate, id, before, result); err != nil {
+			return err
+		}
+		return s.emit(ctx, domain.EventContractTerminated, domain.NewContractEvent(&result))
+	})
 	if err != nil {
 		return nil, err
 	}
-	if entity.TerminatedAt != nil {
-		return nil, fmt.Errorf("%w: contract is already terminated", domain.ErrConflict)
+	logctx.From(ctx, s.logger).Info("Terminated contract", zap.String("id", id))
+	return &result, nil
+}
+
+func (s *Service) Update(ctx context.Context, id string, version int64, patch domain.ContractPatch) (*domain.Contract, error) {
+	ctx, span := tracer.Start(ctx, "ContractService.Update")
+	defer span.End()
+
+	if id == "" {
+		return nil, fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
+	}
+	if patch.StartDate == nil && patch.EndDate == nil {
+		return nil, fmt.Errorf("%w: nothing to update", domain.ErrInvalidInput)
 	}
-	now := s.clock()
-	entity.TerminatedAt = &now
-	entity.TerminatedBy = terminatedBy
-	if err := s.repo.Save(ctx, entity); err != nil {
-		s.logger.Error("Failed to save terminated contract", zap.String("id", id), zap.Error(err))
+	var result domain.Contract
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		entity, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.authorize(ctx, domain.ActionUpdate, entity); err != nil {
+			return err
+		}
+		if version != 0 && entity.Version != version {
+			return domain.ErrPreconditionFailed
+		}
+		if entity.TerminatedAt != nil {
+			return fmt.Errorf("%w: terminated contract cannot be modified", domain.ErrConflict)
+		}
+		before := *entity
+		if patch.StartDate != nil {
+			entity.StartDate = *patch.StartDate
+		}
+		if patch.EndDate != nil {
+			entity.EndDate = *patch.EndDate
+		}
+		if !entity.EndDate.After(entity.StartDate) {
+			return fmt.Errorf("%w: end_date must be after start_date", domain.ErrInvalidInput)
+		}
+		overlapping, err := s.repo.FindOverlapping(ctx,
+			entity.DriverID, entity.LegalEntityID, entity.FleetID,
+			entity.StartDate, entity.EndDate,
+			entity.ID,
+		)
+		if err != nil {
+			return err
+		}
+		if len(overlapping) > 0 {
+			return fmt.Errorf("%w: contract dates overlap with existing contract", domain.ErrConflict)
+		}
+		entity.UpdatedAt, entity.UpdatedBy = s.clock(), domain.PrincipalFromContext(ctx).ID
+		if err := s.repo.Save(ctx, entity); err != nil {
+			logctx.From(ctx, s.logger).Error("Failed to save contract", zap.String("id", id), zap.Error(err))
+			return err
+		}
+		result = *entity
+		return s.record(ctx, domain.AuditUpdate, id, before, result)
+	})
+	if err != nil {
 		return nil, err
 	}
-	s.logger.Info("Terminated contract", zap.String("id", id))
-	result := *entity
+	logctx.From(ctx, s.logger).Info("Updated contract", zap.String("id", id))
 	return &result, nil
 }
 
-func (s *Service) Delete(ctx context.Context, id string) error {
+func (s *Service) Delete(ctx context.Context, id string, opts ports.DeleteOptions) error {
+	ctx, span := tracer.Start(ctx, "ContractService.Delete")
+	defer span.End()
+
 	if id == "" {
 		return fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
 	}
-	return s.repo.SoftDelete(ctx, id)
+	d := ports.Deletion{
+		ID:     s.idGen(),
+		At:     s.clock(),
+		By:     domain.PrincipalFromContext(ctx).ID,
+		Reason: strings.TrimSpace(opts.Reason),
+	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.authorize(ctx, domain.ActionDelete, before); err != nil {
+			return err
+		}
+		if err := s.repo.SoftDelete(ctx, id, d); err != nil {
+			return err
+		}
+		return s.appendAudit(ctx, &domain.AuditEntry{
+			EntityType: domain.EntityContract,
+			EntityID:   id,
+			Action:     domain.AuditDelete,
+			DeletionID: d.ID,
+			Before:     *before,
+		})
+	})
 }
 
 func (s *Service) Undelete(ctx context.Context, id string) error {
+	ctx, span := tracer.Start(ctx, "ContractService.Undelete")
+	defer span.End()
+
 	if id == "" {
 		return fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
 	}
-	return s.repo.Undelete(ctx, id)
+	at, by := s.clock(), domain.PrincipalFromContext(ctx).ID
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		entity, err := s.repo.FindDeletedByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.authorize(ctx, domain.ActionDelete, entity); err != nil {
+			return err
+		}
+		if _, err := s.driverRepo.FindByID(ctx, entity.DriverID); err != nil {
+			return domain.ParentError(err, "driver", entity.DriverID)
+		}
+		if _, err := s.legalRepo.FindByID(ctx, entity.LegalEntityID); err != nil {
+			return domain.ParentError(err, "legal entity", entity.LegalEntityID)
+		}
+		if _, err := s.fleetRepo.FindByID(ctx, entity.FleetID); err != nil {
+			return domain.ParentError(err, "fleet", entity.FleetID)
+		}
+		if err := s.repo.Undelete(ctx, id, at, by); err != nil {
+			return err
+		}
+		after, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.appendAudit(ctx, &domain.AuditEntry{
+			EntityType: domain.EntityContract,
+			EntityID:   id,
+			Action:     domain.AuditUndelete,
+			DeletionID: entity.DeletionID,
+			After:      *after,
+		})
+	})
+}
+
+// authorize checks the principal may act on the contract, which is owned both by its
+// legal entity and by its driver.
+func (s *Service) authorize(ctx context.Context, action domain.Action, c *domain.Contract) error {
+	return s.authz.Authorize(ctx, action, domain.Resource{
+		Type:          domain.EntityContract,
+		LegalEntityID: c.LegalEntityID,
+		DriverID:      c.DriverID,
+	})
+}
+
+// record appends an audit entry for a change of the contract with the given ID.
+func (s *Service) record(ctx context.Context, action domain.AuditAction, id string, before, after any) error {
+	return s.appendAudit(ctx, &domain.AuditEntry{
+		EntityType: domain.EntityContract,
+		EntityID:   id,
+		Action:     action,
+		Before:     before,
+		After:      after,
+	})
+}
+
+// appendAudit sets the ID, actor and time of the entries and appends them to the audit log.
+func (s *Service) appendAudit(ctx context.Context, entries ...*domain.AuditEntry) error {
+	actor, at := domain.PrincipalFromContext(ctx).ID, s.clock()
+	for _, e := range entries {
+		e.ID, e.Actor, e.At = s.idGen(), actor, at
+		if err := s.auditLog.Append(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// emit enqueues an event of the given type to be published once the transaction commits.
+func (s *Service) emit(ctx context.Context, typ domain.EventType, payload any) error {
+	return s.outbox.Enqueue(ctx, &domain.Event{
+		ID:         s.idGen(),
+		Type:       typ,
+		OccurredAt: s.clock(),
+		Payload:    payload,
+	})
 }
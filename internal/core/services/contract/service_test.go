@@ -1,6 +1,7 @@
 package contract_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -10,12 +11,13 @@ import (
 	"go.uber.org/zap/zaptest"
 
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/authz"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/services/contract"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/servicetest"
 )
 
-func stubIDGen() string { return "test-id" }
-
 func TestService_Create_RejectsOverlap(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	driverRepo := mocks.NewMockDriverRepository(ctrl)
@@ -25,11 +27,13 @@ func TestService_Create_RejectsOverlap(t *testing.T) {
 
 	driverRepo.EXPECT().FindByID(gomock.Any(), "d1").Return(&domain.Driver{ID: "d1"}, nil)
 	legalRepo.EXPECT().FindByID(gomock.Any(), "le1").Return(&domain.LegalEntity{ID: "le1"}, nil)
-	fleetRepo.EXPECT().FindByID(gomock.Any(), "f1").Return(&domain.Fleet{ID: "f1"}, nil)
+	fleetRepo.EXPECT().FindByID(gomock.Any(), "f1").Return(&domain.Fleet{ID: "f1", LegalEntityID: "le1"}, nil)
 	contractRepo.EXPECT().FindOverlapping(gomock.Any(), "d1", "le1", "f1",
 		gomock.Any(), gomock.Any(), "").Return([]*domain.Contract{{ID: "existing"}}, nil)
 
-	svc := contract.New(driverRepo, legalRepo, fleetRepo, contractRepo, stubIDGen, time.Now, zaptest.NewLogger(t))
+	svc := contract.New(driverRepo, legalRepo, fleetRepo, contractRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), servicetest.NopOutbox(ctrl), servicetest.AllowAll(ctrl), servicetest.IDGen,
+		time.Now, zaptest.NewLogger(t))
 	start := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
 	end := time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC)
 	_, err := svc.Create(t.Context(), "d1", "le1", "f1", start, end)
@@ -45,11 +49,13 @@ func TestService_Create_Success(t *testing.T) {
 
 	driverRepo.EXPECT().FindByID(gomock.Any(), "d1").Return(&domain.Driver{ID: "d1"}, nil)
 	legalRepo.EXPECT().FindByID(gomock.Any(), "le1").Return(&domain.LegalEntity{ID: "le1"}, nil)
-	fleetRepo.EXPECT().FindByID(gomock.Any(), "f1").Return(&domain.Fleet{ID: "f1"}, nil)
+	fleetRepo.EXPECT().FindByID(gomock.Any(), "f1").Return(&domain.Fleet{ID: "f1", LegalEntityID: "le1"}, nil)
 	contractRepo.EXPECT().FindOverlapping(gomock.Any(), "d1", "le1", "f1", gomock.Any(), gomock.Any(), "").Return(nil, nil)
 	contractRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
 
-	svc := contract.New(driverRepo, legalRepo, fleetRepo, contractRepo, stubIDGen, time.Now, zaptest.NewLogger(t))
+	svc := contract.New(driverRepo, legalRepo, fleetRepo, contractRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), servicetest.NopOutbox(ctrl), servicetest.AllowAll(ctrl), servicetest.IDGen,
+		time.Now, zaptest.NewLogger(t))
 	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	end := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
 	entity, err := svc.Create(t.Context(), "d1", "le1", "f1", start, end)
@@ -57,3 +63,273 @@ func TestService_Create_Success(t *testing.T) {
 	assert.Equal(t, "test-id", entity.ID)
 	assert.Equal(t, "d1", entity.DriverID)
 }
+
+func TestService_Create_RejectsFleetOfAnotherLegalEntity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	driverRepo := mocks.NewMockDriverRepository(ctrl)
+	legalRepo := mocks.NewMockLegalEntityRepository(ctrl)
+	fleetRepo := mocks.NewMockFleetRepository(ctrl)
+	contractRepo := mocks.NewMockContractRepository(ctrl)
+
+	driverRepo.EXPECT().FindByID(gomock.Any(), "d1").Return(&domain.Driver{ID: "d1"}, nil)
+	legalRepo.EXPECT().FindByID(gomock.Any(), "le1").Return(&domain.LegalEntity{ID: "le1"}, nil)
+	fleetRepo.EXPECT().FindByID(gomock.Any(), "f2").Return(&domain.Fleet{ID: "f2", LegalEntityID: "le2"}, nil)
+
+	svc := contract.New(driverRepo, legalRepo, fleetRepo, contractRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), servicetest.NopOutbox(ctrl), servicetest.AllowAll(ctrl), servicetest.IDGen,
+		time.Now, zaptest.NewLogger(t))
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	_, err := svc.Create(t.Context(), "d1", "le1", "f2", start, end)
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+}
+
+func TestService_Create_ReportsEveryInvalidField(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc := contract.New(mocks.NewMockDriverRepository(ctrl), mocks.NewMockLegalEntityRepository(ctrl),
+		mocks.NewMockFleetRepository(ctrl), mocks.NewMockContractRepository(ctrl), servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), servicetest.NopOutbox(ctrl), servicetest.AllowAll(ctrl), servicetest.IDGen, time.Now,
+		zaptest.NewLogger(t))
+
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err := svc.Create(t.Context(), "d1", "", "", day, day)
+	var invalid *domain.ValidationError
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, []domain.Violation{
+		{Field: "legal_entity_id", Rule: domain.RuleRequired, Message: "is required"},
+		{Field: "fleet_id", Rule: domain.RuleRequired, Message: "is required"},
+		{Field: "end_date", Rule: domain.RuleAfter, Message: "must be after start_date"},
+	}, invalid.Violations)
+}
+
+func TestService_Get_Authorization(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal domain.Principal
+		wantErr   error
+	}{
+		{"admin", domain.Principal{Role: domain.RoleAdmin}, nil},
+		{"manager of the legal entity", domain.Principal{Role: domain.RoleFleetManager, LegalEntityIDs: []string{"le1"}}, nil},
+		{"manager of another legal entity", domain.Principal{Role: domain.RoleFleetManager, LegalEntityIDs: []string{"le2"}}, domain.ErrForbidden},
+		{"driver of the contract", domain.Principal{Role: domain.RoleDriver, DriverID: "d1"}, nil},
+		{"another driver", domain.Principal{Role: domain.RoleDriver, DriverID: "d2"}, domain.ErrForbidden},
+		{"anonymous", domain.Principal{}, domain.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			contractRepo := mocks.NewMockContractRepository(ctrl)
+			contractRepo.EXPECT().FindByID(gomock.Any(), "c1").
+				Return(&domain.Contract{ID: "c1", DriverID: "d1", LegalEntityID: "le1", FleetID: "f1"}, nil)
+
+			svc := contract.New(mocks.NewMockDriverRepository(ctrl), mocks.NewMockLegalEntityRepository(ctrl),
+				mocks.NewMockFleetRepository(ctrl), contractRepo, servicetest.PassthroughTx(ctrl),
+				servicetest.NopAuditLog(ctrl), servicetest.NopOutbox(ctrl), authz.New(), servicetest.IDGen, time.Now,
+				zaptest.NewLogger(t))
+			_, err := svc.Get(domain.ContextWithPrincipal(t.Context(), tt.principal), "c1")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestService_ListByDriver_Authorization(t *testing.T) {
+	manager := domain.Principal{Role: domain.RoleFleetManager, LegalEntityIDs: []string{"le1"}}
+	tests := []struct {
+		name      string
+		principal domain.Principal
+		filters   map[string]string
+		allowed   bool
+	}{
+		{"manager filtering by own legal entity", manager, map[string]string{"legal_entity_id": "le1"}, true},
+		{"manager filtering by another legal entity", manager, map[string]string{"legal_entity_id": "le2"}, false},
+		{"manager without filter", manager, nil, false},
+		{"the driver", domain.Principal{Role: domain.RoleDriver, DriverID: "d1"}, nil, true},
+		{"another driver", domain.Principal{Role: domain.RoleDriver, DriverID: "d2"}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			contractRepo := mocks.NewMockContractRepository(ctrl)
+			if tt.allowed {
+				contractRepo.EXPECT().FindByDriverID(gomock.Any(), "d1", gomock.Any()).
+					Return(&ports.Page[*domain.Contract]{}, nil)
+			}
+
+			svc := contract.New(mocks.NewMockDriverRepository(ctrl), mocks.NewMockLegalEntityRepository(ctrl),
+				mocks.NewMockFleetRepository(ctrl), contractRepo, servicetest.PassthroughTx(ctrl),
+				servicetest.NopAuditLog(ctrl), servicetest.NopOutbox(ctrl), authz.New(), servicetest.IDGen, time.Now,
+				zaptest.NewLogger(t))
+			ctx := domain.ContextWithPrincipal(t.Context(), tt.principal)
+			_, err := svc.ListByDriver(ctx, "d1", ports.ListQuery{Filters: tt.filters})
+			if tt.allowed {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, domain.ErrForbidden)
+		})
+	}
+}
+
+func TestService_Update_RejectsOverlap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	driverRepo := mocks.NewMockDriverRepository(ctrl)
+	legalRepo := mocks.NewMockLegalEntityRepository(ctrl)
+	fleetRepo := mocks.NewMockFleetRepository(ctrl)
+	contractRepo := mocks.NewMockContractRepository(ctrl)
+
+	contractRepo.EXPECT().FindByID(gomock.Any(), "c1").Return(&domain.Contract{
+		ID:            "c1",
+		DriverID:      "d1",
+		LegalEntityID: "le1",
+		FleetID:       "f1",
+		StartDate:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:       time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+		Version:       1,
+	}, nil)
+	contractRepo.EXPECT().FindOverlapping(gomock.Any(), "d1", "le1", "f1",
+		gomock.Any(), gomock.Any(), "c1").Return([]*domain.Contract{{ID: "other"}}, nil)
+
+	svc := contract.New(driverRepo, legalRepo, fleetRepo, contractRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), servicetest.NopOutbox(ctrl), servicetest.AllowAll(ctrl), servicetest.IDGen,
+		time.Now, zaptest.NewLogger(t))
+	end := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	_, err := svc.Update(t.Context(), "c1", 1, domain.ContractPatch{EndDate: &end})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+}
+
+func TestService_Update_RejectsTerminated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	driverRepo := mocks.NewMockDriverRepository(ctrl)
+	legalRepo := mocks.NewMockLegalEntityRepository(ctrl)
+	fleetRepo := mocks.NewMockFleetRepository(ctrl)
+	contractRepo := mocks.NewMockContractRepository(ctrl)
+
+	terminatedAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	contractRepo.EXPECT().FindByID(gomock.Any(), "c1").Return(&domain.Contract{
+		ID:           "c1",
+		StartDate:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:      time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+		TerminatedAt: &terminatedAt,
+	}, nil)
+
+	svc := contract.New(driverRepo, legalRepo, fleetRepo, contractRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), servicetest.NopOutbox(ctrl), servicetest.AllowAll(ctrl), servicetest.IDGen,
+		time.Now, zaptest.NewLogger(t))
+	end := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	_, err := svc.Update(t.Context(), "c1", 0, domain.ContractPatch{EndDate: &end})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+}
+
+func TestService_Undelete_RejectsWhenParentDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	driverRepo := mocks.NewMockDriverRepository(ctrl)
+	legalRepo := mocks.NewMockLegalEntityRepository(ctrl)
+	fleetRepo := mocks.NewMockFleetRepository(ctrl)
+	contractRepo := mocks.NewMockContractRepository(ctrl)
+
+	contractRepo.EXPECT().FindDeletedByID(gomock.Any(), "c1").
+		Return(&domain.Contract{ID: "c1", DriverID: "d1", LegalEntityID: "le1", FleetID: "f1"}, nil)
+	driverRepo.EXPECT().FindByID(gomock.Any(), "d1").Return(&domain.Driver{ID: "d1"}, nil)
+	legalRepo.EXPECT().FindByID(gomock.Any(), "le1").Return(nil, domain.ErrNotFound)
+
+	svc := contract.New(driverRepo, legalRepo, fleetRepo, contractRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), servicetest.NopOutbox(ctrl), servicetest.AllowAll(ctrl), servicetest.IDGen,
+		time.Now, zaptest.NewLogger(t))
+	err := svc.Undelete(t.Context(), "c1")
+	require.ErrorIs(t, err, domain.ErrParentDeleted)
+	var parentErr *domain.ParentDeletedError
+	require.ErrorAs(t, err, &parentErr)
+	assert.Equal(t, "legal entity", parentErr.Parent)
+	assert.Equal(t, "le1", parentErr.ID)
+}
+
+func TestService_Undelete_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	driverRepo := mocks.NewMockDriverRepository(ctrl)
+	legalRepo := mocks.NewMockLegalEntityRepository(ctrl)
+	fleetRepo := mocks.NewMockFleetRepository(ctrl)
+	contractRepo := mocks.NewMockContractRepository(ctrl)
+
+	contractRepo.EXPECT().FindDeletedByID(gomock.Any(), "c1").
+		Return(&domain.Contract{ID: "c1", DriverID: "d1", LegalEntityID: "le1", FleetID: "f1"}, nil)
+	driverRepo.EXPECT().FindByID(gomock.Any(), "d1").Return(&domain.Driver{ID: "d1"}, nil)
+	legalRepo.EXPECT().FindByID(gomock.Any(), "le1").Return(&domain.LegalEntity{ID: "le1"}, nil)
+	fleetRepo.EXPECT().FindByID(gomock.Any(), "f1").Return(&domain.Fleet{ID: "f1", LegalEntityID: "le1"}, nil)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	contractRepo.EXPECT().Undelete(gomock.Any(), "c1", now, "alice").Return(nil)
+	contractRepo.EXPECT().FindByID(gomock.Any(), "c1").Return(&domain.Contract{ID: "c1", Version: 2}, nil)
+
+	clock := func() time.Time { return now }
+	svc := contract.New(driverRepo, legalRepo, fleetRepo, contractRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), servicetest.NopOutbox(ctrl), servicetest.AllowAll(ctrl), servicetest.IDGen,
+		clock, zaptest.NewLogger(t))
+	ctx := domain.ContextWithPrincipal(t.Context(), domain.Principal{ID: "alice"})
+	require.NoError(t, svc.Undelete(ctx, "c1"))
+}
+
+func TestService_Terminate_RecordsAuditEntry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	driverRepo := mocks.NewMockDriverRepository(ctrl)
+	legalRepo := mocks.NewMockLegalEntityRepository(ctrl)
+	fleetRepo := mocks.NewMockFleetRepository(ctrl)
+	contractRepo := mocks.NewMockContractRepository(ctrl)
+	auditLog := mocks.NewMockAuditLog(ctrl)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	contractRepo.EXPECT().FindByID(gomock.Any(), "c1").Return(&domain.Contract{ID: "c1", Version: 3}, nil)
+	contractRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+	auditLog.EXPECT().Append(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, e *domain.AuditEntry) error {
+			assert.Equal(t, domain.EntityContract, e.EntityType)
+			assert.Equal(t, "c1", e.EntityID)
+			assert.Equal(t, domain.AuditTerminate, e.Action)
+			assert.Equal(t, "alice", e.Actor)
+			require.IsType(t, domain.Contract{}, e.Before)
+			require.IsType(t, domain.Contract{}, e.After)
+			assert.Nil(t, e.Before.(domain.Contract).TerminatedAt)
+			assert.Equal(t, &now, e.After.(domain.Contract).TerminatedAt)
+			assert.Equal(t, "hr", e.After.(domain.Contract).TerminatedBy)
+			return nil
+		})
+
+	clock := func() time.Time { return now }
+	svc := contract.New(driverRepo, legalRepo, fleetRepo, contractRepo, servicetest.PassthroughTx(ctrl), auditLog,
+		servicetest.NopOutbox(ctrl), servicetest.AllowAll(ctrl), servicetest.IDGen, clock, zaptest.NewLogger(t))
+	ctx := domain.ContextWithPrincipal(t.Context(), domain.Principal{ID: "alice"})
+	_, err := svc.Terminate(ctx, "c1", "hr")
+	require.NoError(t, err)
+}
+
+func TestService_Terminate_EmitsEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	driverRepo := mocks.NewMockDriverRepository(ctrl)
+	legalRepo := mocks.NewMockLegalEntityRepository(ctrl)
+	fleetRepo := mocks.NewMockFleetRepository(ctrl)
+	contractRepo := mocks.NewMockContractRepository(ctrl)
+	outbox := mocks.NewMockOutbox(ctrl)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	contractRepo.EXPECT().FindByID(gomock.Any(), "c1").
+		Return(&domain.Contract{ID: "c1", DriverID: "d1", LegalEntityID: "le1", FleetID: "f1"}, nil)
+	contractRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+	outbox.EXPECT().Enqueue(gomock.Any(), &domain.Event{
+		ID:         "test-id",
+		Type:       domain.EventContractTerminated,
+		OccurredAt: now,
+		Payload: domain.ContractEvent{
+			ContractID: "c1", DriverID: "d1", LegalEntityID: "le1", FleetID: "f1",
+			TerminatedAt: &now, TerminatedBy: "hr",
+		},
+	}).Return(nil)
+
+	clock := func() time.Time { return now }
+	svc := contract.New(driverRepo, legalRepo, fleetRepo, contractRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), outbox, servicetest.AllowAll(ctrl), servicetest.IDGen, clock,
+		zaptest.NewLogger(t))
+	_, err := svc.Terminate(t.Context(), "c1", "hr")
+	require.NoError(t, err)
+}
@@ -6,12 +6,16 @@ import (
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
 
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/logctx"
 )
 
+var tracer = otel.Tracer("github.com/albenik/uber-fx-based-service-example/internal/core/services/driver")
+
 type IDGenerator func() string
 
 type Clock func() time.Time
@@ -20,6 +24,9 @@ type Service struct {
 	repo           ports.DriverRepository
 	contractRepo   ports.ContractRepository
 	assignmentRepo ports.VehicleAssignmentRepository
+	tx             ports.TxManager
+	auditLog       ports.AuditLog
+	authz          ports.Authorizer
 	validator      ports.DriverLicenseValidator
 	idGen          IDGenerator
 	clock          Clock
@@ -30,6 +37,9 @@ func New(
 	repo ports.DriverRepository,
 	contractRepo ports.ContractRepository,
 	assignmentRepo ports.VehicleAssignmentRepository,
+	tx ports.TxManager,
+	auditLog ports.AuditLog,
+	authz ports.Authorizer,
 	validator ports.DriverLicenseValidator,
 	idGen IDGenerator,
 	clock Clock,
@@ -39,6 +49,9 @@ func New(
 		repo:           repo,
 		contractRepo:   contractRepo,
 		assignmentRepo: assignmentRepo,
+		tx:             tx,
+		auditLog:       auditLog,
+		authz:          authz,
 		validator:      validator,
 		idGen:          idGen,
 		clock:          clock,
@@ -47,17 +60,35 @@ func New(
 }
 
 func (s *Service) Create(ctx context.Context, firstName, lastName, licenseNumber string) (*domain.Driver, error) {
+	ctx, span := tracer.Start(ctx, "DriverService.Create")
+	defer span.End()
+
+	if err := s.authorize(ctx, domain.ActionCreate, ""); err != nil {
+		return nil, err
+	}
 	firstName = strings.TrimSpace(firstName)
 	lastName = strings.TrimSpace(lastName)
 	licenseNumber = strings.TrimSpace(licenseNumber)
+	var invalid domain.ValidationError
 	if firstName == "" {
-		return nil, fmt.Errorf("%w: first_name is required", domain.ErrInvalidInput)
+		invalid.Add("first_name", domain.RuleRequired, "is required")
 	}
 	if lastName == "" {
-		return nil, fmt.Errorf("%w: last_name is required", domain.ErrInvalidInput)
+		invalid.Add("last_name", domain.RuleRequired, "is required")
 	}
 	if licenseNumber == "" {
-		return nil, fmt.Errorf("%w: license_number is required", domain.ErrInvalidInput)
+		invalid.Add("license_number", domain.RuleRequired, "is required")
+	}
+	if err := invalid.Err(); err != nil {
+		return nil, err
+	}
+	// Checked before the license validation, which is a paid external call.
+	taken, err := s.repo.ExistsByLicenseNumber(ctx, licenseNumber, "")
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, &domain.UniqueViolationError{Field: "license_number"}
 	}
 	result, err := s.validator.ValidateLicense(ctx, firstName, lastName, licenseNumber)
 	if err != nil {
@@ -70,65 +101,270 @@ func (s *Service) Create(ctx context.Context, firstName, lastName, licenseNumber
 	if id == "" {
 		return nil, fmt.Errorf("id generator returned empty ID")
 	}
-	entity := &domain.Driver{ID: id, FirstName: firstName, LastName: lastName, LicenseNumber: licenseNumber}
-	if err := s.repo.Save(ctx, entity); err != nil {
-		s.logger.Error("Failed to save driver", zap.String("id", id), zap.Error(err))
+	now, actor := s.clock(), domain.PrincipalFromContext(ctx).ID
+	entity := &domain.Driver{
+		ID: id, FirstName: firstName, LastName: lastName, LicenseNumber: licenseNumber,
+		CreatedAt: now, CreatedBy: actor, UpdatedAt: now, UpdatedBy: actor,
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Save(ctx, entity); err != nil {
+			return err
+		}
+		return s.record(ctx, domain.AuditCreate, id, nil, *entity)
+	})
+	if err != nil {
+		logctx.From(ctx, s.logger).Error("Failed to save driver", zap.String("id", id), zap.Error(err))
 		return nil, err
 	}
-	s.logger.Info("Created driver", zap.String("id", id))
+	logctx.From(ctx, s.logger).Info("Created driver", zap.String("id", id))
 	out := *entity
 	return &out, nil
 }
 
 func (s *Service) Get(ctx context.Context, id string) (*domain.Driver, error) {
+	ctx, span := tracer.Start(ctx, "DriverService.Get")
+	defer span.End()
+
 	if id == "" {
 		return nil, fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
 	}
+	if err := s.authorize(ctx, domain.ActionRead, id); err != nil {
+		return nil, err
+	}
 	return s.repo.FindByID(ctx, id)
 }
 
-func (s *Service) List(ctx context.Context) ([]*domain.Driver, error) {
-	return s.repo.FindAll(ctx)
+func (s *Service) List(ctx context.Context, q ports.ListQuery) (*ports.Page[*domain.Driver], error) {
+	ctx, span := tracer.Start(ctx, "DriverService.List")
+	defer span.End()
+
+	if err := s.authorize(ctx, domain.ActionRead, ""); err != nil {
+		return nil, err
+	}
+	q, err := q.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindAll(ctx, q)
 }
 
-func (s *Service) Delete(ctx context.Context, id string) error {
+func (s *Service) Update(ctx context.Context, id string, version int64, patch domain.DriverPatch) (*domain.Driver, error) {
+	ctx, span := tracer.Start(ctx, "DriverService.Update")
+	defer span.End()
+
 	if id == "" {
-		return fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
+		return nil, fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
+	}
+	if patch.FirstName == nil && patch.LastName == nil && patch.LicenseNumber == nil {
+		return nil, fmt.Errorf("%w: nothing to update", domain.ErrInvalidInput)
 	}
-	contracts, err := s.contractRepo.FindByDriverID(ctx, id)
+	if err := s.authorize(ctx, domain.ActionUpdate, id); err != nil {
+		return nil, err
+	}
+	entity, err := s.repo.FindByID(ctx, id)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if version != 0 && entity.Version != version {
+		return nil, domain.ErrPreconditionFailed
+	}
+	before := *entity
+	if patch.FirstName != nil {
+		entity.FirstName = strings.TrimSpace(*patch.FirstName)
+		if entity.FirstName == "" {
+			return nil, fmt.Errorf("%w: first_name is required", domain.ErrInvalidInput)
+		}
+	}
+	if patch.LastName != nil {
+		entity.LastName = strings.TrimSpace(*patch.LastName)
+		if entity.LastName == "" {
+			return nil, fmt.Errorf("%w: last_name is required", domain.ErrInvalidInput)
+		}
+	}
+	if patch.LicenseNumber != nil {
+		entity.LicenseNumber = strings.TrimSpace(*patch.LicenseNumber)
+		if entity.LicenseNumber == "" {
+			return nil, fmt.Errorf("%w: license_number is required", domain.ErrInvalidInput)
+		}
+	}
+	if entity.LicenseNumber != before.LicenseNumber {
+		taken, err := s.repo.ExistsByLicenseNumber(ctx, entity.LicenseNumber, entity.ID)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			return nil, &domain.UniqueViolationError{Field: "license_number"}
+		}
 	}
-	now := s.clock()
-	for _, c := range contracts {
-		if c.TerminatedAt == nil && now.Before(c.EndDate.AddDate(0, 0, 1)) {
-			return domain.ErrDriverHasActiveContracts
+	// License data changed: it must pass the same external validation as on creation.
+	if entity.FirstName != before.FirstName || entity.LastName != before.LastName ||
+		entity.LicenseNumber != before.LicenseNumber {
+		result, err := s.validator.ValidateLicense(ctx, entity.FirstName, entity.LastName, entity.LicenseNumber)
+		if err != nil {
+			return nil, err
+		}
+		if result != domain.LicenseValid {
+			return nil, fmt.Errorf("%w: %s", domain.ErrLicenseValidationFailed, result)
 		}
 	}
-	activeAssignments, err := s.assignmentRepo.FindActiveByDriverID(ctx, id)
+	entity.UpdatedAt, entity.UpdatedBy = s.clock(), domain.PrincipalFromContext(ctx).ID
+	unsaved := *entity
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		*entity = unsaved
+		if err := s.repo.Save(ctx, entity); err != nil {
+			return err
+		}
+		return s.record(ctx, domain.AuditUpdate, id, before, *entity)
+	})
 	if err != nil {
+		logctx.From(ctx, s.logger).Error("Failed to save driver", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+	logctx.From(ctx, s.logger).Info("Updated driver", zap.String("id", id))
+	out := *entity
+	return &out, nil
+}
+
+func (s *Service) Delete(ctx context.Context, id string, opts ports.DeleteOptions) error {
+	ctx, span := tracer.Start(ctx, "DriverService.Delete")
+	defer span.End()
+
+	if id == "" {
+		return fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
+	}
+	if err := s.authorize(ctx, domain.ActionDelete, id); err != nil {
 		return err
 	}
-	if len(activeAssignments) > 0 {
-		return domain.ErrDriverHasActiveAssignments
+	d := ports.Deletion{
+		ID:     s.idGen(),
+		At:     s.clock(),
+		By:     domain.PrincipalFromContext(ctx).ID,
+		Reason: strings.TrimSpace(opts.Reason),
 	}
-	return s.repo.SoftDelete(ctx, id)
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		now := d.At
+		q := ports.ListQuery{Limit: ports.MaxListLimit}
+		for {
+			contracts, err := s.contractRepo.FindByDriverID(ctx, id, q)
+			if err != nil {
+				return err
+			}
+			for _, c := range contracts.Items {
+				if c.TerminatedAt == nil && now.Before(c.EndDate.AddDate(0, 0, 1)) {
+					return domain.ErrDriverHasActiveContracts
+				}
+			}
+			if contracts.NextCursor == "" {
+				break
+			}
+			q.Cursor = contracts.NextCursor
+		}
+		activeAssignments, err := s.assignmentRepo.FindActiveByDriverID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if len(activeAssignments) > 0 {
+			return domain.ErrDriverHasActiveAssignments
+		}
+		if err := s.repo.SoftDelete(ctx, id, d); err != nil {
+			return err
+		}
+		return s.appendAudit(ctx, &domain.AuditEntry{
+			EntityType: domain.EntityDriver,
+			EntityID:   id,
+			Action:     domain.AuditDelete,
+			DeletionID: d.ID,
+			Before:     *before,
+		})
+	})
 }
 
 func (s *Service) Undelete(ctx context.Context, id string) error {
+	ctx, span := tracer.Start(ctx, "DriverService.Undelete")
+	defer span.End()
+
 	if id == "" {
 		return fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
 	}
-	return s.repo.Undelete(ctx, id)
+	if err := s.authorize(ctx, domain.ActionDelete, id); err != nil {
+		return err
+	}
+	at, by := s.clock(), domain.PrincipalFromContext(ctx).ID
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		entity, err := s.repo.FindDeletedByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		taken, err := s.repo.ExistsByLicenseNumber(ctx, entity.LicenseNumber, id)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return &domain.UniqueViolationError{Field: "license_number"}
+		}
+		if err := s.repo.Undelete(ctx, id, at, by); err != nil {
+			return err
+		}
+		after, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.appendAudit(ctx, &domain.AuditEntry{
+			EntityType: domain.EntityDriver,
+			EntityID:   id,
+			Action:     domain.AuditUndelete,
+			DeletionID: entity.DeletionID,
+			After:      *after,
+		})
+	})
 }
 
 func (s *Service) ValidateLicense(ctx context.Context, id string) (domain.LicenseValidationResult, error) {
+	ctx, span := tracer.Start(ctx, "DriverService.ValidateLicense")
+	defer span.End()
+
 	if id == "" {
 		return "", fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
 	}
+	if err := s.authorize(ctx, domain.ActionRead, id); err != nil {
+		return "", err
+	}
 	driver, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return "", err
 	}
 	return s.validator.ValidateLicense(ctx, driver.FirstName, driver.LastName, driver.LicenseNumber)
 }
+
+// authorize checks the principal may act on the driver with the given ID, or on drivers
+// in general when id is empty.
+func (s *Service) authorize(ctx context.Context, action domain.Action, id string) error {
+	return s.authz.Authorize(ctx, action, domain.Resource{Type: domain.EntityDriver, DriverID: id})
+}
+
+// record appends an audit entry for a change of the driver with the given ID.
+func (s *Service) record(ctx context.Context, action domain.AuditAction, id string, before, after any) error {
+	return s.appendAudit(ctx, &domain.AuditEntry{
+		EntityType: domain.EntityDriver,
+		EntityID:   id,
+		Action:     action,
+		Before:     before,
+		After:      after,
+	})
+}
+
+// appendAudit sets the ID, actor and time of the entries and appends them to the audit log.
+func (s *Service) appendAudit(ctx context.Context, entries ...*domain.AuditEntry) error {
+	actor, at := domain.PrincipalFromContext(ctx).ID, s.clock()
+	for _, e := range entries {
+		e.ID, e.Actor, e.At = s.idGen(), actor, at
+		if err := s.auditLog.Append(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
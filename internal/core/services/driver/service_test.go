@@ -1,6 +1,8 @@
 package driver_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -10,11 +12,30 @@ import (
 	"go.uber.org/zap/zaptest"
 
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/services/driver"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/servicetest"
 )
 
-func stubIDGen() string { return "test-id" }
+// errSerialization stands for a serialization failure reported by the database.
+var errSerialization = errors.New("could not serialize access")
+
+// retryingTx returns a TxManager that runs the unit of work again when it fails with
+// errSerialization, as the postgres TxManager does.
+func retryingTx(ctrl *gomock.Controller) *mocks.MockTxManager {
+	tx := mocks.NewMockTxManager(ctrl)
+	tx.EXPECT().WithinTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			err := fn(ctx)
+			if errors.Is(err, errSerialization) {
+				err = fn(ctx)
+			}
+			return err
+		}).
+		AnyTimes()
+	return tx
+}
 
 func TestService_Delete_RejectsWhenActiveContracts(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -22,15 +43,18 @@ func TestService_Delete_RejectsWhenActiveContracts(t *testing.T) {
 	contractRepo := mocks.NewMockContractRepository(ctrl)
 	assignmentRepo := mocks.NewMockVehicleAssignmentRepository(ctrl)
 
+	repo.EXPECT().FindByID(gomock.Any(), "d1").Return(&domain.Driver{ID: "d1"}, nil)
 	future := time.Now().Add(24 * time.Hour)
 	contracts := []*domain.Contract{
 		{ID: "c1", DriverID: "d1", TerminatedAt: nil, EndDate: future},
 	}
-	contractRepo.EXPECT().FindByDriverID(gomock.Any(), "d1").Return(contracts, nil)
+	contractRepo.EXPECT().FindByDriverID(gomock.Any(), "d1", gomock.Any()).Return(&ports.Page[*domain.Contract]{Items: contracts}, nil)
 
 	validator := mocks.NewMockDriverLicenseValidator(ctrl)
-	svc := driver.New(repo, contractRepo, assignmentRepo, validator, stubIDGen, time.Now, zaptest.NewLogger(t))
-	err := svc.Delete(t.Context(), "d1")
+	svc := driver.New(repo, contractRepo, assignmentRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), servicetest.AllowAll(ctrl), validator, servicetest.IDGen, time.Now,
+		zaptest.NewLogger(t))
+	err := svc.Delete(t.Context(), "d1", ports.DeleteOptions{})
 	assert.ErrorIs(t, err, domain.ErrDriverHasActiveContracts)
 }
 
@@ -40,28 +64,121 @@ func TestService_Delete_RejectsWhenActiveAssignments(t *testing.T) {
 	contractRepo := mocks.NewMockContractRepository(ctrl)
 	assignmentRepo := mocks.NewMockVehicleAssignmentRepository(ctrl)
 
-	contractRepo.EXPECT().FindByDriverID(gomock.Any(), "d1").Return([]*domain.Contract{}, nil)
+	repo.EXPECT().FindByID(gomock.Any(), "d1").Return(&domain.Driver{ID: "d1"}, nil)
+	contractRepo.EXPECT().FindByDriverID(gomock.Any(), "d1", gomock.Any()).Return(&ports.Page[*domain.Contract]{}, nil)
 	assignmentRepo.EXPECT().FindActiveByDriverID(gomock.Any(), "d1").Return([]*domain.VehicleAssignment{{ID: "a1"}}, nil)
 
 	validator := mocks.NewMockDriverLicenseValidator(ctrl)
-	svc := driver.New(repo, contractRepo, assignmentRepo, validator, stubIDGen, time.Now, zaptest.NewLogger(t))
-	err := svc.Delete(t.Context(), "d1")
+	svc := driver.New(repo, contractRepo, assignmentRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), servicetest.AllowAll(ctrl), validator, servicetest.IDGen, time.Now,
+		zaptest.NewLogger(t))
+	err := svc.Delete(t.Context(), "d1", ports.DeleteOptions{})
 	assert.ErrorIs(t, err, domain.ErrDriverHasActiveAssignments)
 }
 
+func TestService_Delete_RejectsWhenActiveContractOnLaterPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockDriverRepository(ctrl)
+	contractRepo := mocks.NewMockContractRepository(ctrl)
+	assignmentRepo := mocks.NewMockVehicleAssignmentRepository(ctrl)
+
+	repo.EXPECT().FindByID(gomock.Any(), "d1").Return(&domain.Driver{ID: "d1"}, nil)
+	past := time.Now().Add(-48 * time.Hour)
+	future := time.Now().Add(24 * time.Hour)
+	gomock.InOrder(
+		contractRepo.EXPECT().FindByDriverID(gomock.Any(), "d1", ports.ListQuery{Limit: ports.MaxListLimit}).
+			Return(&ports.Page[*domain.Contract]{
+				Items:      []*domain.Contract{{ID: "c1", DriverID: "d1", EndDate: past}},
+				NextCursor: "next",
+			}, nil),
+		contractRepo.EXPECT().FindByDriverID(gomock.Any(), "d1", ports.ListQuery{Limit: ports.MaxListLimit, Cursor: "next"}).
+			Return(&ports.Page[*domain.Contract]{
+				Items: []*domain.Contract{{ID: "c2", DriverID: "d1", EndDate: future}},
+			}, nil),
+	)
+
+	validator := mocks.NewMockDriverLicenseValidator(ctrl)
+	svc := driver.New(repo, contractRepo, assignmentRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), servicetest.AllowAll(ctrl), validator, servicetest.IDGen, time.Now,
+		zaptest.NewLogger(t))
+	err := svc.Delete(t.Context(), "d1", ports.DeleteOptions{})
+	assert.ErrorIs(t, err, domain.ErrDriverHasActiveContracts)
+}
+
+func TestService_Create_ReportsEveryInvalidField(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc := driver.New(mocks.NewMockDriverRepository(ctrl), mocks.NewMockContractRepository(ctrl),
+		mocks.NewMockVehicleAssignmentRepository(ctrl), servicetest.PassthroughTx(ctrl), servicetest.NopAuditLog(ctrl),
+		servicetest.AllowAll(ctrl), mocks.NewMockDriverLicenseValidator(ctrl), servicetest.IDGen, time.Now,
+		zaptest.NewLogger(t))
+
+	_, err := svc.Create(t.Context(), " ", "", "DL-123")
+	var invalid *domain.ValidationError
+	require.ErrorAs(t, err, &invalid)
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	assert.Equal(t, []domain.Violation{
+		{Field: "first_name", Rule: domain.RuleRequired, Message: "is required"},
+		{Field: "last_name", Rule: domain.RuleRequired, Message: "is required"},
+	}, invalid.Violations)
+}
+
+func TestService_List_RejectsInvalidLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockDriverRepository(ctrl)
+	contractRepo := mocks.NewMockContractRepository(ctrl)
+	assignmentRepo := mocks.NewMockVehicleAssignmentRepository(ctrl)
+	validator := mocks.NewMockDriverLicenseValidator(ctrl)
+
+	svc := driver.New(repo, contractRepo, assignmentRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), servicetest.AllowAll(ctrl), validator, servicetest.IDGen, time.Now,
+		zaptest.NewLogger(t))
+	_, err := svc.List(t.Context(), ports.ListQuery{Limit: ports.MaxListLimit + 1})
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+}
+
+func TestService_List_AppliesDefaults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockDriverRepository(ctrl)
+	contractRepo := mocks.NewMockContractRepository(ctrl)
+	assignmentRepo := mocks.NewMockVehicleAssignmentRepository(ctrl)
+	validator := mocks.NewMockDriverLicenseValidator(ctrl)
+
+	want := ports.ListQuery{Limit: ports.DefaultListLimit, SortBy: "last_name", SortDir: ports.SortAsc}
+	repo.EXPECT().FindAll(gomock.Any(), want).Return(&ports.Page[*domain.Driver]{NextCursor: "abc"}, nil)
+
+	svc := driver.New(repo, contractRepo, assignmentRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), servicetest.AllowAll(ctrl), validator, servicetest.IDGen, time.Now,
+		zaptest.NewLogger(t))
+	page, err := svc.List(t.Context(), ports.ListQuery{SortBy: "last_name"})
+	require.NoError(t, err)
+	assert.Equal(t, "abc", page.NextCursor)
+}
+
 func TestService_Delete_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	repo := mocks.NewMockDriverRepository(ctrl)
 	contractRepo := mocks.NewMockContractRepository(ctrl)
 	assignmentRepo := mocks.NewMockVehicleAssignmentRepository(ctrl)
 
-	contractRepo.EXPECT().FindByDriverID(gomock.Any(), "d1").Return([]*domain.Contract{}, nil)
+	auditLog := mocks.NewMockAuditLog(ctrl)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	before := &domain.Driver{ID: "d1", FirstName: "John"}
+	repo.EXPECT().FindByID(gomock.Any(), "d1").Return(before, nil)
+	contractRepo.EXPECT().FindByDriverID(gomock.Any(), "d1", gomock.Any()).Return(&ports.Page[*domain.Contract]{}, nil)
 	assignmentRepo.EXPECT().FindActiveByDriverID(gomock.Any(), "d1").Return(nil, nil)
-	repo.EXPECT().SoftDelete(gomock.Any(), "d1").Return(nil)
+	repo.EXPECT().SoftDelete(gomock.Any(), "d1", ports.Deletion{ID: "test-id", At: now, By: "alice", Reason: "left company"}).Return(nil)
+	auditLog.EXPECT().Append(gomock.Any(), &domain.AuditEntry{
+		ID: "test-id", EntityType: domain.EntityDriver, EntityID: "d1", Action: domain.AuditDelete,
+		Actor: "alice", At: now, DeletionID: "test-id", Before: *before,
+	}).Return(nil)
 
 	validator := mocks.NewMockDriverLicenseValidator(ctrl)
-	svc := driver.New(repo, contractRepo, assignmentRepo, validator, stubIDGen, time.Now, zaptest.NewLogger(t))
-	err := svc.Delete(t.Context(), "d1")
+	clock := func() time.Time { return now }
+	svc := driver.New(repo, contractRepo, assignmentRepo, servicetest.PassthroughTx(ctrl), auditLog,
+		servicetest.AllowAll(ctrl), validator, servicetest.IDGen, clock, zaptest.NewLogger(t))
+	ctx := domain.ContextWithPrincipal(t.Context(), domain.Principal{ID: "alice"})
+	err := svc.Delete(ctx, "d1", ports.DeleteOptions{Reason: " left company "})
 	require.NoError(t, err)
 }
 
@@ -72,10 +189,13 @@ func TestService_Create_SuccessWhenValidationOk(t *testing.T) {
 	assignmentRepo := mocks.NewMockVehicleAssignmentRepository(ctrl)
 	validator := mocks.NewMockDriverLicenseValidator(ctrl)
 
+	repo.EXPECT().ExistsByLicenseNumber(gomock.Any(), "DL123", "").Return(false, nil)
 	validator.EXPECT().ValidateLicense(gomock.Any(), "John", "Doe", "DL123").Return(domain.LicenseValid, nil)
 	repo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
 
-	svc := driver.New(repo, contractRepo, assignmentRepo, validator, stubIDGen, time.Now, zaptest.NewLogger(t))
+	svc := driver.New(repo, contractRepo, assignmentRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), servicetest.AllowAll(ctrl), validator, servicetest.IDGen, time.Now,
+		zaptest.NewLogger(t))
 	entity, err := svc.Create(t.Context(), "John", "Doe", "DL123")
 	require.NoError(t, err)
 	assert.Equal(t, "test-id", entity.ID)
@@ -91,9 +211,12 @@ func TestService_Create_FailsWhenValidationNotFound(t *testing.T) {
 	assignmentRepo := mocks.NewMockVehicleAssignmentRepository(ctrl)
 	validator := mocks.NewMockDriverLicenseValidator(ctrl)
 
+	repo.EXPECT().ExistsByLicenseNumber(gomock.Any(), "DL999", "").Return(false, nil)
 	validator.EXPECT().ValidateLicense(gomock.Any(), "John", "Doe", "DL999").Return(domain.LicenseNotFound, nil)
 
-	svc := driver.New(repo, contractRepo, assignmentRepo, validator, stubIDGen, time.Now, zaptest.NewLogger(t))
+	svc := driver.New(repo, contractRepo, assignmentRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), servicetest.AllowAll(ctrl), validator, servicetest.IDGen, time.Now,
+		zaptest.NewLogger(t))
 	_, err := svc.Create(t.Context(), "John", "Doe", "DL999")
 	require.Error(t, err)
 	assert.ErrorIs(t, err, domain.ErrLicenseValidationFailed)
@@ -107,10 +230,95 @@ func TestService_Create_FailsWhenValidatorReturnsError(t *testing.T) {
 	assignmentRepo := mocks.NewMockVehicleAssignmentRepository(ctrl)
 	validator := mocks.NewMockDriverLicenseValidator(ctrl)
 
+	repo.EXPECT().ExistsByLicenseNumber(gomock.Any(), "DL123", "").Return(false, nil)
 	validator.EXPECT().ValidateLicense(gomock.Any(), "John", "Doe", "DL123").Return(domain.LicenseValidationResult(""), domain.ErrValidationServiceUnavailable)
 
-	svc := driver.New(repo, contractRepo, assignmentRepo, validator, stubIDGen, time.Now, zaptest.NewLogger(t))
+	svc := driver.New(repo, contractRepo, assignmentRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), servicetest.AllowAll(ctrl), validator, servicetest.IDGen, time.Now,
+		zaptest.NewLogger(t))
 	_, err := svc.Create(t.Context(), "John", "Doe", "DL123")
 	require.Error(t, err)
 	assert.ErrorIs(t, err, domain.ErrValidationServiceUnavailable)
 }
+
+func TestService_Create_RejectsDuplicateLicenseNumber(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockDriverRepository(ctrl)
+	contractRepo := mocks.NewMockContractRepository(ctrl)
+	assignmentRepo := mocks.NewMockVehicleAssignmentRepository(ctrl)
+	validator := mocks.NewMockDriverLicenseValidator(ctrl)
+
+	repo.EXPECT().ExistsByLicenseNumber(gomock.Any(), "DL123", "").Return(true, nil)
+
+	svc := driver.New(repo, contractRepo, assignmentRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.NopAuditLog(ctrl), servicetest.AllowAll(ctrl), validator, servicetest.IDGen, time.Now,
+		zaptest.NewLogger(t))
+	_, err := svc.Create(t.Context(), "John", "Doe", "DL123")
+	require.ErrorIs(t, err, domain.ErrDuplicateValue)
+	var uniqueErr *domain.UniqueViolationError
+	require.ErrorAs(t, err, &uniqueErr)
+	assert.Equal(t, "license_number", uniqueErr.Field)
+}
+
+func TestService_Undelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockDriverRepository(ctrl)
+	deleted := &domain.Driver{ID: "d1", LicenseNumber: "DL123"}
+	repo.EXPECT().FindDeletedByID(gomock.Any(), "d1").Return(deleted, nil)
+	repo.EXPECT().ExistsByLicenseNumber(gomock.Any(), "DL123", "d1").Return(false, nil)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	repo.EXPECT().Undelete(gomock.Any(), "d1", now, "alice").Return(nil)
+	repo.EXPECT().FindByID(gomock.Any(), "d1").Return(&domain.Driver{ID: "d1", LicenseNumber: "DL123"}, nil)
+
+	clock := func() time.Time { return now }
+	svc := driver.New(repo, nil, nil, servicetest.PassthroughTx(ctrl), servicetest.NopAuditLog(ctrl),
+		servicetest.AllowAll(ctrl), nil, servicetest.IDGen, clock, zaptest.NewLogger(t))
+	ctx := domain.ContextWithPrincipal(t.Context(), domain.Principal{ID: "alice"})
+	require.NoError(t, svc.Undelete(ctx, "d1"))
+}
+
+func TestService_Undelete_RejectsReusedLicenseNumber(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockDriverRepository(ctrl)
+	repo.EXPECT().FindDeletedByID(gomock.Any(), "d1").Return(&domain.Driver{ID: "d1", LicenseNumber: "DL123"}, nil)
+	repo.EXPECT().ExistsByLicenseNumber(gomock.Any(), "DL123", "d1").Return(true, nil)
+
+	svc := driver.New(repo, nil, nil, servicetest.PassthroughTx(ctrl), servicetest.NopAuditLog(ctrl),
+		servicetest.AllowAll(ctrl), nil, servicetest.IDGen, time.Now, zaptest.NewLogger(t))
+	err := svc.Undelete(t.Context(), "d1")
+	var uniqueErr *domain.UniqueViolationError
+	require.ErrorAs(t, err, &uniqueErr)
+	assert.Equal(t, "license_number", uniqueErr.Field)
+}
+
+func TestService_Update_RetriesFromTheUnsavedEntity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockDriverRepository(ctrl)
+	repo.EXPECT().FindByID(gomock.Any(), "d1").
+		Return(&domain.Driver{ID: "d1", FirstName: "John", LastName: "Doe", LicenseNumber: "DL-1", Version: 3}, nil)
+	// Like the postgres repository, Save bumps the version it was given.
+	repo.EXPECT().Save(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, e *domain.Driver) error {
+			if e.Version != 3 {
+				return domain.ErrConflict
+			}
+			e.Version++
+			return nil
+		}).
+		Times(2)
+	validator := mocks.NewMockDriverLicenseValidator(ctrl)
+	validator.EXPECT().ValidateLicense(gomock.Any(), "Jane", "Doe", "DL-1").Return(domain.LicenseValid, nil)
+	// The first attempt is rolled back after Save.
+	auditLog := mocks.NewMockAuditLog(ctrl)
+	gomock.InOrder(
+		auditLog.EXPECT().Append(gomock.Any(), gomock.Any()).Return(errSerialization),
+		auditLog.EXPECT().Append(gomock.Any(), gomock.Any()).Return(nil),
+	)
+	svc := driver.New(repo, mocks.NewMockContractRepository(ctrl), mocks.NewMockVehicleAssignmentRepository(ctrl),
+		retryingTx(ctrl), auditLog, servicetest.AllowAll(ctrl), validator, servicetest.IDGen, time.Now, zaptest.NewLogger(t))
+
+	entity, err := svc.Update(t.Context(), "d1", 3, domain.DriverPatch{FirstName: new("Jane")})
+	require.NoError(t, err)
+	assert.Equal(t, "Jane", entity.FirstName)
+	assert.EqualValues(t, 4, entity.Version)
+}
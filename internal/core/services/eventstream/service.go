@@ -0,0 +1,106 @@
+package eventstream
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/logctx"
+)
+
+var tracer = otel.Tracer("github.com/albenik/uber-fx-based-service-example/internal/core/services/eventstream")
+
+// backfillPageSize is the number of past events read from the outbox at a time.
+const backfillPageSize = 100
+
+// Service streams domain events: missed ones from the outbox, then live ones from the feed.
+type Service struct {
+	outbox ports.Outbox
+	feed   ports.EventFeed
+	authz  ports.Authorizer
+	logger *zap.Logger
+}
+
+func New(outbox ports.Outbox, feed ports.EventFeed, authz ports.Authorizer, logger *zap.Logger) *Service {
+	return &Service{outbox: outbox, feed: feed, authz: authz, logger: logger}
+}
+
+// Stream subscribes to the feed before reading the backlog, so events committed meanwhile
+// are not missed; those found in both are sent once. An unknown lastEventID fails with
+// domain.ErrNotFound. Callers allowed to follow the events of some legal entities only
+// must filter the stream by one of them.
+func (s *Service) Stream(ctx context.Context, filter domain.EventFilter, lastEventID string) (<-chan *domain.Event, error) {
+	ctx, span := tracer.Start(ctx, "EventStreamService.Stream")
+	defer span.End()
+
+	err := s.authz.Authorize(ctx, domain.ActionRead, domain.Resource{
+		Type:          domain.ResourceEvents,
+		LegalEntityID: filter.LegalEntityID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	live, err := s.feed.Subscribe(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	var backlog []*domain.Event
+	if lastEventID != "" {
+		// The first page is read right away to report an unknown ID to the caller.
+		if backlog, err = s.outbox.FindAfter(ctx, lastEventID, backfillPageSize); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+
+	out := make(chan *domain.Event)
+	go func() {
+		defer cancel()
+		defer close(out)
+		send := func(e *domain.Event) bool {
+			if !filter.Matches(e) {
+				return true
+			}
+			select {
+			case out <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		seen := make(map[string]struct{})
+		for len(backlog) > 0 {
+			for _, e := range backlog {
+				seen[e.ID] = struct{}{}
+				if !send(e) {
+					return
+				}
+			}
+			if len(backlog) < backfillPageSize {
+				break
+			}
+			var err error
+			if backlog, err = s.outbox.FindAfter(ctx, backlog[len(backlog)-1].ID, backfillPageSize); err != nil {
+				if ctx.Err() == nil {
+					logctx.From(ctx, s.logger).Error("Failed to read event backlog", zap.Error(err))
+				}
+				return
+			}
+		}
+		for e := range live {
+			if _, ok := seen[e.ID]; ok {
+				continue
+			}
+			if !send(e) {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
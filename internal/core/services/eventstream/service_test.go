@@ -0,0 +1,91 @@
+package eventstream_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/eventstream"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/servicetest"
+)
+
+func event(id, fleetID string) *domain.Event {
+	return &domain.Event{
+		ID:      id,
+		Type:    domain.EventAssignmentReturned,
+		Payload: json.RawMessage(`{"fleet_id":"` + fleetID + `","legal_entity_id":"le1"}`),
+	}
+}
+
+func collect(t *testing.T, events <-chan *domain.Event) []string {
+	t.Helper()
+	var ids []string
+	for e := range events {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func TestService_Stream_ResumesThenFollowsFeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	outbox := mocks.NewMockOutbox(ctrl)
+	feed := mocks.NewMockEventFeed(ctrl)
+
+	live := make(chan *domain.Event, 3)
+	feed.EXPECT().Subscribe(gomock.Any()).Return((<-chan *domain.Event)(live), nil)
+	outbox.EXPECT().FindAfter(gomock.Any(), "e0", gomock.Any()).
+		Return([]*domain.Event{event("e1", "f1"), event("e2", "f2")}, nil)
+	// e2 was committed while the backlog was read and arrives from the feed as well.
+	live <- event("e2", "f2")
+	live <- event("e3", "f1")
+	live <- event("e4", "f2")
+	close(live)
+
+	svc := eventstream.New(outbox, feed, servicetest.AllowAll(ctrl), zaptest.NewLogger(t))
+	events, err := svc.Stream(t.Context(), domain.EventFilter{}, "e0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"e1", "e2", "e3", "e4"}, collect(t, events))
+}
+
+func TestService_Stream_FiltersByFleet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	feed := mocks.NewMockEventFeed(ctrl)
+
+	live := make(chan *domain.Event, 3)
+	feed.EXPECT().Subscribe(gomock.Any()).Return((<-chan *domain.Event)(live), nil)
+	live <- event("e1", "f1")
+	live <- event("e2", "f2")
+	live <- &domain.Event{ID: "e3", Payload: domain.ContractEvent{FleetID: "f1"}}
+	close(live)
+
+	svc := eventstream.New(mocks.NewMockOutbox(ctrl), feed, servicetest.AllowAll(ctrl), zaptest.NewLogger(t))
+	events, err := svc.Stream(t.Context(), domain.EventFilter{FleetID: "f1"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"e1", "e3"}, collect(t, events))
+}
+
+func TestService_Stream_UnknownLastEventID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	outbox := mocks.NewMockOutbox(ctrl)
+	feed := mocks.NewMockEventFeed(ctrl)
+
+	var subCtx context.Context
+	feed.EXPECT().Subscribe(gomock.Any()).
+		DoAndReturn(func(ctx context.Context) (<-chan *domain.Event, error) {
+			subCtx = ctx
+			return make(chan *domain.Event), nil
+		})
+	outbox.EXPECT().FindAfter(gomock.Any(), "missing", gomock.Any()).Return(nil, domain.ErrNotFound)
+
+	svc := eventstream.New(outbox, feed, servicetest.AllowAll(ctrl), zaptest.NewLogger(t))
+	_, err := svc.Stream(t.Context(), domain.EventFilter{}, "missing")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Error(t, subCtx.Err(), "the feed subscription must end with the failed stream")
+}
@@ -3,28 +3,70 @@ package fleet
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
+	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
 
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/logctx"
 )
 
+var tracer = otel.Tracer("github.com/albenik/uber-fx-based-service-example/internal/core/services/fleet")
+
 type IDGenerator func() string
 
+type Clock func() time.Time
+
 type Service struct {
 	legalEntityRepo ports.LegalEntityRepository
 	repo            ports.FleetRepository
+	vehicleRepo     ports.VehicleRepository
+	contractRepo    ports.ContractRepository
+	assignmentRepo  ports.VehicleAssignmentRepository
+	tx              ports.TxManager
+	auditLog        ports.AuditLog
+	authz           ports.Authorizer
 	logger          *zap.Logger
 	idGen           IDGenerator
+	clock           Clock
 }
 
-func New(legalEntityRepo ports.LegalEntityRepository, repo ports.FleetRepository, logger *zap.Logger, idGen IDGenerator) *Service {
-	return &Service{legalEntityRepo: legalEntityRepo, repo: repo, logger: logger, idGen: idGen}
+func New(
+	legalEntityRepo ports.LegalEntityRepository,
+	repo ports.FleetRepository,
+	vehicleRepo ports.VehicleRepository,
+	contractRepo ports.ContractRepository,
+	assignmentRepo ports.VehicleAssignmentRepository,
+	tx ports.TxManager,
+	auditLog ports.AuditLog,
+	authz ports.Authorizer,
+	logger *zap.Logger,
+	idGen IDGenerator,
+	clock Clock,
+) *Service {
+	return &Service{
+		legalEntityRepo: legalEntityRepo,
+		repo:            repo,
+		vehicleRepo:     vehicleRepo,
+		contractRepo:    contractRepo,
+		assignmentRepo:  assignmentRepo,
+		tx:              tx,
+		auditLog:        auditLog,
+		authz:           authz,
+		logger:          logger,
+		idGen:           idGen,
+		clock:           clock,
+	}
 }
 
 func (s *Service) Create(ctx context.Context, legalEntityID, name string) (*domain.Fleet, error) {
+	ctx, span := tracer.Start(ctx, "FleetService.Create")
+	defer span.End()
+
 	name = strings.TrimSpace(name)
 	if legalEntityID == "" {
 		return nil, fmt.Errorf("%w: legal_entity_id is required", domain.ErrInvalidInput)
@@ -32,6 +74,9 @@ func (s *Service) Create(ctx context.Context, legalEntityID, name string) (*doma
 	if name == "" {
 		return nil, fmt.Errorf("%w: name is required", domain.ErrInvalidInput)
 	}
+	if err := s.authorize(ctx, domain.ActionCreate, legalEntityID); err != nil {
+		return nil, err
+	}
 	if _, err := s.legalEntityRepo.FindByID(ctx, legalEntityID); err != nil {
 		return nil, err
 	}
@@ -39,40 +84,299 @@ func (s *Service) Create(ctx context.Context, legalEntityID, name string) (*doma
 	if id == "" {
 		return nil, fmt.Errorf("id generator returned empty ID")
 	}
-	entity := &domain.Fleet{ID: id, LegalEntityID: legalEntityID, Name: name}
-	if err := s.repo.Save(ctx, entity); err != nil {
-		s.logger.Error("Failed to save fleet", zap.String("id", id), zap.Error(err))
+	now, actor := s.clock(), domain.PrincipalFromContext(ctx).ID
+	entity := &domain.Fleet{
+		ID: id, LegalEntityID: legalEntityID, Name: name,
+		CreatedAt: now, CreatedBy: actor, UpdatedAt: now, UpdatedBy: actor,
+	}
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Save(ctx, entity); err != nil {
+			return err
+		}
+		return s.record(ctx, domain.AuditCreate, id, nil, *entity)
+	})
+	if err != nil {
+		logctx.From(ctx, s.logger).Error("Failed to save fleet", zap.String("id", id), zap.Error(err))
 		return nil, err
 	}
-	s.logger.Info("Created fleet", zap.String("id", id))
+	logctx.From(ctx, s.logger).Info("Created fleet", zap.String("id", id))
 	result := *entity
 	return &result, nil
 }
 
 func (s *Service) Get(ctx context.Context, id string) (*domain.Fleet, error) {
+	ctx, span := tracer.Start(ctx, "FleetService.Get")
+	defer span.End()
+
 	if id == "" {
 		return nil, fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
 	}
-	return s.repo.FindByID(ctx, id)
+	entity, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, domain.ActionRead, entity.LegalEntityID); err != nil {
+		return nil, err
+	}
+	return entity, nil
 }
 
-func (s *Service) ListByLegalEntity(ctx context.Context, legalEntityID string) ([]*domain.Fleet, error) {
+func (s *Service) ListByLegalEntity(ctx context.Context, legalEntityID string, q ports.ListQuery) (*ports.Page[*domain.Fleet], error) {
+	ctx, span := tracer.Start(ctx, "FleetService.ListByLegalEntity")
+	defer span.End()
+
 	if legalEntityID == "" {
 		return nil, fmt.Errorf("%w: legal_entity_id is required", domain.ErrInvalidInput)
 	}
-	return s.repo.FindByLegalEntityID(ctx, legalEntityID)
+	if err := s.authorize(ctx, domain.ActionRead, legalEntityID); err != nil {
+		return nil, err
+	}
+	q, err := q.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindByLegalEntityID(ctx, legalEntityID, q)
+}
+
+func (s *Service) Update(ctx context.Context, id string, version int64, patch domain.FleetPatch) (*domain.Fleet, error) {
+	ctx, span := tracer.Start(ctx, "FleetService.Update")
+	defer span.End()
+
+	if id == "" {
+		return nil, fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
+	}
+	if patch.Name == nil {
+		return nil, fmt.Errorf("%w: nothing to update", domain.ErrInvalidInput)
+	}
+	name := strings.TrimSpace(*patch.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", domain.ErrInvalidInput)
+	}
+	entity, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, domain.ActionUpdate, entity.LegalEntityID); err != nil {
+		return nil, err
+	}
+	if version != 0 && entity.Version != version {
+		return nil, domain.ErrPreconditionFailed
+	}
+	before := *entity
+	entity.Name = name
+	entity.UpdatedAt, entity.UpdatedBy = s.clock(), domain.PrincipalFromContext(ctx).ID
+	unsaved := *entity
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		*entity = unsaved
+		if err := s.repo.Save(ctx, entity); err != nil {
+			return err
+		}
+		return s.record(ctx, domain.AuditUpdate, id, before, *entity)
+	})
+	if err != nil {
+		logctx.From(ctx, s.logger).Error("Failed to save fleet", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+	logctx.From(ctx, s.logger).Info("Updated fleet", zap.String("id", id))
+	result := *entity
+	return &result, nil
 }
 
-func (s *Service) Delete(ctx context.Context, id string) error {
+func (s *Service) Delete(ctx context.Context, id string, opts ports.DeleteOptions) error {
+	ctx, span := tracer.Start(ctx, "FleetService.Delete")
+	defer span.End()
+
 	if id == "" {
 		return fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
 	}
-	return s.repo.SoftDelete(ctx, id)
+	d := ports.Deletion{
+		ID:     s.idGen(),
+		At:     s.clock(),
+		By:     domain.PrincipalFromContext(ctx).ID,
+		Reason: strings.TrimSpace(opts.Reason),
+	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.authorize(ctx, domain.ActionDelete, before.LegalEntityID); err != nil {
+			return err
+		}
+		// Checked before the cascade: it must not end contracts or assignments silently.
+		hasContracts, err := s.contractRepo.ExistsActiveByFleetID(ctx, id, d.At)
+		if err != nil {
+			return err
+		}
+		if hasContracts {
+			return domain.ErrFleetHasActiveContracts
+		}
+		hasAssignments, err := s.assignmentRepo.ExistsActiveByFleetID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if hasAssignments {
+			return domain.ErrFleetHasActiveAssignments
+		}
+		if opts.Cascade {
+			if err := s.deleteCascade(ctx, id, d); err != nil {
+				return err
+			}
+			return s.appendAudit(ctx, &domain.AuditEntry{
+				EntityType: domain.EntityFleet,
+				EntityID:   id,
+				Action:     domain.AuditDelete,
+				DeletionID: d.ID,
+				Before:     *before,
+			})
+		}
+		hasVehicles, err := s.vehicleRepo.ExistsByFleetID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if hasVehicles {
+			return domain.ErrFleetHasVehicles
+		}
+		if err := s.repo.SoftDelete(ctx, id, d); err != nil {
+			return err
+		}
+		return s.appendAudit(ctx, &domain.AuditEntry{
+			EntityType: domain.EntityFleet,
+			EntityID:   id,
+			Action:     domain.AuditDelete,
+			DeletionID: d.ID,
+			Before:     *before,
+		})
+	})
+}
+
+// deleteCascade soft-deletes the fleet together with its vehicles, contracts and vehicle
+// assignments, none of which is active.
+func (s *Service) deleteCascade(ctx context.Context, id string, d ports.Deletion) error {
+	if err := s.repo.SoftDelete(ctx, id, d); err != nil {
+		return err
+	}
+	assignments, err := s.assignmentRepo.SoftDeleteByFleetID(ctx, id, d)
+	if err != nil {
+		return err
+	}
+	contracts, err := s.contractRepo.SoftDeleteByFleetID(ctx, id, d)
+	if err != nil {
+		return err
+	}
+	vehicles, err := s.vehicleRepo.SoftDeleteByFleetID(ctx, id, d)
+	if err != nil {
+		return err
+	}
+	err = s.appendAudit(ctx, slices.Concat(
+		domain.DeletionEntries(domain.EntityVehicle, domain.AuditDelete, d.ID, vehicles, vehicleID),
+		domain.DeletionEntries(domain.EntityContract, domain.AuditDelete, d.ID, contracts, contractID),
+		domain.DeletionEntries(domain.EntityVehicleAssignment, domain.AuditDelete, d.ID, assignments, assignmentID),
+	)...)
+	if err != nil {
+		return err
+	}
+	logctx.From(ctx, s.logger).Info("Deleted fleet with dependents", zap.String("id", id))
+	return nil
 }
 
-func (s *Service) Undelete(ctx context.Context, id string) error {
+func (s *Service) Undelete(ctx context.Context, id string, opts ports.UndeleteOptions) error {
+	ctx, span := tracer.Start(ctx, "FleetService.Undelete")
+	defer span.End()
+
 	if id == "" {
 		return fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
 	}
-	return s.repo.Undelete(ctx, id)
+	at, by := s.clock(), domain.PrincipalFromContext(ctx).ID
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		entity, err := s.repo.FindDeletedByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.authorize(ctx, domain.ActionDelete, entity.LegalEntityID); err != nil {
+			return err
+		}
+		if _, err := s.legalEntityRepo.FindByID(ctx, entity.LegalEntityID); err != nil {
+			return domain.ParentError(err, "legal entity", entity.LegalEntityID)
+		}
+		if err := s.repo.Undelete(ctx, id, at, by); err != nil {
+			return err
+		}
+		if opts.Cascade && entity.DeletionID != "" {
+			if err := s.undeleteCascade(ctx, id, entity.DeletionID, at, by); err != nil {
+				return err
+			}
+		}
+		after, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.appendAudit(ctx, &domain.AuditEntry{
+			EntityType: domain.EntityFleet,
+			EntityID:   id,
+			Action:     domain.AuditUndelete,
+			DeletionID: entity.DeletionID,
+			After:      *after,
+		})
+	})
+}
+
+// undeleteCascade restores the vehicles, contracts and vehicle assignments that were deleted
+// together with the fleet, parents before children.
+func (s *Service) undeleteCascade(ctx context.Context, id, deletionID string, at time.Time, by string) error {
+	vehicles, err := s.vehicleRepo.UndeleteByFleetID(ctx, id, deletionID, at, by)
+	if err != nil {
+		return err
+	}
+	contracts, err := s.contractRepo.UndeleteByFleetID(ctx, id, deletionID, at, by)
+	if err != nil {
+		return err
+	}
+	assignments, err := s.assignmentRepo.UndeleteByFleetID(ctx, id, deletionID, at, by)
+	if err != nil {
+		return err
+	}
+	err = s.appendAudit(ctx, slices.Concat(
+		domain.DeletionEntries(domain.EntityVehicle, domain.AuditUndelete, deletionID, vehicles, vehicleID),
+		domain.DeletionEntries(domain.EntityContract, domain.AuditUndelete, deletionID, contracts, contractID),
+		domain.DeletionEntries(domain.EntityVehicleAssignment, domain.AuditUndelete, deletionID, assignments, assignmentID),
+	)...)
+	if err != nil {
+		return err
+	}
+	logctx.From(ctx, s.logger).Info("Restored fleet with dependents", zap.String("id", id))
+	return nil
+}
+
+// IDs of dependents, for domain.DeletionEntries.
+func vehicleID(v *domain.Vehicle) string              { return v.ID }
+func contractID(c *domain.Contract) string            { return c.ID }
+func assignmentID(a *domain.VehicleAssignment) string { return a.ID }
+
+// authorize checks the principal may act on the fleets of the given legal entity.
+func (s *Service) authorize(ctx context.Context, action domain.Action, legalEntityID string) error {
+	return s.authz.Authorize(ctx, action, domain.Resource{Type: domain.EntityFleet, LegalEntityID: legalEntityID})
+}
+
+// record appends an audit entry for a change of the fleet with the given ID.
+func (s *Service) record(ctx context.Context, action domain.AuditAction, id string, before, after any) error {
+	return s.appendAudit(ctx, &domain.AuditEntry{
+		EntityType: domain.EntityFleet,
+		EntityID:   id,
+		Action:     action,
+		Before:     before,
+		After:      after,
+	})
+}
+
+// appendAudit sets the ID, actor and time of the entries and appends them to the audit log.
+func (s *Service) appendAudit(ctx context.Context, entries ...*domain.AuditEntry) error {
+	actor, at := domain.PrincipalFromContext(ctx).ID, s.clock()
+	for _, e := range entries {
+		e.ID, e.Actor, e.At = s.idGen(), actor, at
+		if err := s.auditLog.Append(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
 }
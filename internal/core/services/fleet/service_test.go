@@ -0,0 +1,180 @@
+package fleet_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/fleet"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/servicetest"
+)
+
+type serviceMocks struct {
+	legalEntityRepo *mocks.MockLegalEntityRepository
+	repo            *mocks.MockFleetRepository
+	vehicleRepo     *mocks.MockVehicleRepository
+	contractRepo    *mocks.MockContractRepository
+	assignmentRepo  *mocks.MockVehicleAssignmentRepository
+	// audit collects the entries appended to the audit log.
+	audit []*domain.AuditEntry
+}
+
+func newService(t *testing.T) (*fleet.Service, *serviceMocks) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	m := &serviceMocks{
+		legalEntityRepo: mocks.NewMockLegalEntityRepository(ctrl),
+		repo:            mocks.NewMockFleetRepository(ctrl),
+		vehicleRepo:     mocks.NewMockVehicleRepository(ctrl),
+		contractRepo:    mocks.NewMockContractRepository(ctrl),
+		assignmentRepo:  mocks.NewMockVehicleAssignmentRepository(ctrl),
+	}
+	svc := fleet.New(m.legalEntityRepo, m.repo, m.vehicleRepo, m.contractRepo, m.assignmentRepo,
+		servicetest.PassthroughTx(ctrl), servicetest.CollectingAuditLog(ctrl, &m.audit), servicetest.AllowAll(ctrl),
+		zaptest.NewLogger(t), servicetest.IDGen, servicetest.Clock)
+	return svc, m
+}
+
+// expectNoActive expects the checks for active contracts and assignments a delete runs
+// first, and finds none.
+func expectNoActive(m *serviceMocks, id string) {
+	m.contractRepo.EXPECT().ExistsActiveByFleetID(gomock.Any(), id, servicetest.Now).Return(false, nil)
+	m.assignmentRepo.EXPECT().ExistsActiveByFleetID(gomock.Any(), id).Return(false, nil)
+}
+
+func TestService_Delete_RejectsWhenVehiclesExist(t *testing.T) {
+	svc, m := newService(t)
+
+	m.repo.EXPECT().FindByID(gomock.Any(), "f1").Return(&domain.Fleet{ID: "f1", LegalEntityID: "le1"}, nil)
+	expectNoActive(m, "f1")
+	m.vehicleRepo.EXPECT().ExistsByFleetID(gomock.Any(), "f1").Return(true, nil)
+
+	err := svc.Delete(t.Context(), "f1", ports.DeleteOptions{})
+	assert.ErrorIs(t, err, domain.ErrFleetHasVehicles)
+	assert.Empty(t, m.audit)
+}
+
+func TestService_Delete_RejectsWhenActive(t *testing.T) {
+	for _, cascade := range []bool{false, true} {
+		t.Run("cascade="+strconv.FormatBool(cascade), func(t *testing.T) {
+			t.Run("contracts", func(t *testing.T) {
+				svc, m := newService(t)
+				m.repo.EXPECT().FindByID(gomock.Any(), "f1").Return(&domain.Fleet{ID: "f1", LegalEntityID: "le1"}, nil)
+				m.contractRepo.EXPECT().ExistsActiveByFleetID(gomock.Any(), "f1", servicetest.Now).Return(true, nil)
+
+				err := svc.Delete(t.Context(), "f1", ports.DeleteOptions{Cascade: cascade})
+				assert.ErrorIs(t, err, domain.ErrFleetHasActiveContracts)
+				assert.Empty(t, m.audit)
+			})
+			t.Run("assignments", func(t *testing.T) {
+				svc, m := newService(t)
+				m.repo.EXPECT().FindByID(gomock.Any(), "f1").Return(&domain.Fleet{ID: "f1", LegalEntityID: "le1"}, nil)
+				m.contractRepo.EXPECT().ExistsActiveByFleetID(gomock.Any(), "f1", servicetest.Now).Return(false, nil)
+				m.assignmentRepo.EXPECT().ExistsActiveByFleetID(gomock.Any(), "f1").Return(true, nil)
+
+				err := svc.Delete(t.Context(), "f1", ports.DeleteOptions{Cascade: cascade})
+				assert.ErrorIs(t, err, domain.ErrFleetHasActiveAssignments)
+				assert.Empty(t, m.audit)
+			})
+		})
+	}
+}
+
+func TestService_Delete_Success(t *testing.T) {
+	svc, m := newService(t)
+
+	before := &domain.Fleet{ID: "f1", LegalEntityID: "le1", Name: "North"}
+	m.repo.EXPECT().FindByID(gomock.Any(), "f1").Return(before, nil)
+	expectNoActive(m, "f1")
+	m.vehicleRepo.EXPECT().ExistsByFleetID(gomock.Any(), "f1").Return(false, nil)
+	m.repo.EXPECT().SoftDelete(gomock.Any(), "f1", servicetest.Deletion).Return(nil)
+
+	require.NoError(t, svc.Delete(t.Context(), "f1", ports.DeleteOptions{}))
+	require.Len(t, m.audit, 1)
+	assert.Equal(t, domain.AuditDelete, m.audit[0].Action)
+	assert.Equal(t, *before, m.audit[0].Before)
+}
+
+func TestService_Delete_Cascade(t *testing.T) {
+	svc, m := newService(t)
+
+	m.repo.EXPECT().FindByID(gomock.Any(), "f1").Return(&domain.Fleet{ID: "f1", LegalEntityID: "le1"}, nil)
+	expectNoActive(m, "f1")
+	gomock.InOrder(
+		m.repo.EXPECT().SoftDelete(gomock.Any(), "f1", servicetest.Deletion).Return(nil),
+		m.assignmentRepo.EXPECT().SoftDeleteByFleetID(gomock.Any(), "f1", servicetest.Deletion).
+			Return([]*domain.VehicleAssignment{{ID: "a1"}}, nil),
+		m.contractRepo.EXPECT().SoftDeleteByFleetID(gomock.Any(), "f1", servicetest.Deletion).Return(nil, nil),
+		m.vehicleRepo.EXPECT().SoftDeleteByFleetID(gomock.Any(), "f1", servicetest.Deletion).
+			Return([]*domain.Vehicle{{ID: "v1"}, {ID: "v2"}}, nil),
+	)
+
+	require.NoError(t, svc.Delete(t.Context(), "f1", ports.DeleteOptions{Cascade: true}))
+	require.Len(t, m.audit, 4)
+	want := []string{"vehicle v1", "vehicle v2", "vehicle_assignment a1", "fleet f1"}
+	for i, e := range m.audit {
+		assert.Equal(t, want[i], string(e.EntityType)+" "+e.EntityID)
+		assert.Equal(t, domain.AuditDelete, e.Action)
+		assert.Equal(t, "test-id", e.DeletionID)
+	}
+	assert.Equal(t, domain.Vehicle{ID: "v1"}, m.audit[0].Before)
+}
+
+func TestService_Undelete_RejectsWhenLegalEntityDeleted(t *testing.T) {
+	svc, m := newService(t)
+
+	m.repo.EXPECT().FindDeletedByID(gomock.Any(), "f1").Return(&domain.Fleet{ID: "f1", LegalEntityID: "le1"}, nil)
+	m.legalEntityRepo.EXPECT().FindByID(gomock.Any(), "le1").Return(nil, domain.ErrNotFound)
+
+	err := svc.Undelete(t.Context(), "f1", ports.UndeleteOptions{})
+	var parentErr *domain.ParentDeletedError
+	require.ErrorAs(t, err, &parentErr)
+	assert.Equal(t, domain.ParentDeletedError{Parent: "legal entity", ID: "le1"}, *parentErr)
+	assert.Empty(t, m.audit)
+}
+
+func TestService_Undelete_Cascade(t *testing.T) {
+	svc, m := newService(t)
+
+	m.repo.EXPECT().FindDeletedByID(gomock.Any(), "f1").
+		Return(&domain.Fleet{ID: "f1", LegalEntityID: "le1", DeletionID: "d1"}, nil)
+	m.legalEntityRepo.EXPECT().FindByID(gomock.Any(), "le1").Return(&domain.LegalEntity{ID: "le1"}, nil)
+	gomock.InOrder(
+		m.repo.EXPECT().Undelete(gomock.Any(), "f1", servicetest.Now, "").Return(nil),
+		m.vehicleRepo.EXPECT().UndeleteByFleetID(gomock.Any(), "f1", "d1", servicetest.Now, "").Return(nil, nil),
+		m.contractRepo.EXPECT().UndeleteByFleetID(gomock.Any(), "f1", "d1", servicetest.Now, "").
+			Return([]*domain.Contract{{ID: "c1"}}, nil),
+		m.assignmentRepo.EXPECT().UndeleteByFleetID(gomock.Any(), "f1", "d1", servicetest.Now, "").Return(nil, nil),
+	)
+	m.repo.EXPECT().FindByID(gomock.Any(), "f1").Return(&domain.Fleet{ID: "f1", LegalEntityID: "le1"}, nil)
+
+	require.NoError(t, svc.Undelete(t.Context(), "f1", ports.UndeleteOptions{Cascade: true}))
+	require.Len(t, m.audit, 2)
+	want := []string{"contract c1", "fleet f1"}
+	for i, e := range m.audit {
+		assert.Equal(t, want[i], string(e.EntityType)+" "+e.EntityID)
+		assert.Equal(t, domain.AuditUndelete, e.Action)
+		assert.Equal(t, "d1", e.DeletionID)
+	}
+	assert.Equal(t, domain.Contract{ID: "c1"}, m.audit[0].After)
+}
+
+func TestService_Undelete_WithoutCascade(t *testing.T) {
+	svc, m := newService(t)
+
+	m.repo.EXPECT().FindDeletedByID(gomock.Any(), "f1").
+		Return(&domain.Fleet{ID: "f1", LegalEntityID: "le1", DeletionID: "d1"}, nil)
+	m.legalEntityRepo.EXPECT().FindByID(gomock.Any(), "le1").Return(&domain.LegalEntity{ID: "le1"}, nil)
+	m.repo.EXPECT().Undelete(gomock.Any(), "f1", servicetest.Now, "").Return(nil)
+	m.repo.EXPECT().FindByID(gomock.Any(), "f1").Return(&domain.Fleet{ID: "f1", LegalEntityID: "le1"}, nil)
+
+	require.NoError(t, svc.Undelete(t.Context(), "f1", ports.UndeleteOptions{}))
+	require.Len(t, m.audit, 1)
+}
@@ -7,12 +7,19 @@ import (
 	"go.uber.org/fx"
 
 	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/apikey"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/services/assignment"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/audit"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/authz"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/services/contract"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/services/driver"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/eventstream"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/services/fleet"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/idempotency"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/services/legalentity"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/outbox"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/services/vehicle"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/webhook"
 )
 
 // Module provides core business logic services.
@@ -26,11 +33,23 @@ func Module() fx.Option {
 			func() driver.IDGenerator { return uuid.NewString },
 			func() contract.IDGenerator { return uuid.NewString },
 			func() assignment.IDGenerator { return uuid.NewString },
+			func() webhook.IDGenerator { return uuid.NewString },
+			func() legalentity.Clock { return time.Now },
+			func() fleet.Clock { return time.Now },
+			func() vehicle.Clock { return time.Now },
 			func() driver.Clock { return time.Now },
 			func() contract.Clock { return time.Now },
 			func() assignment.Clock { return time.Now },
+			func() outbox.Clock { return time.Now },
+			func() webhook.Clock { return time.Now },
+			func() apikey.Clock { return time.Now },
+			func() idempotency.Clock { return time.Now },
 		),
 		fx.Provide(
+			fx.Annotate(
+				authz.New,
+				fx.As(new(ports.Authorizer)),
+			),
 			fx.Annotate(
 				legalentity.New,
 				fx.As(new(ports.LegalEntityService)),
@@ -55,6 +74,32 @@ func Module() fx.Option {
 				assignment.New,
 				fx.As(new(ports.VehicleAssignmentService)),
 			),
+			fx.Annotate(
+				audit.New,
+				fx.As(new(ports.AuditService)),
+			),
+			fx.Annotate(
+				outbox.New,
+				fx.ParamTags(``, `group:"event_publishers"`),
+				fx.As(new(ports.EventRelay)),
+			),
+			fx.Annotate(
+				eventstream.New,
+				fx.As(new(ports.EventStreamService)),
+			),
+			fx.Annotate(
+				webhook.New,
+				fx.As(new(ports.WebhookService)),
+				fx.As(new(ports.WebhookDispatcher)),
+			),
+			fx.Annotate(
+				apikey.New,
+				fx.As(new(ports.APIKeyService)),
+			),
+			fx.Annotate(
+				idempotency.New,
+				fx.As(new(ports.IdempotencyService)),
+			),
 		),
 	)
 }
@@ -0,0 +1,87 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+)
+
+var tracer = otel.Tracer("github.com/albenik/uber-fx-based-service-example/internal/core/services/idempotency")
+
+const (
+	// maxKeyLength bounds the keys clients may choose.
+	maxKeyLength = 255
+	// ttl is how long a response is kept for retries.
+	ttl = 24 * time.Hour
+	// lockTimeout is how long a key stays claimed by a request that neither completed
+	// nor released it, e.g. because the instance processing it crashed. It must cover
+	// processing a request.
+	lockTimeout = time.Minute
+	// purgeBatchSize is the number of expired keys removed by one PurgeExpired call.
+	purgeBatchSize = 500
+)
+
+type Clock func() time.Time
+
+// Service remembers the responses to requests made with an idempotency key.
+type Service struct {
+	repo  ports.IdempotencyRepository
+	clock Clock
+}
+
+func New(repo ports.IdempotencyRepository, clock Clock) *Service {
+	return &Service{repo: repo, clock: clock}
+}
+
+func (s *Service) Begin(ctx context.Context, key, fingerprint string) (*domain.IdempotentResponse, error) {
+	ctx, span := tracer.Start(ctx, "IdempotencyService.Begin")
+	defer span.End()
+
+	if key == "" || len(key) > maxKeyLength {
+		return nil, fmt.Errorf("%w: idempotency key must have 1 to %d characters", domain.ErrInvalidInput, maxKeyLength)
+	}
+	now := s.clock()
+	existing, err := s.repo.Claim(ctx, &domain.IdempotencyRecord{
+		PrincipalID: domain.PrincipalFromContext(ctx).ID,
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(lockTimeout),
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case existing == nil:
+		return nil, nil
+	case existing.Fingerprint != fingerprint:
+		return nil, domain.ErrIdempotencyKeyReused
+	case existing.Response == nil:
+		return nil, fmt.Errorf("%w: a request with this idempotency key is in progress", domain.ErrConflict)
+	default:
+		return existing.Response, nil
+	}
+}
+
+func (s *Service) Complete(ctx context.Context, key string, resp *domain.IdempotentResponse) error {
+	ctx, span := tracer.Start(ctx, "IdempotencyService.Complete")
+	defer span.End()
+
+	return s.repo.SaveResponse(ctx, domain.PrincipalFromContext(ctx).ID, key, resp, s.clock().Add(ttl))
+}
+
+func (s *Service) Release(ctx context.Context, key string) error {
+	ctx, span := tracer.Start(ctx, "IdempotencyService.Release")
+	defer span.End()
+
+	return s.repo.Delete(ctx, domain.PrincipalFromContext(ctx).ID, key)
+}
+
+func (s *Service) PurgeExpired(ctx context.Context) (int, error) {
+	return s.repo.DeleteExpired(ctx, s.clock(), purgeBatchSize)
+}
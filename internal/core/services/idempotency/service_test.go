@@ -0,0 +1,71 @@
+package idempotency_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/idempotency"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/servicetest"
+)
+
+func TestService_Begin(t *testing.T) {
+	stored := &domain.IdempotentResponse{StatusCode: 201, Body: []byte(`{"id":"d1"}`)}
+	tests := []struct {
+		name     string
+		existing *domain.IdempotencyRecord
+		want     *domain.IdempotentResponse
+		wantErr  error
+	}{
+		{"first request", nil, nil, nil},
+		{"retry", &domain.IdempotencyRecord{Fingerprint: "fp1", Response: stored}, stored, nil},
+		{"retry while in progress", &domain.IdempotencyRecord{Fingerprint: "fp1"}, nil, domain.ErrConflict},
+		{"key reused", &domain.IdempotencyRecord{Fingerprint: "fp2", Response: stored}, nil, domain.ErrIdempotencyKeyReused},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockIdempotencyRepository(gomock.NewController(t))
+			repo.EXPECT().Claim(gomock.Any(), gomock.Any(), servicetest.Now).
+				DoAndReturn(func(_ any, rec *domain.IdempotencyRecord, _ time.Time) (*domain.IdempotencyRecord, error) {
+					assert.Equal(t, "user-1", rec.PrincipalID)
+					assert.Equal(t, "k1", rec.Key)
+					assert.Equal(t, "fp1", rec.Fingerprint)
+					assert.True(t, rec.ExpiresAt.After(servicetest.Now))
+					return tt.existing, nil
+				})
+
+			ctx := domain.ContextWithPrincipal(t.Context(), domain.Principal{ID: "user-1"})
+			resp, err := idempotency.New(repo, servicetest.Clock).Begin(ctx, "k1", "fp1")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, resp)
+		})
+	}
+}
+
+func TestService_Begin_InvalidKey(t *testing.T) {
+	svc := idempotency.New(mocks.NewMockIdempotencyRepository(gomock.NewController(t)), servicetest.Clock)
+
+	_, err := svc.Begin(t.Context(), "", "fp1")
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	_, err = svc.Begin(t.Context(), strings.Repeat("k", 256), "fp1")
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+}
+
+func TestService_Complete_KeepsResponseForTTL(t *testing.T) {
+	repo := mocks.NewMockIdempotencyRepository(gomock.NewController(t))
+	resp := &domain.IdempotentResponse{StatusCode: 201}
+	repo.EXPECT().SaveResponse(gomock.Any(), "user-1", "k1", resp, servicetest.Now.Add(24*time.Hour)).Return(nil)
+
+	ctx := domain.ContextWithPrincipal(t.Context(), domain.Principal{ID: "user-1"})
+	require.NoError(t, idempotency.New(repo, servicetest.Clock).Complete(ctx, "k1", resp))
+}
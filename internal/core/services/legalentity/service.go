@@ -3,27 +3,73 @@ package legalentity
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
+	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
 
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/logctx"
 )
 
+var tracer = otel.Tracer("github.com/albenik/uber-fx-based-service-example/internal/core/services/legalentity")
+
 type IDGenerator func() string
 
+type Clock func() time.Time
+
 type Service struct {
-	repo   ports.LegalEntityRepository
-	logger *zap.Logger
-	idGen  IDGenerator
+	repo           ports.LegalEntityRepository
+	fleetRepo      ports.FleetRepository
+	vehicleRepo    ports.VehicleRepository
+	contractRepo   ports.ContractRepository
+	assignmentRepo ports.VehicleAssignmentRepository
+	tx             ports.TxManager
+	auditLog       ports.AuditLog
+	authz          ports.Authorizer
+	logger         *zap.Logger
+	idGen          IDGenerator
+	clock          Clock
 }
 
-func New(repo ports.LegalEntityRepository, logger *zap.Logger, idGen IDGenerator) *Service {
-	return &Service{repo: repo, logger: logger, idGen: idGen}
+func New(
+	repo ports.LegalEntityRepository,
+	fleetRepo ports.FleetRepository,
+	vehicleRepo ports.VehicleRepository,
+	contractRepo ports.ContractRepository,
+	assignmentRepo ports.VehicleAssignmentRepository,
+	tx ports.TxManager,
+	auditLog ports.AuditLog,
+	authz ports.Authorizer,
+	logger *zap.Logger,
+	idGen IDGenerator,
+	clock Clock,
+) *Service {
+	return &Service{
+		repo:           repo,
+		fleetRepo:      fleetRepo,
+		vehicleRepo:    vehicleRepo,
+		contractRepo:   contractRepo,
+		assignmentRepo: assignmentRepo,
+		tx:             tx,
+		auditLog:       auditLog,
+		authz:          authz,
+		logger:         logger,
+		idGen:          idGen,
+		clock:          clock,
+	}
 }
 
 func (s *Service) Create(ctx context.Context, name, taxID string) (*domain.LegalEntity, error) {
+	ctx, span := tracer.Start(ctx, "LegalEntityService.Create")
+	defer span.End()
+
+	if err := s.authorize(ctx, domain.ActionCreate, ""); err != nil {
+		return nil, err
+	}
 	name = strings.TrimSpace(name)
 	taxID = strings.TrimSpace(taxID)
 	if name == "" {
@@ -32,41 +78,342 @@ func (s *Service) Create(ctx context.Context, name, taxID string) (*domain.Legal
 	if taxID == "" {
 		return nil, fmt.Errorf("%w: tax_id is required", domain.ErrInvalidInput)
 	}
+	taken, err := s.repo.ExistsByTaxID(ctx, taxID, "")
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, &domain.UniqueViolationError{Field: "tax_id"}
+	}
 	id := s.idGen()
 	if id == "" {
 		return nil, fmt.Errorf("id generator returned empty ID")
 	}
-	entity := &domain.LegalEntity{ID: id, Name: name, TaxID: taxID}
-	if err := s.repo.Save(ctx, entity); err != nil {
-		s.logger.Error("Failed to save legal entity", zap.String("id", id), zap.Error(err))
+	now, actor := s.clock(), domain.PrincipalFromContext(ctx).ID
+	entity := &domain.LegalEntity{
+		ID: id, Name: name, TaxID: taxID,
+		CreatedAt: now, CreatedBy: actor, UpdatedAt: now, UpdatedBy: actor,
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Save(ctx, entity); err != nil {
+			return err
+		}
+		return s.record(ctx, domain.AuditCreate, id, nil, *entity)
+	})
+	if err != nil {
+		logctx.From(ctx, s.logger).Error("Failed to save legal entity", zap.String("id", id), zap.Error(err))
 		return nil, err
 	}
-	s.logger.Info("Created legal entity", zap.String("id", id))
+	logctx.From(ctx, s.logger).Info("Created legal entity", zap.String("id", id))
 	result := *entity
 	return &result, nil
 }
 
 func (s *Service) Get(ctx context.Context, id string) (*domain.LegalEntity, error) {
+	ctx, span := tracer.Start(ctx, "LegalEntityService.Get")
+	defer span.End()
+
 	if id == "" {
 		return nil, fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
 	}
+	if err := s.authorize(ctx, domain.ActionRead, id); err != nil {
+		return nil, err
+	}
 	return s.repo.FindByID(ctx, id)
 }
 
-func (s *Service) List(ctx context.Context) ([]*domain.LegalEntity, error) {
-	return s.repo.FindAll(ctx)
+func (s *Service) List(ctx context.Context, q ports.ListQuery) (*ports.Page[*domain.LegalEntity], error) {
+	ctx, span := tracer.Start(ctx, "LegalEntityService.List")
+	defer span.End()
+
+	if err := s.authorize(ctx, domain.ActionRead, ""); err != nil {
+		return nil, err
+	}
+	q, err := q.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindAll(ctx, q)
+}
+
+func (s *Service) Update(
+	ctx context.Context,
+	id string,
+	version int64,
+	patch domain.LegalEntityPatch,
+) (*domain.LegalEntity, error) {
+	ctx, span := tracer.Start(ctx, "LegalEntityService.Update")
+	defer span.End()
+
+	if id == "" {
+		return nil, fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
+	}
+	if patch.Name == nil && patch.TaxID == nil {
+		return nil, fmt.Errorf("%w: nothing to update", domain.ErrInvalidInput)
+	}
+	if err := s.authorize(ctx, domain.ActionUpdate, id); err != nil {
+		return nil, err
+	}
+	entity, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if version != 0 && entity.Version != version {
+		return nil, domain.ErrPreconditionFailed
+	}
+	before := *entity
+	if patch.Name != nil {
+		entity.Name = strings.TrimSpace(*patch.Name)
+		if entity.Name == "" {
+			return nil, fmt.Errorf("%w: name is required", domain.ErrInvalidInput)
+		}
+	}
+	if patch.TaxID != nil && strings.TrimSpace(*patch.TaxID) != entity.TaxID {
+		entity.TaxID = strings.TrimSpace(*patch.TaxID)
+		if entity.TaxID == "" {
+			return nil, fmt.Errorf("%w: tax_id is required", domain.ErrInvalidInput)
+		}
+		taken, err := s.repo.ExistsByTaxID(ctx, entity.TaxID, entity.ID)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			return nil, &domain.UniqueViolationError{Field: "tax_id"}
+		}
+	}
+	entity.UpdatedAt, entity.UpdatedBy = s.clock(), domain.PrincipalFromContext(ctx).ID
+	unsaved := *entity
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		*entity = unsaved
+		if err := s.repo.Save(ctx, entity); err != nil {
+			return err
+		}
+		return s.record(ctx, domain.AuditUpdate, id, before, *entity)
+	})
+	if err != nil {
+		logctx.From(ctx, s.logger).Error("Failed to save legal entity", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+	logctx.From(ctx, s.logger).Info("Updated legal entity", zap.String("id", id))
+	result := *entity
+	return &result, nil
 }
 
-func (s *Service) Delete(ctx context.Context, id string) error {
+func (s *Service) Delete(ctx context.Context, id string, opts ports.DeleteOptions) error {
+	ctx, span := tracer.Start(ctx, "LegalEntityService.Delete")
+	defer span.End()
+
 	if id == "" {
 		return fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
 	}
-	return s.repo.SoftDelete(ctx, id)
+	if err := s.authorize(ctx, domain.ActionDelete, id); err != nil {
+		return err
+	}
+	d := ports.Deletion{
+		ID:     s.idGen(),
+		At:     s.clock(),
+		By:     domain.PrincipalFromContext(ctx).ID,
+		Reason: strings.TrimSpace(opts.Reason),
+	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		// Active contracts and assignments block a cascade too: ending them is left to
+		// Terminate and Return, which publish the events the rest of the system relies on.
+		hasContracts, err := s.contractRepo.ExistsActiveByLegalEntityID(ctx, id, d.At)
+		if err != nil {
+			return err
+		}
+		if hasContracts {
+			return domain.ErrLegalEntityHasActiveContracts
+		}
+		hasAssignments, err := s.assignmentRepo.ExistsActiveByLegalEntityID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if hasAssignments {
+			return domain.ErrLegalEntityHasActiveAssignments
+		}
+		if opts.Cascade {
+			if err := s.deleteCascade(ctx, id, d); err != nil {
+				return err
+			}
+			return s.appendAudit(ctx, &domain.AuditEntry{
+				EntityType: domain.EntityLegalEntity,
+				EntityID:   id,
+				Action:     domain.AuditDelete,
+				DeletionID: d.ID,
+				Before:     *before,
+			})
+		}
+		hasFleets, err := s.fleetRepo.ExistsByLegalEntityID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if hasFleets {
+			return domain.ErrLegalEntityHasFleets
+		}
+		if err := s.repo.SoftDelete(ctx, id, d); err != nil {
+			return err
+		}
+		return s.appendAudit(ctx, &domain.AuditEntry{
+			EntityType: domain.EntityLegalEntity,
+			EntityID:   id,
+			Action:     domain.AuditDelete,
+			DeletionID: d.ID,
+			Before:     *before,
+		})
+	})
+}
+
+// deleteCascade soft-deletes the legal entity together with its fleets, their vehicles,
+// and all contracts and vehicle assignments under them, none of which is active.
+func (s *Service) deleteCascade(ctx context.Context, id string, d ports.Deletion) error {
+	if err := s.repo.SoftDelete(ctx, id, d); err != nil {
+		return err
+	}
+	assignments, err := s.assignmentRepo.SoftDeleteByLegalEntityID(ctx, id, d)
+	if err != nil {
+		return err
+	}
+	contracts, err := s.contractRepo.SoftDeleteByLegalEntityID(ctx, id, d)
+	if err != nil {
+		return err
+	}
+	vehicles, err := s.vehicleRepo.SoftDeleteByLegalEntityID(ctx, id, d)
+	if err != nil {
+		return err
+	}
+	fleets, err := s.fleetRepo.SoftDeleteByLegalEntityID(ctx, id, d)
+	if err != nil {
+		return err
+	}
+	err = s.appendAudit(ctx, slices.Concat(
+		domain.DeletionEntries(domain.EntityFleet, domain.AuditDelete, d.ID, fleets, fleetID),
+		domain.DeletionEntries(domain.EntityVehicle, domain.AuditDelete, d.ID, vehicles, vehicleID),
+		domain.DeletionEntries(domain.EntityContract, domain.AuditDelete, d.ID, contracts, contractID),
+		domain.DeletionEntries(domain.EntityVehicleAssignment, domain.AuditDelete, d.ID, assignments, assignmentID),
+	)...)
+	if err != nil {
+		return err
+	}
+	logctx.From(ctx, s.logger).Info("Deleted legal entity with dependents", zap.String("id", id))
+	return nil
 }
 
-func (s *Service) Undelete(ctx context.Context, id string) error {
+func (s *Service) Undelete(ctx context.Context, id string, opts ports.UndeleteOptions) error {
+	ctx, span := tracer.Start(ctx, "LegalEntityService.Undelete")
+	defer span.End()
+
 	if id == "" {
 		return fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
 	}
-	return s.repo.Undelete(ctx, id)
+	if err := s.authorize(ctx, domain.ActionDelete, id); err != nil {
+		return err
+	}
+	at, by := s.clock(), domain.PrincipalFromContext(ctx).ID
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		entity, err := s.repo.FindDeletedByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		taken, err := s.repo.ExistsByTaxID(ctx, entity.TaxID, id)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return &domain.UniqueViolationError{Field: "tax_id"}
+		}
+		if err := s.repo.Undelete(ctx, id, at, by); err != nil {
+			return err
+		}
+		// DeletionID is empty when the entity was deleted before deletions were recorded:
+		// there is nothing to match dependents by.
+		if opts.Cascade && entity.DeletionID != "" {
+			if err := s.undeleteCascade(ctx, id, entity.DeletionID, at, by); err != nil {
+				return err
+			}
+		}
+		after, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.appendAudit(ctx, &domain.AuditEntry{
+			EntityType: domain.EntityLegalEntity,
+			EntityID:   id,
+			Action:     domain.AuditUndelete,
+			DeletionID: entity.DeletionID,
+			After:      *after,
+		})
+	})
+}
+
+// undeleteCascade restores the fleets, vehicles, contracts and vehicle assignments that were
+// deleted together with the legal entity. Parents are restored before their children: the
+// repositories skip entities whose other parents are still deleted.
+func (s *Service) undeleteCascade(ctx context.Context, id, deletionID string, at time.Time, by string) error {
+	fleets, err := s.fleetRepo.UndeleteByLegalEntityID(ctx, id, deletionID, at, by)
+	if err != nil {
+		return err
+	}
+	vehicles, err := s.vehicleRepo.UndeleteByLegalEntityID(ctx, id, deletionID, at, by)
+	if err != nil {
+		return err
+	}
+	contracts, err := s.contractRepo.UndeleteByLegalEntityID(ctx, id, deletionID, at, by)
+	if err != nil {
+		return err
+	}
+	assignments, err := s.assignmentRepo.UndeleteByLegalEntityID(ctx, id, deletionID, at, by)
+	if err != nil {
+		return err
+	}
+	err = s.appendAudit(ctx, slices.Concat(
+		domain.DeletionEntries(domain.EntityFleet, domain.AuditUndelete, deletionID, fleets, fleetID),
+		domain.DeletionEntries(domain.EntityVehicle, domain.AuditUndelete, deletionID, vehicles, vehicleID),
+		domain.DeletionEntries(domain.EntityContract, domain.AuditUndelete, deletionID, contracts, contractID),
+		domain.DeletionEntries(domain.EntityVehicleAssignment, domain.AuditUndelete, deletionID, assignments, assignmentID),
+	)...)
+	if err != nil {
+		return err
+	}
+	logctx.From(ctx, s.logger).Info("Restored legal entity with dependents", zap.String("id", id))
+	return nil
+}
+
+// IDs of dependents, for domain.DeletionEntries.
+func fleetID(f *domain.Fleet) string                  { return f.ID }
+func vehicleID(v *domain.Vehicle) string              { return v.ID }
+func contractID(c *domain.Contract) string            { return c.ID }
+func assignmentID(a *domain.VehicleAssignment) string { return a.ID }
+
+// authorize checks the principal may act on the legal entity with the given ID, or on
+// legal entities in general when id is empty.
+func (s *Service) authorize(ctx context.Context, action domain.Action, id string) error {
+	return s.authz.Authorize(ctx, action, domain.Resource{Type: domain.EntityLegalEntity, LegalEntityID: id})
+}
+
+// record appends an audit entry for a change of the legal entity with the given ID.
+func (s *Service) record(ctx context.Context, action domain.AuditAction, id string, before, after any) error {
+	return s.appendAudit(ctx, &domain.AuditEntry{
+		EntityType: domain.EntityLegalEntity,
+		EntityID:   id,
+		Action:     action,
+		Before:     before,
+		After:      after,
+	})
+}
+
+// appendAudit sets the ID, actor and time of the entries and appends them to the audit log.
+func (s *Service) appendAudit(ctx context.Context, entries ...*domain.AuditEntry) error {
+	actor, at := domain.PrincipalFromContext(ctx).ID, s.clock()
+	for _, e := range entries {
+		e.ID, e.Actor, e.At = s.idGen(), actor, at
+		if err := s.auditLog.Append(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
 }
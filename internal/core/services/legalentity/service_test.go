@@ -1,6 +1,8 @@
 package legalentity_test
 
 import (
+	"context"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -9,19 +11,44 @@ import (
 	"go.uber.org/zap/zaptest"
 
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/services/legalentity"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/servicetest"
 )
 
-func stubIDGen() string { return "test-id" }
+type serviceMocks struct {
+	repo           *mocks.MockLegalEntityRepository
+	fleetRepo      *mocks.MockFleetRepository
+	vehicleRepo    *mocks.MockVehicleRepository
+	contractRepo   *mocks.MockContractRepository
+	assignmentRepo *mocks.MockVehicleAssignmentRepository
+	// audit collects the entries appended to the audit log.
+	audit []*domain.AuditEntry
+}
 
-func TestService_Create(t *testing.T) {
+func newService(t *testing.T) (*legalentity.Service, *serviceMocks) {
+	t.Helper()
 	ctrl := gomock.NewController(t)
-	repo := mocks.NewMockLegalEntityRepository(ctrl)
+	m := &serviceMocks{
+		repo:           mocks.NewMockLegalEntityRepository(ctrl),
+		fleetRepo:      mocks.NewMockFleetRepository(ctrl),
+		vehicleRepo:    mocks.NewMockVehicleRepository(ctrl),
+		contractRepo:   mocks.NewMockContractRepository(ctrl),
+		assignmentRepo: mocks.NewMockVehicleAssignmentRepository(ctrl),
+	}
+	svc := legalentity.New(m.repo, m.fleetRepo, m.vehicleRepo, m.contractRepo, m.assignmentRepo,
+		servicetest.PassthroughTx(ctrl), servicetest.CollectingAuditLog(ctrl, &m.audit), servicetest.AllowAll(ctrl),
+		zaptest.NewLogger(t), servicetest.IDGen, servicetest.Clock)
+	return svc, m
+}
+
+func TestService_Create(t *testing.T) {
+	svc, m := newService(t)
 
-	repo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+	m.repo.EXPECT().ExistsByTaxID(gomock.Any(), "123", "").Return(false, nil)
+	m.repo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
 
-	svc := legalentity.New(repo, zaptest.NewLogger(t), stubIDGen)
 	entity, err := svc.Create(t.Context(), "Acme", "123")
 	require.NoError(t, err)
 	assert.Equal(t, "test-id", entity.ID)
@@ -29,20 +56,265 @@ func TestService_Create(t *testing.T) {
 	assert.Equal(t, "123", entity.TaxID)
 }
 
+func TestService_Create_RecordsAudit(t *testing.T) {
+	svc, m := newService(t)
+
+	m.repo.EXPECT().ExistsByTaxID(gomock.Any(), "123", "").Return(false, nil)
+	m.repo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+
+	ctx := domain.ContextWithPrincipal(t.Context(), domain.Principal{ID: "alice"})
+	entity, err := svc.Create(ctx, "Acme", "123")
+	require.NoError(t, err)
+	assert.Equal(t, servicetest.Now, entity.CreatedAt)
+	assert.Equal(t, "alice", entity.CreatedBy)
+	assert.Equal(t, servicetest.Now, entity.UpdatedAt)
+	assert.Equal(t, "alice", entity.UpdatedBy)
+}
+
 func TestService_Create_EmptyName(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	repo := mocks.NewMockLegalEntityRepository(ctrl)
+	svc, _ := newService(t)
 
-	svc := legalentity.New(repo, zaptest.NewLogger(t), stubIDGen)
 	_, err := svc.Create(t.Context(), "", "123")
 	assert.ErrorIs(t, err, domain.ErrInvalidInput)
 }
 
 func TestService_Create_EmptyTaxID(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	repo := mocks.NewMockLegalEntityRepository(ctrl)
+	svc, _ := newService(t)
 
-	svc := legalentity.New(repo, zaptest.NewLogger(t), stubIDGen)
 	_, err := svc.Create(t.Context(), "Acme", "")
 	assert.ErrorIs(t, err, domain.ErrInvalidInput)
 }
+
+func TestService_Update(t *testing.T) {
+	svc, m := newService(t)
+
+	m.repo.EXPECT().FindByID(gomock.Any(), "1").Return(&domain.LegalEntity{ID: "1", Name: "Acme", TaxID: "123", Version: 2}, nil)
+	m.repo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *domain.LegalEntity) error {
+		e.Version++
+		return nil
+	})
+
+	name := " Acme Corp "
+	ctx := domain.ContextWithPrincipal(t.Context(), domain.Principal{ID: "bob"})
+	entity, err := svc.Update(ctx, "1", 2, domain.LegalEntityPatch{Name: &name})
+	require.NoError(t, err)
+	assert.Equal(t, "Acme Corp", entity.Name)
+	assert.Equal(t, "123", entity.TaxID)
+	assert.Equal(t, int64(3), entity.Version)
+	assert.Equal(t, servicetest.Now, entity.UpdatedAt)
+	assert.Equal(t, "bob", entity.UpdatedBy)
+
+	require.Len(t, m.audit, 1)
+	entry := m.audit[0]
+	assert.Equal(t, domain.EntityLegalEntity, entry.EntityType)
+	assert.Equal(t, "1", entry.EntityID)
+	assert.Equal(t, domain.AuditUpdate, entry.Action)
+	assert.Equal(t, "bob", entry.Actor)
+	assert.Equal(t, servicetest.Now, entry.At)
+	require.IsType(t, domain.LegalEntity{}, entry.Before)
+	require.IsType(t, domain.LegalEntity{}, entry.After)
+	assert.Equal(t, "Acme", entry.Before.(domain.LegalEntity).Name)
+	assert.Equal(t, "Acme Corp", entry.After.(domain.LegalEntity).Name)
+	assert.Equal(t, int64(3), entry.After.(domain.LegalEntity).Version)
+}
+
+func TestService_Update_VersionMismatch(t *testing.T) {
+	svc, m := newService(t)
+
+	m.repo.EXPECT().FindByID(gomock.Any(), "1").Return(&domain.LegalEntity{ID: "1", Name: "Acme", TaxID: "123", Version: 3}, nil)
+
+	name := "Acme Corp"
+	_, err := svc.Update(t.Context(), "1", 2, domain.LegalEntityPatch{Name: &name})
+	assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+}
+
+func TestService_Update_EmptyPatch(t *testing.T) {
+	svc, _ := newService(t)
+
+	_, err := svc.Update(t.Context(), "1", 0, domain.LegalEntityPatch{})
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+}
+
+func TestService_Create_DuplicateTaxID(t *testing.T) {
+	svc, m := newService(t)
+
+	m.repo.EXPECT().ExistsByTaxID(gomock.Any(), "123", "").Return(true, nil)
+
+	_, err := svc.Create(t.Context(), "Acme", "123")
+	var uniqueErr *domain.UniqueViolationError
+	require.ErrorAs(t, err, &uniqueErr)
+	assert.Equal(t, "tax_id", uniqueErr.Field)
+}
+
+func TestService_Update_DuplicateTaxID(t *testing.T) {
+	svc, m := newService(t)
+
+	m.repo.EXPECT().FindByID(gomock.Any(), "1").Return(&domain.LegalEntity{ID: "1", Name: "Acme", TaxID: "123", Version: 1}, nil)
+	m.repo.EXPECT().ExistsByTaxID(gomock.Any(), "456", "1").Return(true, nil)
+
+	taxID := "456"
+	_, err := svc.Update(t.Context(), "1", 0, domain.LegalEntityPatch{TaxID: &taxID})
+	assert.ErrorIs(t, err, domain.ErrDuplicateValue)
+}
+
+// expectNoActive expects the checks for active contracts and assignments a delete runs
+// before anything else, and finds none.
+func expectNoActive(m *serviceMocks, id string) {
+	m.contractRepo.EXPECT().ExistsActiveByLegalEntityID(gomock.Any(), id, servicetest.Now).Return(false, nil)
+	m.assignmentRepo.EXPECT().ExistsActiveByLegalEntityID(gomock.Any(), id).Return(false, nil)
+}
+
+func TestService_Delete_RejectsWhenFleetsExist(t *testing.T) {
+	svc, m := newService(t)
+
+	m.repo.EXPECT().FindByID(gomock.Any(), "1").Return(&domain.LegalEntity{ID: "1"}, nil)
+	expectNoActive(m, "1")
+	m.fleetRepo.EXPECT().ExistsByLegalEntityID(gomock.Any(), "1").Return(true, nil)
+
+	err := svc.Delete(t.Context(), "1", ports.DeleteOptions{})
+	assert.ErrorIs(t, err, domain.ErrLegalEntityHasFleets)
+}
+
+func TestService_Delete_RejectsWhenActive(t *testing.T) {
+	for _, cascade := range []bool{false, true} {
+		t.Run("cascade="+strconv.FormatBool(cascade), func(t *testing.T) {
+			t.Run("contracts", func(t *testing.T) {
+				svc, m := newService(t)
+				m.repo.EXPECT().FindByID(gomock.Any(), "1").Return(&domain.LegalEntity{ID: "1"}, nil)
+				m.contractRepo.EXPECT().ExistsActiveByLegalEntityID(gomock.Any(), "1", servicetest.Now).Return(true, nil)
+
+				err := svc.Delete(t.Context(), "1", ports.DeleteOptions{Cascade: cascade})
+				assert.ErrorIs(t, err, domain.ErrLegalEntityHasActiveContracts)
+				assert.Empty(t, m.audit)
+			})
+			t.Run("assignments", func(t *testing.T) {
+				svc, m := newService(t)
+				m.repo.EXPECT().FindByID(gomock.Any(), "1").Return(&domain.LegalEntity{ID: "1"}, nil)
+				m.contractRepo.EXPECT().ExistsActiveByLegalEntityID(gomock.Any(), "1", servicetest.Now).Return(false, nil)
+				m.assignmentRepo.EXPECT().ExistsActiveByLegalEntityID(gomock.Any(), "1").Return(true, nil)
+
+				err := svc.Delete(t.Context(), "1", ports.DeleteOptions{Cascade: cascade})
+				assert.ErrorIs(t, err, domain.ErrLegalEntityHasActiveAssignments)
+				assert.Empty(t, m.audit)
+			})
+		})
+	}
+}
+
+func TestService_Delete_Success(t *testing.T) {
+	svc, m := newService(t)
+
+	m.repo.EXPECT().FindByID(gomock.Any(), "1").Return(&domain.LegalEntity{ID: "1"}, nil)
+	expectNoActive(m, "1")
+	m.fleetRepo.EXPECT().ExistsByLegalEntityID(gomock.Any(), "1").Return(false, nil)
+	m.repo.EXPECT().SoftDelete(gomock.Any(), "1", servicetest.Deletion).Return(nil)
+
+	require.NoError(t, svc.Delete(t.Context(), "1", ports.DeleteOptions{}))
+	require.Len(t, m.audit, 1)
+	assert.Equal(t, domain.AuditDelete, m.audit[0].Action)
+	assert.Equal(t, domain.LegalEntity{ID: "1"}, m.audit[0].Before)
+	assert.Nil(t, m.audit[0].After)
+}
+
+func TestService_Delete_Cascade(t *testing.T) {
+	svc, m := newService(t)
+
+	m.repo.EXPECT().FindByID(gomock.Any(), "1").Return(&domain.LegalEntity{ID: "1"}, nil)
+	expectNoActive(m, "1")
+	fleet := &domain.Fleet{ID: "f1", LegalEntityID: "1"}
+	gomock.InOrder(
+		m.repo.EXPECT().SoftDelete(gomock.Any(), "1", servicetest.Deletion).Return(nil),
+		m.assignmentRepo.EXPECT().SoftDeleteByLegalEntityID(gomock.Any(), "1", servicetest.Deletion).
+			Return([]*domain.VehicleAssignment{{ID: "a1"}}, nil),
+		m.contractRepo.EXPECT().SoftDeleteByLegalEntityID(gomock.Any(), "1", servicetest.Deletion).
+			Return([]*domain.Contract{{ID: "c1"}, {ID: "c2"}}, nil),
+		m.vehicleRepo.EXPECT().SoftDeleteByLegalEntityID(gomock.Any(), "1", servicetest.Deletion).Return(nil, nil),
+		m.fleetRepo.EXPECT().SoftDeleteByLegalEntityID(gomock.Any(), "1", servicetest.Deletion).
+			Return([]*domain.Fleet{fleet}, nil),
+	)
+
+	require.NoError(t, svc.Delete(t.Context(), "1", ports.DeleteOptions{Cascade: true}))
+	assert.Equal(t, []string{
+		"fleet f1", "contract c1", "contract c2", "vehicle_assignment a1", "legal_entity 1",
+	}, auditedEntities(m.audit, domain.AuditDelete, "test-id"))
+	assert.Equal(t, *fleet, m.audit[0].Before)
+	assert.Nil(t, m.audit[0].After)
+}
+
+// auditedEntities returns the type and ID of the entities in entries, in order, after checking
+// that every entry records action for deletionID.
+func auditedEntities(entries []*domain.AuditEntry, action domain.AuditAction, deletionID string) []string {
+	var result []string
+	for _, e := range entries {
+		if e.Action != action || e.DeletionID != deletionID {
+			return nil
+		}
+		result = append(result, string(e.EntityType)+" "+e.EntityID)
+	}
+	return result
+}
+
+func TestService_Delete_CascadeStopsWhenNotFound(t *testing.T) {
+	svc, m := newService(t)
+
+	m.repo.EXPECT().FindByID(gomock.Any(), "1").Return(nil, domain.ErrNotFound)
+
+	err := svc.Delete(t.Context(), "1", ports.DeleteOptions{Cascade: true})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Empty(t, m.audit)
+}
+
+func TestService_Undelete_Cascade(t *testing.T) {
+	svc, m := newService(t)
+
+	m.repo.EXPECT().FindDeletedByID(gomock.Any(), "1").
+		Return(&domain.LegalEntity{ID: "1", TaxID: "123", DeletionID: "del-1"}, nil)
+	m.repo.EXPECT().ExistsByTaxID(gomock.Any(), "123", "1").Return(false, nil)
+	vehicle := &domain.Vehicle{ID: "v1", FleetID: "f1"}
+	gomock.InOrder(
+		m.repo.EXPECT().Undelete(gomock.Any(), "1", servicetest.Now, "carol").Return(nil),
+		m.fleetRepo.EXPECT().UndeleteByLegalEntityID(gomock.Any(), "1", "del-1", servicetest.Now, "carol").
+			Return([]*domain.Fleet{{ID: "f1"}}, nil),
+		m.vehicleRepo.EXPECT().UndeleteByLegalEntityID(gomock.Any(), "1", "del-1", servicetest.Now, "carol").
+			Return([]*domain.Vehicle{vehicle}, nil),
+		m.contractRepo.EXPECT().UndeleteByLegalEntityID(gomock.Any(), "1", "del-1", servicetest.Now, "carol").Return(nil, nil),
+		m.assignmentRepo.EXPECT().UndeleteByLegalEntityID(gomock.Any(), "1", "del-1", servicetest.Now, "carol").
+			Return([]*domain.VehicleAssignment{{ID: "a1"}}, nil),
+		m.repo.EXPECT().FindByID(gomock.Any(), "1").Return(&domain.LegalEntity{ID: "1"}, nil),
+	)
+
+	ctx := domain.ContextWithPrincipal(t.Context(), domain.Principal{ID: "carol"})
+	err := svc.Undelete(ctx, "1", ports.UndeleteOptions{Cascade: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"fleet f1", "vehicle v1", "vehicle_assignment a1", "legal_entity 1",
+	}, auditedEntities(m.audit, domain.AuditUndelete, "del-1"))
+	assert.Nil(t, m.audit[1].Before)
+	assert.Equal(t, *vehicle, m.audit[1].After)
+	assert.Equal(t, "carol", m.audit[1].Actor)
+}
+
+func TestService_Undelete_CascadeWithoutDeletionID(t *testing.T) {
+	svc, m := newService(t)
+
+	m.repo.EXPECT().FindDeletedByID(gomock.Any(), "1").Return(&domain.LegalEntity{ID: "1", TaxID: "123"}, nil)
+	m.repo.EXPECT().ExistsByTaxID(gomock.Any(), "123", "1").Return(false, nil)
+	m.repo.EXPECT().Undelete(gomock.Any(), "1", servicetest.Now, "").Return(nil)
+	m.repo.EXPECT().FindByID(gomock.Any(), "1").Return(&domain.LegalEntity{ID: "1"}, nil)
+
+	err := svc.Undelete(t.Context(), "1", ports.UndeleteOptions{Cascade: true})
+	require.NoError(t, err)
+}
+
+func TestService_Undelete_RejectsReusedTaxID(t *testing.T) {
+	svc, m := newService(t)
+
+	m.repo.EXPECT().FindDeletedByID(gomock.Any(), "1").Return(&domain.LegalEntity{ID: "1", TaxID: "123"}, nil)
+	m.repo.EXPECT().ExistsByTaxID(gomock.Any(), "123", "1").Return(true, nil)
+
+	err := svc.Undelete(t.Context(), "1", ports.UndeleteOptions{})
+	var uniqueErr *domain.UniqueViolationError
+	require.ErrorAs(t, err, &uniqueErr)
+	assert.Equal(t, "tax_id", uniqueErr.Field)
+	assert.Empty(t, m.audit)
+}
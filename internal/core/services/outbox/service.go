@@ -0,0 +1,91 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+)
+
+const (
+	// batchSize is the number of events claimed by one RelayBatch call.
+	batchSize = 20
+	// leaseDuration must cover publishing a whole batch; an event still unconfirmed
+	// when its lease expires may be delivered twice.
+	leaseDuration = 5 * time.Minute
+	// minRetryDelay and maxRetryDelay bound the exponential backoff between attempts.
+	minRetryDelay = time.Second
+	maxRetryDelay = time.Hour
+)
+
+type Clock func() time.Time
+
+// Service relays events from the outbox to every publisher.
+type Service struct {
+	outbox     ports.Outbox
+	publishers []ports.EventPublisher
+	clock      Clock
+	logger     *zap.Logger
+}
+
+func New(outbox ports.Outbox, publishers []ports.EventPublisher, clock Clock, logger *zap.Logger) *Service {
+	return &Service{
+		outbox:     outbox,
+		publishers: publishers,
+		clock:      clock,
+		logger:     logger,
+	}
+}
+
+// RelayBatch publishes a batch of due events. An event counts as delivered only once
+// every publisher accepted it; after a failure it is published to all of them again.
+func (s *Service) RelayBatch(ctx context.Context) (int, error) {
+	now := s.clock()
+	messages, err := s.outbox.Claim(ctx, batchSize, now, now.Add(leaseDuration))
+	if err != nil {
+		return 0, err
+	}
+	for _, m := range messages {
+		if err := s.publish(ctx, m.Event); err != nil {
+			attempts := m.Attempts + 1
+			s.logger.Warn("Failed to publish event",
+				zap.String("id", m.Event.ID),
+				zap.String("type", string(m.Event.Type)),
+				zap.Int("attempts", attempts),
+				zap.Error(err),
+			)
+			if err := s.outbox.MarkFailed(ctx, m.Event.ID, err.Error(), s.clock().Add(retryDelay(attempts))); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		if err := s.outbox.MarkDelivered(ctx, m.Event.ID, s.clock()); err != nil {
+			return 0, err
+		}
+	}
+	return len(messages), nil
+}
+
+func (s *Service) publish(ctx context.Context, event *domain.Event) error {
+	var errs []error
+	for _, p := range s.publishers {
+		if err := p.Publish(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%T: %w", p, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// retryDelay returns the delay before the next attempt after the given number of failed ones.
+func retryDelay(attempts int) time.Duration {
+	d := minRetryDelay
+	for i := 1; i < attempts && d < maxRetryDelay; i++ {
+		d *= 2
+	}
+	return min(d, maxRetryDelay)
+}
@@ -0,0 +1,90 @@
+package outbox_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/outbox"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/servicetest"
+)
+
+func TestService_RelayBatch_MarksDelivered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mocks.NewMockOutbox(ctrl)
+	logPub := mocks.NewMockEventPublisher(ctrl)
+	webhookPub := mocks.NewMockEventPublisher(ctrl)
+
+	event := &domain.Event{ID: "e1", Type: domain.EventContractTerminated}
+	store.EXPECT().Claim(gomock.Any(), gomock.Any(), servicetest.Now, gomock.Any()).
+		Return([]*ports.OutboxMessage{{Event: event}}, nil)
+	logPub.EXPECT().Publish(gomock.Any(), event).Return(nil)
+	webhookPub.EXPECT().Publish(gomock.Any(), event).Return(nil)
+	store.EXPECT().MarkDelivered(gomock.Any(), "e1", servicetest.Now).Return(nil)
+
+	svc := outbox.New(store, []ports.EventPublisher{logPub, webhookPub}, servicetest.Clock, zaptest.NewLogger(t))
+	n, err := svc.RelayBatch(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestService_RelayBatch_SchedulesRetryWithBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int
+		want     time.Duration
+	}{
+		{"first failure", 0, time.Second},
+		{"second failure", 1, 2 * time.Second},
+		{"fifth failure", 4, 16 * time.Second},
+		{"capped", 40, time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mocks.NewMockOutbox(ctrl)
+			logPub := mocks.NewMockEventPublisher(ctrl)
+			webhookPub := mocks.NewMockEventPublisher(ctrl)
+
+			event := &domain.Event{ID: "e1", Type: domain.EventAssignmentReturned}
+			store.EXPECT().Claim(gomock.Any(), gomock.Any(), servicetest.Now, gomock.Any()).
+				Return([]*ports.OutboxMessage{{Event: event, Attempts: tt.attempts}}, nil)
+			logPub.EXPECT().Publish(gomock.Any(), event).Return(nil)
+			webhookPub.EXPECT().Publish(gomock.Any(), event).Return(errors.New("connection refused"))
+			store.EXPECT().MarkFailed(gomock.Any(), "e1", gomock.Any(), servicetest.Now.Add(tt.want)).Return(nil)
+
+			svc := outbox.New(store, []ports.EventPublisher{logPub, webhookPub}, servicetest.Clock, zaptest.NewLogger(t))
+			n, err := svc.RelayBatch(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, 1, n)
+		})
+	}
+}
+
+func TestService_RelayBatch_ContinuesAfterFailedEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mocks.NewMockOutbox(ctrl)
+	pub := mocks.NewMockEventPublisher(ctrl)
+
+	first := &domain.Event{ID: "e1"}
+	second := &domain.Event{ID: "e2"}
+	store.EXPECT().Claim(gomock.Any(), gomock.Any(), servicetest.Now, gomock.Any()).
+		Return([]*ports.OutboxMessage{{Event: first}, {Event: second}}, nil)
+	pub.EXPECT().Publish(gomock.Any(), first).Return(errors.New("timeout"))
+	pub.EXPECT().Publish(gomock.Any(), second).Return(nil)
+	store.EXPECT().MarkFailed(gomock.Any(), "e1", gomock.Any(), gomock.Any()).Return(nil)
+	store.EXPECT().MarkDelivered(gomock.Any(), "e2", servicetest.Now).Return(nil)
+
+	svc := outbox.New(store, []ports.EventPublisher{pub}, servicetest.Clock, zaptest.NewLogger(t))
+	n, err := svc.RelayBatch(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+}
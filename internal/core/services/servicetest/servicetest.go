@@ -0,0 +1,71 @@
+// Package servicetest provides the fixtures and test doubles shared by the tests of
+// the core services.
+package servicetest
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
+)
+
+// ID is the ID generated by IDGen.
+const ID = "test-id"
+
+// Now is the time told by Clock.
+var Now = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// Deletion is the Deletion recorded by a delete without principal or reason.
+var Deletion = ports.Deletion{ID: ID, At: Now}
+
+// IDGen generates ID every time.
+func IDGen() string { return ID }
+
+// Clock tells Now every time.
+func Clock() time.Time { return Now }
+
+// PassthroughTx returns a TxManager that runs the unit of work in the caller's context.
+func PassthroughTx(ctrl *gomock.Controller) *mocks.MockTxManager {
+	tx := mocks.NewMockTxManager(ctrl)
+	tx.EXPECT().WithinTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) }).
+		AnyTimes()
+	return tx
+}
+
+// AllowAll authorizes every action.
+func AllowAll(ctrl *gomock.Controller) *mocks.MockAuthorizer {
+	authz := mocks.NewMockAuthorizer(ctrl)
+	authz.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return authz
+}
+
+// NopAuditLog accepts any number of audit entries.
+func NopAuditLog(ctrl *gomock.Controller) *mocks.MockAuditLog {
+	auditLog := mocks.NewMockAuditLog(ctrl)
+	auditLog.EXPECT().Append(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return auditLog
+}
+
+// CollectingAuditLog accepts any number of audit entries and appends them to entries.
+func CollectingAuditLog(ctrl *gomock.Controller, entries *[]*domain.AuditEntry) *mocks.MockAuditLog {
+	auditLog := mocks.NewMockAuditLog(ctrl)
+	auditLog.EXPECT().Append(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, e *domain.AuditEntry) error {
+			*entries = append(*entries, e)
+			return nil
+		}).
+		AnyTimes()
+	return auditLog
+}
+
+// NopOutbox accepts any number of events.
+func NopOutbox(ctrl *gomock.Controller) *mocks.MockOutbox {
+	outbox := mocks.NewMockOutbox(ctrl)
+	outbox.EXPECT().Enqueue(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return outbox
+}
@@ -4,83 +4,367 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
+	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
 
 	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
 	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/logctx"
 )
 
+var tracer = otel.Tracer("github.com/albenik/uber-fx-based-service-example/internal/core/services/vehicle")
+
 type IDGenerator func() string
 
+type Clock func() time.Time
+
 type Service struct {
-	fleetRepo ports.FleetRepository
-	repo      ports.VehicleRepository
-	logger    *zap.Logger
-	idGen     IDGenerator
+	fleetRepo      ports.FleetRepository
+	repo           ports.VehicleRepository
+	assignmentRepo ports.VehicleAssignmentRepository
+	tx             ports.TxManager
+	auditLog       ports.AuditLog
+	authz          ports.Authorizer
+	logger         *zap.Logger
+	idGen          IDGenerator
+	clock          Clock
 }
 
-func New(fleetRepo ports.FleetRepository, repo ports.VehicleRepository, logger *zap.Logger, idGen IDGenerator) *Service {
-	return &Service{fleetRepo: fleetRepo, repo: repo, logger: logger, idGen: idGen}
+func New(
+	fleetRepo ports.FleetRepository,
+	repo ports.VehicleRepository,
+	assignmentRepo ports.VehicleAssignmentRepository,
+	tx ports.TxManager,
+	auditLog ports.AuditLog,
+	authz ports.Authorizer,
+	logger *zap.Logger,
+	idGen IDGenerator,
+	clock Clock,
+) *Service {
+	return &Service{
+		fleetRepo:      fleetRepo,
+		repo:           repo,
+		assignmentRepo: assignmentRepo,
+		tx:             tx,
+		auditLog:       auditLog,
+		authz:          authz,
+		logger:         logger,
+		idGen:          idGen,
+		clock:          clock,
+	}
 }
 
 func (s *Service) Create(ctx context.Context, fleetID, make, model, licensePlate string, year int) (*domain.Vehicle, error) {
+	ctx, span := tracer.Start(ctx, "VehicleService.Create")
+	defer span.End()
+
 	make = strings.TrimSpace(make)
 	model = strings.TrimSpace(model)
 	licensePlate = strings.TrimSpace(licensePlate)
+	var invalid domain.ValidationError
 	if fleetID == "" {
-		return nil, fmt.Errorf("%w: fleet_id is required", domain.ErrInvalidInput)
+		invalid.Add("fleet_id", domain.RuleRequired, "is required")
 	}
 	if make == "" {
-		return nil, fmt.Errorf("%w: make is required", domain.ErrInvalidInput)
+		invalid.Add("make", domain.RuleRequired, "is required")
 	}
 	if model == "" {
-		return nil, fmt.Errorf("%w: model is required", domain.ErrInvalidInput)
+		invalid.Add("model", domain.RuleRequired, "is required")
 	}
 	if year < 1900 || year > 2100 {
-		return nil, fmt.Errorf("%w: year must be between 1900 and 2100", domain.ErrInvalidInput)
+		invalid.Add("year", domain.RuleRange, "must be between 1900 and 2100")
+	}
+	if err := invalid.Err(); err != nil {
+		return nil, err
 	}
-	if _, err := s.fleetRepo.FindByID(ctx, fleetID); err != nil {
+	fleet, err := s.fleetRepo.FindByID(ctx, fleetID)
+	if err != nil {
 		return nil, err
 	}
+	if err := s.authorize(ctx, domain.ActionCreate, fleet.LegalEntityID); err != nil {
+		return nil, err
+	}
+	if licensePlate != "" {
+		taken, err := s.repo.ExistsByLicensePlate(ctx, licensePlate, "")
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			return nil, &domain.UniqueViolationError{Field: "license_plate"}
+		}
+	}
 	id := s.idGen()
 	if id == "" {
 		return nil, fmt.Errorf("id generator returned empty ID")
 	}
-	entity := &domain.Vehicle{ID: id, FleetID: fleetID, Make: make, Model: model, Year: year, LicensePlate: licensePlate}
-	if err := s.repo.Save(ctx, entity); err != nil {
-		s.logger.Error("Failed to save vehicle", zap.String("id", id), zap.Error(err))
+	now, actor := s.clock(), domain.PrincipalFromContext(ctx).ID
+	entity := &domain.Vehicle{
+		ID: id, FleetID: fleetID, Make: make, Model: model, Year: year, LicensePlate: licensePlate,
+		CreatedAt: now, CreatedBy: actor, UpdatedAt: now, UpdatedBy: actor,
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Save(ctx, entity); err != nil {
+			return err
+		}
+		return s.record(ctx, domain.AuditCreate, id, nil, *entity)
+	})
+	if err != nil {
+		logctx.From(ctx, s.logger).Error("Failed to save vehicle", zap.String("id", id), zap.Error(err))
 		return nil, err
 	}
-	s.logger.Info("Created vehicle", zap.String("id", id))
+	logctx.From(ctx, s.logger).Info("Created vehicle", zap.String("id", id))
 	result := *entity
 	return &result, nil
 }
 
 func (s *Service) Get(ctx context.Context, id string) (*domain.Vehicle, error) {
+	ctx, span := tracer.Start(ctx, "VehicleService.Get")
+	defer span.End()
+
 	if id == "" {
 		return nil, fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
 	}
-	return s.repo.FindByID(ctx, id)
+	entity, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeFleet(ctx, domain.ActionRead, entity.FleetID); err != nil {
+		return nil, err
+	}
+	return entity, nil
 }
 
-func (s *Service) ListByFleet(ctx context.Context, fleetID string) ([]*domain.Vehicle, error) {
+func (s *Service) ListByFleet(ctx context.Context, fleetID string, q ports.ListQuery) (*ports.Page[*domain.Vehicle], error) {
+	ctx, span := tracer.Start(ctx, "VehicleService.ListByFleet")
+	defer span.End()
+
 	if fleetID == "" {
 		return nil, fmt.Errorf("%w: fleet_id is required", domain.ErrInvalidInput)
 	}
-	return s.repo.FindByFleetID(ctx, fleetID)
+	if err := s.authorizeFleet(ctx, domain.ActionRead, fleetID); err != nil {
+		return nil, err
+	}
+	q, err := q.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindByFleetID(ctx, fleetID, q)
+}
+
+func (s *Service) Update(ctx context.Context, id string, version int64, patch domain.VehiclePatch) (*domain.Vehicle, error) {
+	ctx, span := tracer.Start(ctx, "VehicleService.Update")
+	defer span.End()
+
+	if id == "" {
+		return nil, fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
+	}
+	if patch.Make == nil && patch.Model == nil && patch.Year == nil && patch.LicensePlate == nil {
+		return nil, fmt.Errorf("%w: nothing to update", domain.ErrInvalidInput)
+	}
+	entity, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeFleet(ctx, domain.ActionUpdate, entity.FleetID); err != nil {
+		return nil, err
+	}
+	if version != 0 && entity.Version != version {
+		return nil, domain.ErrPreconditionFailed
+	}
+	before := *entity
+	if patch.Make != nil {
+		entity.Make = strings.TrimSpace(*patch.Make)
+		if entity.Make == "" {
+			return nil, fmt.Errorf("%w: make is required", domain.ErrInvalidInput)
+		}
+	}
+	if patch.Model != nil {
+		entity.Model = strings.TrimSpace(*patch.Model)
+		if entity.Model == "" {
+			return nil, fmt.Errorf("%w: model is required", domain.ErrInvalidInput)
+		}
+	}
+	if patch.Year != nil {
+		entity.Year = *patch.Year
+		if entity.Year < 1900 || entity.Year > 2100 {
+			return nil, fmt.Errorf("%w: year must be between 1900 and 2100", domain.ErrInvalidInput)
+		}
+	}
+	if patch.LicensePlate != nil && strings.TrimSpace(*patch.LicensePlate) != entity.LicensePlate {
+		entity.LicensePlate = strings.TrimSpace(*patch.LicensePlate)
+		if entity.LicensePlate != "" {
+			taken, err := s.repo.ExistsByLicensePlate(ctx, entity.LicensePlate, entity.ID)
+			if err != nil {
+				return nil, err
+			}
+			if taken {
+				return nil, &domain.UniqueViolationError{Field: "license_plate"}
+			}
+		}
+	}
+	entity.UpdatedAt, entity.UpdatedBy = s.clock(), domain.PrincipalFromContext(ctx).ID
+	unsaved := *entity
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		*entity = unsaved
+		if err := s.repo.Save(ctx, entity); err != nil {
+			return err
+		}
+		return s.record(ctx, domain.AuditUpdate, id, before, *entity)
+	})
+	if err != nil {
+		logctx.From(ctx, s.logger).Error("Failed to save vehicle", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+	logctx.From(ctx, s.logger).Info("Updated vehicle", zap.String("id", id))
+	result := *entity
+	return &result, nil
 }
 
-func (s *Service) Delete(ctx context.Context, id string) error {
+func (s *Service) Delete(ctx context.Context, id string, opts ports.DeleteOptions) error {
+	ctx, span := tracer.Start(ctx, "VehicleService.Delete")
+	defer span.End()
+
 	if id == "" {
 		return fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
 	}
-	return s.repo.SoftDelete(ctx, id)
+	d := ports.Deletion{
+		ID:     s.idGen(),
+		At:     s.clock(),
+		By:     domain.PrincipalFromContext(ctx).ID,
+		Reason: strings.TrimSpace(opts.Reason),
+	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.authorizeFleet(ctx, domain.ActionDelete, before.FleetID); err != nil {
+			return err
+		}
+		// A cascade only takes returned assignments along; Return ends active ones.
+		hasAssignments, err := s.assignmentRepo.ExistsActiveByVehicleID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if hasAssignments {
+			return domain.ErrVehicleHasActiveAssignments
+		}
+		if err := s.repo.SoftDelete(ctx, id, d); err != nil {
+			return err
+		}
+		if opts.Cascade {
+			assignments, err := s.assignmentRepo.SoftDeleteByVehicleID(ctx, id, d)
+			if err != nil {
+				return err
+			}
+			err = s.appendAudit(ctx, domain.DeletionEntries(domain.EntityVehicleAssignment, domain.AuditDelete, d.ID,
+				assignments, func(a *domain.VehicleAssignment) string { return a.ID })...)
+			if err != nil {
+				return err
+			}
+			logctx.From(ctx, s.logger).Info("Deleted vehicle with assignments", zap.String("id", id))
+		}
+		return s.appendAudit(ctx, &domain.AuditEntry{
+			EntityType: domain.EntityVehicle,
+			EntityID:   id,
+			Action:     domain.AuditDelete,
+			DeletionID: d.ID,
+			Before:     *before,
+		})
+	})
 }
 
-func (s *Service) Undelete(ctx context.Context, id string) error {
+func (s *Service) Undelete(ctx context.Context, id string, opts ports.UndeleteOptions) error {
+	ctx, span := tracer.Start(ctx, "VehicleService.Undelete")
+	defer span.End()
+
 	if id == "" {
 		return fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
 	}
-	return s.repo.Undelete(ctx, id)
+	at, by := s.clock(), domain.PrincipalFromContext(ctx).ID
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		entity, err := s.repo.FindDeletedByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		fleet, err := s.fleetRepo.FindByID(ctx, entity.FleetID)
+		if err != nil {
+			return domain.ParentError(err, "fleet", entity.FleetID)
+		}
+		if err := s.authorize(ctx, domain.ActionDelete, fleet.LegalEntityID); err != nil {
+			return err
+		}
+		taken, err := s.repo.ExistsByLicensePlate(ctx, entity.LicensePlate, id)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return &domain.UniqueViolationError{Field: "license_plate"}
+		}
+		if err := s.repo.Undelete(ctx, id, at, by); err != nil {
+			return err
+		}
+		if opts.Cascade && entity.DeletionID != "" {
+			assignments, err := s.assignmentRepo.UndeleteByVehicleID(ctx, id, entity.DeletionID, at, by)
+			if err != nil {
+				return err
+			}
+			err = s.appendAudit(ctx, domain.DeletionEntries(domain.EntityVehicleAssignment, domain.AuditUndelete,
+				entity.DeletionID, assignments, func(a *domain.VehicleAssignment) string { return a.ID })...)
+			if err != nil {
+				return err
+			}
+			logctx.From(ctx, s.logger).Info("Restored vehicle with assignments", zap.String("id", id))
+		}
+		after, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.appendAudit(ctx, &domain.AuditEntry{
+			EntityType: domain.EntityVehicle,
+			EntityID:   id,
+			Action:     domain.AuditUndelete,
+			DeletionID: entity.DeletionID,
+			After:      *after,
+		})
+	})
+}
+
+// authorize checks the principal may act on the vehicles of the given legal entity.
+func (s *Service) authorize(ctx context.Context, action domain.Action, legalEntityID string) error {
+	return s.authz.Authorize(ctx, action, domain.Resource{Type: domain.EntityVehicle, LegalEntityID: legalEntityID})
+}
+
+// authorizeFleet checks the principal may act on the vehicles of the given fleet.
+func (s *Service) authorizeFleet(ctx context.Context, action domain.Action, fleetID string) error {
+	fleet, err := s.fleetRepo.FindByID(ctx, fleetID)
+	if err != nil {
+		return err
+	}
+	return s.authorize(ctx, action, fleet.LegalEntityID)
+}
+
+// record appends an audit entry for a change of the vehicle with the given ID.
+func (s *Service) record(ctx context.Context, action domain.AuditAction, id string, before, after any) error {
+	return s.appendAudit(ctx, &domain.AuditEntry{
+		EntityType: domain.EntityVehicle,
+		EntityID:   id,
+		Action:     action,
+		Before:     before,
+		After:      after,
+	})
+}
+
+// appendAudit sets the ID, actor and time of the entries and appends them to the audit log.
+func (s *Service) appendAudit(ctx context.Context, entries ...*domain.AuditEntry) error {
+	actor, at := domain.PrincipalFromContext(ctx).ID, s.clock()
+	for _, e := range entries {
+		e.ID, e.Actor, e.At = s.idGen(), actor, at
+		if err := s.auditLog.Append(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
 }
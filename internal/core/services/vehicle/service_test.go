@@ -0,0 +1,175 @@
+package vehicle_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/servicetest"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/vehicle"
+)
+
+type serviceMocks struct {
+	fleetRepo      *mocks.MockFleetRepository
+	repo           *mocks.MockVehicleRepository
+	assignmentRepo *mocks.MockVehicleAssignmentRepository
+	// audit collects the entries appended to the audit log.
+	audit []*domain.AuditEntry
+}
+
+func newService(t *testing.T) (*vehicle.Service, *serviceMocks) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	m := &serviceMocks{
+		fleetRepo:      mocks.NewMockFleetRepository(ctrl),
+		repo:           mocks.NewMockVehicleRepository(ctrl),
+		assignmentRepo: mocks.NewMockVehicleAssignmentRepository(ctrl),
+	}
+	svc := vehicle.New(m.fleetRepo, m.repo, m.assignmentRepo, servicetest.PassthroughTx(ctrl),
+		servicetest.CollectingAuditLog(ctrl, &m.audit), servicetest.AllowAll(ctrl), zaptest.NewLogger(t),
+		servicetest.IDGen, servicetest.Clock)
+	return svc, m
+}
+
+func TestService_Create_ReportsEveryInvalidField(t *testing.T) {
+	svc, m := newService(t)
+
+	_, err := svc.Create(t.Context(), "", " ", "", "AB-123", 1899)
+	var invalid *domain.ValidationError
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, []domain.Violation{
+		{Field: "fleet_id", Rule: domain.RuleRequired, Message: "is required"},
+		{Field: "make", Rule: domain.RuleRequired, Message: "is required"},
+		{Field: "model", Rule: domain.RuleRequired, Message: "is required"},
+		{Field: "year", Rule: domain.RuleRange, Message: "must be between 1900 and 2100"},
+	}, invalid.Violations)
+	assert.Empty(t, m.audit)
+}
+
+func TestService_Create_Success(t *testing.T) {
+	svc, m := newService(t)
+
+	m.fleetRepo.EXPECT().FindByID(gomock.Any(), "f1").Return(&domain.Fleet{ID: "f1", LegalEntityID: "le1"}, nil)
+	m.repo.EXPECT().ExistsByLicensePlate(gomock.Any(), "AB-123", "").Return(false, nil)
+	m.repo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+
+	entity, err := svc.Create(t.Context(), "f1", " Toyota ", "Corolla", "AB-123", 2020)
+	require.NoError(t, err)
+	assert.Equal(t, "test-id", entity.ID)
+	assert.Equal(t, "Toyota", entity.Make)
+	require.Len(t, m.audit, 1)
+	assert.Equal(t, domain.AuditCreate, m.audit[0].Action)
+}
+
+func TestService_Undelete_RejectsReusedLicensePlate(t *testing.T) {
+	svc, m := newService(t)
+
+	m.repo.EXPECT().FindDeletedByID(gomock.Any(), "v1").
+		Return(&domain.Vehicle{ID: "v1", FleetID: "f1", LicensePlate: "AB-123"}, nil)
+	m.fleetRepo.EXPECT().FindByID(gomock.Any(), "f1").Return(&domain.Fleet{ID: "f1", LegalEntityID: "le1"}, nil)
+	m.repo.EXPECT().ExistsByLicensePlate(gomock.Any(), "AB-123", "v1").Return(true, nil)
+
+	err := svc.Undelete(t.Context(), "v1", ports.UndeleteOptions{})
+	var uniqueErr *domain.UniqueViolationError
+	require.ErrorAs(t, err, &uniqueErr)
+	assert.Equal(t, "license_plate", uniqueErr.Field)
+	assert.Empty(t, m.audit)
+}
+
+func TestService_Delete_RejectsWhenActiveAssignments(t *testing.T) {
+	for _, cascade := range []bool{false, true} {
+		t.Run("cascade="+strconv.FormatBool(cascade), func(t *testing.T) {
+			svc, m := newService(t)
+			m.repo.EXPECT().FindByID(gomock.Any(), "v1").Return(&domain.Vehicle{ID: "v1", FleetID: "f1"}, nil)
+			m.fleetRepo.EXPECT().FindByID(gomock.Any(), "f1").Return(&domain.Fleet{ID: "f1", LegalEntityID: "le1"}, nil)
+			m.assignmentRepo.EXPECT().ExistsActiveByVehicleID(gomock.Any(), "v1").Return(true, nil)
+
+			err := svc.Delete(t.Context(), "v1", ports.DeleteOptions{Cascade: cascade})
+			assert.ErrorIs(t, err, domain.ErrVehicleHasActiveAssignments)
+			assert.Empty(t, m.audit)
+		})
+	}
+}
+
+func TestService_Delete_Success(t *testing.T) {
+	svc, m := newService(t)
+
+	before := &domain.Vehicle{ID: "v1", FleetID: "f1", LicensePlate: "AB-123"}
+	m.repo.EXPECT().FindByID(gomock.Any(), "v1").Return(before, nil)
+	m.fleetRepo.EXPECT().FindByID(gomock.Any(), "f1").Return(&domain.Fleet{ID: "f1", LegalEntityID: "le1"}, nil)
+	m.assignmentRepo.EXPECT().ExistsActiveByVehicleID(gomock.Any(), "v1").Return(false, nil)
+	m.repo.EXPECT().SoftDelete(gomock.Any(), "v1", servicetest.Deletion).Return(nil)
+
+	require.NoError(t, svc.Delete(t.Context(), "v1", ports.DeleteOptions{}))
+	require.Len(t, m.audit, 1)
+	assert.Equal(t, domain.AuditDelete, m.audit[0].Action)
+	assert.Equal(t, *before, m.audit[0].Before)
+}
+
+func TestService_Delete_Cascade(t *testing.T) {
+	svc, m := newService(t)
+
+	m.repo.EXPECT().FindByID(gomock.Any(), "v1").Return(&domain.Vehicle{ID: "v1", FleetID: "f1"}, nil)
+	m.fleetRepo.EXPECT().FindByID(gomock.Any(), "f1").Return(&domain.Fleet{ID: "f1", LegalEntityID: "le1"}, nil)
+	m.assignmentRepo.EXPECT().ExistsActiveByVehicleID(gomock.Any(), "v1").Return(false, nil)
+	gomock.InOrder(
+		m.repo.EXPECT().SoftDelete(gomock.Any(), "v1", servicetest.Deletion).Return(nil),
+		m.assignmentRepo.EXPECT().SoftDeleteByVehicleID(gomock.Any(), "v1", servicetest.Deletion).
+			Return([]*domain.VehicleAssignment{{ID: "a1", VehicleID: "v1"}}, nil),
+	)
+
+	require.NoError(t, svc.Delete(t.Context(), "v1", ports.DeleteOptions{Cascade: true}))
+	require.Len(t, m.audit, 2)
+	assert.Equal(t, domain.EntityVehicleAssignment, m.audit[0].EntityType)
+	assert.Equal(t, domain.VehicleAssignment{ID: "a1", VehicleID: "v1"}, m.audit[0].Before)
+	assert.Equal(t, domain.EntityVehicle, m.audit[1].EntityType)
+	for _, e := range m.audit {
+		assert.Equal(t, domain.AuditDelete, e.Action)
+		assert.Equal(t, "test-id", e.DeletionID)
+	}
+}
+
+func TestService_Undelete_RejectsWhenFleetDeleted(t *testing.T) {
+	svc, m := newService(t)
+
+	m.repo.EXPECT().FindDeletedByID(gomock.Any(), "v1").Return(&domain.Vehicle{ID: "v1", FleetID: "f1"}, nil)
+	m.fleetRepo.EXPECT().FindByID(gomock.Any(), "f1").Return(nil, domain.ErrNotFound)
+
+	err := svc.Undelete(t.Context(), "v1", ports.UndeleteOptions{})
+	var parentErr *domain.ParentDeletedError
+	require.ErrorAs(t, err, &parentErr)
+	assert.Equal(t, domain.ParentDeletedError{Parent: "fleet", ID: "f1"}, *parentErr)
+	assert.Empty(t, m.audit)
+}
+
+func TestService_Undelete_Cascade(t *testing.T) {
+	svc, m := newService(t)
+
+	deleted := &domain.Vehicle{ID: "v1", FleetID: "f1", LicensePlate: "AB-123", DeletionID: "d1"}
+	m.repo.EXPECT().FindDeletedByID(gomock.Any(), "v1").Return(deleted, nil)
+	m.fleetRepo.EXPECT().FindByID(gomock.Any(), "f1").Return(&domain.Fleet{ID: "f1", LegalEntityID: "le1"}, nil)
+	m.repo.EXPECT().ExistsByLicensePlate(gomock.Any(), "AB-123", "v1").Return(false, nil)
+	gomock.InOrder(
+		m.repo.EXPECT().Undelete(gomock.Any(), "v1", servicetest.Now, "").Return(nil),
+		m.assignmentRepo.EXPECT().UndeleteByVehicleID(gomock.Any(), "v1", "d1", servicetest.Now, "").
+			Return([]*domain.VehicleAssignment{{ID: "a1", VehicleID: "v1"}}, nil),
+	)
+	m.repo.EXPECT().FindByID(gomock.Any(), "v1").Return(&domain.Vehicle{ID: "v1", FleetID: "f1"}, nil)
+
+	require.NoError(t, svc.Undelete(t.Context(), "v1", ports.UndeleteOptions{Cascade: true}))
+	require.Len(t, m.audit, 2)
+	assert.Equal(t, domain.EntityVehicleAssignment, m.audit[0].EntityType)
+	assert.Equal(t, domain.VehicleAssignment{ID: "a1", VehicleID: "v1"}, m.audit[0].After)
+	assert.Equal(t, domain.EntityVehicle, m.audit[1].EntityType)
+	for _, e := range m.audit {
+		assert.Equal(t, domain.AuditUndelete, e.Action)
+		assert.Equal(t, "d1", e.DeletionID)
+	}
+}
@@ -0,0 +1,456 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/logctx"
+)
+
+var tracer = otel.Tracer("github.com/albenik/uber-fx-based-service-example/internal/core/services/webhook")
+
+const (
+	// minSecretLength keeps signing secrets from being guessable.
+	minSecretLength = 16
+	// batchSize is the number of deliveries claimed by one DeliverBatch call.
+	batchSize = 20
+	// leaseDuration must cover sending a whole batch; a delivery still unconfirmed
+	// when its lease expires may be sent twice.
+	leaseDuration = 5 * time.Minute
+	// maxAttempts is the number of attempts after which a delivery is given up.
+	maxAttempts = 8
+	// minRetryDelay and maxRetryDelay bound the exponential backoff between attempts.
+	minRetryDelay = 30 * time.Second
+	maxRetryDelay = time.Hour
+	// disableAfterFailures is the number of consecutive failed attempts after which
+	// a subscription is deactivated.
+	disableAfterFailures = 20
+)
+
+type IDGenerator func() string
+
+type Clock func() time.Time
+
+// Service manages webhook subscriptions and delivers events to them.
+type Service struct {
+	subs       ports.WebhookSubscriptionRepository
+	deliveries ports.WebhookDeliveryRepository
+	tx         ports.TxManager
+	auditLog   ports.AuditLog
+	authz      ports.Authorizer
+	sender     ports.WebhookSender
+	idGen      IDGenerator
+	clock      Clock
+	logger     *zap.Logger
+}
+
+func New(
+	subs ports.WebhookSubscriptionRepository,
+	deliveries ports.WebhookDeliveryRepository,
+	tx ports.TxManager,
+	auditLog ports.AuditLog,
+	authz ports.Authorizer,
+	sender ports.WebhookSender,
+	idGen IDGenerator,
+	clock Clock,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		subs:       subs,
+		deliveries: deliveries,
+		tx:         tx,
+		auditLog:   auditLog,
+		authz:      authz,
+		sender:     sender,
+		idGen:      idGen,
+		clock:      clock,
+		logger:     logger,
+	}
+}
+
+func (s *Service) Create(ctx context.Context, rawURL string, eventTypes []domain.EventType, secret string) (*domain.WebhookSubscription, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.Create")
+	defer span.End()
+
+	if err := s.authorize(ctx, domain.ActionCreate); err != nil {
+		return nil, err
+	}
+	rawURL, err := validateURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	eventTypes, err = validateEventTypes(eventTypes)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSecret(secret); err != nil {
+		return nil, err
+	}
+	id := s.idGen()
+	if id == "" {
+		return nil, fmt.Errorf("id generator returned empty ID")
+	}
+	now, actor := s.clock(), domain.PrincipalFromContext(ctx).ID
+	sub := &domain.WebhookSubscription{
+		ID: id, URL: rawURL, EventTypes: eventTypes, Secret: secret, Active: true,
+		CreatedAt: now, CreatedBy: actor, UpdatedAt: now, UpdatedBy: actor,
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.subs.Save(ctx, sub); err != nil {
+			return err
+		}
+		return s.record(ctx, domain.AuditCreate, id, nil, redact(*sub))
+	})
+	if err != nil {
+		logctx.From(ctx, s.logger).Error("Failed to save webhook subscription", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+	logctx.From(ctx, s.logger).Info("Created webhook subscription", zap.String("id", id), zap.String("url", rawURL))
+	out := *sub
+	return &out, nil
+}
+
+func (s *Service) Get(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.Get")
+	defer span.End()
+
+	if id == "" {
+		return nil, fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
+	}
+	if err := s.authorize(ctx, domain.ActionRead); err != nil {
+		return nil, err
+	}
+	return s.subs.FindByID(ctx, id)
+}
+
+func (s *Service) List(ctx context.Context, q ports.ListQuery) (*ports.Page[*domain.WebhookSubscription], error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.List")
+	defer span.End()
+
+	if err := s.authorize(ctx, domain.ActionRead); err != nil {
+		return nil, err
+	}
+	q, err := q.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	return s.subs.FindAll(ctx, q)
+}
+
+func (s *Service) Update(ctx context.Context, id string, version int64, patch domain.WebhookSubscriptionPatch) (*domain.WebhookSubscription, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.Update")
+	defer span.End()
+
+	if id == "" {
+		return nil, fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
+	}
+	if patch.URL == nil && patch.EventTypes == nil && patch.Secret == nil && patch.Active == nil {
+		return nil, fmt.Errorf("%w: nothing to update", domain.ErrInvalidInput)
+	}
+	if err := s.authorize(ctx, domain.ActionUpdate); err != nil {
+		return nil, err
+	}
+	sub, err := s.subs.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if version != 0 && sub.Version != version {
+		return nil, domain.ErrPreconditionFailed
+	}
+	before := redact(*sub)
+	if patch.URL != nil {
+		if sub.URL, err = validateURL(*patch.URL); err != nil {
+			return nil, err
+		}
+	}
+	if patch.EventTypes != nil {
+		if sub.EventTypes, err = validateEventTypes(patch.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+	if patch.Secret != nil {
+		if err := validateSecret(*patch.Secret); err != nil {
+			return nil, err
+		}
+		sub.Secret = *patch.Secret
+	}
+	if patch.Active != nil {
+		// Reactivating gives the subscription a fresh start after an automatic deactivation.
+		if *patch.Active && !sub.Active {
+			sub.ConsecutiveFailures, sub.DisabledAt = 0, nil
+		}
+		sub.Active = *patch.Active
+	}
+	sub.UpdatedAt, sub.UpdatedBy = s.clock(), domain.PrincipalFromContext(ctx).ID
+	unsaved := *sub
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		*sub = unsaved
+		if err := s.subs.Save(ctx, sub); err != nil {
+			return err
+		}
+		return s.record(ctx, domain.AuditUpdate, id, before, redact(*sub))
+	})
+	if err != nil {
+		logctx.From(ctx, s.logger).Error("Failed to save webhook subscription", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+	logctx.From(ctx, s.logger).Info("Updated webhook subscription", zap.String("id", id))
+	out := *sub
+	return &out, nil
+}
+
+func (s *Service) Delete(ctx context.Context, id string) error {
+	ctx, span := tracer.Start(ctx, "WebhookService.Delete")
+	defer span.End()
+
+	if id == "" {
+		return fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
+	}
+	if err := s.authorize(ctx, domain.ActionDelete); err != nil {
+		return err
+	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.subs.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.subs.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.record(ctx, domain.AuditDelete, id, redact(*before), nil)
+	})
+}
+
+func (s *Service) ListDeliveries(ctx context.Context, subscriptionID string, q ports.ListQuery) (*ports.Page[*domain.WebhookDelivery], error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.ListDeliveries")
+	defer span.End()
+
+	if subscriptionID == "" {
+		return nil, fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
+	}
+	if err := s.authorize(ctx, domain.ActionRead); err != nil {
+		return nil, err
+	}
+	q, err := q.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.subs.FindByID(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	return s.deliveries.FindBySubscriptionID(ctx, subscriptionID, q)
+}
+
+func (s *Service) Replay(ctx context.Context, subscriptionID, deliveryID string) (*domain.WebhookDelivery, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.Replay")
+	defer span.End()
+
+	if subscriptionID == "" || deliveryID == "" {
+		return nil, fmt.Errorf("%w: id is required", domain.ErrInvalidInput)
+	}
+	if err := s.authorize(ctx, domain.ActionUpdate); err != nil {
+		return nil, err
+	}
+	sub, err := s.subs.FindByID(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if !sub.Active {
+		return nil, fmt.Errorf("%w: subscription is inactive", domain.ErrConflict)
+	}
+	d, err := s.deliveries.FindByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if d.SubscriptionID != subscriptionID {
+		return nil, domain.ErrNotFound
+	}
+	if d.Status == domain.WebhookDeliveryPending {
+		return nil, fmt.Errorf("%w: delivery is pending", domain.ErrConflict)
+	}
+	if err := s.deliveries.Reschedule(ctx, deliveryID, s.clock()); err != nil {
+		return nil, err
+	}
+	logctx.From(ctx, s.logger).Info("Replaying webhook delivery",
+		zap.String("subscription_id", subscriptionID),
+		zap.String("id", deliveryID),
+	)
+	return s.deliveries.FindByID(ctx, deliveryID)
+}
+
+// Publish schedules a delivery of event to every active subscription for its type.
+// The relay may publish an event more than once; the repository keeps a single
+// delivery per subscription and event.
+func (s *Service) Publish(ctx context.Context, event *domain.Event) error {
+	ctx, span := tracer.Start(ctx, "WebhookService.Publish")
+	defer span.End()
+
+	subs, err := s.subs.FindActiveByEventType(ctx, event.Type)
+	if err != nil || len(subs) == 0 {
+		return err
+	}
+	now := s.clock()
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		for _, sub := range subs {
+			err := s.deliveries.Create(ctx, &domain.WebhookDelivery{
+				ID:             s.idGen(),
+				SubscriptionID: sub.ID,
+				Event:          *event,
+				Status:         domain.WebhookDeliveryPending,
+				NextAttemptAt:  now,
+				CreatedAt:      now,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeliverBatch sends a batch of due deliveries. Failed attempts are retried with
+// exponential backoff up to maxAttempts and count towards deactivating the subscription.
+func (s *Service) DeliverBatch(ctx context.Context) (int, error) {
+	now := s.clock()
+	deliveries, err := s.deliveries.Claim(ctx, batchSize, now, now.Add(leaseDuration))
+	if err != nil {
+		return 0, err
+	}
+	for _, d := range deliveries {
+		if err := s.deliver(ctx, d); err != nil {
+			return 0, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+func (s *Service) deliver(ctx context.Context, d *domain.WebhookDelivery) error {
+	sub, err := s.subs.FindByID(ctx, d.SubscriptionID)
+	if errors.Is(err, domain.ErrNotFound) {
+		// Deleted after the claim together with the delivery.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	status, sendErr := s.sender.Send(ctx, sub.URL, sub.Secret, &d.Event)
+	if sendErr == nil {
+		if err := s.deliveries.MarkSucceeded(ctx, d.ID, status, s.clock()); err != nil {
+			return err
+		}
+		if sub.ConsecutiveFailures > 0 {
+			return s.subs.ResetFailures(ctx, sub.ID)
+		}
+		return nil
+	}
+
+	attempts := d.Attempts + 1
+	var retryAt *time.Time
+	if attempts < maxAttempts {
+		t := s.clock().Add(retryDelay(attempts))
+		retryAt = &t
+	}
+	s.logger.Warn("Failed to deliver webhook",
+		zap.String("id", d.ID),
+		zap.String("subscription_id", sub.ID),
+		zap.String("event_id", d.Event.ID),
+		zap.Int("attempts", attempts),
+		zap.Bool("gave_up", retryAt == nil),
+		zap.Error(sendErr),
+	)
+	if err := s.deliveries.MarkFailed(ctx, d.ID, status, sendErr.Error(), retryAt); err != nil {
+		return err
+	}
+	disabled, err := s.subs.RecordFailure(ctx, sub.ID, disableAfterFailures, s.clock())
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+	if disabled {
+		s.logger.Warn("Deactivated webhook subscription after repeated failures",
+			zap.String("id", sub.ID),
+			zap.Int("failures", disableAfterFailures),
+		)
+	}
+	return nil
+}
+
+// authorize checks the principal may manage webhook subscriptions. The background
+// delivery is not authorized: it runs on behalf of no one.
+func (s *Service) authorize(ctx context.Context, action domain.Action) error {
+	return s.authz.Authorize(ctx, action, domain.Resource{Type: domain.EntityWebhook})
+}
+
+// record appends an audit entry for a change of the subscription with the given ID.
+func (s *Service) record(ctx context.Context, action domain.AuditAction, id string, before, after any) error {
+	return s.auditLog.Append(ctx, &domain.AuditEntry{
+		ID:         s.idGen(),
+		EntityType: domain.EntityWebhook,
+		EntityID:   id,
+		Action:     action,
+		Actor:      domain.PrincipalFromContext(ctx).ID,
+		At:         s.clock(),
+		Before:     before,
+		After:      after,
+	})
+}
+
+// redact returns a copy of sub safe to keep in the audit log.
+func redact(sub domain.WebhookSubscription) domain.WebhookSubscription {
+	sub.Secret = ""
+	return sub
+}
+
+func validateURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", fmt.Errorf("%w: url is required", domain.ErrInvalidInput)
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("%w: url must be an absolute http or https URL", domain.ErrInvalidInput)
+	}
+	return raw, nil
+}
+
+// validateEventTypes checks that all types are known and drops duplicates.
+func validateEventTypes(types []domain.EventType) ([]domain.EventType, error) {
+	if len(types) == 0 {
+		return nil, fmt.Errorf("%w: event_types is required", domain.ErrInvalidInput)
+	}
+	out := make([]domain.EventType, 0, len(types))
+	for _, t := range types {
+		if !t.Valid() {
+			return nil, fmt.Errorf("%w: unknown event type %q", domain.ErrInvalidInput, t)
+		}
+		if !slices.Contains(out, t) {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func validateSecret(secret string) error {
+	if len(secret) < minSecretLength {
+		return fmt.Errorf("%w: secret must be at least %d characters", domain.ErrInvalidInput, minSecretLength)
+	}
+	return nil
+}
+
+// retryDelay returns the delay before the next attempt after the given number of failed ones.
+func retryDelay(attempts int) time.Duration {
+	d := minRetryDelay
+	for i := 1; i < attempts && d < maxRetryDelay; i++ {
+		d *= 2
+	}
+	return min(d, maxRetryDelay)
+}
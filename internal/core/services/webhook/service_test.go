@@ -0,0 +1,231 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/core/domain"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/ports/mocks"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/servicetest"
+	"github.com/albenik/uber-fx-based-service-example/internal/core/services/webhook"
+)
+
+const secret = "0123456789abcdef"
+
+type serviceMocks struct {
+	subs       *mocks.MockWebhookSubscriptionRepository
+	deliveries *mocks.MockWebhookDeliveryRepository
+	auditLog   *mocks.MockAuditLog
+	sender     *mocks.MockWebhookSender
+}
+
+func setupService(t *testing.T) (*webhook.Service, serviceMocks) {
+	ctrl := gomock.NewController(t)
+	m := serviceMocks{
+		subs:       mocks.NewMockWebhookSubscriptionRepository(ctrl),
+		deliveries: mocks.NewMockWebhookDeliveryRepository(ctrl),
+		auditLog:   mocks.NewMockAuditLog(ctrl),
+		sender:     mocks.NewMockWebhookSender(ctrl),
+	}
+	svc := webhook.New(m.subs, m.deliveries, servicetest.PassthroughTx(ctrl), m.auditLog, servicetest.AllowAll(ctrl),
+		m.sender, servicetest.IDGen, servicetest.Clock, zaptest.NewLogger(t))
+	return svc, m
+}
+
+func TestService_Create_RedactsSecretInAuditLog(t *testing.T) {
+	svc, m := setupService(t)
+
+	m.subs.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+	m.auditLog.EXPECT().Append(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, e *domain.AuditEntry) error {
+			after, ok := e.After.(domain.WebhookSubscription)
+			require.True(t, ok)
+			assert.Empty(t, after.Secret)
+			assert.Equal(t, domain.EntityWebhook, e.EntityType)
+			return nil
+		})
+
+	sub, err := svc.Create(t.Context(), " https://partner.example/hook ",
+		[]domain.EventType{domain.EventContractTerminated, domain.EventContractTerminated}, secret)
+	require.NoError(t, err)
+	assert.Equal(t, "https://partner.example/hook", sub.URL)
+	assert.Equal(t, []domain.EventType{domain.EventContractTerminated}, sub.EventTypes)
+	assert.Equal(t, secret, sub.Secret)
+	assert.True(t, sub.Active)
+}
+
+func TestService_Create_Validation(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		eventTypes []domain.EventType
+		secret     string
+	}{
+		{"missing url", "", []domain.EventType{domain.EventContractCreated}, secret},
+		{"relative url", "/hook", []domain.EventType{domain.EventContractCreated}, secret},
+		{"unsupported scheme", "ftp://partner.example", []domain.EventType{domain.EventContractCreated}, secret},
+		{"no event types", "https://partner.example", nil, secret},
+		{"unknown event type", "https://partner.example", []domain.EventType{"driver.created"}, secret},
+		{"short secret", "https://partner.example", []domain.EventType{domain.EventContractCreated}, "short"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := setupService(t)
+			_, err := svc.Create(t.Context(), tt.url, tt.eventTypes, tt.secret)
+			assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		})
+	}
+}
+
+func TestService_Update_ReactivationResetsFailures(t *testing.T) {
+	svc, m := setupService(t)
+
+	disabledAt := servicetest.Now.Add(-time.Hour)
+	m.subs.EXPECT().FindByID(gomock.Any(), "w1").Return(&domain.WebhookSubscription{
+		ID: "w1", Version: 3, ConsecutiveFailures: 20, DisabledAt: &disabledAt,
+	}, nil)
+	m.subs.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+	m.auditLog.EXPECT().Append(gomock.Any(), gomock.Any()).Return(nil)
+
+	active := true
+	sub, err := svc.Update(t.Context(), "w1", 3, domain.WebhookSubscriptionPatch{Active: &active})
+	require.NoError(t, err)
+	assert.True(t, sub.Active)
+	assert.Zero(t, sub.ConsecutiveFailures)
+	assert.Nil(t, sub.DisabledAt)
+}
+
+func TestService_Publish_SchedulesDeliveryPerSubscription(t *testing.T) {
+	svc, m := setupService(t)
+
+	event := &domain.Event{ID: "e1", Type: domain.EventAssignmentReturned, OccurredAt: servicetest.Now}
+	m.subs.EXPECT().FindActiveByEventType(gomock.Any(), domain.EventAssignmentReturned).
+		Return([]*domain.WebhookSubscription{{ID: "w1"}, {ID: "w2"}}, nil)
+	var got []string
+	m.deliveries.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, d *domain.WebhookDelivery) error {
+			assert.Equal(t, *event, d.Event)
+			assert.Equal(t, domain.WebhookDeliveryPending, d.Status)
+			assert.Equal(t, servicetest.Now, d.NextAttemptAt)
+			got = append(got, d.SubscriptionID)
+			return nil
+		}).Times(2)
+
+	require.NoError(t, svc.Publish(t.Context(), event))
+	assert.Equal(t, []string{"w1", "w2"}, got)
+}
+
+func TestService_DeliverBatch_Succeeds(t *testing.T) {
+	svc, m := setupService(t)
+
+	d := &domain.WebhookDelivery{ID: "d1", SubscriptionID: "w1", Event: domain.Event{ID: "e1"}}
+	m.deliveries.EXPECT().Claim(gomock.Any(), gomock.Any(), servicetest.Now, gomock.Any()).Return([]*domain.WebhookDelivery{d}, nil)
+	m.subs.EXPECT().FindByID(gomock.Any(), "w1").Return(&domain.WebhookSubscription{
+		ID: "w1", URL: "https://partner.example/hook", Secret: secret, Active: true, ConsecutiveFailures: 2,
+	}, nil)
+	m.sender.EXPECT().Send(gomock.Any(), "https://partner.example/hook", secret, &d.Event).Return(204, nil)
+	m.deliveries.EXPECT().MarkSucceeded(gomock.Any(), "d1", 204, servicetest.Now).Return(nil)
+	m.subs.EXPECT().ResetFailures(gomock.Any(), "w1").Return(nil)
+
+	n, err := svc.DeliverBatch(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestService_DeliverBatch_Failure(t *testing.T) {
+	retryAt := func(d time.Duration) *time.Time {
+		at := servicetest.Now.Add(d)
+		return &at
+	}
+	tests := []struct {
+		name      string
+		attempts  int
+		wantRetry *time.Time
+	}{
+		{"first failure", 0, retryAt(30 * time.Second)},
+		{"backs off", 3, retryAt(4 * time.Minute)},
+		{"gives up", 7, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := setupService(t)
+
+			d := &domain.WebhookDelivery{ID: "d1", SubscriptionID: "w1", Attempts: tt.attempts}
+			m.deliveries.EXPECT().Claim(gomock.Any(), gomock.Any(), servicetest.Now, gomock.Any()).Return([]*domain.WebhookDelivery{d}, nil)
+			m.subs.EXPECT().FindByID(gomock.Any(), "w1").Return(&domain.WebhookSubscription{ID: "w1", Active: true}, nil)
+			m.sender.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(500, errors.New("webhook responded with status 500"))
+			m.deliveries.EXPECT().MarkFailed(gomock.Any(), "d1", 500, "webhook responded with status 500", tt.wantRetry).Return(nil)
+			m.subs.EXPECT().RecordFailure(gomock.Any(), "w1", 20, servicetest.Now).Return(false, nil)
+
+			n, err := svc.DeliverBatch(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, 1, n)
+		})
+	}
+}
+
+func TestService_Replay(t *testing.T) {
+	tests := []struct {
+		name     string
+		sub      *domain.WebhookSubscription
+		delivery *domain.WebhookDelivery
+		wantErr  error
+	}{
+		{
+			name:     "inactive subscription",
+			sub:      &domain.WebhookSubscription{ID: "w1"},
+			delivery: nil,
+			wantErr:  domain.ErrConflict,
+		},
+		{
+			name:     "delivery of another subscription",
+			sub:      &domain.WebhookSubscription{ID: "w1", Active: true},
+			delivery: &domain.WebhookDelivery{ID: "d1", SubscriptionID: "w2", Status: domain.WebhookDeliveryFailed},
+			wantErr:  domain.ErrNotFound,
+		},
+		{
+			name:     "pending delivery",
+			sub:      &domain.WebhookSubscription{ID: "w1", Active: true},
+			delivery: &domain.WebhookDelivery{ID: "d1", SubscriptionID: "w1", Status: domain.WebhookDeliveryPending},
+			wantErr:  domain.ErrConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := setupService(t)
+
+			m.subs.EXPECT().FindByID(gomock.Any(), "w1").Return(tt.sub, nil)
+			if tt.delivery != nil {
+				m.deliveries.EXPECT().FindByID(gomock.Any(), "d1").Return(tt.delivery, nil)
+			}
+
+			_, err := svc.Replay(t.Context(), "w1", "d1")
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestService_Replay_Reschedules(t *testing.T) {
+	svc, m := setupService(t)
+
+	m.subs.EXPECT().FindByID(gomock.Any(), "w1").Return(&domain.WebhookSubscription{ID: "w1", Active: true}, nil)
+	gomock.InOrder(
+		m.deliveries.EXPECT().FindByID(gomock.Any(), "d1").
+			Return(&domain.WebhookDelivery{ID: "d1", SubscriptionID: "w1", Status: domain.WebhookDeliveryFailed, Attempts: 8}, nil),
+		m.deliveries.EXPECT().Reschedule(gomock.Any(), "d1", servicetest.Now).Return(nil),
+		m.deliveries.EXPECT().FindByID(gomock.Any(), "d1").
+			Return(&domain.WebhookDelivery{ID: "d1", SubscriptionID: "w1", Status: domain.WebhookDeliveryPending}, nil),
+	)
+
+	d, err := svc.Replay(t.Context(), "w1", "d1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.WebhookDeliveryPending, d.Status)
+}
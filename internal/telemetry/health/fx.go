@@ -0,0 +1,50 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/config"
+)
+
+// Module provides the probe registry of the checkers in the "health" group and fails
+// readiness as soon as the app starts stopping. It must come after every module with
+// a lifecycle hook, so that its OnStop hook runs first.
+func Module() fx.Option {
+	return fx.Module("health",
+		fx.Provide(
+			fx.Annotate(
+				newRegistry,
+				fx.ParamTags(`group:"health"`),
+			),
+		),
+		fx.Invoke(drainLifecycle),
+	)
+}
+
+func newRegistry(checkers []Checker, cfg *config.HealthConfig) *Registry {
+	return NewRegistry(checkers, cfg.CheckTimeout)
+}
+
+func drainLifecycle(lc fx.Lifecycle, registry *Registry, cfg *config.HealthConfig, logger *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			registry.Drain()
+			if cfg.DrainDelay <= 0 {
+				return nil
+			}
+			logger.Info("Draining before shutdown", zap.Duration("delay", cfg.DrainDelay))
+			timer := time.NewTimer(cfg.DrainDelay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+}
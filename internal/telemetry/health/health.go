@@ -0,0 +1,129 @@
+// Package health serves the liveness and readiness probes of the service. Adapters
+// contribute the checks of their dependencies to the "health" value group; the
+// readiness probe runs all of them.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Probe statuses reported in the JSON body of /livez and /readyz.
+const (
+	StatusOK       = "ok"
+	StatusFailing  = "failing"
+	StatusDraining = "draining"
+)
+
+// Checker checks one dependency of the service. Check returns nil when the dependency
+// is usable.
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// NewChecker creates a checker named name that calls check.
+func NewChecker(name string, check func(ctx context.Context) error) Checker {
+	return funcChecker{name: name, check: check}
+}
+
+type funcChecker struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+func (c funcChecker) Name() string { return c.name }
+
+func (c funcChecker) Check(ctx context.Context) error { return c.check(ctx) }
+
+// Registry holds the named checkers of the service and serves the probes.
+type Registry struct {
+	checkers []Checker
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+// NewRegistry creates a registry of checkers, each of which is given timeout to
+// complete.
+func NewRegistry(checkers []Checker, timeout time.Duration) *Registry {
+	return &Registry{checkers: checkers, timeout: timeout}
+}
+
+// Drain fails every readiness probe from now on.
+func (r *Registry) Drain() {
+	r.draining.Store(true)
+}
+
+// Report is the JSON body of a probe.
+type Report struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks,omitempty"`
+}
+
+// CheckResult is the outcome of one checker.
+type CheckResult struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Check runs every checker concurrently and reports their results in registration
+// order. The report is failing if any checker fails, and draining once Drain has been
+// called.
+func (r *Registry) Check(ctx context.Context) Report {
+	results := make([]CheckResult, len(r.checkers))
+	var wg sync.WaitGroup
+	for i, c := range r.checkers {
+		wg.Go(func() {
+			ctx, cancel := context.WithTimeout(ctx, r.timeout)
+			defer cancel()
+			start := time.Now()
+			err := c.Check(ctx)
+			results[i] = CheckResult{Name: c.Name(), Status: StatusOK, Duration: time.Since(start).String()}
+			if err != nil {
+				results[i].Status = StatusFailing
+				results[i].Error = err.Error()
+			}
+		})
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: results}
+	for _, res := range results {
+		if res.Status != StatusOK {
+			report.Status = StatusFailing
+		}
+	}
+	if r.draining.Load() {
+		report.Status = StatusDraining
+	}
+	return report
+}
+
+// Livez reports that the process is up and serving. It checks no dependencies, so that
+// an outage of one does not get the service restarted.
+func (r *Registry) Livez(w http.ResponseWriter, _ *http.Request) {
+	writeReport(w, Report{Status: StatusOK})
+}
+
+// Readyz reports whether the service can take traffic: every checker passes and the
+// app is not stopping.
+func (r *Registry) Readyz(w http.ResponseWriter, req *http.Request) {
+	writeReport(w, r.Check(req.Context()))
+}
+
+func writeReport(w http.ResponseWriter, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Status == StatusOK {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,89 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/config"
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/health"
+)
+
+func probe(t *testing.T, handler http.HandlerFunc) (int, health.Report) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var report health.Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	return rec.Code, report
+}
+
+func TestRegistry_Readyz_ReportsEveryCheck(t *testing.T) {
+	registry := health.NewRegistry([]health.Checker{
+		health.NewChecker("postgres_master", func(context.Context) error { return nil }),
+		health.NewChecker("grpc_driver_license", func(context.Context) error {
+			return errors.New("connection is TRANSIENT_FAILURE")
+		}),
+		health.NewChecker("slow", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+	}, 10*time.Millisecond)
+
+	code, report := probe(t, registry.Readyz)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, health.StatusFailing, report.Status)
+	require.Len(t, report.Checks, 3)
+	assert.Equal(t, "postgres_master", report.Checks[0].Name)
+	assert.Equal(t, health.StatusOK, report.Checks[0].Status)
+	assert.Empty(t, report.Checks[0].Error)
+	assert.Equal(t, "grpc_driver_license", report.Checks[1].Name)
+	assert.Equal(t, health.StatusFailing, report.Checks[1].Status)
+	assert.Equal(t, "connection is TRANSIENT_FAILURE", report.Checks[1].Error)
+	assert.Equal(t, "slow", report.Checks[2].Name)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks[2].Error)
+
+	code, report = probe(t, registry.Livez)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusOK, report.Status)
+	assert.Empty(t, report.Checks)
+}
+
+func TestModule_FailsReadinessOnStop(t *testing.T) {
+	var registry *health.Registry
+	app := fxtest.New(t,
+		fx.Supply(&config.HealthConfig{CheckTimeout: time.Second}),
+		fx.Supply(zaptest.NewLogger(t)),
+		fx.Supply(fx.Annotate(
+			health.NewChecker("postgres_master", func(context.Context) error { return nil }),
+			fx.As(new(health.Checker)),
+			fx.ResultTags(`group:"health"`),
+		)),
+		health.Module(),
+		fx.Populate(&registry),
+	)
+	app.RequireStart()
+
+	code, report := probe(t, registry.Readyz)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusOK, report.Status)
+
+	app.RequireStop()
+
+	code, report = probe(t, registry.Readyz)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, health.StatusDraining, report.Status)
+	code, _ = probe(t, registry.Livez)
+	assert.Equal(t, http.StatusOK, code)
+}
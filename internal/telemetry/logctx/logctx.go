@@ -0,0 +1,24 @@
+// Package logctx carries a request-scoped logger in a context, so that everything
+// logged while serving a request can be correlated with it.
+package logctx
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type loggerKey struct{}
+
+// With returns a copy of ctx that carries logger.
+func With(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// From returns the logger carried by ctx, or fallback when there is none.
+func From(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+		return logger
+	}
+	return fallback
+}
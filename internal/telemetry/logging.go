@@ -38,7 +38,8 @@ func NewLogger() (*zap.Logger, zap.AtomicLevel, error) {
 	return logger, level, nil
 }
 
-// ReconfigureLogLevel sets the logger level from config. Called after config validation.
+// ReconfigureLogLevel sets the logger level from config. Called after config validation;
+// the admin server can change the level later on.
 func ReconfigureLogLevel(level zap.AtomicLevel, cfg *config.TelemetryConfig) error {
 	var lvl zapcore.Level
 	if err := lvl.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
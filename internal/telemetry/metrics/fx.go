@@ -0,0 +1,23 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/fx"
+)
+
+// Module provides the metrics registry and instruments. The admin server serves them
+// at /metrics.
+func Module() fx.Option {
+	return fx.Module("metrics",
+		fx.Provide(
+			fx.Annotate(
+				NewRegistry,
+				fx.ParamTags(`group:"metrics"`),
+				fx.As(new(prometheus.Registerer)),
+				fx.As(new(prometheus.Gatherer)),
+			),
+			NewHTTP,
+			NewGRPCClient,
+		),
+	)
+}
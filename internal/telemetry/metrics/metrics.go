@@ -0,0 +1,121 @@
+// Package metrics exposes Prometheus metrics of the service. Instruments of the
+// adapters are defined here; collectors that need an adapter of their own, such as
+// connection pool stats, are contributed to the "metrics" value group.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// unmatchedRoute labels requests that matched no route, so that arbitrary paths do
+// not create new series.
+const unmatchedRoute = "unmatched"
+
+// NewRegistry creates the registry of the service with the Go runtime and process
+// collectors and every collector of the group.
+func NewRegistry(cs []prometheus.Collector) (*prometheus.Registry, error) {
+	reg := prometheus.NewRegistry()
+	all := append([]prometheus.Collector{
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	}, cs...)
+	for _, c := range all {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return reg, nil
+}
+
+// HTTP measures the requests served by the public HTTP server.
+type HTTP struct {
+	duration *prometheus.HistogramVec
+}
+
+// NewHTTP creates the HTTP instruments and registers them with reg.
+func NewHTTP(reg prometheus.Registerer) (*HTTP, error) {
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Duration of HTTP requests by method, chi route pattern and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+	if err := reg.Register(duration); err != nil {
+		return nil, err
+	}
+	return &HTTP{duration: duration}, nil
+}
+
+// Middleware observes every request once it has been served. It is labelled by route
+// pattern rather than path, which is only known after routing.
+func (m *HTTP) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		route := unmatchedRoute
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		code := ww.Status()
+		if code == 0 {
+			code = http.StatusOK
+		}
+		m.duration.WithLabelValues(r.Method, route, strconv.Itoa(code)).Observe(time.Since(start).Seconds())
+	})
+}
+
+// GRPCClient measures the calls made by gRPC clients.
+type GRPCClient struct {
+	duration *prometheus.HistogramVec
+	requests *prometheus.CounterVec
+}
+
+// NewGRPCClient creates the gRPC client instruments and registers them with reg.
+func NewGRPCClient(reg prometheus.Registerer) (*GRPCClient, error) {
+	m := &GRPCClient{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_client_request_duration_seconds",
+			Help:    "Duration of gRPC client calls by method.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method"}),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_client_requests_total",
+			Help: "gRPC client calls by method and status code; every code but OK is an error.",
+		}, []string{"method", "code"}),
+	}
+	for _, c := range []prometheus.Collector{m.duration, m.requests} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// UnaryClientInterceptor observes every unary call of a client connection.
+func (m *GRPCClient) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		m.duration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+		m.requests.WithLabelValues(method, status.Code(err).String()).Inc()
+		return err
+	}
+}
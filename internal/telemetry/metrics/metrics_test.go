@@ -0,0 +1,82 @@
+package metrics_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/telemetry/metrics"
+)
+
+func TestHTTP_Middleware_LabelsByRoutePattern(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := metrics.NewHTTP(reg)
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Use(m.Middleware)
+	r.Route("/drivers", func(r chi.Router) {
+		r.Get("/{id}", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNotFound) })
+		r.Post("/", func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write([]byte("{}")) })
+	})
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/drivers/d-1", nil),
+		httptest.NewRequest(http.MethodGet, "/drivers/d-2", nil),
+		httptest.NewRequest(http.MethodPost, "/drivers", nil),
+		httptest.NewRequest(http.MethodGet, "/no/such/path", nil),
+	} {
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, 3, testutil.CollectAndCount(reg, "http_request_duration_seconds"))
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	counts := map[string]uint64{}
+	for _, metric := range families[0].GetMetric() {
+		var labels []string
+		for _, l := range metric.GetLabel() {
+			labels = append(labels, l.GetValue())
+		}
+		counts[strings.Join(labels, " ")] = metric.GetHistogram().GetSampleCount()
+	}
+	assert.Equal(t, map[string]uint64{
+		"GET /drivers/{id} 404": 2,
+		"POST /drivers 200":     1,
+		"GET unmatched 404":     1,
+	}, counts)
+}
+
+func TestGRPCClient_UnaryClientInterceptor_CountsCodes(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := metrics.NewGRPCClient(reg)
+	require.NoError(t, err)
+
+	intercept := m.UnaryClientInterceptor()
+	const method = "/driverlicense.v1.DriverLicenseValidationService/ValidateLicense"
+	ok := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error { return nil }
+	unavailable := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		return status.Error(codes.Unavailable, "connection refused")
+	}
+	require.NoError(t, intercept(context.Background(), method, nil, nil, nil, ok))
+	require.Error(t, intercept(context.Background(), method, nil, nil, nil, unavailable))
+
+	err = testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP grpc_client_requests_total gRPC client calls by method and status code; every code but OK is an error.
+# TYPE grpc_client_requests_total counter
+grpc_client_requests_total{code="OK",method="`+method+`"} 1
+grpc_client_requests_total{code="Unavailable",method="`+method+`"} 1
+`), "grpc_client_requests_total")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, testutil.CollectAndCount(reg, "grpc_client_request_duration_seconds"))
+}
@@ -0,0 +1,48 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/albenik/uber-fx-based-service-example/internal/config"
+)
+
+// Module installs the W3C trace context propagator and, unless TRACING_EXPORTER is
+// "none", a global tracer provider that is flushed when the app stops.
+func Module() fx.Option {
+	return fx.Module("tracing",
+		fx.Invoke(tracingLifecycle),
+	)
+}
+
+func tracingLifecycle(lc fx.Lifecycle, cfg *config.TelemetryConfig, logger *zap.Logger) error {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	if cfg.TracingExporter == "" || cfg.TracingExporter == config.TracingExporterNone {
+		return nil
+	}
+
+	provider, err := NewTracerProvider(context.Background(), cfg)
+	if err != nil {
+		return err
+	}
+	otel.SetTracerProvider(provider)
+	logger.Info("Tracing enabled",
+		zap.String("exporter", cfg.TracingExporter),
+		zap.Float64("sample_ratio", cfg.TracingSampleRatio),
+	)
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			logger.Info("Flushing traces")
+			return provider.Shutdown(ctx)
+		},
+	})
+	return nil
+}
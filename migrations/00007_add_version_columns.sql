@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE legal_entities ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE fleets ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE vehicles ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE drivers ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE contracts ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE vehicle_assignments ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE vehicle_assignments DROP COLUMN version;
+ALTER TABLE contracts DROP COLUMN version;
+ALTER TABLE drivers DROP COLUMN version;
+ALTER TABLE vehicles DROP COLUMN version;
+ALTER TABLE fleets DROP COLUMN version;
+ALTER TABLE legal_entities DROP COLUMN version;
@@ -0,0 +1,47 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- The effective period of a contract ends at its termination date if it was
+-- terminated early. GREATEST keeps the range valid for contracts terminated
+-- before they started; such ranges are empty and overlap nothing.
+-- Existing overlaps are reported up front, so they can be resolved before the
+-- migration is retried.
+-- +goose StatementBegin
+DO $$
+DECLARE
+    overlapping TEXT;
+BEGIN
+    WITH periods AS (
+        SELECT id, driver_id, legal_entity_id, fleet_id,
+               daterange(
+                   start_date,
+                   GREATEST(start_date, COALESCE((terminated_at AT TIME ZONE 'UTC')::date, end_date)),
+                   '[)'
+               ) AS period
+        FROM contracts
+        WHERE deleted_at IS NULL
+    )
+    SELECT string_agg(a.id || ' and ' || b.id, ', ') INTO overlapping
+    FROM periods a
+    JOIN periods b ON b.driver_id = a.driver_id AND b.legal_entity_id = a.legal_entity_id
+        AND b.fleet_id = a.fleet_id AND b.id > a.id AND b.period && a.period;
+    IF overlapping IS NOT NULL THEN
+        RAISE EXCEPTION 'live contracts overlap: %', overlapping
+            USING HINT = 'Terminate or soft-delete one contract of each pair.';
+    END IF;
+END
+$$;
+-- +goose StatementEnd
+ALTER TABLE contracts ADD CONSTRAINT excl_contracts_overlap EXCLUDE USING gist (
+    driver_id WITH =,
+    legal_entity_id WITH =,
+    fleet_id WITH =,
+    daterange(
+        start_date,
+        GREATEST(start_date, COALESCE((terminated_at AT TIME ZONE 'UTC')::date, end_date)),
+        '[)'
+    ) WITH &&
+) WHERE (deleted_at IS NULL);
+
+-- +goose Down
+ALTER TABLE contracts DROP CONSTRAINT excl_contracts_overlap;
@@ -0,0 +1,34 @@
+-- +goose Up
+ALTER TABLE vehicle_assignments ADD COLUMN fleet_id UUID REFERENCES fleets(id);
+UPDATE vehicle_assignments va SET fleet_id = c.fleet_id FROM contracts c WHERE c.id = va.contract_id;
+ALTER TABLE vehicle_assignments ALTER COLUMN fleet_id SET NOT NULL;
+
+-- Drivers already holding several active assignments in a fleet are reported up
+-- front, so they can be resolved before the migration is retried.
+-- +goose StatementBegin
+DO $$
+DECLARE
+    duplicates TEXT;
+BEGIN
+    SELECT string_agg(format('driver %s in fleet %s: %s', driver_id, fleet_id, ids), '; ') INTO duplicates
+    FROM (
+        SELECT driver_id, fleet_id, string_agg(id::text, ', ') AS ids
+        FROM vehicle_assignments
+        WHERE end_time IS NULL AND deleted_at IS NULL
+        GROUP BY driver_id, fleet_id
+        HAVING count(*) > 1
+    ) d;
+    IF duplicates IS NOT NULL THEN
+        RAISE EXCEPTION 'drivers with several active assignments in a fleet: %', duplicates
+            USING HINT = 'End all but one active assignment of each driver in the fleet.';
+    END IF;
+END
+$$;
+-- +goose StatementEnd
+
+CREATE UNIQUE INDEX uq_vehicle_assignments_active ON vehicle_assignments(driver_id, fleet_id)
+    WHERE end_time IS NULL AND deleted_at IS NULL;
+
+-- +goose Down
+DROP INDEX uq_vehicle_assignments_active;
+ALTER TABLE vehicle_assignments DROP COLUMN fleet_id;
@@ -0,0 +1,39 @@
+-- +goose Up
+-- Values already shared by several live rows are reported up front, so they can be
+-- resolved before the migration is retried.
+-- +goose StatementBegin
+DO $$
+DECLARE
+    duplicates TEXT;
+BEGIN
+    SELECT concat_ws('; ',
+        (SELECT string_agg(format('tax_id %L: legal entities %s', tax_id, ids), '; ')
+         FROM (SELECT tax_id, string_agg(id::text, ', ') AS ids
+               FROM legal_entities WHERE deleted_at IS NULL
+               GROUP BY tax_id HAVING count(*) > 1) d),
+        (SELECT string_agg(format('license_number %L: drivers %s', license_number, ids), '; ')
+         FROM (SELECT license_number, string_agg(id::text, ', ') AS ids
+               FROM drivers WHERE deleted_at IS NULL
+               GROUP BY license_number HAVING count(*) > 1) d),
+        (SELECT string_agg(format('license_plate %L: vehicles %s', license_plate, ids), '; ')
+         FROM (SELECT license_plate, string_agg(id::text, ', ') AS ids
+               FROM vehicles WHERE deleted_at IS NULL AND license_plate <> ''
+               GROUP BY license_plate HAVING count(*) > 1) d)
+    ) INTO duplicates;
+    IF duplicates <> '' THEN
+        RAISE EXCEPTION 'values shared by live rows: %', duplicates
+            USING HINT = 'Correct or soft-delete all but one row sharing each value.';
+    END IF;
+END
+$$;
+-- +goose StatementEnd
+CREATE UNIQUE INDEX uq_legal_entities_tax_id ON legal_entities(tax_id) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX uq_drivers_license_number ON drivers(license_number) WHERE deleted_at IS NULL;
+-- License plates are optional; vehicles without one are not constrained.
+CREATE UNIQUE INDEX uq_vehicles_license_plate ON vehicles(license_plate)
+    WHERE deleted_at IS NULL AND license_plate <> '';
+
+-- +goose Down
+DROP INDEX uq_vehicles_license_plate;
+DROP INDEX uq_drivers_license_number;
+DROP INDEX uq_legal_entities_tax_id;
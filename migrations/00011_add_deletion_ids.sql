@@ -0,0 +1,17 @@
+-- +goose Up
+-- Entities soft-deleted by one operation, including a cascade, share a deletion_id
+-- so a cascading restore can bring back exactly what was deleted together.
+ALTER TABLE legal_entities ADD COLUMN deletion_id UUID;
+ALTER TABLE fleets ADD COLUMN deletion_id UUID;
+ALTER TABLE vehicles ADD COLUMN deletion_id UUID;
+ALTER TABLE drivers ADD COLUMN deletion_id UUID;
+ALTER TABLE contracts ADD COLUMN deletion_id UUID;
+ALTER TABLE vehicle_assignments ADD COLUMN deletion_id UUID;
+
+-- +goose Down
+ALTER TABLE vehicle_assignments DROP COLUMN deletion_id;
+ALTER TABLE contracts DROP COLUMN deletion_id;
+ALTER TABLE drivers DROP COLUMN deletion_id;
+ALTER TABLE vehicles DROP COLUMN deletion_id;
+ALTER TABLE fleets DROP COLUMN deletion_id;
+ALTER TABLE legal_entities DROP COLUMN deletion_id;
@@ -0,0 +1,88 @@
+-- +goose Up
+-- Rows created before this migration get the migration time and an unknown actor.
+ALTER TABLE legal_entities
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN created_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN updated_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN deleted_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN delete_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE fleets
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN created_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN updated_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN deleted_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN delete_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE vehicles
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN created_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN updated_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN deleted_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN delete_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE drivers
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN created_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN updated_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN deleted_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN delete_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE contracts
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN created_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN updated_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN deleted_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN delete_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE vehicle_assignments
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN created_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN updated_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN deleted_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN delete_reason TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE vehicle_assignments
+    DROP COLUMN delete_reason,
+    DROP COLUMN deleted_by,
+    DROP COLUMN updated_by,
+    DROP COLUMN updated_at,
+    DROP COLUMN created_by,
+    DROP COLUMN created_at;
+ALTER TABLE contracts
+    DROP COLUMN delete_reason,
+    DROP COLUMN deleted_by,
+    DROP COLUMN updated_by,
+    DROP COLUMN updated_at,
+    DROP COLUMN created_by,
+    DROP COLUMN created_at;
+ALTER TABLE drivers
+    DROP COLUMN delete_reason,
+    DROP COLUMN deleted_by,
+    DROP COLUMN updated_by,
+    DROP COLUMN updated_at,
+    DROP COLUMN created_by,
+    DROP COLUMN created_at;
+ALTER TABLE vehicles
+    DROP COLUMN delete_reason,
+    DROP COLUMN deleted_by,
+    DROP COLUMN updated_by,
+    DROP COLUMN updated_at,
+    DROP COLUMN created_by,
+    DROP COLUMN created_at;
+ALTER TABLE fleets
+    DROP COLUMN delete_reason,
+    DROP COLUMN deleted_by,
+    DROP COLUMN updated_by,
+    DROP COLUMN updated_at,
+    DROP COLUMN created_by,
+    DROP COLUMN created_at;
+ALTER TABLE legal_entities
+    DROP COLUMN delete_reason,
+    DROP COLUMN deleted_by,
+    DROP COLUMN updated_by,
+    DROP COLUMN updated_at,
+    DROP COLUMN created_by,
+    DROP COLUMN created_at;
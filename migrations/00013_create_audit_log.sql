@@ -0,0 +1,29 @@
+-- +goose Up
+CREATE TABLE audit_log (
+    id          UUID PRIMARY KEY,
+    entity_type TEXT NOT NULL,
+    entity_id   UUID NOT NULL,
+    action      TEXT NOT NULL,
+    actor       TEXT NOT NULL,
+    at          TIMESTAMPTZ NOT NULL,
+    deletion_id UUID,
+    before      JSONB,
+    after       JSONB
+);
+CREATE INDEX idx_audit_log_entity ON audit_log(entity_type, entity_id, at);
+CREATE INDEX idx_audit_log_deletion ON audit_log(deletion_id) WHERE deletion_id IS NOT NULL;
+
+-- The audit log is append-only: entries can be neither changed nor removed.
+-- +goose StatementBegin
+CREATE FUNCTION reject_audit_log_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only' USING ERRCODE = 'insufficient_privilege';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+CREATE TRIGGER trg_audit_log_append_only BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION reject_audit_log_change();
+
+-- +goose Down
+DROP TABLE audit_log;
+DROP FUNCTION reject_audit_log_change();
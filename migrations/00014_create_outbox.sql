@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE outbox (
+    id              UUID PRIMARY KEY,
+    event_type      TEXT NOT NULL,
+    payload         JSONB NOT NULL,
+    occurred_at     TIMESTAMPTZ NOT NULL,
+    attempts        INT NOT NULL DEFAULT 0,
+    last_error      TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    locked_until    TIMESTAMPTZ,
+    delivered_at    TIMESTAMPTZ
+);
+CREATE INDEX idx_outbox_pending ON outbox(next_attempt_at) WHERE delivered_at IS NULL;
+
+-- +goose Down
+DROP TABLE outbox;
@@ -0,0 +1,38 @@
+-- +goose Up
+CREATE TABLE webhook_subscriptions (
+    id                   UUID PRIMARY KEY,
+    url                  TEXT NOT NULL,
+    event_types          JSONB NOT NULL,
+    secret               TEXT NOT NULL,
+    active               BOOLEAN NOT NULL DEFAULT TRUE,
+    consecutive_failures INT NOT NULL DEFAULT 0,
+    disabled_at          TIMESTAMPTZ,
+    version              BIGINT NOT NULL DEFAULT 1,
+    created_at           TIMESTAMPTZ NOT NULL,
+    created_by           TEXT NOT NULL DEFAULT '',
+    updated_at           TIMESTAMPTZ NOT NULL,
+    updated_by           TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE webhook_deliveries (
+    id               UUID PRIMARY KEY,
+    subscription_id  UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id         UUID NOT NULL,
+    event_type       TEXT NOT NULL,
+    payload          JSONB NOT NULL,
+    occurred_at      TIMESTAMPTZ NOT NULL,
+    status           TEXT NOT NULL,
+    attempts         INT NOT NULL DEFAULT 0,
+    last_status_code INT NOT NULL DEFAULT 0,
+    last_error       TEXT NOT NULL DEFAULT '',
+    next_attempt_at  TIMESTAMPTZ NOT NULL,
+    locked_until     TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL,
+    delivered_at     TIMESTAMPTZ,
+    CONSTRAINT uq_webhook_deliveries_event UNIQUE (subscription_id, event_id)
+);
+CREATE INDEX idx_webhook_deliveries_pending ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+
+-- +goose Down
+DROP TABLE webhook_deliveries;
+DROP TABLE webhook_subscriptions;
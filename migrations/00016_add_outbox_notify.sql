@@ -0,0 +1,28 @@
+-- +goose Up
+-- Events are streamed in (txid, seq) order: txid is the enqueuing transaction, seq
+-- orders the events within it. Readers only see transactions older than every running
+-- one, so an event committed later never sorts before one already read. Existing rows
+-- share the migration's txid and keep their identity order.
+ALTER TABLE outbox ADD COLUMN seq BIGINT GENERATED ALWAYS AS IDENTITY;
+ALTER TABLE outbox ADD COLUMN txid XID8 NOT NULL DEFAULT pg_current_xact_id();
+CREATE UNIQUE INDEX uq_outbox_position ON outbox(txid, seq);
+
+-- Notifications are sent on commit and only wake listeners up; identical ones are
+-- folded into one per transaction.
+-- +goose StatementBegin
+CREATE FUNCTION notify_outbox_event() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('outbox_events', '');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+CREATE TRIGGER trg_outbox_notify AFTER INSERT ON outbox
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_outbox_event();
+
+-- +goose Down
+DROP TRIGGER trg_outbox_notify ON outbox;
+DROP FUNCTION notify_outbox_event();
+DROP INDEX uq_outbox_position;
+ALTER TABLE outbox DROP COLUMN txid;
+ALTER TABLE outbox DROP COLUMN seq;
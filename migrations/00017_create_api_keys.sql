@@ -0,0 +1,17 @@
+-- +goose Up
+-- Keys are provisioned out of band; store the hex SHA-256 of the key, e.g.
+-- INSERT INTO api_keys (id, name, key_hash, principal_id)
+-- VALUES (gen_random_uuid(), 'payroll', encode(sha256('<key>'::bytea), 'hex'), 'svc-payroll');
+CREATE TABLE api_keys (
+    id           UUID PRIMARY KEY,
+    name         TEXT NOT NULL,
+    key_hash     TEXT NOT NULL,
+    principal_id TEXT NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at   TIMESTAMPTZ,
+    revoked_at   TIMESTAMPTZ,
+    CONSTRAINT uq_api_keys_key_hash UNIQUE (key_hash)
+);
+
+-- +goose Down
+DROP TABLE api_keys;
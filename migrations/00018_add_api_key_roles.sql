@@ -0,0 +1,10 @@
+-- +goose Up
+-- Existing keys keep full access; new keys must be given a role explicitly.
+ALTER TABLE api_keys ADD COLUMN role TEXT NOT NULL DEFAULT 'admin';
+ALTER TABLE api_keys ALTER COLUMN role DROP DEFAULT;
+-- The legal entities a fleet_manager key is scoped to.
+ALTER TABLE api_keys ADD COLUMN legal_entity_ids JSONB NOT NULL DEFAULT '[]';
+
+-- +goose Down
+ALTER TABLE api_keys DROP COLUMN legal_entity_ids;
+ALTER TABLE api_keys DROP COLUMN role;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- status_code is NULL while the first request with the key is in progress.
+CREATE TABLE idempotency_keys (
+    principal_id TEXT NOT NULL,
+    key          TEXT NOT NULL,
+    fingerprint  TEXT NOT NULL,
+    status_code  INTEGER,
+    header       JSONB,
+    body         BYTEA,
+    created_at   TIMESTAMPTZ NOT NULL,
+    expires_at   TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (principal_id, key)
+);
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+
+-- +goose Down
+DROP TABLE idempotency_keys;